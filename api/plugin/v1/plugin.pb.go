@@ -143,7 +143,7 @@ func (x *InfoResponse) GetUrl() string {
 // ValidateRequest contains the context passed to plugin validators.
 type ValidateRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// event_type is the hook event type ("PreToolUse", "PostToolUse", "Notification").
+	// event_type is the hook event type ("PreToolUse", "PostToolUse", "Stop", etc.).
 	EventType string `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
 	// tool_name is the tool being invoked ("Bash", "Write", "Edit", etc.).
 	ToolName string `protobuf:"bytes,2,opt,name=tool_name,json=toolName,proto3" json:"tool_name,omitempty"`
@@ -160,7 +160,19 @@ type ValidateRequest struct {
 	// pattern is the search pattern (for Grep/Glob tools).
 	Pattern string `protobuf:"bytes,8,opt,name=pattern,proto3" json:"pattern,omitempty"`
 	// config contains plugin-specific configuration from the config file.
-	Config        map[string]string `protobuf:"bytes,9,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Config map[string]string `protobuf:"bytes,9,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// prompt is the submitted prompt (for UserPromptSubmit events).
+	Prompt string `protobuf:"bytes,10,opt,name=prompt,proto3" json:"prompt,omitempty"`
+	// cwd is the working directory of the Claude Code session.
+	Cwd string `protobuf:"bytes,11,opt,name=cwd,proto3" json:"cwd,omitempty"`
+	// stop_hook_active is true when Claude Code is already continuing because of a stop hook.
+	StopHookActive bool `protobuf:"varint,12,opt,name=stop_hook_active,json=stopHookActive,proto3" json:"stop_hook_active,omitempty"`
+	// source is how the session started (for SessionStart events).
+	Source string `protobuf:"bytes,13,opt,name=source,proto3" json:"source,omitempty"`
+	// reason is why the session ended (for SessionEnd events).
+	Reason string `protobuf:"bytes,14,opt,name=reason,proto3" json:"reason,omitempty"`
+	// trigger is what triggered compaction (for PreCompact events).
+	Trigger       string `protobuf:"bytes,15,opt,name=trigger,proto3" json:"trigger,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -258,6 +270,48 @@ func (x *ValidateRequest) GetConfig() map[string]string {
 	return nil
 }
 
+func (x *ValidateRequest) GetPrompt() string {
+	if x != nil {
+		return x.Prompt
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetCwd() string {
+	if x != nil {
+		return x.Cwd
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetStopHookActive() bool {
+	if x != nil {
+		return x.StopHookActive
+	}
+	return false
+}
+
+func (x *ValidateRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ValidateRequest) GetTrigger() string {
+	if x != nil {
+		return x.Trigger
+	}
+	return ""
+}
+
 // ValidateResponse contains the validation result returned by a plugin.
 type ValidateResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -274,7 +328,10 @@ type ValidateResponse struct {
 	// doc_link is a URL to detailed documentation for this error.
 	DocLink string `protobuf:"bytes,6,opt,name=doc_link,json=docLink,proto3" json:"doc_link,omitempty"`
 	// details contains additional structured information about the result.
-	Details       map[string]string `protobuf:"bytes,7,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Details map[string]string `protobuf:"bytes,7,rep,name=details,proto3" json:"details,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// should_ask indicates whether the user should be asked to confirm the operation.
+	// Ignored when should_block is true.
+	ShouldAsk     bool `protobuf:"varint,8,opt,name=should_ask,json=shouldAsk,proto3" json:"should_ask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -358,6 +415,13 @@ func (x *ValidateResponse) GetDetails() map[string]string {
 	return nil
 }
 
+func (x *ValidateResponse) GetShouldAsk() bool {
+	if x != nil {
+		return x.ShouldAsk
+	}
+	return false
+}
+
 var File_plugin_v1_plugin_proto protoreflect.FileDescriptor
 
 const file_plugin_v1_plugin_proto_rawDesc = "" +
@@ -369,7 +433,7 @@ const file_plugin_v1_plugin_proto_rawDesc = "" +
 	"\aversion\x18\x02 \x01(\tR\aversion\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x16\n" +
 	"\x06author\x18\x04 \x01(\tR\x06author\x12\x10\n" +
-	"\x03url\x18\x05 \x01(\tR\x03url\"\xf1\x02\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\"\x8f\x04\n" +
 	"\x0fValidateRequest\x12\x1d\n" +
 	"\n" +
 	"event_type\x18\x01 \x01(\tR\teventType\x12\x1b\n" +
@@ -382,10 +446,17 @@ const file_plugin_v1_plugin_proto_rawDesc = "" +
 	"\n" +
 	"new_string\x18\a \x01(\tR\tnewString\x12\x18\n" +
 	"\apattern\x18\b \x01(\tR\apattern\x12>\n" +
-	"\x06config\x18\t \x03(\v2&.plugin.v1.ValidateRequest.ConfigEntryR\x06config\x1a9\n" +
+	"\x06config\x18\t \x03(\v2&.plugin.v1.ValidateRequest.ConfigEntryR\x06config\x12\x16\n" +
+	"\x06prompt\x18\n" +
+	" \x01(\tR\x06prompt\x12\x10\n" +
+	"\x03cwd\x18\v \x01(\tR\x03cwd\x12(\n" +
+	"\x10stop_hook_active\x18\f \x01(\bR\x0estopHookActive\x12\x16\n" +
+	"\x06source\x18\r \x01(\tR\x06source\x12\x16\n" +
+	"\x06reason\x18\x0e \x01(\tR\x06reason\x12\x18\n" +
+	"\atrigger\x18\x0f \x01(\tR\atrigger\x1a9\n" +
 	"\vConfigEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xbc\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xdb\x02\n" +
 	"\x10ValidateResponse\x12\x16\n" +
 	"\x06passed\x18\x01 \x01(\bR\x06passed\x12!\n" +
 	"\fshould_block\x18\x02 \x01(\bR\vshouldBlock\x12\x18\n" +
@@ -394,7 +465,9 @@ const file_plugin_v1_plugin_proto_rawDesc = "" +
 	"error_code\x18\x04 \x01(\tR\terrorCode\x12\x19\n" +
 	"\bfix_hint\x18\x05 \x01(\tR\afixHint\x12\x19\n" +
 	"\bdoc_link\x18\x06 \x01(\tR\adocLink\x12B\n" +
-	"\adetails\x18\a \x03(\v2(.plugin.v1.ValidateResponse.DetailsEntryR\adetails\x1a:\n" +
+	"\adetails\x18\a \x03(\v2(.plugin.v1.ValidateResponse.DetailsEntryR\adetails\x12\x1d\n" +
+	"\n" +
+	"should_ask\x18\b \x01(\bR\tshouldAsk\x1a:\n" +
 	"\fDetailsEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x012\x8f\x01\n" +
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// Check command flags.
+var (
+	checkBash        string
+	checkWrite       string
+	checkContentFile string
+	checkPayload     string
+	checkJSON        bool
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check whether a tool call would be allowed",
+	Long: `Run a tool call through the full validation pipeline without a hook.
+
+Builds a PreToolUse Bash or Write call from flags, or reads a hook payload
+from a file, and reports the decision together with the validators and rules
+that matched. Session state, exception rate limits and audit logs are never
+changed. Exits with code 2 if the call would be blocked.
+
+Examples:
+  klaudiush check --bash 'git push origin main'
+  klaudiush check --write README.md --content-file draft.md
+  klaudiush check --payload hook.json --json
+  cat hook.json | klaudiush check --payload -`,
+	Args: cobra.NoArgs,
+	RunE: runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().StringVar(&checkBash, "bash", "", "Bash command to check")
+	checkCmd.Flags().StringVar(&checkWrite, "write", "", "File path of a Write call to check")
+	checkCmd.Flags().StringVar(
+		&checkContentFile,
+		"content-file",
+		"",
+		"File holding the content of the Write call",
+	)
+	checkCmd.Flags().StringVar(
+		&checkPayload,
+		"payload",
+		"",
+		"Hook JSON payload file to check (- for stdin)",
+	)
+	checkCmd.Flags().BoolVar(&checkJSON, "json", false, "Output the report as JSON")
+
+	checkCmd.MarkFlagsMutuallyExclusive("bash", "write", "payload")
+	checkCmd.MarkFlagsOneRequired("bash", "write", "payload")
+}
+
+// checkReport is the outcome of checking a single tool call.
+type checkReport struct {
+	Decision   string             `json:"decision"`
+	Event      string             `json:"event"`
+	Tool       string             `json:"tool,omitempty"`
+	Validators []string           `json:"validators"`
+	Rules      []checkRuleMatch   `json:"rules"`
+	Errors     []checkErrorReport `json:"errors"`
+
+	validationErrors []*dispatcher.ValidationError
+}
+
+// checkRuleMatch is a rule that matched while checking.
+type checkRuleMatch struct {
+	Name      string `json:"name"`
+	Validator string `json:"validator"`
+	Action    string `json:"action"`
+}
+
+// checkErrorReport is a validation error in the JSON report.
+type checkErrorReport struct {
+	Validator string `json:"validator"`
+	Message   string `json:"message"`
+	Block     bool   `json:"block"`
+	Ask       bool   `json:"ask"`
+	Reference string `json:"reference,omitempty"`
+	FixHint   string `json:"fix_hint,omitempty"`
+
+	// UpdatedInput is the tool input rewritten by a rule.
+	UpdatedInput map[string]any `json:"updated_input,omitempty"`
+}
+
+func runCheck(_ *cobra.Command, _ []string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return errors.Wrap(err, "failed to get home directory")
+	}
+
+	logFile := filepath.Join(homeDir, ".claude", "hooks", "dispatcher.log")
+
+	log, err := logger.NewFileLogger(logFile, false, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to create logger")
+	}
+
+	log.Info("check command invoked")
+
+	hookCtx, err := buildCheckContext()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig(log)
+	if err != nil {
+		return errors.Wrap(err, "failed to load configuration")
+	}
+
+	rt, err := app.New(cfg, log, app.WithDryRun())
+	if err != nil {
+		return errors.Wrap(err, "failed to build runtime")
+	}
+
+	report := runCheckReport(rt, hookCtx)
+
+	if checkJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal report")
+		}
+
+		fmt.Println(string(data))
+	} else {
+		printCheckReport(report)
+	}
+
+	if report.Decision == string(app.DecisionBlock) {
+		os.Exit(ExitCodeBlock)
+	}
+
+	return nil
+}
+
+// buildCheckContext builds the hook context from the check flags.
+func buildCheckContext() (*hook.Context, error) {
+	switch {
+	case checkBash != "":
+		return &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			ToolInput: hook.ToolInput{Command: checkBash},
+		}, nil
+
+	case checkWrite != "":
+		var content []byte
+
+		if checkContentFile != "" {
+			data, err := os.ReadFile(checkContentFile)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to read content file")
+			}
+
+			content = data
+		}
+
+		return &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeWrite,
+			ToolInput: hook.ToolInput{FilePath: checkWrite, Content: string(content)},
+		}, nil
+
+	default:
+		var input io.Reader = os.Stdin
+
+		if checkPayload != "-" {
+			file, err := os.Open(checkPayload)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to open payload")
+			}
+			defer file.Close()
+
+			input = file
+		}
+
+		hookCtx, err := app.ParseHookInput("", input)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse payload")
+		}
+
+		return hookCtx, nil
+	}
+}
+
+// runCheckReport dispatches the hook context and collects the matched
+// validators and rules.
+func runCheckReport(rt *app.Runtime, hookCtx *hook.Context) *checkReport {
+	var (
+		mu      sync.Mutex
+		matches []checkRuleMatch
+	)
+
+	if engine := rt.RuleEngine(); engine != nil {
+		engine.AddObserver(func(matchCtx *rules.MatchContext, result *rules.RuleResult) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			matches = append(matches, checkRuleMatch{
+				Name:      result.Rule.Name,
+				Validator: string(matchCtx.ValidatorType),
+				Action:    string(result.Action),
+			})
+		})
+	}
+
+	validators := rt.MatchingValidators(hookCtx)
+	errs := rt.Dispatch(context.Background(), hookCtx)
+
+	report := &checkReport{
+		Decision:         string(app.Decide(errs)),
+		Event:            hookCtx.EventType.String(),
+		Tool:             hookCtx.ToolName.String(),
+		Validators:       make([]string, 0, len(validators)),
+		Rules:            matches,
+		Errors:           make([]checkErrorReport, 0, len(errs)),
+		validationErrors: errs,
+	}
+
+	if report.Rules == nil {
+		report.Rules = []checkRuleMatch{}
+	}
+
+	for _, v := range validators {
+		report.Validators = append(report.Validators, v.Name())
+	}
+
+	for _, e := range errs {
+		report.Errors = append(report.Errors, checkErrorReport{
+			Validator: e.Validator,
+			Message:   e.Message,
+			Block:     e.ShouldBlock,
+			Ask:       e.ShouldAsk,
+			Reference: string(e.Reference),
+			FixHint:   e.FixHint,
+
+			UpdatedInput: e.UpdatedInput,
+		})
+	}
+
+	return report
+}
+
+// printCheckReport prints the report in human-readable form.
+func printCheckReport(report *checkReport) {
+	fmt.Printf("Decision: %s\n", strings.ToUpper(report.Decision))
+	fmt.Printf("Event: %s\n", report.Event)
+
+	if report.Tool != "" {
+		fmt.Printf("Tool: %s\n", report.Tool)
+	}
+
+	fmt.Println("")
+	fmt.Println("Validators matched:")
+
+	if len(report.Validators) == 0 {
+		fmt.Println("  (none)")
+	}
+
+	for _, name := range report.Validators {
+		fmt.Printf("  - %s\n", name)
+	}
+
+	fmt.Println("")
+	fmt.Println("Rules matched:")
+
+	if len(report.Rules) == 0 {
+		fmt.Println("  (none)")
+	}
+
+	for _, match := range report.Rules {
+		fmt.Printf("  - %s (%s, %s)\n", match.Name, match.Validator, match.Action)
+	}
+
+	if len(report.validationErrors) > 0 {
+		fmt.Print(dispatcher.FormatErrors(report.validationErrors))
+	}
+}
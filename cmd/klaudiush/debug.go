@@ -3,6 +3,7 @@ package main
 
 import (
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
@@ -14,7 +15,9 @@ import (
 	"github.com/spf13/cobra"
 
 	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/config/factory"
 	"github.com/smykla-labs/klaudiush/internal/exceptions"
+	"github.com/smykla-labs/klaudiush/internal/rules"
 	"github.com/smykla-labs/klaudiush/pkg/config"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
 )
@@ -58,9 +61,14 @@ var debugRulesCmd = &cobra.Command{
 Displays all rules with their match conditions, actions, and priorities.
 Rules are shown in evaluation order (highest priority first).
 
+With --lint, also analyzes enabled rules for conflicts: identical match
+conditions with different actions, block rules made unreachable by a
+higher-priority allow rule, overlapping patterns, and unknown validator types.
+
 Examples:
   klaudiush debug rules                    # Show all rules
-  klaudiush debug rules --validator git.push  # Show rules for git.push validator`,
+  klaudiush debug rules --validator git.push  # Show rules for git.push validator
+  klaudiush debug rules --lint             # Show rules and rule conflicts`,
 	RunE: runDebugRules,
 }
 
@@ -93,7 +101,10 @@ Examples:
 	RunE: runDebugExceptions,
 }
 
-var showState bool
+var (
+	showState bool
+	rulesLint bool
+)
 
 func init() {
 	rootCmd.AddCommand(debugCmd)
@@ -121,6 +132,13 @@ func init() {
 		"Filter rules by validator type (e.g., git.push, file.*, secrets.secrets)",
 	)
 
+	debugRulesCmd.Flags().BoolVar(
+		&rulesLint,
+		"lint",
+		false,
+		"Analyze rules for conflicts, shadowing and unknown validator types",
+	)
+
 	debugExceptionsCmd.Flags().BoolVar(
 		&showState,
 		"state",
@@ -137,6 +155,10 @@ func runDebugRules(_ *cobra.Command, _ []string) error {
 
 	displayRulesConfig(cfg, validatorFilter)
 
+	if rulesLint {
+		displayRulesLint(cfg)
+	}
+
 	return nil
 }
 
@@ -222,6 +244,32 @@ func displayRulesConfig(cfg *config.Config, filter string) {
 	}
 }
 
+// displayRulesLint displays the conflicts found across all enabled rules.
+func displayRulesLint(cfg *config.Config) {
+	rulesCfg := cfg.GetRules()
+	if rulesCfg == nil || len(rulesCfg.Rules) == 0 {
+		return
+	}
+
+	fmt.Println("Rule Lint")
+	fmt.Println("=========")
+	fmt.Println("")
+
+	findings := rules.Lint(factory.ConvertRuleConfigs(rulesCfg.Rules))
+	if len(findings) == 0 {
+		fmt.Println("No issues found.")
+
+		return
+	}
+
+	for _, finding := range findings {
+		fmt.Printf("[%s] %s\n", finding.Kind, finding.Message)
+	}
+
+	fmt.Println("")
+	fmt.Printf("%d issue(s) found\n", len(findings))
+}
+
 func filterRules(rules []config.RuleConfig, filter string) []config.RuleConfig {
 	if filter == "" {
 		return rules
@@ -281,6 +329,10 @@ func displayRule(index int, rule *config.RuleConfig) {
 	fmt.Printf("Rule #%d: %s [%s]\n", index, rule.Name, enabledStr)
 	fmt.Printf("  Priority: %d\n", rule.Priority)
 
+	if rule.Source != "" {
+		fmt.Printf("  Source: %s\n", rule.Source)
+	}
+
 	if rule.Description != "" {
 		fmt.Printf("  Description: %s\n", rule.Description)
 	}
@@ -303,47 +355,139 @@ func displayRule(index int, rule *config.RuleConfig) {
 		if rule.Action.Reference != "" {
 			fmt.Printf("    Reference: %s\n", rule.Action.Reference)
 		}
+
+		if rule.Action.FixHint != "" {
+			fmt.Printf("    Fix Hint: %s\n", rule.Action.FixHint)
+		}
+
+		if rewrite := rule.Action.Rewrite; rewrite != nil {
+			displayRuleRewrite(rewrite)
+		}
 	}
 
 	fmt.Println("")
 }
 
+func displayRuleRewrite(rewrite *config.RuleRewriteConfig) {
+	fmt.Println("    Rewrite:")
+
+	if len(rewrite.AddFlags) > 0 {
+		fmt.Printf("      Add Flags: %s\n", strings.Join(rewrite.AddFlags, " "))
+	}
+
+	if rewrite.ReplacePrefix != "" {
+		fmt.Printf("      Replace Prefix: %s -> %s\n", rewrite.ReplacePrefix, rewrite.ReplacePrefixWith)
+	}
+
+	if rewrite.InsertRemote != "" {
+		fmt.Printf("      Insert Remote: %s\n", rewrite.InsertRemote)
+	}
+}
+
 func displayMatchCondition(indent string, match *config.RuleMatchConfig) {
-	if match.ValidatorType != "" {
-		fmt.Printf("%sValidator Type: %s\n", indent, match.ValidatorType)
+	for _, line := range matchConditionLines(match) {
+		fmt.Printf("%s%s\n", indent, line)
 	}
+}
+
+// matchConditionLines renders a match block as lines, with nested any, all
+// and not blocks as an indented tree.
+func matchConditionLines(match *config.RuleMatchConfig) []string {
+	var lines []string
 
-	if match.RepoPattern != "" {
-		fmt.Printf("%sRepo Pattern: %s\n", indent, match.RepoPattern)
+	add := func(label, value string) {
+		if value != "" {
+			lines = append(lines, label+": "+value)
+		}
 	}
 
-	if match.Remote != "" {
-		fmt.Printf("%sRemote: %s\n", indent, match.Remote)
+	add("Validator Type", match.ValidatorType)
+	add("Repo Pattern", match.RepoPattern)
+	add("Remote", match.Remote)
+	add("Branch Pattern", match.BranchPattern)
+	add("File Pattern", match.FilePattern)
+	add("Content Pattern", match.ContentPattern)
+	add("Command Pattern", match.CommandPattern)
+	add("Command Name", match.CommandName)
+	add("Git Subcommand", match.GitSubcommand)
+	add("Has Flag", match.HasFlag)
+	add("Has Flags", strings.Join(match.HasFlags, ", "))
+
+	if len(match.HasFlags) > 0 {
+		add("Flag Mode", match.GetFlagMode())
 	}
+	add("Arg Pattern", match.ArgPattern)
 
-	if match.BranchPattern != "" {
-		fmt.Printf("%sBranch Pattern: %s\n", indent, match.BranchPattern)
+	if match.InPipeline != nil {
+		add("In Pipeline", strconv.FormatBool(*match.InPipeline))
 	}
 
-	if match.FilePattern != "" {
-		fmt.Printf("%sFile Pattern: %s\n", indent, match.FilePattern)
+	if match.DynamicArgs != nil {
+		add("Dynamic Args", strconv.FormatBool(*match.DynamicArgs))
 	}
 
-	if match.ContentPattern != "" {
-		fmt.Printf("%sContent Pattern: %s\n", indent, match.ContentPattern)
+	add("Staged File Pattern", match.StagedFilePattern)
+	add("Modified File Pattern", match.ModifiedFilePattern)
+	add("Untracked File Pattern", match.UntrackedFilePattern)
+
+	if match.DirtyTree != nil {
+		add("Dirty Tree", strconv.FormatBool(*match.DirtyTree))
+	}
+
+	if match.AheadOfUpstream > 0 {
+		add("Ahead Of Upstream", strconv.Itoa(match.AheadOfUpstream))
 	}
 
-	if match.CommandPattern != "" {
-		fmt.Printf("%sCommand Pattern: %s\n", indent, match.CommandPattern)
+	if match.BehindUpstream > 0 {
+		add("Behind Upstream", strconv.Itoa(match.BehindUpstream))
 	}
 
-	if match.ToolType != "" {
-		fmt.Printf("%sTool Type: %s\n", indent, match.ToolType)
+	add("Last Commit Author", match.LastCommitAuthor)
+
+	if tw := match.TimeWindow; tw != nil {
+		add("Time Window", formatTimeWindow(tw))
 	}
 
-	if match.EventType != "" {
-		fmt.Printf("%sEvent Type: %s\n", indent, match.EventType)
+	for _, name := range slices.Sorted(maps.Keys(match.Env)) {
+		add("Env "+name, match.Env[name])
 	}
+
+	add("Hostname Pattern", match.HostnamePattern)
+
+	add("Tool Type", match.ToolType)
+	add("Event Type", match.EventType)
+
+	addList := func(label string, nested []*config.RuleMatchConfig) {
+		if len(nested) == 0 {
+			return
+		}
+
+		lines = append(lines, label+":")
+
+		for _, block := range nested {
+			for i, line := range matchConditionLines(block) {
+				prefix := "    "
+				if i == 0 {
+					prefix = "  - "
+				}
+
+				lines = append(lines, prefix+line)
+			}
+		}
+	}
+
+	addList("Any of", match.Any)
+	addList("All of", match.All)
+
+	if match.Not != nil {
+		lines = append(lines, "Not:")
+
+		for _, line := range matchConditionLines(match.Not) {
+			lines = append(lines, "  "+line)
+		}
+	}
+
+	return lines
 }
 
 func runDebugExceptions(_ *cobra.Command, _ []string) error {
@@ -852,3 +996,21 @@ func displayNotificationValidators(notif *config.NotificationConfig, filter stri
 
 	fmt.Println("")
 }
+
+// formatTimeWindow formats a time window as its days, hours and timezone.
+func formatTimeWindow(tw *config.TimeWindowConfig) string {
+	parts := []string{"every day"}
+	if len(tw.Days) > 0 {
+		parts[0] = strings.Join(tw.Days, ", ")
+	}
+
+	if tw.Hours != "" {
+		parts = append(parts, tw.Hours)
+	}
+
+	if tw.Timezone != "" {
+		parts = append(parts, tw.Timezone)
+	}
+
+	return strings.Join(parts, " ")
+}
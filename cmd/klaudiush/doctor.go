@@ -162,6 +162,7 @@ func buildDoctorRegistry() *doctor.Registry {
 
 	// Register rules checkers
 	registry.RegisterChecker(ruleschecker.NewRulesChecker())
+	registry.RegisterChecker(ruleschecker.NewAnalysisChecker())
 
 	// Register tools checkers
 	registry.RegisterChecker(tools.NewShellcheckChecker())
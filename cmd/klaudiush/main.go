@@ -2,21 +2,24 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
 	"github.com/cockroachdb/errors"
 	"github.com/spf13/cobra"
 
+	"github.com/smykla-labs/klaudiush/internal/app"
 	"github.com/smykla-labs/klaudiush/internal/backup"
 	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
-	"github.com/smykla-labs/klaudiush/internal/config/factory"
 	"github.com/smykla-labs/klaudiush/internal/crashdump"
+	"github.com/smykla-labs/klaudiush/internal/daemon"
 	"github.com/smykla-labs/klaudiush/internal/dispatcher"
 	"github.com/smykla-labs/klaudiush/internal/parser"
-	"github.com/smykla-labs/klaudiush/internal/session"
 	"github.com/smykla-labs/klaudiush/pkg/config"
 	"github.com/smykla-labs/klaudiush/pkg/hook"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
@@ -43,6 +46,7 @@ var (
 	configPath   string
 	globalConfig string
 	disableList  []string
+	outputFormat string
 
 	// crashContext stores the current hook context for crash recovery.
 	// Set during validation dispatch and accessed by panic handler.
@@ -93,7 +97,7 @@ func init() {
 		"hook-type",
 		"T",
 		"",
-		"Hook event type (PreToolUse, PostToolUse, Notification)",
+		"Hook event type (PreToolUse, PostToolUse, Notification, UserPromptSubmit, Stop, SubagentStop, SessionStart, SessionEnd, PreCompact)",
 	)
 	rootCmd.Flags().BoolVar(&debugMode, "debug", true, "Enable debug logging")
 	rootCmd.Flags().BoolVar(&traceMode, "trace", false, "Enable trace logging")
@@ -116,6 +120,12 @@ func init() {
 		[]string{},
 		"Comma-separated list of validators to disable (e.g., commit,markdown)",
 	)
+	rootCmd.Flags().StringVar(
+		&outputFormat,
+		"output-format",
+		"",
+		"Hook decision output format: text (stderr + exit code) or json (structured stdout)",
+	)
 }
 
 func run(_ *cobra.Command, _ []string) error {
@@ -137,10 +147,11 @@ func run(_ *cobra.Command, _ []string) error {
 		log.Error("first-run migration failed", "error", migErr)
 	}
 
-	// Determine event type using enumer-generated function
+	// Determine event type using enumer-generated function. If the flag is
+	// missing, the event type is derived from hook_event_name in the payload.
 	eventType, err := hook.EventTypeString(hookType)
 	if err != nil {
-		eventType = hook.EventTypePreToolUse // Default to PreToolUse
+		eventType = hook.EventTypeUnknown
 	}
 
 	log.Info("hook invoked",
@@ -149,27 +160,103 @@ func run(_ *cobra.Command, _ []string) error {
 		"trace", traceMode,
 	)
 
+	// Read the payload once so it can be forwarded to the daemon or parsed in-process
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return errors.Wrap(err, "failed to read input")
+	}
+
+	result, err := dispatchViaDaemon(homeDir, input)
+	if err != nil {
+		if !errors.Is(err, daemon.ErrUnavailable) {
+			log.Info("daemon request failed, validating in-process", "error", err)
+		}
+
+		result, err = dispatchInProcess(input, log)
+		if err != nil {
+			return err
+		}
+	}
+
+	if result == nil {
+		log.Info("no input provided, allowing")
+
+		return nil
+	}
+
+	return report(result, log)
+}
+
+// hookResult is the outcome of validating a single hook invocation.
+type hookResult struct {
+	eventType    hook.EventType
+	outputFormat string
+	errs         []*dispatcher.ValidationError
+}
+
+// dispatchViaDaemon forwards the payload to a running daemon. It returns a nil
+// result for empty input, and an error if the daemon is unavailable or could
+// not validate the payload.
+func dispatchViaDaemon(homeDir string, input []byte) (*hookResult, error) {
+	path := socketPath
+	if path == "" {
+		path = daemon.DefaultSocketPath(homeDir)
+	}
+
+	workDir, err := os.Getwd()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get working directory")
+	}
+
+	resp, err := daemon.NewClient(path).Do(&daemon.Request{
+		WorkDir:   workDir,
+		HookType:  hookType,
+		Flags:     hookFlags(),
+		Env:       daemon.ConfigEnv(),
+		ClientEnv: daemon.ProcessEnv(),
+		Payload:   input,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Empty {
+		return nil, nil //nolint:nilnil // empty input is allowed without a result
+	}
+
+	eventType, err := hook.EventTypeString(resp.EventType)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid event type in daemon response")
+	}
+
+	return &hookResult{
+		eventType:    eventType,
+		outputFormat: resp.OutputFormat,
+		errs:         resp.Errors,
+	}, nil
+}
+
+// dispatchInProcess loads the configuration, builds the runtime and validates
+// the payload. It returns a nil result for empty input.
+func dispatchInProcess(input []byte, log logger.Logger) (*hookResult, error) {
 	// Load configuration
 	cfg, err := loadConfig(log)
 	if err != nil {
-		return errors.Wrap(err, "failed to load configuration")
+		return nil, errors.Wrap(err, "failed to load configuration")
 	}
 
 	// Parse JSON input
-	jsonParser := parser.NewJSONParser(os.Stdin)
-
-	ctx, err := jsonParser.Parse(eventType)
+	ctx, err := app.ParseHookInput(hookType, bytes.NewReader(input))
 	if err != nil {
 		if errors.Is(err, parser.ErrEmptyInput) {
-			log.Info("no input provided, allowing")
-
-			return nil
+			return nil, nil //nolint:nilnil // empty input is allowed without a result
 		}
 
-		return errors.Wrap(err, "failed to parse input")
+		return nil, errors.Wrap(err, "failed to parse input")
 	}
 
 	log.Info("context parsed",
+		"event", ctx.EventType,
 		"tool", ctx.ToolName,
 		"command", ctx.GetCommand(),
 		"file", filepath.Base(ctx.GetFilePath()),
@@ -179,33 +266,41 @@ func run(_ *cobra.Command, _ []string) error {
 	crashContext = ctx
 	crashConfig = cfg
 
-	// Build validator registry from configuration
-	registryBuilder := factory.NewRegistryBuilder(log)
-	registry := registryBuilder.Build(cfg)
+	// Compose the runtime (registry, executor, exceptions, session) from configuration
+	rt, err := app.New(cfg, log, app.WithSkipInvalidRules())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build runtime")
+	}
+
+	// Dispatch validation
+	errs := rt.Dispatch(context.Background(), ctx)
 
-	// Create and initialize session tracker if enabled
-	sessionTracker := initSessionTracker(cfg, log)
+	// Save session and exception state after dispatch
+	if err := rt.Save(); err != nil {
+		log.Info("failed to save runtime state", "error", err)
+	}
 
-	// Create dispatcher with session tracker
-	disp := dispatcher.NewDispatcherWithOptions(
-		registry,
-		log,
-		dispatcher.NewSequentialExecutor(log),
-		dispatcher.WithSessionTracker(sessionTracker),
-	)
+	return &hookResult{
+		eventType:    ctx.EventType,
+		outputFormat: cfg.GetGlobal().GetOutputFormat(),
+		errs:         errs,
+	}, nil
+}
 
-	// Dispatch validation
-	errs := disp.Dispatch(context.Background(), ctx)
+// report writes the hook decision in the configured output format.
+func report(result *hookResult, log logger.Logger) error {
+	errs := result.errs
 
-	// Save session state after dispatch
-	if sessionTracker != nil {
-		if err := sessionTracker.Save(); err != nil {
-			log.Info("failed to save session state", "error", err)
-		}
+	// Report structured JSON decision (always exits 0)
+	if result.outputFormat == config.OutputFormatJSON {
+		return writeJSONResponse(result.eventType, errs, log)
 	}
 
-	// Check if we should block
-	if dispatcher.ShouldBlock(errs) {
+	// Check if we should block. Exit codes cannot prompt the user or rewrite
+	// the tool input, so confirmation requests and rewrites fail closed in
+	// text mode, showing the rewritten call.
+	if dispatcher.ShouldBlock(errs) || dispatcher.ShouldAsk(errs) ||
+		dispatcher.AppliedRewrite(errs) != nil {
 		errorMsg := dispatcher.FormatErrors(errs)
 		fmt.Fprint(os.Stderr, errorMsg)
 
@@ -224,6 +319,13 @@ func run(_ *cobra.Command, _ []string) error {
 		log.Info("validation passed with warnings",
 			"warningCount", len(errs),
 		)
+
+		// The tool has already run in PostToolUse, so the exit code only decides
+		// whether stderr is fed back to the model. Only findings of the opt-in
+		// whole-file validation are fed back, other warnings stay warnings.
+		if result.eventType == hook.EventTypePostToolUse && dispatcher.HasFeedback(errs) {
+			os.Exit(ExitCodeBlock)
+		}
 	} else {
 		log.Info("validation passed")
 	}
@@ -231,6 +333,32 @@ func run(_ *cobra.Command, _ []string) error {
 	return nil
 }
 
+// writeJSONResponse writes the structured hook response to stdout.
+// Nothing is written when there are no validation errors.
+func writeJSONResponse(eventType hook.EventType, errs []*dispatcher.ValidationError, log logger.Logger) error {
+	resp := dispatcher.BuildHookResponse(eventType, errs)
+	if resp.IsEmpty() {
+		log.Info("validation passed")
+
+		return nil
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal hook response")
+	}
+
+	fmt.Fprintln(os.Stdout, string(data))
+
+	log.Info("validation response written",
+		"format", config.OutputFormatJSON,
+		"errorCount", len(errs),
+		"blocked", dispatcher.ShouldBlock(errs),
+	)
+
+	return nil
+}
+
 // loadConfig loads configuration from all sources with precedence.
 func loadConfig(log logger.Logger) (*config.Config, error) {
 	// Build flags map from CLI arguments
@@ -253,48 +381,21 @@ func loadConfig(log logger.Logger) (*config.Config, error) {
 	return cfg, nil
 }
 
-// initSessionTracker creates and initializes a session tracker if enabled in the config.
-func initSessionTracker(cfg *config.Config, log logger.Logger) *session.Tracker {
-	sessionCfg := cfg.GetSession()
-	if !sessionCfg.IsEnabled() {
-		return nil
-	}
-
-	tracker := session.NewTracker(
-		sessionCfg,
-		session.WithLogger(log),
-	)
-
-	// Load existing session state
-	if err := tracker.Load(); err != nil {
-		log.Info("failed to load session state, starting fresh", "error", err)
+// hookFlags returns the CLI flags that affect configuration loading.
+func hookFlags() daemon.Flags {
+	return daemon.Flags{
+		ConfigPath:   configPath,
+		GlobalConfig: globalConfig,
+		Disable:      disableList,
+		OutputFormat: outputFormat,
 	}
-
-	log.Debug("session tracker initialized",
-		"state_file", sessionCfg.GetStateFile(),
-		"max_session_age", sessionCfg.GetMaxSessionAge(),
-	)
-
-	return tracker
 }
 
 // buildFlagsMap converts CLI flags to a map for the config provider.
 func buildFlagsMap() map[string]any {
-	flags := make(map[string]any)
-
-	if configPath != "" {
-		flags["config_path"] = configPath
-	}
-
-	if globalConfig != "" {
-		flags["global_config"] = globalConfig
-	}
-
-	if len(disableList) > 0 {
-		flags["disable"] = disableList
-	}
+	flags := hookFlags()
 
-	return flags
+	return flags.Map()
 }
 
 // performFirstRunMigration creates initial backups for existing configs on first run.
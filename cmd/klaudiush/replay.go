@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/replay"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// Replay command flags.
+var (
+	replayConfig string
+	replayJSON   bool
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <file>...",
+	Short: "Replay recorded tool calls against a candidate config",
+	Long: `Replay recorded tool calls and report the ones whose decision would change.
+
+Reads Claude Code transcripts (~/.claude/projects/*/*.jsonl), hook payloads
+(one JSON object per line) or klaudiush session and exception audit logs, and
+validates every tool call with the loaded configuration and with the candidate
+configuration given by --config, which is layered on top of it like a project
+config. Calls that would be newly blocked, no longer blocked, or get different
+warnings are reported.
+
+Git state is stubbed, with the recorded working directory as repository root.
+Session state, exception rate limits and audit logs are never changed.
+
+Examples:
+  klaudiush replay --config stricter.toml ~/.claude/projects/my-project/*.jsonl
+  klaudiush replay --config stricter.toml ~/.klaudiush/exception_audit.jsonl --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().StringVar(
+		&replayConfig,
+		"config",
+		"",
+		"Candidate config file layered over the loaded configuration",
+	)
+	replayCmd.Flags().BoolVar(&replayJSON, "json", false, "Output the report as JSON")
+
+	_ = replayCmd.MarkFlagRequired("config")
+}
+
+func runReplay(_ *cobra.Command, args []string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return errors.Wrap(err, "failed to get home directory")
+	}
+
+	logFile := filepath.Join(homeDir, ".claude", "hooks", "dispatcher.log")
+
+	log, err := logger.NewFileLogger(logFile, false, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to create logger")
+	}
+
+	log.Info("replay command invoked", "files", len(args), "config", replayConfig)
+
+	var calls []*replay.ToolCall
+
+	for _, path := range args {
+		fileCalls, err := replay.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s", path)
+		}
+
+		calls = append(calls, fileCalls...)
+	}
+
+	baseline, err := loadConfig(log)
+	if err != nil {
+		return errors.Wrap(err, "failed to load configuration")
+	}
+
+	loader, err := internalconfig.NewKoanfLoader()
+	if err != nil {
+		return errors.Wrap(err, "failed to create config loader")
+	}
+
+	loader.SetOverlayPath(replayConfig)
+
+	candidate, err := loader.Load(buildFlagsMap())
+	if err != nil {
+		return errors.Wrap(err, "failed to load candidate configuration")
+	}
+
+	report, err := replay.NewReplayer(baseline, candidate, replay.WithLogger(log)).
+		Run(context.Background(), calls)
+	if err != nil {
+		return err
+	}
+
+	if replayJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal report")
+		}
+
+		fmt.Println(string(data))
+
+		return nil
+	}
+
+	printReplayReport(report)
+
+	return nil
+}
+
+// replayKindLabels are the human-readable labels of change kinds.
+var replayKindLabels = map[replay.ChangeKind]string{
+	replay.ChangeBlocked: "NEWLY BLOCKED",
+	replay.ChangeAllowed: "NEWLY ALLOWED",
+	replay.ChangeOther:   "CHANGED",
+}
+
+// printReplayReport prints the report in human-readable form.
+func printReplayReport(report *replay.Report) {
+	for _, change := range report.Changes {
+		fmt.Printf("%s  %s\n", replayKindLabels[change.Kind], change.Call.Source)
+		fmt.Printf("  %s\n", change.Summary)
+		fmt.Printf("  before: %s\n", formatReplayOutcome(change.Before))
+		fmt.Printf("  after:  %s\n", formatReplayOutcome(change.After))
+		fmt.Println("")
+	}
+
+	fmt.Printf("Replayed %d tool calls: %d changed (%d newly blocked, %d newly allowed, %d other)\n",
+		report.Total,
+		len(report.Changes),
+		report.Count(replay.ChangeBlocked),
+		report.Count(replay.ChangeAllowed),
+		report.Count(replay.ChangeOther),
+	)
+}
+
+// formatReplayOutcome formats an outcome as its decision and errors.
+func formatReplayOutcome(outcome *replay.Outcome) string {
+	if len(outcome.Errors) == 0 {
+		return outcome.Decision
+	}
+
+	return fmt.Sprintf("%s (%s)", outcome.Decision, strings.Join(outcome.Errors, ", "))
+}
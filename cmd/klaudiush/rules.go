@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/smykla-labs/klaudiush/internal/config/factory"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// Rules stats defaults.
+const (
+	// defaultDeadRuleDays is the default number of days without a match
+	// after which a rule is reported as dead.
+	defaultDeadRuleDays = 90
+
+	// defaultHotRuleCount is the default number of hot rules listed.
+	defaultHotRuleCount = 10
+
+	// hoursPerDay converts --days to a duration.
+	hoursPerDay = 24
+
+	// statsTimeFormat is the format of times in the stats report.
+	statsTimeFormat = "2006-01-02 15:04"
+)
+
+// Rules command flags.
+var (
+	rulesStatsDays int
+	rulesStatsTop  int
+	rulesStatsJSON bool
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect validation rule usage",
+	Long: `Inspect validation rule usage.
+
+Subcommands:
+  stats  Show rule hit statistics`,
+}
+
+var rulesStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show rule hit statistics",
+	Long: `Show which configured rules match, which never do, and which are shadowed.
+
+Rule matches are recorded to ~/.klaudiush/rule_stats.json (see [rules.stats]).
+Lists the rules with the most hits, dead rules that did not match within
+--days, and rules that matched but were shadowed by a higher-priority rule
+under stop_on_first_match.
+
+Examples:
+  klaudiush rules stats
+  klaudiush rules stats --days 30 --top 5
+  klaudiush rules stats --json`,
+	Args: cobra.NoArgs,
+	RunE: runRulesStats,
+}
+
+func init() {
+	rootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesStatsCmd)
+
+	rulesStatsCmd.Flags().IntVar(
+		&rulesStatsDays,
+		"days",
+		defaultDeadRuleDays,
+		"Report rules without a match in this many days as dead",
+	)
+	rulesStatsCmd.Flags().IntVar(
+		&rulesStatsTop,
+		"top",
+		defaultHotRuleCount,
+		"Number of hot rules to list (0 = all)",
+	)
+	rulesStatsCmd.Flags().BoolVar(&rulesStatsJSON, "json", false, "Output the report as JSON")
+}
+
+func runRulesStats(_ *cobra.Command, _ []string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return errors.Wrap(err, "failed to get home directory")
+	}
+
+	logFile := filepath.Join(homeDir, ".claude", "hooks", "dispatcher.log")
+
+	log, err := logger.NewFileLogger(logFile, false, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to create logger")
+	}
+
+	log.Info("rules stats command invoked", "days", rulesStatsDays, "top", rulesStatsTop)
+
+	cfg, err := loadConfig(log)
+	if err != nil {
+		return errors.Wrap(err, "failed to load configuration")
+	}
+
+	engine, err := factory.NewRulesFactory(log).CreateRuleEngine(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to build rule engine")
+	}
+
+	if engine == nil {
+		fmt.Println("No enabled rules configured.")
+
+		return nil
+	}
+
+	stats := engine.Stats()
+	if stats == nil {
+		fmt.Println("Rule stats are disabled (rules.stats.enabled = false).")
+
+		return nil
+	}
+
+	if err := stats.Load(); err != nil {
+		return errors.Wrap(err, "failed to load rule stats")
+	}
+
+	report := rules.NewStatsReport(
+		engine.GetAllRules(),
+		stats.State(),
+		time.Now(),
+		time.Duration(rulesStatsDays)*hoursPerDay*time.Hour,
+	)
+
+	if rulesStatsTop > 0 && len(report.Hot) > rulesStatsTop {
+		report.Hot = report.Hot[:rulesStatsTop]
+	}
+
+	if rulesStatsJSON {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal report")
+		}
+
+		fmt.Println(string(data))
+
+		return nil
+	}
+
+	printRulesStatsReport(report, stats.StateFile(), engine.Size())
+
+	return nil
+}
+
+// printRulesStatsReport prints the report in human-readable form.
+func printRulesStatsReport(report *rules.StatsReport, statsFile string, ruleCount int) {
+	fmt.Println("Rule Statistics")
+	fmt.Println("===============")
+	fmt.Println("")
+	fmt.Printf("Stats File: %s\n", statsFile)
+	fmt.Printf("Recording Since: %s\n", report.Since.Local().Format(statsTimeFormat))
+	fmt.Printf("Enabled Rules: %d\n", ruleCount)
+	fmt.Println("")
+
+	printRulesStatsSection("Hot Rules", report.Hot, func(entry *rules.RuleStatsEntry) string {
+		return fmt.Sprintf("%d hits, last %s (%s)",
+			entry.Hits,
+			entry.LastHit.Local().Format(statsTimeFormat),
+			formatCounts(entry.Actions),
+		)
+	})
+
+	printRulesStatsSection(
+		fmt.Sprintf("Dead Rules (no match in %d days)", rulesStatsDays),
+		report.Dead,
+		func(entry *rules.RuleStatsEntry) string {
+			if entry.LastHit.IsZero() {
+				return "never matched"
+			}
+
+			return "last matched " + entry.LastHit.Local().Format(statsTimeFormat)
+		},
+	)
+
+	printRulesStatsSection("Shadowed Rules", report.Shadowed, func(entry *rules.RuleStatsEntry) string {
+		return fmt.Sprintf("shadowed %d times by %s", entry.Shadowed(), formatCounts(entry.ShadowedBy))
+	})
+}
+
+// printRulesStatsSection prints a titled list of rules with a detail each.
+func printRulesStatsSection(
+	title string,
+	entries []*rules.RuleStatsEntry,
+	detail func(entry *rules.RuleStatsEntry) string,
+) {
+	fmt.Println(title)
+	fmt.Println(strings.Repeat("-", len(title)))
+
+	if len(entries) == 0 {
+		fmt.Println("  (none)")
+	}
+
+	width := 0
+	for _, entry := range entries {
+		width = max(width, len(entry.Name))
+	}
+
+	for _, entry := range entries {
+		fmt.Printf("  %-*s  %s\n", width, entry.Name, detail(entry))
+	}
+
+	fmt.Println("")
+}
+
+// formatCounts formats counts by key as "a: 2, b: 1", sorted by key.
+func formatCounts[K ~string](counts map[K]int) string {
+	parts := make([]string, 0, len(counts))
+
+	for _, key := range slices.Sorted(maps.Keys(counts)) {
+		parts = append(parts, fmt.Sprintf("%s: %d", key, counts[key]))
+	}
+
+	return strings.Join(parts, ", ")
+}
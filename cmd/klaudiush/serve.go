@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/smykla-labs/klaudiush/internal/daemon"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// socketPath is the daemon socket path set by the --socket flag.
+var socketPath string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a persistent validation daemon",
+	Long: `Run a long-lived daemon that validates hook invocations over a Unix socket.
+
+The daemon keeps the loaded configuration, compiled rules, git repository
+cache and session state in memory, and reloads the configuration when a
+config file changes. Hook invocations forward their payload to the daemon
+and fall back to in-process validation when it is not running.
+
+The daemon uses the KLAUDIUSH_* environment it was started with. Hook
+invocations with a different KLAUDIUSH_* environment validate in-process.
+
+Examples:
+  klaudiush serve                              # Listen on ~/.klaudiush/daemon.sock
+  klaudiush serve --socket /tmp/klaudiush.sock # Listen on a custom socket`,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	rootCmd.PersistentFlags().StringVar(
+		&socketPath,
+		"socket",
+		"",
+		"Path to the daemon socket (default: ~/.klaudiush/daemon.sock)",
+	)
+}
+
+func runServe(_ *cobra.Command, _ []string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return errors.Wrap(err, "failed to get home directory")
+	}
+
+	logFile := filepath.Join(homeDir, ".claude", "hooks", "dispatcher.log")
+
+	log, err := logger.NewFileLogger(logFile, debugMode, traceMode)
+	if err != nil {
+		return errors.Wrap(err, "failed to create logger")
+	}
+
+	path := socketPath
+	if path == "" {
+		path = daemon.DefaultSocketPath(homeDir)
+	}
+
+	server, err := daemon.NewServer(path, daemon.WithServerLogger(log))
+	if err != nil {
+		return errors.Wrap(err, "failed to create daemon")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return server.ListenAndServe(ctx)
+}
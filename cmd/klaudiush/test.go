@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cockroachdb/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/smykla-labs/klaudiush/internal/policytest"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// Test command flags.
+var (
+	testJSON    bool
+	testVerbose bool
+)
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test <suite>...",
+	Short: "Run policy test suites against the configuration",
+	Long: `Run declarative policy test suites against the loaded configuration.
+
+A suite is a TOML or YAML file of cases. Each case is a tool call, the
+repository state it runs in and the expected decision, and optionally the
+validator and reference code expected to report it. Git state is stubbed, so
+suites give the same results on any machine. Exits with code 1 if any case
+fails.
+
+Example suite (TOML):
+
+  [[cases]]
+  name = "block push to origin in org repos"
+  bash = "git push origin main"
+
+  [cases.git]
+  repo_root = "/src/myorg/service"
+  branch = "main"
+
+  [cases.expect]
+  decision = "block"
+  validator = "validate-git-push"
+  reference = "ORG001"
+
+Examples:
+  klaudiush test policy.toml
+  klaudiush test tests/*.yaml --json`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPolicyTest,
+}
+
+func init() {
+	rootCmd.AddCommand(policyTestCmd)
+
+	policyTestCmd.Flags().BoolVar(&testJSON, "json", false, "Output the reports as JSON")
+	policyTestCmd.Flags().BoolVarP(&testVerbose, "verbose", "v", false, "Show passing cases")
+}
+
+func runPolicyTest(_ *cobra.Command, args []string) error {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return errors.Wrap(err, "failed to get home directory")
+	}
+
+	logFile := filepath.Join(homeDir, ".claude", "hooks", "dispatcher.log")
+
+	log, err := logger.NewFileLogger(logFile, false, false)
+	if err != nil {
+		return errors.Wrap(err, "failed to create logger")
+	}
+
+	log.Info("test command invoked", "suites", len(args))
+
+	suites := make([]*policytest.Suite, 0, len(args))
+
+	for _, path := range args {
+		suite, err := policytest.Load(path)
+		if err != nil {
+			return err
+		}
+
+		suites = append(suites, suite)
+	}
+
+	cfg, err := loadConfig(log)
+	if err != nil {
+		return errors.Wrap(err, "failed to load configuration")
+	}
+
+	runner := policytest.NewRunner(cfg, policytest.WithLogger(log))
+	reports := make([]*policytest.Report, 0, len(suites))
+	failed := 0
+
+	for _, suite := range suites {
+		report := runner.Run(context.Background(), suite)
+		reports = append(reports, report)
+		failed += report.Failed()
+	}
+
+	if testJSON {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal reports")
+		}
+
+		fmt.Println(string(data))
+	} else {
+		printPolicyTestReports(reports)
+	}
+
+	if failed > 0 {
+		os.Exit(1)
+	}
+
+	return nil
+}
+
+// printPolicyTestReports prints the reports in human-readable form.
+func printPolicyTestReports(reports []*policytest.Report) {
+	total, failed := 0, 0
+
+	for _, report := range reports {
+		fmt.Printf("%s\n", report.Path)
+
+		for _, result := range report.Results {
+			total++
+
+			if result.Passed {
+				if testVerbose {
+					fmt.Printf("  PASS %s\n", result.Name)
+				}
+
+				continue
+			}
+
+			failed++
+
+			fmt.Printf("  FAIL %s\n", result.Name)
+
+			if result.Error != "" {
+				fmt.Printf("    error: %s\n", result.Error)
+			}
+
+			for _, diff := range result.Diffs {
+				fmt.Printf("    %s:\n", diff.Field)
+				fmt.Printf("      - expected: %s\n", diff.Expected)
+				fmt.Printf("      + actual:   %s\n", diff.Actual)
+			}
+		}
+	}
+
+	fmt.Println("")
+	fmt.Printf("%d passed, %d failed\n", total-failed, failed)
+}
@@ -25,6 +25,7 @@ func mainFunc() {
 	configPath = ""
 	globalConfig = ""
 	disableList = []string{}
+	outputFormat = ""
 	globalFlag = false
 	forceFlag = false
 	noTUIFlag = false
@@ -32,6 +33,20 @@ func mainFunc() {
 	fixFlag = false
 	categoryFlag = []string{}
 	validatorFilter = ""
+	rulesLint = false
+	socketPath = ""
+	checkBash = ""
+	checkWrite = ""
+	checkContentFile = ""
+	checkPayload = ""
+	checkJSON = false
+	testJSON = false
+	testVerbose = false
+	replayConfig = ""
+	replayJSON = false
+	rulesStatsDays = defaultDeadRuleDays
+	rulesStatsTop = defaultHotRuleCount
+	rulesStatsJSON = false
 
 	// Reset git repository cache so each test discovers its own repo
 	gitpkg.ResetRepositoryCache()
@@ -93,3 +108,31 @@ func TestScriptDebug(t *testing.T) {
 		Setup: setupTestEnv,
 	})
 }
+
+func TestScriptTest(t *testing.T) {
+	testscript.Run(t, testscript.Params{
+		Dir:   "testdata/scripts/test",
+		Setup: setupTestEnv,
+	})
+}
+
+func TestScriptCheck(t *testing.T) {
+	testscript.Run(t, testscript.Params{
+		Dir:   "testdata/scripts/check",
+		Setup: setupTestEnv,
+	})
+}
+
+func TestScriptReplay(t *testing.T) {
+	testscript.Run(t, testscript.Params{
+		Dir:   "testdata/scripts/replay",
+		Setup: setupTestEnv,
+	})
+}
+
+func TestScriptRules(t *testing.T) {
+	testscript.Run(t, testscript.Params{
+		Dir:   "testdata/scripts/rules",
+		Setup: setupTestEnv,
+	})
+}
@@ -0,0 +1,41 @@
+package app
+
+import "github.com/smykla-labs/klaudiush/internal/dispatcher"
+
+// Decision is the overall outcome of validating a tool call.
+type Decision string
+
+// Decisions, from least to most restrictive.
+const (
+	DecisionAllow   Decision = "allow"
+	DecisionWarn    Decision = "warn"
+	DecisionRewrite Decision = "rewrite"
+	DecisionAsk     Decision = "ask"
+	DecisionBlock   Decision = "block"
+)
+
+// Decide summarizes validation errors as a single decision.
+func Decide(errs []*dispatcher.ValidationError) Decision {
+	switch {
+	case dispatcher.ShouldBlock(errs):
+		return DecisionBlock
+	case dispatcher.ShouldAsk(errs):
+		return DecisionAsk
+	case dispatcher.AppliedRewrite(errs) != nil:
+		return DecisionRewrite
+	case len(errs) > 0:
+		return DecisionWarn
+	default:
+		return DecisionAllow
+	}
+}
+
+// IsValid reports whether d is a known decision.
+func (d Decision) IsValid() bool {
+	switch d {
+	case DecisionAllow, DecisionWarn, DecisionRewrite, DecisionAsk, DecisionBlock:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,35 @@
+package app_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+)
+
+var _ = Describe("Decide", func() {
+	DescribeTable("should pick the most restrictive decision",
+		func(errs []*dispatcher.ValidationError, expected app.Decision) {
+			Expect(app.Decide(errs)).To(Equal(expected))
+		},
+		Entry("no errors", nil, app.DecisionAllow),
+		Entry("warning", []*dispatcher.ValidationError{{}}, app.DecisionWarn),
+		Entry("rewrite",
+			[]*dispatcher.ValidationError{{}, {UpdatedInput: map[string]any{"command": "ls"}}},
+			app.DecisionRewrite,
+		),
+		Entry("ask", []*dispatcher.ValidationError{{}, {ShouldAsk: true}}, app.DecisionAsk),
+		Entry("ask with rewrite",
+			[]*dispatcher.ValidationError{
+				{ShouldAsk: true},
+				{UpdatedInput: map[string]any{"command": "ls"}},
+			},
+			app.DecisionAsk,
+		),
+		Entry("block",
+			[]*dispatcher.ValidationError{{ShouldAsk: true}, {ShouldBlock: true}},
+			app.DecisionBlock,
+		),
+	)
+})
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"io"
+
+	"github.com/smykla-labs/klaudiush/internal/parser"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+// ParseHookInput parses a hook payload for the given --hook-type value. If the
+// value is empty or unknown, the event type is derived from hook_event_name in
+// the payload, defaulting to PreToolUse. Returns parser.ErrEmptyInput when the
+// payload is empty.
+func ParseHookInput(hookType string, input io.Reader) (*hook.Context, error) {
+	eventType, err := hook.EventTypeString(hookType)
+	if err != nil {
+		eventType = hook.EventTypeUnknown
+	}
+
+	ctx, err := parser.NewJSONParser(input).Parse(eventType)
+	if err != nil {
+		return nil, err
+	}
+
+	if ctx.EventType == hook.EventTypeUnknown {
+		ctx.EventType = hook.EventTypePreToolUse // Default to PreToolUse
+	}
+
+	return ctx, nil
+}
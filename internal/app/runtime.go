@@ -0,0 +1,316 @@
+// Package app composes the klaudiush hook runtime from configuration.
+package app
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/internal/config/factory"
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+	"github.com/smykla-labs/klaudiush/internal/exceptions"
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/session"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	gitvalidators "github.com/smykla-labs/klaudiush/internal/validators/git"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// Runtime holds a dispatcher together with the stateful subsystems it was built with.
+type Runtime struct {
+	dispatcher       *dispatcher.Dispatcher
+	registry         *validator.Registry
+	ruleEngine       *rules.RuleEngine
+	sessionTracker   *session.Tracker
+	exceptionHandler *exceptions.Handler
+	gitRunner        git.Runner
+	dryRun           bool
+	skipInvalidRules bool
+	log              logger.Logger
+}
+
+// Option configures how a Runtime is built.
+type Option func(*Runtime)
+
+// WithDryRun builds a runtime that never changes persisted state: session
+// tracking is disabled, exception use is neither audited nor recorded, and
+// Save is a no-op. Exception tokens are still evaluated.
+func WithDryRun() Option {
+	return func(r *Runtime) {
+		r.dryRun = true
+	}
+}
+
+// WithGitRunner builds the git validators with the given runner instead of
+// one running the git CLI, e.g. a git.FakeRunner holding stubbed repository
+// state.
+func WithGitRunner(runner git.Runner) Option {
+	return func(r *Runtime) {
+		r.gitRunner = runner
+	}
+}
+
+// WithSkipInvalidRules builds the rule engine without the rules that fail to
+// compile, logging them, instead of failing. Hook invocations use it so that a
+// broken rule does not disable validation altogether.
+func WithSkipInvalidRules() Option {
+	return func(r *Runtime) {
+		r.skipInvalidRules = true
+	}
+}
+
+// New builds a Runtime from the provided configuration. It creates the
+// validator registry and rule engine, selects the sequential or parallel
+// executor, and wires exception checking, session tracking and session audit
+// logging when they are enabled. The session audit log also records rule
+// rewrites, so it does not depend on session tracking. Persisted session
+// state, rate limit state and rule stats are loaded here and written back by
+// Save.
+func New(cfg *config.Config, log logger.Logger, opts ...Option) (*Runtime, error) {
+	r := &Runtime{log: log}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	builder := factory.NewRegistryBuilder(log)
+
+	// The cached runner is shared by all validators and reset before every
+	// dispatch, as a runtime may outlive changes to the repository (daemon)
+	if r.gitRunner == nil {
+		r.gitRunner = git.NewCachedRunner(gitvalidators.NewGitRunner())
+	}
+
+	builder.SetGitRunner(r.gitRunner)
+
+	builder.SetSkipInvalidRules(r.skipInvalidRules)
+
+	registry, ruleEngine, err := builder.BuildWithRuleEngine(cfg)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build validator registry")
+	}
+
+	r.registry = registry
+	r.ruleEngine = ruleEngine
+
+	r.exceptionHandler = newExceptionHandler(cfg.GetExceptions(), r.dryRun, log)
+
+	if !r.dryRun {
+		r.sessionTracker = newSessionTracker(cfg.GetSession(), log)
+	}
+
+	dispatcherOpts := []dispatcher.DispatcherOption{
+		dispatcher.WithMaxNestingDepth(cfg.GetGlobal().GetMaxShellNestingDepth()),
+	}
+
+	if r.exceptionHandler != nil {
+		dispatcherOpts = append(dispatcherOpts, dispatcher.WithExceptionChecker(
+			dispatcher.NewExceptionChecker(
+				r.exceptionHandler,
+				dispatcher.WithExceptionCheckerLogger(log),
+			),
+		))
+	}
+
+	if r.sessionTracker != nil {
+		dispatcherOpts = append(dispatcherOpts, dispatcher.WithSessionTracker(r.sessionTracker))
+	}
+
+	// Rule rewrites are audited even when session tracking is disabled
+	if !r.dryRun {
+		auditLogger := session.NewAuditLogger(
+			cfg.GetSession().GetAudit(),
+			session.WithAuditLoggerLogger(log),
+		)
+		if auditLogger.IsEnabled() {
+			dispatcherOpts = append(dispatcherOpts, dispatcher.WithSessionAuditLogger(auditLogger))
+		}
+	}
+
+	r.dispatcher = dispatcher.NewDispatcherWithOptions(
+		registry,
+		log,
+		NewExecutor(cfg.GetGlobal(), log),
+		dispatcherOpts...,
+	)
+
+	r.LoadState()
+
+	return r, nil
+}
+
+// Dispatch validates the hook context with the composed dispatcher.
+func (r *Runtime) Dispatch(ctx context.Context, hookCtx *hook.Context) []*dispatcher.ValidationError {
+	r.resetGitCache()
+
+	return r.dispatcher.Dispatch(ctx, hookCtx)
+}
+
+// Dispatcher returns the composed dispatcher.
+func (r *Runtime) Dispatcher() *dispatcher.Dispatcher {
+	return r.dispatcher
+}
+
+// MatchingValidators returns the validators whose predicates match the hook context.
+func (r *Runtime) MatchingValidators(hookCtx *hook.Context) []validator.Validator {
+	return r.registry.FindValidators(hookCtx)
+}
+
+// resetGitCache drops the repository state cached by the git runner during
+// the previous dispatch.
+func (r *Runtime) resetGitCache() {
+	if cached, ok := r.gitRunner.(*git.CachedRunner); ok {
+		cached.Reset()
+	}
+}
+
+// RuleEngine returns the rule engine, or nil if rules are disabled.
+func (r *Runtime) RuleEngine() *rules.RuleEngine {
+	return r.ruleEngine
+}
+
+// LoadState (re)loads persisted session state, exception rate limit state
+// and rule stats. Failures are logged and the affected subsystem starts fresh.
+func (r *Runtime) LoadState() {
+	if r.sessionTracker != nil {
+		if err := r.sessionTracker.Load(); err != nil {
+			r.log.Info("failed to load session state, starting fresh", "error", err)
+		}
+	}
+
+	if r.exceptionHandler != nil {
+		if err := r.exceptionHandler.LoadState(); err != nil {
+			r.log.Info("failed to load exception state, starting fresh", "error", err)
+		}
+	}
+
+	if stats := r.ruleStats(); stats != nil {
+		if err := stats.Load(); err != nil {
+			r.log.Info("failed to load rule stats, starting fresh", "error", err)
+		}
+	}
+}
+
+// Save persists session state, exception rate limit state and rule stats.
+func (r *Runtime) Save() error {
+	if r.dryRun {
+		return nil
+	}
+
+	var errs error
+
+	if r.sessionTracker != nil {
+		if err := r.sessionTracker.Save(); err != nil {
+			errs = errors.CombineErrors(errs, errors.Wrap(err, "failed to save session state"))
+		}
+	}
+
+	if r.exceptionHandler != nil {
+		if err := r.exceptionHandler.SaveState(); err != nil {
+			errs = errors.CombineErrors(
+				errs,
+				errors.Wrap(err, "failed to save exception rate limit state"),
+			)
+		}
+	}
+
+	if stats := r.ruleStats(); stats != nil {
+		if err := stats.Save(); err != nil {
+			errs = errors.CombineErrors(errs, errors.Wrap(err, "failed to save rule stats"))
+		}
+	}
+
+	return errs
+}
+
+// ruleStats returns the rule stats to persist, or nil in dry-run mode or if
+// matches are not recorded.
+func (r *Runtime) ruleStats() *rules.Stats {
+	if r.dryRun || r.ruleEngine == nil {
+		return nil
+	}
+
+	return r.ruleEngine.Stats()
+}
+
+// NewExecutor returns a ParallelExecutor when parallel execution is enabled,
+// otherwise a SequentialExecutor. Unset or non-positive worker limits fall
+// back to dispatcher.DefaultParallelConfig.
+func NewExecutor(global *config.GlobalConfig, log logger.Logger) dispatcher.Executor {
+	if !global.IsParallelExecutionEnabled() {
+		return dispatcher.NewSequentialExecutor(log)
+	}
+
+	parallelCfg := dispatcher.DefaultParallelConfig()
+
+	if n := global.MaxCPUWorkers; n != nil && *n > 0 {
+		parallelCfg.MaxCPUWorkers = *n
+	}
+
+	if n := global.MaxIOWorkers; n != nil && *n > 0 {
+		parallelCfg.MaxIOWorkers = *n
+	}
+
+	if n := global.MaxGitWorkers; n != nil && *n > 0 {
+		parallelCfg.MaxGitWorkers = *n
+	}
+
+	log.Info("parallel execution enabled",
+		"max_cpu_workers", parallelCfg.MaxCPUWorkers,
+		"max_io_workers", parallelCfg.MaxIOWorkers,
+		"max_git_workers", parallelCfg.MaxGitWorkers,
+	)
+
+	return dispatcher.NewParallelExecutor(log, parallelCfg)
+}
+
+// newSessionTracker creates a session tracker if enabled in the config.
+func newSessionTracker(sessionCfg *config.SessionConfig, log logger.Logger) *session.Tracker {
+	if !sessionCfg.IsEnabled() {
+		return nil
+	}
+
+	tracker := session.NewTracker(
+		sessionCfg,
+		session.WithLogger(log),
+	)
+
+	log.Debug("session tracker initialized",
+		"state_file", sessionCfg.GetStateFile(),
+		"max_session_age", sessionCfg.GetMaxSessionAge(),
+	)
+
+	return tracker
+}
+
+// newExceptionHandler creates an exception handler if exceptions are enabled
+// in the config. In dry-run mode exception use is not written to the audit log.
+func newExceptionHandler(
+	exceptionsCfg *config.ExceptionsConfig,
+	dryRun bool,
+	log logger.Logger,
+) *exceptions.Handler {
+	if !exceptionsCfg.IsEnabled() {
+		return nil
+	}
+
+	opts := []exceptions.HandlerOption{exceptions.WithHandlerLogger(log)}
+
+	if dryRun {
+		disabled := false
+		opts = append(opts, exceptions.WithAuditLogger(
+			exceptions.NewAuditLogger(&config.ExceptionAuditConfig{Enabled: &disabled}),
+		))
+	}
+
+	handler := exceptions.NewHandler(exceptionsCfg, opts...)
+
+	log.Debug("exception handler initialized",
+		"token_prefix", exceptionsCfg.GetTokenPrefix(),
+	)
+
+	return handler
+}
@@ -0,0 +1,294 @@
+package app_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+var _ = Describe("Runtime", func() {
+	var (
+		log     logger.Logger
+		tempDir string
+		cfg     *config.Config
+	)
+
+	BeforeEach(func() {
+		log = logger.NewNoOpLogger()
+		tempDir = GinkgoT().TempDir()
+		cfg = internalconfig.DefaultConfig()
+		cfg.Exceptions = &config.ExceptionsConfig{
+			RateLimit: &config.ExceptionRateLimitConfig{
+				StateFile: filepath.Join(tempDir, "exception_state.json"),
+			},
+			Audit: &config.ExceptionAuditConfig{
+				LogFile: filepath.Join(tempDir, "exception_audit.jsonl"),
+			},
+		}
+		cfg.Session = &config.SessionConfig{
+			StateFile: filepath.Join(tempDir, "session_state.json"),
+		}
+	})
+
+	Describe("New", func() {
+		It("should build a runtime without a rule engine when no rules are defined", func() {
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rt.Dispatcher()).NotTo(BeNil())
+			Expect(rt.RuleEngine()).To(BeNil())
+		})
+
+		It("should attach the rule engine when rules are defined", func() {
+			cfg.Rules = &config.RulesConfig{
+				Rules: []config.RuleConfig{
+					{
+						Name:   "block-main-push",
+						Match:  &config.RuleMatchConfig{BranchPattern: "main"},
+						Action: &config.RuleActionConfig{Type: "block"},
+					},
+				},
+			}
+
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rt.RuleEngine()).NotTo(BeNil())
+			Expect(rt.RuleEngine().Size()).To(Equal(1))
+		})
+
+		It("should fail on a rule that does not compile", func() {
+			cfg.Rules = &config.RulesConfig{
+				Rules: []config.RuleConfig{
+					{
+						Name:   "broken",
+						Match:  &config.RuleMatchConfig{CommandPattern: "^git (push"},
+						Action: &config.RuleActionConfig{Type: "block"},
+					},
+				},
+			}
+
+			_, err := app.New(cfg, log)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should keep built-in validators and valid rules when skipping invalid rules", func() {
+			cfg.Rules = &config.RulesConfig{
+				Rules: []config.RuleConfig{
+					{
+						Name:   "broken",
+						Match:  &config.RuleMatchConfig{CommandPattern: "^git (push"},
+						Action: &config.RuleActionConfig{Type: "block"},
+					},
+					{
+						Name:   "valid",
+						Match:  &config.RuleMatchConfig{CommandPattern: "^terraform apply"},
+						Action: &config.RuleActionConfig{Type: "block"},
+					},
+				},
+			}
+
+			rt, err := app.New(cfg, log, app.WithSkipInvalidRules())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rt.RuleEngine().Size()).To(Equal(1))
+
+			errs := rt.Dispatch(context.Background(), &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: "git commit -m wip"},
+			})
+			Expect(dispatcher.ShouldBlock(errs)).To(BeTrue())
+		})
+
+		It("should dispatch contexts without matching validators", func() {
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+
+			errs := rt.Dispatch(context.Background(), &hook.Context{
+				EventType: hook.EventTypeStop,
+			})
+			Expect(errs).To(BeEmpty())
+		})
+	})
+
+	Describe("Save", func() {
+		It("should persist exception rate limit state", func() {
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rt.Save()).To(Succeed())
+			Expect(filepath.Join(tempDir, "exception_state.json")).To(BeAnExistingFile())
+		})
+
+		It("should persist session state when session tracking is enabled", func() {
+			cfg.Session.Enabled = ptr(true)
+
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rt.Save()).To(Succeed())
+			Expect(filepath.Join(tempDir, "session_state.json")).To(BeAnExistingFile())
+		})
+
+		It("should persist the stats of matched rules", func() {
+			statsFile := filepath.Join(tempDir, "rule_stats.json")
+			cfg.Rules = &config.RulesConfig{
+				Stats: &config.RuleStatsConfig{StateFile: statsFile},
+				Rules: []config.RuleConfig{
+					{
+						Name:   "warn-read",
+						Match:  &config.RuleMatchConfig{ToolType: "Read"},
+						Action: &config.RuleActionConfig{Type: "warn"},
+					},
+				},
+			}
+
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+
+			rt.Dispatch(context.Background(), &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeRead,
+				ToolInput: hook.ToolInput{FilePath: "README.md"},
+			})
+
+			Expect(rt.Save()).To(Succeed())
+
+			data, err := os.ReadFile(statsFile)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring(`"warn-read"`))
+		})
+
+		It("should not persist exception state when exceptions are disabled", func() {
+			cfg.Exceptions.Enabled = ptr(false)
+
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rt.Save()).To(Succeed())
+			Expect(filepath.Join(tempDir, "exception_state.json")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("WithDryRun", func() {
+		It("should not persist any state", func() {
+			cfg.Session.Enabled = ptr(true)
+
+			rt, err := app.New(cfg, log, app.WithDryRun())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(rt.Save()).To(Succeed())
+			Expect(filepath.Join(tempDir, "exception_state.json")).NotTo(BeAnExistingFile())
+			Expect(filepath.Join(tempDir, "session_state.json")).NotTo(BeAnExistingFile())
+		})
+
+		It("should not persist rule stats", func() {
+			cfg.Rules = &config.RulesConfig{
+				Stats: &config.RuleStatsConfig{StateFile: filepath.Join(tempDir, "rule_stats.json")},
+				Rules: []config.RuleConfig{
+					{
+						Name:   "warn-read",
+						Match:  &config.RuleMatchConfig{ToolType: "Read"},
+						Action: &config.RuleActionConfig{Type: "warn"},
+					},
+				},
+			}
+
+			rt, err := app.New(cfg, log, app.WithDryRun())
+			Expect(err).NotTo(HaveOccurred())
+
+			rt.Dispatch(context.Background(), &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeRead,
+				ToolInput: hook.ToolInput{FilePath: "README.md"},
+			})
+
+			Expect(rt.Save()).To(Succeed())
+			Expect(filepath.Join(tempDir, "rule_stats.json")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Describe("MatchingValidators", func() {
+		It("should return the validators matching the hook context", func() {
+			rt, err := app.New(cfg, log)
+			Expect(err).NotTo(HaveOccurred())
+
+			validators := rt.MatchingValidators(&hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: "git commit -sS -m 'fix: typo'"},
+			})
+
+			names := make([]string, 0, len(validators))
+			for _, v := range validators {
+				names = append(names, v.Name())
+			}
+
+			Expect(names).To(ContainElement("validate-commit"))
+		})
+
+		It("should parse nested scripts up to the depth configured for each runtime", func() {
+			shallowCfg := internalconfig.DefaultConfig()
+			shallowCfg.Global = &config.GlobalConfig{MaxShellNestingDepth: ptr(0)}
+
+			shallow, err := app.New(shallowCfg, log, app.WithDryRun())
+			Expect(err).NotTo(HaveOccurred())
+
+			deep, err := app.New(cfg, log, app.WithDryRun())
+			Expect(err).NotTo(HaveOccurred())
+
+			hookCtx := &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: `bash -c "git commit -m 'fix: typo'"`},
+			}
+
+			names := func(validators []validator.Validator) []string {
+				result := make([]string, 0, len(validators))
+				for _, v := range validators {
+					result = append(result, v.Name())
+				}
+
+				return result
+			}
+
+			Expect(names(deep.MatchingValidators(hookCtx))).To(ContainElement("validate-commit"))
+			Expect(names(shallow.MatchingValidators(hookCtx))).NotTo(ContainElement("validate-commit"))
+			Expect(names(deep.MatchingValidators(hookCtx))).To(ContainElement("validate-commit"))
+		})
+	})
+
+	Describe("NewExecutor", func() {
+		It("should return a sequential executor by default", func() {
+			executor := app.NewExecutor(&config.GlobalConfig{}, log)
+			Expect(executor).To(BeAssignableToTypeOf(&dispatcher.SequentialExecutor{}))
+		})
+
+		It("should return a sequential executor for nil global config", func() {
+			executor := app.NewExecutor(nil, log)
+			Expect(executor).To(BeAssignableToTypeOf(&dispatcher.SequentialExecutor{}))
+		})
+
+		It("should return a parallel executor when parallel execution is enabled", func() {
+			executor := app.NewExecutor(&config.GlobalConfig{
+				ParallelExecution: ptr(true),
+				MaxCPUWorkers:     ptr(2),
+				MaxIOWorkers:      ptr(0),
+			}, log)
+			Expect(executor).To(BeAssignableToTypeOf(&dispatcher.ParallelExecutor{}))
+		})
+	})
+})
@@ -55,6 +55,7 @@ func DefaultValidatorsConfig() *config.ValidatorsConfig {
 		GitHub:       DefaultGitHubConfig(),
 		File:         DefaultFileConfig(),
 		Notification: DefaultNotificationConfig(),
+		Shell:        DefaultShellConfig(),
 	}
 }
 
@@ -108,6 +109,7 @@ func DefaultFileConfig() *config.FileConfig {
 		Workflow:    DefaultWorkflowValidatorConfig(),
 		Python:      DefaultPythonValidatorConfig(),
 		JavaScript:  DefaultJavaScriptValidatorConfig(),
+		Paths:       DefaultPathsValidatorConfig(),
 	}
 }
 
@@ -118,6 +120,13 @@ func DefaultNotificationConfig() *config.NotificationConfig {
 	}
 }
 
+// DefaultShellConfig returns the default shell validators configuration.
+func DefaultShellConfig() *config.ShellConfig {
+	return &config.ShellConfig{
+		Destructive: DefaultDestructiveValidatorConfig(),
+	}
+}
+
 // DefaultCommitValidatorConfig returns the default commit validator configuration.
 func DefaultCommitValidatorConfig() *config.CommitValidatorConfig {
 	enabled := true
@@ -423,6 +432,50 @@ func DefaultJavaScriptValidatorConfig() *config.JavaScriptValidatorConfig {
 	}
 }
 
+// DefaultPathsValidatorConfig returns the default protected paths validator configuration.
+func DefaultPathsValidatorConfig() *config.PathsValidatorConfig {
+	enabled := true
+
+	return &config.PathsValidatorConfig{
+		ValidatorConfig: config.ValidatorConfig{
+			Enabled:  &enabled,
+			Severity: config.SeverityError,
+		},
+		Deny: []config.PathRuleConfig{
+			{
+				Paths:      []string{"~/.ssh/**", "~/.aws/**"},
+				Suggestion: "Do not access credentials, ask the user to run commands that need them",
+			},
+			{
+				Paths:      []string{".env", ".env.*"},
+				Suggestion: "Environment files contain secrets, use .env.example to document variables",
+			},
+			{
+				Paths:      []string{"/etc/**"},
+				Access:     []string{config.PathAccessWrite},
+				Suggestion: "Do not change system configuration, change the project configuration instead",
+			},
+		},
+		Allow: []config.PathRuleConfig{
+			{Paths: []string{".env.example", ".env.sample", ".env.template"}},
+		},
+	}
+}
+
+// DefaultDestructiveValidatorConfig returns the default destructive command validator configuration.
+func DefaultDestructiveValidatorConfig() *config.DestructiveValidatorConfig {
+	enabled := true
+
+	return &config.DestructiveValidatorConfig{
+		ValidatorConfig: config.ValidatorConfig{
+			Enabled:  &enabled,
+			Severity: config.SeverityError,
+		},
+		DisabledPatterns: []string{},
+		AllowedPaths:     []string{},
+	}
+}
+
 // DefaultBellValidatorConfig returns the default bell validator configuration.
 func DefaultBellValidatorConfig() *config.BellValidatorConfig {
 	enabled := true
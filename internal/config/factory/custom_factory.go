@@ -0,0 +1,73 @@
+package factory
+
+import (
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/internal/validators/custom"
+	gitvalidators "github.com/smykla-labs/klaudiush/internal/validators/git"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// CustomValidatorFactory creates the rules validator, which applies rules to
+// every tool call.
+type CustomValidatorFactory struct {
+	log        logger.Logger
+	gitRunner  git.Runner
+	ruleEngine *rules.RuleEngine
+}
+
+// NewCustomValidatorFactory creates a new CustomValidatorFactory.
+func NewCustomValidatorFactory(log logger.Logger) *CustomValidatorFactory {
+	return &CustomValidatorFactory{log: log}
+}
+
+// SetRuleEngine sets the rule engine for the factory.
+func (f *CustomValidatorFactory) SetRuleEngine(engine *rules.RuleEngine) {
+	f.ruleEngine = engine
+}
+
+// SetGitRunner sets the git runner used to build the git context for rule matching.
+func (f *CustomValidatorFactory) SetGitRunner(runner git.Runner) {
+	f.gitRunner = runner
+}
+
+// CreateValidators creates the rules validator if the rule engine is set.
+// Unscoped rules are left to the built-in validators for the tool calls
+// they handle.
+func (f *CustomValidatorFactory) CreateValidators(
+	cfg *config.Config,
+	builtins []ValidatorWithPredicate,
+) []ValidatorWithPredicate {
+	if f.ruleEngine == nil {
+		return nil
+	}
+
+	predicates := make([]validator.Predicate, 0, len(builtins))
+	for _, vp := range builtins {
+		if !vp.ScopedRulesOnly {
+			predicates = append(predicates, vp.Predicate)
+		}
+	}
+
+	opts := []rules.AdapterOption{
+		rules.WithAdapterLogger(f.log),
+		rules.WithRuleFilter(custom.NewRuleFilter(validator.Or(predicates...))),
+	}
+
+	if f.gitRunner != nil {
+		opts = append(opts, rules.WithHookGitContextProvider(
+			gitvalidators.NewRuleGitContextProvider(f.gitRunner, parserOptions(cfg)...),
+		))
+	}
+
+	ruleAdapter := rules.NewRuleValidatorAdapter(f.ruleEngine, rules.ValidatorCustom, opts...)
+
+	return []ValidatorWithPredicate{
+		{
+			Validator: custom.NewRulesValidator(f.log, ruleAdapter),
+			Predicate: validator.Always(),
+		},
+	}
+}
@@ -2,16 +2,37 @@
 package factory
 
 import (
+	"github.com/smykla-labs/klaudiush/internal/git"
 	"github.com/smykla-labs/klaudiush/internal/rules"
 	"github.com/smykla-labs/klaudiush/internal/validator"
 	"github.com/smykla-labs/klaudiush/pkg/config"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
 )
 
 // ValidatorWithPredicate pairs a validator with its registration predicate.
 type ValidatorWithPredicate struct {
 	Validator validator.Validator
 	Predicate validator.Predicate
+
+	// ScopedRulesOnly marks validators that only evaluate the rules scoped
+	// to them, as they check tool calls other validators handle as well.
+	// Unscoped rules are left to the other validators, or the rules validator.
+	ScopedRulesOnly bool
+}
+
+// nestingDepthSetter is implemented by the validators that parse Bash
+// commands, i.e. those embedding validator.BaseValidator.
+type nestingDepthSetter interface {
+	SetMaxNestingDepth(depth int)
+}
+
+// parserOptions returns the options of the Bash parsers used by predicates
+// and rule git contexts, as configured by cfg.
+func parserOptions(cfg *config.Config) []parser.BashParserOption {
+	return []parser.BashParserOption{
+		parser.WithMaxNestingDepth(cfg.GetGlobal().GetMaxShellNestingDepth()),
+	}
 }
 
 // ValidatorFactory creates validators from configuration.
@@ -19,6 +40,10 @@ type ValidatorFactory interface {
 	// SetRuleEngine sets the rule engine for all factories.
 	SetRuleEngine(engine *rules.RuleEngine)
 
+	// SetGitRunner sets the git runner used by git validators, the
+	// destructive command validator and the protected paths validator.
+	SetGitRunner(runner git.Runner)
+
 	// CreateGitValidators creates all git validators from config.
 	CreateGitValidators(cfg *config.Config) []ValidatorWithPredicate
 
@@ -53,6 +78,7 @@ type DefaultValidatorFactory struct {
 	secretsFactory      *SecretsValidatorFactory
 	shellFactory        *ShellValidatorFactory
 	pluginFactory       *PluginValidatorFactory
+	customFactory       *CustomValidatorFactory
 }
 
 // NewValidatorFactory creates a new DefaultValidatorFactory.
@@ -65,6 +91,7 @@ func NewValidatorFactory(log logger.Logger) *DefaultValidatorFactory {
 		secretsFactory:      NewSecretsValidatorFactory(log),
 		shellFactory:        NewShellValidatorFactory(log),
 		pluginFactory:       NewPluginValidatorFactory(log),
+		customFactory:       NewCustomValidatorFactory(log),
 	}
 }
 
@@ -76,6 +103,13 @@ func (f *DefaultValidatorFactory) SetRuleEngine(engine *rules.RuleEngine) {
 	f.notificationFactory.SetRuleEngine(engine)
 	f.secretsFactory.SetRuleEngine(engine)
 	f.shellFactory.SetRuleEngine(engine)
+	f.customFactory.SetRuleEngine(engine)
+}
+
+// SetGitRunner sets the git runner used by git validators, the
+// destructive command validator and the protected paths validator.
+func (f *DefaultValidatorFactory) SetGitRunner(runner git.Runner) {
+	f.gitFactory.SetGitRunner(runner)
 }
 
 // CreateGitValidators creates all git validators from config.
@@ -90,10 +124,13 @@ func (f *DefaultValidatorFactory) CreateGitHubValidators(
 	return f.githubFactory.CreateValidators(cfg)
 }
 
-// CreateFileValidators creates all file validators from config.
+// CreateFileValidators creates all file validators from config. The
+// protected paths validator shares the git runner of git validators.
 func (f *DefaultValidatorFactory) CreateFileValidators(
 	cfg *config.Config,
 ) []ValidatorWithPredicate {
+	f.fileFactory.SetGitRunner(f.gitFactory.getGitRunner())
+
 	return f.fileFactory.CreateValidators(cfg)
 }
 
@@ -111,10 +148,13 @@ func (f *DefaultValidatorFactory) CreateSecretsValidators(
 	return f.secretsFactory.CreateValidators(cfg)
 }
 
-// CreateShellValidators creates all shell validators from config.
+// CreateShellValidators creates all shell validators from config. The
+// destructive command validator shares the git runner of git validators.
 func (f *DefaultValidatorFactory) CreateShellValidators(
 	cfg *config.Config,
 ) []ValidatorWithPredicate {
+	f.shellFactory.SetGitRunner(f.gitFactory.getGitRunner())
+
 	return f.shellFactory.CreateValidators(cfg)
 }
 
@@ -125,7 +165,10 @@ func (f *DefaultValidatorFactory) CreatePluginValidators(
 	return f.pluginFactory.CreateValidators(cfg)
 }
 
-// CreateAll creates all validators from config.
+// CreateAll creates all validators from config. The rules validator is
+// created last, as it needs to know which tool calls the built-in validators
+// handle. Validators parsing Bash commands get the configured shell nesting
+// depth.
 func (f *DefaultValidatorFactory) CreateAll(cfg *config.Config) []ValidatorWithPredicate {
 	var all []ValidatorWithPredicate
 
@@ -135,7 +178,19 @@ func (f *DefaultValidatorFactory) CreateAll(cfg *config.Config) []ValidatorWithP
 	all = append(all, f.CreateNotificationValidators(cfg)...)
 	all = append(all, f.CreateSecretsValidators(cfg)...)
 	all = append(all, f.CreateShellValidators(cfg)...)
+
+	f.customFactory.SetGitRunner(f.gitFactory.getGitRunner())
+
+	all = append(all, f.customFactory.CreateValidators(cfg, all)...)
 	all = append(all, f.CreatePluginValidators(cfg)...)
 
+	depth := cfg.GetGlobal().GetMaxShellNestingDepth()
+
+	for _, vp := range all {
+		if setter, ok := vp.Validator.(nestingDepthSetter); ok {
+			setter.SetMaxNestingDepth(depth)
+		}
+	}
+
 	return all
 }
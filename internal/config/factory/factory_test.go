@@ -1,12 +1,16 @@
 package factory_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/smykla-labs/klaudiush/internal/config/factory"
+	"github.com/smykla-labs/klaudiush/internal/git"
 	"github.com/smykla-labs/klaudiush/internal/rules"
 	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
 )
 
@@ -547,6 +551,79 @@ var _ = Describe("DefaultValidatorFactory", func() {
 			Expect(validators).To(BeEmpty())
 		})
 
+		It("should create destructive validator when enabled", func() {
+			cfg := &config.Config{
+				Validators: &config.ValidatorsConfig{
+					Shell: &config.ShellConfig{
+						Destructive: &config.DestructiveValidatorConfig{
+							ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(true)},
+						},
+					},
+				},
+			}
+
+			validators := validatorFactory.CreateShellValidators(cfg)
+			Expect(validators).To(HaveLen(1))
+			Expect(validators[0].Validator.Name()).To(Equal("validate-destructive"))
+		})
+
+		It("should evaluate rules with the git context in the destructive validator", func() {
+			cfg := &config.Config{
+				Rules: &config.RulesConfig{
+					Enabled: ptrBool(true),
+					Rules: []config.RuleConfig{
+						{
+							Name: "no-echo-on-main",
+							Match: &config.RuleMatchConfig{
+								BranchPattern:  "main",
+								CommandPattern: "echo*",
+							},
+							Action: &config.RuleActionConfig{Type: "block", Message: "not on main"},
+						},
+					},
+				},
+				Validators: &config.ValidatorsConfig{
+					Shell: &config.ShellConfig{
+						Destructive: &config.DestructiveValidatorConfig{
+							ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(true)},
+						},
+					},
+				},
+			}
+
+			engine, err := factory.NewRulesFactory(log).CreateRuleEngine(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			validatorFactory.SetRuleEngine(engine)
+			validatorFactory.SetGitRunner(git.NewFakeRunner())
+
+			validators := validatorFactory.CreateShellValidators(cfg)
+			Expect(validators).To(HaveLen(1))
+
+			result := validators[0].Validator.Validate(context.Background(), &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: "echo hi"},
+			})
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(result.Message).To(Equal("not on main"))
+		})
+
+		It("should not create destructive validator when disabled", func() {
+			cfg := &config.Config{
+				Validators: &config.ValidatorsConfig{
+					Shell: &config.ShellConfig{
+						Destructive: &config.DestructiveValidatorConfig{
+							ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(false)},
+						},
+					},
+				},
+			}
+
+			validators := validatorFactory.CreateShellValidators(cfg)
+			Expect(validators).To(BeEmpty())
+		})
+
 		It("should return empty when shell config is nil", func() {
 			cfg := &config.Config{
 				Validators: &config.ValidatorsConfig{
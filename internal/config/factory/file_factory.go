@@ -4,6 +4,7 @@ import (
 	"time"
 
 	execpkg "github.com/smykla-labs/klaudiush/internal/exec"
+	"github.com/smykla-labs/klaudiush/internal/git"
 	githubpkg "github.com/smykla-labs/klaudiush/internal/github"
 	"github.com/smykla-labs/klaudiush/internal/linters"
 	"github.com/smykla-labs/klaudiush/internal/rules"
@@ -23,6 +24,7 @@ const (
 type FileValidatorFactory struct {
 	log        logger.Logger
 	ruleEngine *rules.RuleEngine
+	gitRunner  git.Runner
 }
 
 // NewFileValidatorFactory creates a new FileValidatorFactory.
@@ -35,6 +37,11 @@ func (f *FileValidatorFactory) SetRuleEngine(engine *rules.RuleEngine) {
 	f.ruleEngine = engine
 }
 
+// SetGitRunner sets the git runner used by the protected paths validator.
+func (f *FileValidatorFactory) SetGitRunner(runner git.Runner) {
+	f.gitRunner = runner
+}
+
 // CreateValidators creates all file validators based on configuration.
 func (f *FileValidatorFactory) CreateValidators(cfg *config.Config) []ValidatorWithPredicate {
 	var validators []ValidatorWithPredicate
@@ -112,6 +119,10 @@ func (f *FileValidatorFactory) CreateValidators(cfg *config.Config) []ValidatorW
 		)
 	}
 
+	if cfg.Validators.File.Paths != nil && cfg.Validators.File.Paths.IsEnabled() {
+		validators = append(validators, f.createPathsValidator(cfg.Validators.File.Paths))
+	}
+
 	return validators
 }
 
@@ -131,7 +142,7 @@ func (f *FileValidatorFactory) createMarkdownValidator(
 	return ValidatorWithPredicate{
 		Validator: filevalidators.NewMarkdownValidator(cfg, linter, f.log, ruleAdapter),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
+			fileEventPredicate(&cfg.PostToolUseConfig),
 			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.FileExtensionIs(".md"),
 		),
@@ -155,7 +166,7 @@ func (f *FileValidatorFactory) createTerraformValidator(
 	return ValidatorWithPredicate{
 		Validator: filevalidators.NewTerraformValidator(formatter, linter, f.log, cfg, ruleAdapter),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
+			fileEventPredicate(&cfg.PostToolUseConfig),
 			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.FileExtensionIs(".tf"),
 		),
@@ -178,7 +189,7 @@ func (f *FileValidatorFactory) createShellScriptValidator(
 	return ValidatorWithPredicate{
 		Validator: filevalidators.NewShellScriptValidator(f.log, checker, cfg, ruleAdapter),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
+			fileEventPredicate(&cfg.PostToolUseConfig),
 			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.Or(
 				validator.FileExtensionIs(".sh"),
@@ -207,7 +218,7 @@ func (f *FileValidatorFactory) createWorkflowValidator(
 			linter, githubClient, f.log, cfg, ruleAdapter,
 		),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
+			fileEventPredicate(&cfg.PostToolUseConfig),
 			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.Or(
 				validator.FilePathContains(".github/workflows/"),
@@ -237,8 +248,8 @@ func (f *FileValidatorFactory) createGofumptValidator(
 	return ValidatorWithPredicate{
 		Validator: filevalidators.NewGofumptValidator(f.log, checker, cfg, ruleAdapter),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
-			validator.ToolTypeIn(hook.ToolTypeWrite),
+			fileEventPredicate(&cfg.PostToolUseConfig),
+			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.FileExtensionIs(".go"),
 		),
 	}
@@ -261,7 +272,7 @@ func (f *FileValidatorFactory) createPythonValidator(
 	return ValidatorWithPredicate{
 		Validator: filevalidators.NewPythonValidator(f.log, checker, cfg, ruleAdapter),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
+			fileEventPredicate(&cfg.PostToolUseConfig),
 			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.FileExtensionIs(".py"),
 		),
@@ -284,7 +295,7 @@ func (f *FileValidatorFactory) createJavaScriptValidator(
 	return ValidatorWithPredicate{
 		Validator: filevalidators.NewJavaScriptValidator(f.log, checker, cfg, ruleAdapter),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
+			fileEventPredicate(&cfg.PostToolUseConfig),
 			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.Or(
 				validator.FileExtensionIs(".js"),
@@ -313,9 +324,58 @@ func (f *FileValidatorFactory) createRustValidator(
 	return ValidatorWithPredicate{
 		Validator: filevalidators.NewRustValidator(f.log, checker, cfg, ruleAdapter),
 		Predicate: validator.And(
-			validator.EventTypeIs(hook.EventTypePreToolUse),
+			fileEventPredicate(&cfg.PostToolUseConfig),
 			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 			validator.FileExtensionIs(".rs"),
 		),
 	}
 }
+
+func (f *FileValidatorFactory) createPathsValidator(
+	cfg *config.PathsValidatorConfig,
+) ValidatorWithPredicate {
+	var ruleAdapter *rules.RuleValidatorAdapter
+	if f.ruleEngine != nil {
+		ruleAdapter = rules.NewRuleValidatorAdapter(
+			f.ruleEngine,
+			rules.ValidatorFilePaths,
+			rules.WithAdapterLogger(f.log),
+			rules.WithRuleFilter(pathsRuleFilter),
+		)
+	}
+
+	return ValidatorWithPredicate{
+		Validator: filevalidators.NewPathsValidator(f.log, f.gitRunner, cfg, ruleAdapter),
+		Predicate: validator.And(
+			validator.EventTypeIs(hook.EventTypePreToolUse),
+			validator.ToolTypeIn(
+				hook.ToolTypeBash,
+				hook.ToolTypeWrite,
+				hook.ToolTypeEdit,
+				hook.ToolTypeMultiEdit,
+				hook.ToolTypeRead,
+				hook.ToolTypeGrep,
+				hook.ToolTypeGlob,
+			),
+		),
+		ScopedRulesOnly: true,
+	}
+}
+
+// pathsRuleFilter restricts the protected paths validator to the rules
+// scoped to it.
+func pathsRuleFilter(*hook.Context) func(rule *rules.Rule) bool {
+	return func(rule *rules.Rule) bool {
+		return rule.Match != nil && rule.Match.ValidatorType == rules.ValidatorFilePaths
+	}
+}
+
+// fileEventPredicate matches PreToolUse events, and PostToolUse events if
+// whole-file validation after the tool has run is enabled.
+func fileEventPredicate(cfg *config.PostToolUseConfig) validator.Predicate {
+	if cfg.IsPostToolUseEnabled() {
+		return validator.EventTypeIn(hook.EventTypePreToolUse, hook.EventTypePostToolUse)
+	}
+
+	return validator.EventTypeIs(hook.EventTypePreToolUse)
+}
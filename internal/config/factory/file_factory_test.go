@@ -1,11 +1,15 @@
 package factory_test
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/smykla-labs/klaudiush/internal/config/factory"
+	"github.com/smykla-labs/klaudiush/internal/validator"
 	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
 )
 
@@ -103,6 +107,27 @@ var _ = Describe("FileValidatorFactory", func() {
 				Expect(len(validators)).To(BeNumerically(">=", 1))
 			})
 
+			It("should match Write, Edit and MultiEdit of Go files", func() {
+				cfg.Validators.File.Gofumpt = &config.GofumptValidatorConfig{
+					ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(true)},
+				}
+
+				validators := fileFactory.CreateValidators(cfg)
+				Expect(validators).To(HaveLen(1))
+
+				for _, tool := range []hook.ToolType{
+					hook.ToolTypeWrite,
+					hook.ToolTypeEdit,
+					hook.ToolTypeMultiEdit,
+				} {
+					Expect(validators[0].Predicate(&hook.Context{
+						EventType: hook.EventTypePreToolUse,
+						ToolName:  tool,
+						ToolInput: hook.ToolInput{FilePath: "main.go"},
+					})).To(BeTrue(), "tool %s", tool)
+				}
+			})
+
 			It("should not create gofumpt validator when disabled", func() {
 				cfg.Validators.File.Gofumpt = &config.GofumptValidatorConfig{
 					ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(false)},
@@ -120,6 +145,35 @@ var _ = Describe("FileValidatorFactory", func() {
 			})
 		})
 
+		Context("Paths validator", func() {
+			It("should create paths validator for tools reading and writing files", func() {
+				cfg.Validators.File.Paths = &config.PathsValidatorConfig{
+					ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(true)},
+				}
+
+				validators := fileFactory.CreateValidators(cfg)
+				Expect(validators).To(HaveLen(1))
+				Expect(validators[0].Validator.Name()).To(Equal("validate-paths"))
+				Expect(validators[0].Predicate(&hook.Context{
+					EventType: hook.EventTypePreToolUse,
+					ToolName:  hook.ToolTypeRead,
+				})).To(BeTrue())
+				Expect(validators[0].Predicate(&hook.Context{
+					EventType: hook.EventTypePreToolUse,
+					ToolName:  hook.ToolTypeBash,
+				})).To(BeTrue())
+			})
+
+			It("should not create paths validator when disabled", func() {
+				cfg.Validators.File.Paths = &config.PathsValidatorConfig{
+					ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(false)},
+				}
+
+				validators := fileFactory.CreateValidators(cfg)
+				Expect(validators).To(BeEmpty())
+			})
+		})
+
 		Context("Multiple file validators", func() {
 			It("should create multiple validators when enabled", func() {
 				enabled := true
@@ -138,6 +192,35 @@ var _ = Describe("FileValidatorFactory", func() {
 			})
 		})
 
+		Context("PostToolUse validation", func() {
+			postToolUseCtx := &hook.Context{
+				EventType: hook.EventTypePostToolUse,
+				ToolName:  hook.ToolTypeEdit,
+				ToolInput: hook.ToolInput{FilePath: "README.md"},
+			}
+
+			It("should only match PreToolUse by default", func() {
+				cfg.Validators.File.Markdown = &config.MarkdownValidatorConfig{
+					ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(true)},
+				}
+
+				validators := fileFactory.CreateValidators(cfg)
+				Expect(validators).To(HaveLen(1))
+				Expect(validators[0].Predicate(postToolUseCtx)).To(BeFalse())
+			})
+
+			It("should match PostToolUse when enabled", func() {
+				cfg.Validators.File.Markdown = &config.MarkdownValidatorConfig{
+					ValidatorConfig:   config.ValidatorConfig{Enabled: ptrBool(true)},
+					PostToolUseConfig: config.PostToolUseConfig{PostToolUse: ptrBool(true)},
+				}
+
+				validators := fileFactory.CreateValidators(cfg)
+				Expect(validators).To(HaveLen(1))
+				Expect(validators[0].Predicate(postToolUseCtx)).To(BeTrue())
+			})
+		})
+
 		Context("when rule engine is configured", func() {
 			It("should attach rule adapter to validators", func() {
 				enabled := true
@@ -169,6 +252,57 @@ var _ = Describe("FileValidatorFactory", func() {
 
 				Expect(len(validators)).To(BeNumerically(">=", 1))
 			})
+
+			It("should only evaluate rules scoped to the paths validator", func() {
+				enabled := true
+				rulesCfg := &config.Config{
+					Rules: &config.RulesConfig{
+						Enabled: &enabled,
+						Rules: []config.RuleConfig{
+							{
+								Name:   "unscoped",
+								Match:  &config.RuleMatchConfig{FilePattern: "*.txt"},
+								Action: &config.RuleActionConfig{Type: "block", Message: "unscoped"},
+							},
+							{
+								Name: "scoped",
+								Match: &config.RuleMatchConfig{
+									ValidatorType: "file.paths",
+									FilePattern:   "*.log",
+								},
+								Action: &config.RuleActionConfig{Type: "block", Message: "scoped"},
+							},
+						},
+					},
+					Validators: &config.ValidatorsConfig{
+						File: &config.FileConfig{
+							Paths: &config.PathsValidatorConfig{
+								ValidatorConfig: config.ValidatorConfig{Enabled: ptrBool(true)},
+							},
+						},
+					},
+				}
+
+				rulesFactory := factory.NewRulesFactory(log)
+				engine, err := rulesFactory.CreateRuleEngine(rulesCfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				fileFactory.SetRuleEngine(engine)
+				validators := fileFactory.CreateValidators(rulesCfg)
+				Expect(validators).To(HaveLen(1))
+				Expect(validators[0].ScopedRulesOnly).To(BeTrue())
+
+				validate := func(path string) *validator.Result {
+					return validators[0].Validator.Validate(context.Background(), &hook.Context{
+						EventType: hook.EventTypePreToolUse,
+						ToolName:  hook.ToolTypeWrite,
+						ToolInput: hook.ToolInput{FilePath: path},
+					})
+				}
+
+				Expect(validate("notes.txt").Passed).To(BeTrue())
+				Expect(validate("build.log").Message).To(Equal("scoped"))
+			})
 		})
 	})
 
@@ -37,6 +37,19 @@ func (f *GitValidatorFactory) getGitRunner() git.Runner {
 	return f.gitRunner
 }
 
+// SetGitRunner sets the git runner shared by the created validators and used
+// to build the git context for rule matching. By default a cached runner
+// wrapping the git CLI is used.
+func (f *GitValidatorFactory) SetGitRunner(runner git.Runner) {
+	f.gitRunner = runner
+}
+
+// predicates returns the git command predicates, parsing Bash commands with
+// the configured shell nesting depth.
+func (f *GitValidatorFactory) predicates() *validator.Predicates {
+	return validator.NewPredicates(parserOptions(f.cfg)...)
+}
+
 // SetRuleEngine sets the rule engine for the factory.
 func (f *GitValidatorFactory) SetRuleEngine(engine *rules.RuleEngine) {
 	f.ruleEngine = engine
@@ -92,6 +105,9 @@ func (f *GitValidatorFactory) createAddValidator(
 			f.ruleEngine,
 			rules.ValidatorGitAdd,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -99,7 +115,7 @@ func (f *GitValidatorFactory) createAddValidator(
 		Validator: gitvalidators.NewAddValidator(f.log, f.getGitRunner(), cfg, ruleAdapter),
 		Predicate: validator.And(
 			validator.EventTypeIs(hook.EventTypePreToolUse),
-			validator.GitSubcommandIs("add"),
+			f.predicates().GitSubcommandIs("add"),
 		),
 	}
 }
@@ -113,6 +129,9 @@ func (f *GitValidatorFactory) createNoVerifyValidator(
 			f.ruleEngine,
 			rules.ValidatorGitNoVerify,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -120,7 +139,7 @@ func (f *GitValidatorFactory) createNoVerifyValidator(
 		Validator: gitvalidators.NewNoVerifyValidator(f.log, cfg, ruleAdapter),
 		Predicate: validator.And(
 			validator.EventTypeIs(hook.EventTypePreToolUse),
-			validator.GitSubcommandIs("commit"),
+			f.predicates().GitSubcommandIs("commit"),
 		),
 	}
 }
@@ -134,6 +153,9 @@ func (f *GitValidatorFactory) createCommitValidator(
 			f.ruleEngine,
 			rules.ValidatorGitCommit,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -141,7 +163,7 @@ func (f *GitValidatorFactory) createCommitValidator(
 		Validator: gitvalidators.NewCommitValidator(f.log, f.getGitRunner(), cfg, ruleAdapter),
 		Predicate: validator.And(
 			validator.EventTypeIs(hook.EventTypePreToolUse),
-			validator.GitSubcommandIs("commit"),
+			f.predicates().GitSubcommandIs("commit"),
 		),
 	}
 }
@@ -155,6 +177,9 @@ func (f *GitValidatorFactory) createPushValidator(
 			f.ruleEngine,
 			rules.ValidatorGitPush,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -162,7 +187,7 @@ func (f *GitValidatorFactory) createPushValidator(
 		Validator: gitvalidators.NewPushValidator(f.log, f.getGitRunner(), cfg, ruleAdapter),
 		Predicate: validator.And(
 			validator.EventTypeIs(hook.EventTypePreToolUse),
-			validator.GitSubcommandIs("push"),
+			f.predicates().GitSubcommandIs("push"),
 		),
 	}
 }
@@ -176,6 +201,9 @@ func (f *GitValidatorFactory) createFetchValidator(
 			f.ruleEngine,
 			rules.ValidatorGitFetch,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -183,7 +211,7 @@ func (f *GitValidatorFactory) createFetchValidator(
 		Validator: gitvalidators.NewFetchValidator(f.log, f.getGitRunner(), cfg, ruleAdapter),
 		Predicate: validator.And(
 			validator.EventTypeIs(hook.EventTypePreToolUse),
-			validator.GitSubcommandIs("fetch"),
+			f.predicates().GitSubcommandIs("fetch"),
 		),
 	}
 }
@@ -197,6 +225,9 @@ func (f *GitValidatorFactory) createPRValidator(
 			f.ruleEngine,
 			rules.ValidatorGitPR,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -219,6 +250,9 @@ func (f *GitValidatorFactory) createBranchValidator(
 			f.ruleEngine,
 			rules.ValidatorGitBranch,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -228,9 +262,9 @@ func (f *GitValidatorFactory) createBranchValidator(
 			validator.EventTypeIs(hook.EventTypePreToolUse),
 			validator.Or(
 				// git checkout -b or --branch (create new branch)
-				validator.GitSubcommandWithAnyFlag("checkout", "-b", "--branch"),
+				f.predicates().GitSubcommandWithAnyFlag("checkout", "-b", "--branch"),
 				// git switch -c/--create/-C/--force-create (create new branch)
-				validator.GitSubcommandWithAnyFlag(
+				f.predicates().GitSubcommandWithAnyFlag(
 					"switch",
 					"-c",
 					"--create",
@@ -238,7 +272,7 @@ func (f *GitValidatorFactory) createBranchValidator(
 					"--force-create",
 				),
 				// git branch without delete flags (create new branch)
-				validator.GitSubcommandWithoutAnyFlag("branch", "-d", "-D", "--delete"),
+				f.predicates().GitSubcommandWithoutAnyFlag("branch", "-d", "-D", "--delete"),
 			),
 		),
 	}
@@ -253,6 +287,9 @@ func (f *GitValidatorFactory) createMergeValidator(
 			f.ruleEngine,
 			rules.ValidatorGitMerge,
 			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.getGitRunner(), parserOptions(f.cfg)...),
+			),
 		)
 	}
 
@@ -84,11 +84,15 @@ func (v *PluginRegistryValidator) Validate(
 
 	var hasBlockingResult bool
 
+	var askResult validator.Result
+
+	var hasAskResult bool
+
 	for _, p := range plugins {
 		result := p.Validate(ctx, hookCtx)
 
 		// Collect warnings
-		if !result.Passed && !result.ShouldBlock {
+		if !result.Passed && !result.ShouldBlock && !result.ShouldAsk {
 			warnings = append(warnings, result.Message)
 		}
 
@@ -97,6 +101,12 @@ func (v *PluginRegistryValidator) Validate(
 			blockingResult = *result
 			hasBlockingResult = true
 		}
+
+		// Keep first confirmation request
+		if result.ShouldAsk && !result.ShouldBlock && !hasAskResult {
+			askResult = *result
+			hasAskResult = true
+		}
 	}
 
 	// If any plugin blocked, return aggregated blocking result
@@ -110,6 +120,16 @@ func (v *PluginRegistryValidator) Validate(
 		return &blockingResult
 	}
 
+	// If any plugin asked for confirmation, return aggregated confirmation result
+	if hasAskResult {
+		if len(warnings) > 0 {
+			askResult.Message += "\n\nWarnings from other plugins:\n- " +
+				strings.Join(warnings, "\n- ")
+		}
+
+		return &askResult
+	}
+
 	// If only warnings, return warning result with all warnings
 	if len(warnings) > 0 {
 		return validator.Warn(strings.Join(warnings, "\n"))
@@ -1,6 +1,7 @@
 package factory
 
 import (
+	"github.com/smykla-labs/klaudiush/internal/git"
 	"github.com/smykla-labs/klaudiush/internal/rules"
 	"github.com/smykla-labs/klaudiush/internal/validator"
 	"github.com/smykla-labs/klaudiush/pkg/config"
@@ -23,6 +24,18 @@ func NewRegistryBuilder(log logger.Logger) *RegistryBuilder {
 	}
 }
 
+// SetGitRunner sets the git runner used by git validators, e.g. a
+// git.FakeRunner to validate against stubbed repository state.
+func (b *RegistryBuilder) SetGitRunner(runner git.Runner) {
+	b.factory.SetGitRunner(runner)
+}
+
+// SetSkipInvalidRules makes BuildWithRuleEngine log and skip rules that fail
+// to compile, so the remaining rules and the built-in validators still run.
+func (b *RegistryBuilder) SetSkipInvalidRules(skip bool) {
+	b.rulesFactory.SetSkipInvalidRules(skip)
+}
+
 // Build creates a validator registry from the provided configuration.
 // It creates all enabled validators and registers them with their predicates.
 func (b *RegistryBuilder) Build(cfg *config.Config) *validator.Registry {
@@ -9,7 +9,8 @@ import (
 
 // RulesFactory creates a RuleEngine from configuration.
 type RulesFactory struct {
-	log logger.Logger
+	log              logger.Logger
+	skipInvalidRules bool
 }
 
 // NewRulesFactory creates a new RulesFactory.
@@ -19,6 +20,12 @@ func NewRulesFactory(log logger.Logger) *RulesFactory {
 	}
 }
 
+// SetSkipInvalidRules makes CreateRuleEngine log and skip rules that fail to
+// compile instead of returning an error.
+func (f *RulesFactory) SetSkipInvalidRules(skip bool) {
+	f.skipInvalidRules = skip
+}
+
 // CreateRuleEngine creates a RuleEngine from the provided configuration.
 // Returns nil if rules are disabled or no rules are defined.
 //
@@ -42,16 +49,7 @@ func (f *RulesFactory) CreateRuleEngine(cfg *config.Config) (*rules.RuleEngine,
 	}
 
 	// Convert config rules to internal rules
-	internalRules := make([]*rules.Rule, 0, len(rulesConfig.Rules))
-
-	for _, ruleConfig := range rulesConfig.Rules {
-		if !ruleConfig.IsRuleEnabled() {
-			continue
-		}
-
-		internalRule := convertRuleConfig(ruleConfig)
-		internalRules = append(internalRules, internalRule)
-	}
+	internalRules := ConvertRuleConfigs(rulesConfig.Rules)
 
 	if len(internalRules) == 0 {
 		f.log.Debug("no enabled rules")
@@ -63,6 +61,22 @@ func (f *RulesFactory) CreateRuleEngine(cfg *config.Config) (*rules.RuleEngine,
 	opts := []rules.EngineOption{
 		rules.WithLogger(f.log),
 		rules.WithEngineStopOnFirstMatch(rulesConfig.ShouldStopOnFirstMatch()),
+		rules.WithEngineMaxNestingDepth(cfg.GetGlobal().GetMaxShellNestingDepth()),
+	}
+
+	if statsConfig := rulesConfig.GetStats(); statsConfig.IsEnabled() {
+		opts = append(opts, rules.WithEngineStats(
+			rules.NewStats(statsConfig.GetStateFile(), rules.WithStatsLogger(f.log)),
+		))
+	}
+
+	if f.skipInvalidRules {
+		opts = append(opts, rules.WithInvalidRuleHandler(func(rule *rules.Rule, err error) {
+			f.log.Error("skipping invalid rule",
+				"rule", rule.Name,
+				"error", err,
+			)
+		}))
 	}
 
 	engine, err := rules.NewRuleEngine(internalRules, opts...)
@@ -77,6 +91,22 @@ func (f *RulesFactory) CreateRuleEngine(cfg *config.Config) (*rules.RuleEngine,
 	return engine, nil
 }
 
+// ConvertRuleConfigs converts the enabled rules of a configuration to
+// rules.Rule values, e.g. for rules.Lint.
+func ConvertRuleConfigs(ruleConfigs []config.RuleConfig) []*rules.Rule {
+	internalRules := make([]*rules.Rule, 0, len(ruleConfigs))
+
+	for _, ruleConfig := range ruleConfigs {
+		if !ruleConfig.IsRuleEnabled() {
+			continue
+		}
+
+		internalRules = append(internalRules, convertRuleConfig(ruleConfig))
+	}
+
+	return internalRules
+}
+
 // convertRuleConfig converts a config.RuleConfig to a rules.Rule.
 func convertRuleConfig(cfg config.RuleConfig) *rules.Rule {
 	rule := &rules.Rule{
@@ -87,26 +117,7 @@ func convertRuleConfig(cfg config.RuleConfig) *rules.Rule {
 	}
 
 	// Convert match conditions
-	if cfg.Match != nil {
-		rule.Match = &rules.RuleMatch{
-			ValidatorType:   rules.ValidatorType(cfg.Match.ValidatorType),
-			RepoPattern:     cfg.Match.RepoPattern,
-			RepoPatterns:    cfg.Match.RepoPatterns,
-			Remote:          cfg.Match.Remote,
-			BranchPattern:   cfg.Match.BranchPattern,
-			BranchPatterns:  cfg.Match.BranchPatterns,
-			FilePattern:     cfg.Match.FilePattern,
-			FilePatterns:    cfg.Match.FilePatterns,
-			ContentPattern:  cfg.Match.ContentPattern,
-			ContentPatterns: cfg.Match.ContentPatterns,
-			CommandPattern:  cfg.Match.CommandPattern,
-			CommandPatterns: cfg.Match.CommandPatterns,
-			ToolType:        cfg.Match.ToolType,
-			EventType:       cfg.Match.EventType,
-			CaseInsensitive: cfg.Match.IsCaseInsensitive(),
-			PatternMode:     cfg.Match.GetPatternMode(),
-		}
-	}
+	rule.Match = convertRuleMatchConfig(cfg.Match)
 
 	// Convert action
 	if cfg.Action != nil {
@@ -114,12 +125,101 @@ func convertRuleConfig(cfg config.RuleConfig) *rules.Rule {
 			Type:      convertActionType(cfg.Action.GetActionType()),
 			Message:   cfg.Action.Message,
 			Reference: cfg.Action.Reference,
+			FixHint:   cfg.Action.FixHint,
+			Rewrite:   convertRuleRewriteConfig(cfg.Action.Rewrite),
 		}
 	}
 
 	return rule
 }
 
+// convertRuleRewriteConfig converts a config.RuleRewriteConfig to a
+// rules.RuleRewrite.
+func convertRuleRewriteConfig(cfg *config.RuleRewriteConfig) *rules.RuleRewrite {
+	if cfg == nil {
+		return nil
+	}
+
+	return &rules.RuleRewrite{
+		AddFlags:          cfg.AddFlags,
+		ReplacePrefix:     cfg.ReplacePrefix,
+		ReplacePrefixWith: cfg.ReplacePrefixWith,
+		InsertRemote:      cfg.InsertRemote,
+	}
+}
+
+// convertRuleMatchConfig converts a config.RuleMatchConfig, including its
+// nested match blocks, to a rules.RuleMatch.
+func convertRuleMatchConfig(cfg *config.RuleMatchConfig) *rules.RuleMatch {
+	if cfg == nil {
+		return nil
+	}
+
+	match := &rules.RuleMatch{
+		ValidatorType:   rules.ValidatorType(cfg.ValidatorType),
+		RepoPattern:     cfg.RepoPattern,
+		RepoPatterns:    cfg.RepoPatterns,
+		Remote:          cfg.Remote,
+		BranchPattern:   cfg.BranchPattern,
+		BranchPatterns:  cfg.BranchPatterns,
+		FilePattern:     cfg.FilePattern,
+		FilePatterns:    cfg.FilePatterns,
+		ContentPattern:  cfg.ContentPattern,
+		ContentPatterns: cfg.ContentPatterns,
+		CommandPattern:  cfg.CommandPattern,
+		CommandPatterns: cfg.CommandPatterns,
+		CommandName:     cfg.CommandName,
+		GitSubcommand:   cfg.GitSubcommand,
+		HasFlag:         cfg.HasFlag,
+		HasFlags:        cfg.HasFlags,
+		FlagMode:        cfg.GetFlagMode(),
+		ArgPattern:      cfg.ArgPattern,
+		InPipeline:      cfg.InPipeline,
+		DynamicArgs:     cfg.DynamicArgs,
+
+		StagedFilePattern:    cfg.StagedFilePattern,
+		ModifiedFilePattern:  cfg.ModifiedFilePattern,
+		UntrackedFilePattern: cfg.UntrackedFilePattern,
+		DirtyTree:            cfg.DirtyTree,
+		AheadOfUpstream:      cfg.AheadOfUpstream,
+		BehindUpstream:       cfg.BehindUpstream,
+		LastCommitAuthor:     cfg.LastCommitAuthor,
+
+		TimeWindow:      convertTimeWindowConfig(cfg.TimeWindow),
+		Env:             cfg.Env,
+		HostnamePattern: cfg.HostnamePattern,
+
+		ToolType:        cfg.ToolType,
+		EventType:       cfg.EventType,
+		CaseInsensitive: cfg.IsCaseInsensitive(),
+		PatternMode:     cfg.GetPatternMode(),
+		Not:             convertRuleMatchConfig(cfg.Not),
+	}
+
+	for _, nested := range cfg.Any {
+		match.Any = append(match.Any, convertRuleMatchConfig(nested))
+	}
+
+	for _, nested := range cfg.All {
+		match.All = append(match.All, convertRuleMatchConfig(nested))
+	}
+
+	return match
+}
+
+// convertTimeWindowConfig converts a config.TimeWindowConfig to a rules.TimeWindow.
+func convertTimeWindowConfig(cfg *config.TimeWindowConfig) *rules.TimeWindow {
+	if cfg == nil {
+		return nil
+	}
+
+	return &rules.TimeWindow{
+		Days:     cfg.Days,
+		Hours:    cfg.Hours,
+		Timezone: cfg.Timezone,
+	}
+}
+
 // convertActionType converts a string action type to rules.ActionType.
 func convertActionType(actionType string) rules.ActionType {
 	switch actionType {
@@ -127,8 +227,12 @@ func convertActionType(actionType string) rules.ActionType {
 		return rules.ActionBlock
 	case "warn":
 		return rules.ActionWarn
+	case "ask":
+		return rules.ActionAsk
 	case "allow":
 		return rules.ActionAllow
+	case "rewrite":
+		return rules.ActionRewrite
 	default:
 		return rules.ActionBlock
 	}
@@ -66,7 +66,7 @@ func (f *SecretsValidatorFactory) CreateValidators(cfg *config.Config) []Validat
 		Validator: secrets.NewSecretsValidator(f.log, detector, gitleaks, secretsCfg, ruleAdapter),
 		Predicate: validator.And(
 			validator.EventTypeIs(hook.EventTypePreToolUse),
-			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit),
+			validator.ToolTypeIn(hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit),
 		),
 	})
 
@@ -1,8 +1,10 @@
 package factory
 
 import (
+	"github.com/smykla-labs/klaudiush/internal/git"
 	"github.com/smykla-labs/klaudiush/internal/rules"
 	"github.com/smykla-labs/klaudiush/internal/validator"
+	gitvalidators "github.com/smykla-labs/klaudiush/internal/validators/git"
 	shellvalidators "github.com/smykla-labs/klaudiush/internal/validators/shell"
 	"github.com/smykla-labs/klaudiush/pkg/config"
 	"github.com/smykla-labs/klaudiush/pkg/hook"
@@ -14,6 +16,7 @@ type ShellValidatorFactory struct {
 	cfg        *config.Config
 	log        logger.Logger
 	ruleEngine *rules.RuleEngine
+	gitRunner  git.Runner
 }
 
 // NewShellValidatorFactory creates a new ShellValidatorFactory.
@@ -26,6 +29,11 @@ func (f *ShellValidatorFactory) SetRuleEngine(engine *rules.RuleEngine) {
 	f.ruleEngine = engine
 }
 
+// SetGitRunner sets the git runner used by the destructive command validator.
+func (f *ShellValidatorFactory) SetGitRunner(runner git.Runner) {
+	f.gitRunner = runner
+}
+
 // CreateValidators creates all shell validators based on configuration.
 func (f *ShellValidatorFactory) CreateValidators(cfg *config.Config) []ValidatorWithPredicate {
 	f.cfg = cfg // Store config for use in create methods
@@ -41,6 +49,10 @@ func (f *ShellValidatorFactory) CreateValidators(cfg *config.Config) []Validator
 		validators = append(validators, f.createBacktickValidator(cfg.Validators.Shell.Backtick))
 	}
 
+	if cfg.Validators.Shell.Destructive != nil && cfg.Validators.Shell.Destructive.IsEnabled() {
+		validators = append(validators, f.createDestructiveValidator(cfg.Validators.Shell.Destructive))
+	}
+
 	return validators
 }
 
@@ -72,3 +84,27 @@ func (f *ShellValidatorFactory) createBacktickValidator(
 		),
 	}
 }
+
+func (f *ShellValidatorFactory) createDestructiveValidator(
+	cfg *config.DestructiveValidatorConfig,
+) ValidatorWithPredicate {
+	var ruleAdapter *rules.RuleValidatorAdapter
+	if f.ruleEngine != nil {
+		ruleAdapter = rules.NewRuleValidatorAdapter(
+			f.ruleEngine,
+			rules.ValidatorShellDestructive,
+			rules.WithAdapterLogger(f.log),
+			rules.WithHookGitContextProvider(
+				gitvalidators.NewRuleGitContextProvider(f.gitRunner, parserOptions(f.cfg)...),
+			),
+		)
+	}
+
+	return ValidatorWithPredicate{
+		Validator: shellvalidators.NewDestructiveValidator(f.log, f.gitRunner, cfg, ruleAdapter),
+		Predicate: validator.And(
+			validator.EventTypeIs(hook.EventTypePreToolUse),
+			validator.ToolTypeIs(hook.ToolTypeBash),
+		),
+	}
+}
@@ -83,14 +83,20 @@ var defaultBranchValidTypes = []string{
 // Precedence order (highest to lowest):
 // 1. CLI Flags
 // 2. Environment Variables (KLAUDIUSH_*)
-// 3. Project Config (.klaudiush/config.toml or klaudiush.toml)
-// 4. Global Config (~/.klaudiush/config.toml)
-// 5. Defaults
+// 3. Overlay Config (optional, see SetOverlayPath)
+// 4. Project Config (.klaudiush/config.toml or klaudiush.toml)
+// 5. Global Config (~/.klaudiush/config.toml)
+// 6. Defaults
 type KoanfLoader struct {
-	k        *koanf.Koanf
-	homeDir  string
-	workDir  string
-	tomlOpts koanf.UnmarshalConf
+	k           *koanf.Koanf
+	homeDir     string
+	workDir     string
+	overlayPath string
+	tomlOpts    koanf.UnmarshalConf
+
+	// includePaths are the files the rules includes of the last load were
+	// looked up in.
+	includePaths []string
 }
 
 // NewKoanfLoader creates a new KoanfLoader with default directories.
@@ -123,6 +129,13 @@ func NewKoanfLoaderWithDirs(homeDir, workDir string) (*KoanfLoader, error) {
 	}, nil
 }
 
+// SetOverlayPath sets a config file loaded on top of the project config, e.g.
+// to evaluate a candidate config before rolling it out. Its rules are merged
+// like project rules: same names override, different names are combined.
+func (l *KoanfLoader) SetOverlayPath(path string) {
+	l.overlayPath = path
+}
+
 // Load loads configuration from all sources with precedence.
 // Defaults → Global TOML → Project TOML → Env Vars → CLI Flags
 //
@@ -149,6 +162,7 @@ func (l *KoanfLoader) Load(flags map[string]any) (*config.Config, error) {
 func (l *KoanfLoader) LoadWithoutValidation(flags map[string]any) (*config.Config, error) {
 	// Reset koanf instance for fresh load
 	l.k = koanf.New(".")
+	l.includePaths = nil
 
 	// Track rules from each source for proper merging
 	var globalRules []config.RuleConfig
@@ -163,20 +177,46 @@ func (l *KoanfLoader) LoadWithoutValidation(flags map[string]any) (*config.Confi
 
 	// 2. Global config: ~/.klaudiush/config.toml
 	globalPath := l.GlobalConfigPath()
-	if err := l.loadTOMLFile(globalPath); err != nil && !os.IsNotExist(err) {
+	if globalK, err := l.loadTOMLFile(globalPath); err != nil && !os.IsNotExist(err) {
 		return nil, errors.Wrap(err, "failed to load global config")
 	} else if err == nil {
-		globalRules = l.extractRules()
+		globalRules, err = l.fileRules(globalK, globalPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load global config")
+		}
 	}
 
 	// 3. Project config: .klaudiush/config.toml or klaudiush.toml
 	projectPath := l.findProjectConfig()
 	if projectPath != "" {
-		if err := l.loadTOMLFile(projectPath); err != nil {
+		projectK, err := l.loadTOMLFile(projectPath)
+		if err != nil {
 			return nil, errors.Wrap(err, "failed to load project config")
 		}
 
-		projectRules = l.extractRules()
+		rules, err := l.fileRules(projectK, projectPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load project config")
+		}
+
+		projectRules = rules
+	}
+
+	var overlayRules []config.RuleConfig
+
+	// 3b. Overlay config
+	if l.overlayPath != "" {
+		overlayK, err := l.loadTOMLFile(l.overlayPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load overlay config")
+		}
+
+		rules, err := l.fileRules(overlayK, l.overlayPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load overlay config")
+		}
+
+		overlayRules = rules
 	}
 
 	// 4. Environment variables: KLAUDIUSH_*
@@ -204,7 +244,7 @@ func (l *KoanfLoader) LoadWithoutValidation(flags map[string]any) (*config.Confi
 	}
 
 	// Merge rules: project overrides global by name, different names are combined
-	mergedRules := mergeRules(globalRules, projectRules)
+	mergedRules := mergeRules(mergeRules(globalRules, projectRules), overlayRules)
 
 	if cfg.Rules == nil {
 		cfg.Rules = &config.RulesConfig{}
@@ -215,9 +255,25 @@ func (l *KoanfLoader) LoadWithoutValidation(flags map[string]any) (*config.Confi
 	return &cfg, nil
 }
 
-// extractRules extracts rules from the current koanf state.
-func (l *KoanfLoader) extractRules() []config.RuleConfig {
-	rulesSlice := l.k.Slices("rules.rules")
+// fileRules returns the rules of a config file, merged over the rules it
+// includes, so that the file's own rules override included rules by name.
+func (l *KoanfLoader) fileRules(fileK *koanf.Koanf, path string) ([]config.RuleConfig, error) {
+	rules, err := l.extractRules(fileK)
+	if err != nil {
+		return nil, err
+	}
+
+	included, err := l.includedRules(fileK.Strings("rules.include"), filepath.Dir(path))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeRules(included, rules), nil
+}
+
+// extractRules extracts rules from a koanf state.
+func (l *KoanfLoader) extractRules(k *koanf.Koanf) ([]config.RuleConfig, error) {
+	rulesSlice := k.Slices("rules.rules")
 	rules := make([]config.RuleConfig, 0, len(rulesSlice))
 
 	for _, ruleK := range rulesSlice {
@@ -233,18 +289,11 @@ func (l *KoanfLoader) extractRules() []config.RuleConfig {
 			rule.Enabled = &enabled
 		}
 
-		// Extract match conditions
+		// Extract match conditions, including nested any/all/not blocks
 		if ruleK.Exists("match") {
-			rule.Match = &config.RuleMatchConfig{
-				ValidatorType:  ruleK.String("match.validator_type"),
-				RepoPattern:    ruleK.String("match.repo_pattern"),
-				Remote:         ruleK.String("match.remote"),
-				BranchPattern:  ruleK.String("match.branch_pattern"),
-				FilePattern:    ruleK.String("match.file_pattern"),
-				ContentPattern: ruleK.String("match.content_pattern"),
-				CommandPattern: ruleK.String("match.command_pattern"),
-				ToolType:       ruleK.String("match.tool_type"),
-				EventType:      ruleK.String("match.event_type"),
+			rule.Match = &config.RuleMatchConfig{}
+			if err := ruleK.UnmarshalWithConf("match", rule.Match, l.tomlOpts); err != nil {
+				return nil, errors.Wrapf(err, "failed to unmarshal match of rule %q", rule.Name)
 			}
 		}
 
@@ -254,13 +303,21 @@ func (l *KoanfLoader) extractRules() []config.RuleConfig {
 				Type:      ruleK.String("action.type"),
 				Message:   ruleK.String("action.message"),
 				Reference: ruleK.String("action.reference"),
+				FixHint:   ruleK.String("action.fix_hint"),
+			}
+
+			if ruleK.Exists("action.rewrite") {
+				rule.Action.Rewrite = &config.RuleRewriteConfig{}
+				if err := ruleK.UnmarshalWithConf("action.rewrite", rule.Action.Rewrite, l.tomlOpts); err != nil {
+					return nil, errors.Wrapf(err, "failed to unmarshal rewrite of rule %q", rule.Name)
+				}
 			}
 		}
 
 		rules = append(rules, rule)
 	}
 
-	return rules
+	return rules, nil
 }
 
 // mergeRules merges global and project rules.
@@ -318,8 +375,28 @@ func mergeRules(globalRules, projectRules []config.RuleConfig) []config.RuleConf
 	return merged
 }
 
-// loadTOMLFile loads a TOML configuration file with security checks.
-func (l *KoanfLoader) loadTOMLFile(path string) error {
+// loadTOMLFile loads a TOML configuration file with security checks and
+// returns the configuration of the file alone.
+func (l *KoanfLoader) loadTOMLFile(path string) (*koanf.Koanf, error) {
+	if err := checkFilePermissions(path); err != nil {
+		return nil, err
+	}
+
+	fileK := koanf.New(".")
+	if err := fileK.Load(file.Provider(path), tomlparser.Parser()); err != nil {
+		return nil, err
+	}
+
+	if err := l.k.Merge(fileK); err != nil {
+		return nil, err
+	}
+
+	return fileK, nil
+}
+
+// checkFilePermissions returns an error if the file does not exist or is
+// world-writable.
+func checkFilePermissions(path string) error {
 	// Check if file exists
 	info, err := os.Stat(path)
 	if err != nil {
@@ -336,7 +413,7 @@ func (l *KoanfLoader) loadTOMLFile(path string) error {
 		)
 	}
 
-	return l.k.Load(file.Provider(path), tomlparser.Parser())
+	return nil
 }
 
 // envTransform transforms environment variable names to config paths.
@@ -446,6 +523,12 @@ func (*KoanfLoader) flagsToConfig(flags map[string]any) map[string]any {
 				globalMap := ensureMapKey(result, "global")
 				globalMap["default_timeout"] = strVal
 			}
+
+		case "output-format":
+			if strVal, ok := value.(string); ok {
+				globalMap := ensureMapKey(result, "global")
+				globalMap["output_format"] = strVal
+			}
 		}
 	}
 
@@ -553,6 +636,7 @@ func defaultGlobalMap() map[string]any {
 	return map[string]any{
 		"use_sdk_git":     true,
 		"default_timeout": defaultTimeoutStr,
+		"output_format":   config.OutputFormatText,
 	}
 }
 
@@ -561,6 +645,7 @@ func defaultValidatorsMap() map[string]any {
 		"git":          defaultGitValidatorsMap(),
 		"file":         defaultFileValidatorsMap(),
 		"notification": defaultNotificationValidatorsMap(),
+		"shell":        defaultShellValidatorsMap(),
 	}
 }
 
@@ -660,6 +745,7 @@ func defaultFileValidatorsMap() map[string]any {
 		"shellscript": defaultShellscriptMap(),
 		"terraform":   defaultTerraformMap(),
 		"workflow":    defaultWorkflowMap(),
+		"paths":       defaultPathsMap(),
 	}
 }
 
@@ -713,6 +799,33 @@ func defaultWorkflowMap() map[string]any {
 	}
 }
 
+func defaultPathsMap() map[string]any {
+	cfg := DefaultPathsValidatorConfig()
+
+	return map[string]any{
+		"enabled":  true,
+		"severity": "error",
+		"deny":     pathRulesToMaps(cfg.Deny),
+		"allow":    pathRulesToMaps(cfg.Allow),
+	}
+}
+
+// pathRulesToMaps converts path rules to maps for koanf loading.
+func pathRulesToMaps(rules []config.PathRuleConfig) []map[string]any {
+	result := make([]map[string]any, 0, len(rules))
+
+	for _, rule := range rules {
+		result = append(result, map[string]any{
+			"paths":           rule.Paths,
+			"outside_project": rule.OutsideProject,
+			"access":          rule.Access,
+			"suggestion":      rule.Suggestion,
+		})
+	}
+
+	return result
+}
+
 func defaultNotificationValidatorsMap() map[string]any {
 	return map[string]any{
 		"bell": map[string]any{
@@ -722,6 +835,17 @@ func defaultNotificationValidatorsMap() map[string]any {
 	}
 }
 
+func defaultShellValidatorsMap() map[string]any {
+	return map[string]any{
+		"destructive": map[string]any{
+			"enabled":           true,
+			"severity":          "error",
+			"disabled_patterns": []string{},
+			"allowed_paths":     []string{},
+		},
+	}
+}
+
 // fileExists checks if a file exists and is not a directory.
 func fileExists(path string) bool {
 	info, err := os.Stat(path)
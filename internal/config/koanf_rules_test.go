@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"os"
 	"path/filepath"
 	"testing"
@@ -335,5 +337,308 @@ type = "block"
 			Expect(cfg.Rules).NotTo(BeNil())
 			Expect(cfg.Rules.IsEnabled()).To(BeFalse())
 		})
+
+		It("should merge overlay rules over project rules", func() {
+			projectDir := filepath.Join(workDir, ProjectConfigDir)
+			Expect(os.MkdirAll(projectDir, 0o755)).To(Succeed())
+
+			projectConfig := `
+[[rules.rules]]
+name = "shared-rule"
+priority = 50
+[rules.rules.match]
+validator_type = "git.push"
+[rules.rules.action]
+type = "warn"
+`
+			err := os.WriteFile(
+				filepath.Join(projectDir, ProjectConfigFile),
+				[]byte(projectConfig),
+				0o600,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			overlayConfig := `
+[[rules.rules]]
+name = "shared-rule"
+priority = 50
+[rules.rules.match]
+validator_type = "git.push"
+[rules.rules.action]
+type = "block"
+
+[[rules.rules]]
+name = "overlay-rule"
+[rules.rules.match]
+validator_type = "git.commit"
+[rules.rules.action]
+type = "ask"
+`
+			overlayPath := filepath.Join(workDir, "candidate.toml")
+			Expect(os.WriteFile(overlayPath, []byte(overlayConfig), 0o600)).To(Succeed())
+
+			loader.SetOverlayPath(overlayPath)
+
+			cfg, err := loader.Load(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Rules.Rules).To(HaveLen(2))
+			Expect(cfg.Rules.Rules[0].Name).To(Equal("shared-rule"))
+			Expect(cfg.Rules.Rules[0].Action.Type).To(Equal("block"))
+			Expect(cfg.Rules.Rules[1].Name).To(Equal("overlay-rule"))
+		})
+
+		It("should load nested any, all and not match blocks", func() {
+			projectDir := filepath.Join(workDir, ProjectConfigDir)
+			Expect(os.MkdirAll(projectDir, 0o755)).To(Succeed())
+
+			projectConfig := `
+[[rules.rules]]
+name = "nested-rule"
+[rules.rules.match]
+validator_type = "git.push"
+branch_patterns = ["main", "master"]
+
+[[rules.rules.match.any]]
+branch_pattern = "main"
+
+[[rules.rules.match.any]]
+remote = "upstream"
+
+[rules.rules.match.not]
+file_pattern = "docs/**"
+
+[[rules.rules.match.not.all]]
+tool_type = "Write"
+`
+			err := os.WriteFile(
+				filepath.Join(projectDir, ProjectConfigFile),
+				[]byte(projectConfig),
+				0o600,
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg, err := loader.Load(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.Rules.Rules).To(HaveLen(1))
+
+			match := cfg.Rules.Rules[0].Match
+			Expect(match.ValidatorType).To(Equal("git.push"))
+			Expect(match.BranchPatterns).To(Equal([]string{"main", "master"}))
+			Expect(match.Any).To(HaveLen(2))
+			Expect(match.Any[0].BranchPattern).To(Equal("main"))
+			Expect(match.Any[1].Remote).To(Equal("upstream"))
+			Expect(match.Not).NotTo(BeNil())
+			Expect(match.Not.FilePattern).To(Equal("docs/**"))
+			Expect(match.Not.All).To(HaveLen(1))
+			Expect(match.Not.All[0].ToolType).To(Equal("Write"))
+		})
+
+		It("should fail when the overlay config is missing", func() {
+			loader.SetOverlayPath(filepath.Join(workDir, "missing.toml"))
+
+			_, err := loader.Load(nil)
+			Expect(err).To(MatchError(ContainSubstring("failed to load overlay config")))
+		})
+	})
+})
+
+var _ = Describe("KoanfLoader rule includes", func() {
+	const packRules = `
+[[rules.rules]]
+name = "no-force-push"
+[rules.rules.match]
+validator_type = "git.push"
+[rules.rules.action]
+type = "block"
+message = "From the pack"
+
+[[rules.rules]]
+name = "warn-main"
+[rules.rules.match]
+branch_pattern = "main"
+[rules.rules.action]
+type = "warn"
+`
+
+	var (
+		loader  *KoanfLoader
+		homeDir string
+		workDir string
+	)
+
+	writeFile := func(path, content string) {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	writeProjectConfig := func(content string) {
+		writeFile(filepath.Join(workDir, ProjectConfigDir, ProjectConfigFile), content)
+	}
+
+	ruleNames := func(cfg *config.Config) []string {
+		names := make([]string, 0, len(cfg.Rules.Rules))
+		for _, rule := range cfg.Rules.Rules {
+			names = append(names, rule.Name)
+		}
+
+		return names
+	}
+
+	BeforeEach(func() {
+		homeDir = GinkgoT().TempDir()
+		workDir = GinkgoT().TempDir()
+
+		var err error
+
+		loader, err = NewKoanfLoaderWithDirs(homeDir, workDir)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should namespace pack rules and record their source", func() {
+		writeFile(filepath.Join(workDir, ProjectConfigDir, PacksDir, "kong-org", "v2.toml"), packRules)
+		writeProjectConfig(`
+[rules]
+include = ["pack:kong-org@v2"]
+`)
+
+		cfg, err := loader.Load(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleNames(cfg)).To(Equal([]string{"kong-org/no-force-push", "kong-org/warn-main"}))
+		Expect(cfg.Rules.Rules[0].Source).To(Equal("pack:kong-org@v2"))
+		Expect(cfg.Rules.Rules[0].Action.Message).To(Equal("From the pack"))
 	})
+
+	It("should let config rules override pack rules by name", func() {
+		writeFile(filepath.Join(homeDir, GlobalConfigDir, PacksDir, "kong-org", "v2.toml"), packRules)
+		writeProjectConfig(`
+[rules]
+include = ["pack:kong-org@v2"]
+
+[[rules.rules]]
+name = "kong-org/warn-main"
+enabled = false
+`)
+
+		cfg, err := loader.Load(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleNames(cfg)).To(Equal([]string{"kong-org/no-force-push", "kong-org/warn-main"}))
+		Expect(cfg.Rules.Rules[1].IsRuleEnabled()).To(BeFalse())
+		Expect(cfg.Rules.Rules[1].Source).To(BeEmpty())
+	})
+
+	It("should resolve file includes relative to the including config", func() {
+		writeFile(filepath.Join(workDir, ProjectConfigDir, "rules", "security.toml"), packRules)
+		writeProjectConfig(`
+[rules]
+include = ["./rules/security.toml"]
+`)
+
+		cfg, err := loader.Load(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleNames(cfg)).To(Equal([]string{"no-force-push", "warn-main"}))
+		Expect(cfg.Rules.Rules[0].Source).To(Equal("./rules/security.toml"))
+	})
+
+	It("should report every path its includes were looked up in", func() {
+		writeFile(filepath.Join(homeDir, GlobalConfigDir, PacksDir, "kong-org", "v2.toml"), packRules)
+		writeFile(filepath.Join(workDir, ProjectConfigDir, "rules", "security.toml"), packRules)
+		writeProjectConfig(`
+[rules]
+include = ["pack:kong-org@v2", "./rules/security.toml"]
+`)
+
+		_, err := loader.Load(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loader.IncludePaths()).To(Equal([]string{
+			filepath.Join(workDir, ProjectConfigDir, PacksDir, "kong-org", "v2.toml"),
+			filepath.Join(homeDir, GlobalConfigDir, PacksDir, "kong-org", "v2.toml"),
+			filepath.Join(homeDir, GlobalConfigDir, PackCacheDir, "kong-org", "v2.toml"),
+			filepath.Join(workDir, ProjectConfigDir, "rules", "security.toml"),
+		}))
+	})
+
+	It("should keep global includes when the project config has its own", func() {
+		writeFile(filepath.Join(homeDir, GlobalConfigDir, "org.toml"), packRules)
+		writeFile(filepath.Join(homeDir, GlobalConfigDir, GlobalConfigFile), `
+[rules]
+include = ["org.toml"]
+`)
+		writeFile(filepath.Join(workDir, ProjectConfigDir, "team.toml"), `
+[[rules.rules]]
+name = "warn-main"
+[rules.rules.match]
+branch_pattern = "main"
+[rules.rules.action]
+type = "allow"
+`)
+		writeProjectConfig(`
+[rules]
+include = ["team.toml"]
+`)
+
+		cfg, err := loader.Load(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ruleNames(cfg)).To(Equal([]string{"no-force-push", "warn-main"}))
+		Expect(cfg.Rules.Rules[0].Source).To(Equal("org.toml"))
+		Expect(cfg.Rules.Rules[1].Source).To(Equal("team.toml"))
+		Expect(cfg.Rules.Rules[1].Action.Type).To(Equal("allow"))
+	})
+
+	Describe("pack cache", func() {
+		const checksum = "#sha256:3d1de7e5d3e1bd5a7c5a5da1a5f3e5e1b1b6c2f1b5f7b8f2e4a3c1d9e0f1a2b3"
+
+		BeforeEach(func() {
+			writeFile(filepath.Join(homeDir, GlobalConfigDir, PackCacheDir, "kong-org", "v2.toml"), packRules)
+		})
+
+		It("should load cached packs pinned with their checksum", func() {
+			sum := sha256.Sum256([]byte(packRules))
+			writeProjectConfig(`
+[rules]
+include = ["pack:kong-org@v2#sha256:` + hex.EncodeToString(sum[:]) + `"]
+`)
+
+			cfg, err := loader.Load(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ruleNames(cfg)).To(Equal([]string{"kong-org/no-force-push", "kong-org/warn-main"}))
+		})
+
+		It("should reject cached packs that are not pinned", func() {
+			writeProjectConfig(`
+[rules]
+include = ["pack:kong-org@v2"]
+`)
+
+			_, err := loader.Load(nil)
+			Expect(err).To(MatchError(ErrInvalidInclude))
+			Expect(err.Error()).To(ContainSubstring("must be pinned"))
+		})
+
+		It("should reject packs that do not match their checksum", func() {
+			writeProjectConfig(`
+[rules]
+include = ["pack:kong-org@v2` + checksum + `"]
+`)
+
+			_, err := loader.Load(nil)
+			Expect(err).To(MatchError(ErrChecksumMismatch))
+		})
+	})
+
+	DescribeTable("should reject invalid includes",
+		func(include string, expected error) {
+			writeProjectConfig(`
+[rules]
+include = ["` + include + `"]
+`)
+
+			_, err := loader.Load(nil)
+			Expect(err).To(MatchError(expected))
+			Expect(err.Error()).To(ContainSubstring("failed to load project config"))
+		},
+		Entry("missing pack", "pack:missing@v1", ErrPackNotFound),
+		Entry("missing version", "pack:kong-org", ErrInvalidInclude),
+		Entry("invalid checksum", "pack:kong-org@v2#sha256:abc", ErrInvalidInclude),
+		Entry("missing file", "rules/missing.toml", os.ErrNotExist),
+	)
 })
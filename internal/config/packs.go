@@ -0,0 +1,273 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	tomlparser "github.com/knadh/koanf/parsers/toml/v2"
+	"github.com/knadh/koanf/providers/confmap"
+	"github.com/knadh/koanf/v2"
+
+	"github.com/smykla-labs/klaudiush/pkg/config"
+)
+
+var (
+	// ErrInvalidInclude is returned when a rules include cannot be parsed.
+	ErrInvalidInclude = errors.New("invalid rules include")
+
+	// ErrPackNotFound is returned when a rule pack is in none of the pack
+	// directories or the pack cache.
+	ErrPackNotFound = errors.New("rule pack not found")
+
+	// ErrChecksumMismatch is returned when an included file does not match
+	// its pinned checksum.
+	ErrChecksumMismatch = errors.New("rules include checksum mismatch")
+)
+
+const (
+	// PacksDir is the directory, under the global and project config
+	// directories, holding local rule packs as <name>/<version>.toml.
+	PacksDir = "packs"
+
+	// PackCacheDir is the directory, under the global config directory,
+	// holding cached rule packs as <name>/<version>.toml. Cached packs must
+	// be pinned with a checksum.
+	PackCacheDir = "cache/packs"
+
+	// packIncludePrefix marks an include as a rule pack.
+	packIncludePrefix = "pack:"
+
+	// checksumSeparator separates an include from its pinned checksum.
+	checksumSeparator = "#sha256:"
+
+	// packNamespaceSeparator separates the pack name from rule names.
+	packNamespaceSeparator = "/"
+)
+
+var (
+	// packIdentPattern matches pack names and versions.
+	packIdentPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+	// checksumPattern matches a hex-encoded SHA-256 checksum.
+	checksumPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// ruleInclude is a parsed entry of rules.include, either a rule pack
+// ("pack:kong-org@v2") or a file path ("./rules/security.toml"), optionally
+// pinned with a checksum ("#sha256:<hex>").
+type ruleInclude struct {
+	// source is the include without its checksum.
+	source   string
+	path     string
+	pack     string
+	version  string
+	checksum string
+}
+
+// parseInclude parses an entry of rules.include.
+func parseInclude(spec string) (*ruleInclude, error) {
+	source, checksum, pinned := strings.Cut(strings.TrimSpace(spec), checksumSeparator)
+	if pinned && !checksumPattern.MatchString(checksum) {
+		return nil, errors.Wrapf(ErrInvalidInclude, "%q has an invalid sha256 checksum", spec)
+	}
+
+	inc := &ruleInclude{source: source, checksum: checksum}
+
+	ref, isPack := strings.CutPrefix(source, packIncludePrefix)
+	if !isPack {
+		if source == "" {
+			return nil, errors.Wrapf(ErrInvalidInclude, "%q has no path", spec)
+		}
+
+		inc.path = source
+
+		return inc, nil
+	}
+
+	name, version, _ := strings.Cut(ref, "@")
+	if !packIdentPattern.MatchString(name) || !packIdentPattern.MatchString(version) {
+		return nil, errors.Wrapf(
+			ErrInvalidInclude,
+			"%q must be pack:<name>@<version>",
+			spec,
+		)
+	}
+
+	inc.pack = name
+	inc.version = version
+
+	return inc, nil
+}
+
+// includedRules loads the rules of the includes of a config file, in order.
+// Rules of later includes override rules of earlier ones with the same name.
+func (l *KoanfLoader) includedRules(specs []string, baseDir string) ([]config.RuleConfig, error) {
+	var rules []config.RuleConfig
+
+	for _, spec := range specs {
+		included, err := l.loadInclude(spec, baseDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to include %q", spec)
+		}
+
+		rules = mergeRules(rules, included)
+	}
+
+	return rules, nil
+}
+
+// loadInclude loads the rules of an include. Relative file paths are
+// resolved against baseDir. Names of pack rules are prefixed with the pack
+// name, so that they can be overridden as "<pack>/<rule>".
+func (l *KoanfLoader) loadInclude(spec, baseDir string) ([]config.RuleConfig, error) {
+	inc, err := parseInclude(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	// Track every candidate, so that a pack added to a directory searched
+	// earlier is noticed too
+	l.includePaths = append(l.includePaths, l.includeCandidates(inc, baseDir)...)
+
+	path, err := l.includePath(inc, baseDir)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := readIncludeFile(path, inc.checksum)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := tomlparser.Parser().Unmarshal(data)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidTOML, "%s: %v", path, err)
+	}
+
+	k := koanf.New(".")
+	if err := k.Load(confmap.Provider(raw, ""), nil); err != nil {
+		return nil, errors.Wrapf(err, "failed to load %s", path)
+	}
+
+	if k.Exists("rules.include") {
+		return nil, errors.Wrapf(ErrInvalidInclude, "%s cannot include other files", path)
+	}
+
+	rules, err := l.extractRules(k)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rules {
+		rules[i].Source = inc.source
+
+		if inc.pack != "" && rules[i].Name != "" {
+			rules[i].Name = inc.pack + packNamespaceSeparator + rules[i].Name
+		}
+	}
+
+	return rules, nil
+}
+
+// includePath returns the path of the file of an include. Packs are looked
+// up in the project and global pack directories, then in the pack cache.
+func (l *KoanfLoader) includePath(inc *ruleInclude, baseDir string) (string, error) {
+	candidates := l.includeCandidates(inc, baseDir)
+	if inc.pack == "" {
+		return candidates[0], nil
+	}
+
+	cached := candidates[len(candidates)-1]
+
+	for _, path := range candidates[:len(candidates)-1] {
+		if fileExists(path) {
+			return path, nil
+		}
+	}
+
+	if !fileExists(cached) {
+		return "", errors.Wrapf(ErrPackNotFound, "%s@%s", inc.pack, inc.version)
+	}
+
+	if inc.checksum == "" {
+		return "", errors.Wrapf(
+			ErrInvalidInclude,
+			"cached pack %s@%s must be pinned with %s<checksum>",
+			inc.pack,
+			inc.version,
+			checksumSeparator,
+		)
+	}
+
+	return cached, nil
+}
+
+// includeCandidates returns the paths an include is looked up in, in order.
+func (l *KoanfLoader) includeCandidates(inc *ruleInclude, baseDir string) []string {
+	if inc.pack == "" {
+		if filepath.IsAbs(inc.path) {
+			return []string{inc.path}
+		}
+
+		return []string{filepath.Join(baseDir, inc.path)}
+	}
+
+	packFile := filepath.Join(inc.pack, inc.version+".toml")
+	paths := make([]string, 0, len(l.PackDirs())+1)
+
+	for _, dir := range l.PackDirs() {
+		paths = append(paths, filepath.Join(dir, packFile))
+	}
+
+	return append(paths, filepath.Join(l.homeDir, GlobalConfigDir, PackCacheDir, packFile))
+}
+
+// IncludePaths returns the files the rules includes of the last load were
+// looked up in, including pack locations that do not exist, so that callers
+// can detect changes to included rules.
+func (l *KoanfLoader) IncludePaths() []string {
+	return l.includePaths
+}
+
+// PackDirs returns the local rule pack directories, project first.
+func (l *KoanfLoader) PackDirs() []string {
+	return []string{
+		filepath.Join(l.workDir, ProjectConfigDir, PacksDir),
+		filepath.Join(l.homeDir, GlobalConfigDir, PacksDir),
+	}
+}
+
+// readIncludeFile reads an included file with security checks, verifying
+// its checksum if one is given.
+func readIncludeFile(path, checksum string) ([]byte, error) {
+	if err := checkFilePermissions(path); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if checksum == "" {
+		return data, nil
+	}
+
+	sum := sha256.Sum256(data)
+	if actual := hex.EncodeToString(sum[:]); actual != checksum {
+		return nil, errors.Wrapf(
+			ErrChecksumMismatch,
+			"%s has sha256 %s, expected %s",
+			path,
+			actual,
+			checksum,
+		)
+	}
+
+	return data, nil
+}
@@ -4,9 +4,13 @@ package config
 import (
 	"fmt"
 	"slices"
+	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/cockroachdb/errors"
 
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/templates"
 	"github.com/smykla-labs/klaudiush/pkg/config"
 	"github.com/smykla-labs/klaudiush/pkg/stringutil"
 )
@@ -87,8 +91,24 @@ func (v *Validator) Validate(cfg *config.Config) error {
 }
 
 // validateGlobalConfig validates global configuration.
-func (*Validator) validateGlobalConfig(*config.GlobalConfig) error {
-	// No specific validation needed for global config currently
+func (*Validator) validateGlobalConfig(cfg *config.GlobalConfig) error {
+	if cfg.OutputFormat != "" && !slices.Contains(config.ValidOutputFormats, cfg.OutputFormat) {
+		return errors.Wrapf(
+			ErrInvalidOption,
+			"output_format must be one of %v, got %q",
+			config.ValidOutputFormats,
+			cfg.OutputFormat,
+		)
+	}
+
+	if cfg.MaxShellNestingDepth != nil && *cfg.MaxShellNestingDepth < 0 {
+		return errors.Wrapf(
+			ErrInvalidOption,
+			"max_shell_nesting_depth must not be negative, got %d",
+			*cfg.MaxShellNestingDepth,
+		)
+	}
+
 	return nil
 }
 
@@ -114,6 +134,12 @@ func (v *Validator) validateValidatorsConfig(cfg *config.ValidatorsConfig) error
 		}
 	}
 
+	if cfg.Shell != nil {
+		if err := v.validateShellConfig(cfg.Shell); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
 	if len(validationErrors) > 0 {
 		return combineErrors(validationErrors)
 	}
@@ -217,6 +243,15 @@ func (v *Validator) validateFileConfig(cfg *config.FileConfig) error {
 		}
 	}
 
+	if cfg.Paths != nil {
+		if err := v.validatePathsConfig(cfg.Paths); err != nil {
+			validationErrors = append(
+				validationErrors,
+				errors.Wrap(err, "validators.file.paths"),
+			)
+		}
+	}
+
 	if len(validationErrors) > 0 {
 		return combineErrors(validationErrors)
 	}
@@ -224,6 +259,58 @@ func (v *Validator) validateFileConfig(cfg *config.FileConfig) error {
 	return nil
 }
 
+// validatePathsConfig validates protected paths validator configuration.
+func (v *Validator) validatePathsConfig(cfg *config.PathsValidatorConfig) error {
+	if err := v.validateBaseConfig(&cfg.ValidatorConfig); err != nil {
+		return err
+	}
+
+	for i := range cfg.Deny {
+		if err := v.validatePathRule(&cfg.Deny[i]); err != nil {
+			return errors.Wrapf(err, "deny[%d]", i)
+		}
+	}
+
+	for i := range cfg.Allow {
+		if err := v.validatePathRule(&cfg.Allow[i]); err != nil {
+			return errors.Wrapf(err, "allow[%d]", i)
+		}
+	}
+
+	return nil
+}
+
+// validatePathRule validates a deny or allow rule of the paths validator.
+func (*Validator) validatePathRule(rule *config.PathRuleConfig) error {
+	if len(rule.Paths) == 0 && !rule.OutsideProject {
+		return errors.Wrap(ErrInvalidOption, "paths must not be empty unless outside_project is set")
+	}
+
+	for _, pattern := range rule.Paths {
+		if pattern == "" || !doublestar.ValidatePattern(pattern) {
+			return errors.Wrapf(
+				ErrInvalidOption,
+				"paths contains invalid glob pattern %q",
+				pattern,
+			)
+		}
+	}
+
+	for _, access := range rule.Access {
+		if access != config.PathAccessRead && access != config.PathAccessWrite {
+			return errors.Wrapf(
+				ErrInvalidOption,
+				"access must contain %q or %q, got %q",
+				config.PathAccessRead,
+				config.PathAccessWrite,
+				access,
+			)
+		}
+	}
+
+	return nil
+}
+
 // validateNotificationConfig validates notification validators configuration.
 func (v *Validator) validateNotificationConfig(cfg *config.NotificationConfig) error {
 	if cfg.Bell != nil {
@@ -235,6 +322,55 @@ func (v *Validator) validateNotificationConfig(cfg *config.NotificationConfig) e
 	return nil
 }
 
+// validateShellConfig validates shell validators configuration.
+func (v *Validator) validateShellConfig(cfg *config.ShellConfig) error {
+	if cfg.Backtick != nil {
+		if err := v.validateBaseConfig(&cfg.Backtick.ValidatorConfig); err != nil {
+			return errors.Wrap(err, "validators.shell.backtick")
+		}
+	}
+
+	if cfg.Destructive != nil {
+		if err := v.validateDestructiveConfig(cfg.Destructive); err != nil {
+			return errors.Wrap(err, "validators.shell.destructive")
+		}
+	}
+
+	return nil
+}
+
+// validateDestructiveConfig validates destructive command validator configuration.
+func (v *Validator) validateDestructiveConfig(cfg *config.DestructiveValidatorConfig) error {
+	if err := v.validateBaseConfig(&cfg.ValidatorConfig); err != nil {
+		return err
+	}
+
+	for name, severity := range cfg.Severities {
+		if severity == config.SeverityUnknown || !severity.IsASeverity() {
+			return errors.Wrapf(
+				ErrInvalidSeverity,
+				"severities.%s must be %q or %q, got %q",
+				name,
+				config.SeverityError.String(),
+				config.SeverityWarning.String(),
+				severity.String(),
+			)
+		}
+	}
+
+	for _, pattern := range cfg.AllowedPaths {
+		if !doublestar.ValidatePattern(pattern) {
+			return errors.Wrapf(
+				ErrInvalidOption,
+				"allowed_paths contains invalid glob pattern %q",
+				pattern,
+			)
+		}
+	}
+
+	return nil
+}
+
 // validateCommitConfig validates commit validator configuration.
 func (v *Validator) validateCommitConfig(cfg *config.CommitValidatorConfig) error {
 	if err := v.validateBaseConfig(&cfg.ValidatorConfig); err != nil {
@@ -517,6 +653,10 @@ func (v *Validator) validateRule(rule *config.RuleConfig, ruleID string) error {
 		if err := v.validateRuleMatchFields(rule.Match, ruleID); err != nil {
 			validationErrors = append(validationErrors, err)
 		}
+
+		if err := v.validateNestedMatches(rule.Match, ruleID); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
 	}
 
 	// Validate action
@@ -524,6 +664,10 @@ func (v *Validator) validateRule(rule *config.RuleConfig, ruleID string) error {
 		validationErrors = append(validationErrors, err)
 	}
 
+	if err := v.validateRuleRewrite(rule, ruleID); err != nil {
+		validationErrors = append(validationErrors, err)
+	}
+
 	if len(validationErrors) > 0 {
 		return combineErrors(validationErrors)
 	}
@@ -569,6 +713,49 @@ func (*Validator) validateRuleMatchFields(match *config.RuleMatchConfig, ruleID
 		}
 	}
 
+	// Validate has_flag and has_flags
+	for _, flag := range append([]string{match.HasFlag}, match.HasFlags...) {
+		if flag != "" && !strings.HasPrefix(flag, "-") {
+			validationErrors = append(
+				validationErrors,
+				errors.Wrapf(ErrInvalidRule, "%s has invalid flag %q (must start with -)", ruleID, flag),
+			)
+		}
+	}
+
+	// Validate ahead_of_upstream and behind_upstream
+	if match.AheadOfUpstream < 0 || match.BehindUpstream < 0 {
+		validationErrors = append(
+			validationErrors,
+			errors.Wrapf(
+				ErrInvalidRule,
+				"%s has negative ahead_of_upstream or behind_upstream",
+				ruleID,
+			),
+		)
+	}
+
+	// Validate time_window
+	if tw := match.TimeWindow; tw != nil {
+		window := &rules.TimeWindow{Days: tw.Days, Hours: tw.Hours, Timezone: tw.Timezone}
+		if err := window.Validate(); err != nil {
+			validationErrors = append(
+				validationErrors,
+				errors.Wrapf(ErrInvalidRule, "%s has invalid time_window: %v", ruleID, err),
+			)
+		}
+	}
+
+	// Validate env variable names
+	for name := range match.Env {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			validationErrors = append(
+				validationErrors,
+				errors.Wrapf(ErrInvalidRule, "%s has invalid env variable name %q", ruleID, name),
+			)
+		}
+	}
+
 	// Validate tool_type if specified
 	if match.ToolType != "" {
 		if !stringutil.ContainsCaseInsensitive(config.ValidToolTypes, match.ToolType) {
@@ -592,6 +779,50 @@ func (*Validator) validateRuleMatchFields(match *config.RuleMatchConfig, ruleID
 	return nil
 }
 
+// validateNestedMatches validates the nested any, all and not blocks of a match
+// section, recursively. Each block is identified by its path, e.g.
+// rule["name"].any[1].not.
+func (v *Validator) validateNestedMatches(match *config.RuleMatchConfig, matchID string) error {
+	var validationErrors []error
+
+	validate := func(nested *config.RuleMatchConfig, nestedID string) {
+		if !nested.HasMatchConditions() {
+			validationErrors = append(
+				validationErrors,
+				errors.Wrapf(ErrEmptyMatchConditions, "%s is an empty match block", nestedID),
+			)
+
+			return
+		}
+
+		if err := v.validateRuleMatchFields(nested, nestedID); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+
+		if err := v.validateNestedMatches(nested, nestedID); err != nil {
+			validationErrors = append(validationErrors, err)
+		}
+	}
+
+	for i, nested := range match.Any {
+		validate(nested, fmt.Sprintf("%s.any[%d]", matchID, i))
+	}
+
+	for i, nested := range match.All {
+		validate(nested, fmt.Sprintf("%s.all[%d]", matchID, i))
+	}
+
+	if match.Not != nil {
+		validate(match.Not, matchID+".not")
+	}
+
+	if len(validationErrors) > 0 {
+		return combineErrors(validationErrors)
+	}
+
+	return nil
+}
+
 // validateRuleAction validates a rule's action configuration.
 func (*Validator) validateRuleAction(action *config.RuleActionConfig, ruleID string) error {
 	if action == nil {
@@ -599,17 +830,94 @@ func (*Validator) validateRuleAction(action *config.RuleActionConfig, ruleID str
 		return nil
 	}
 
+	var validationErrors []error
+
 	// Validate action type if specified
 	if action.Type != "" && !slices.Contains(config.ValidActionTypes, action.Type) {
-		return errors.Wrapf(
+		validationErrors = append(validationErrors, errors.Wrapf(
 			ErrInvalidRule,
 			"%s has invalid action type %q (valid: %v)",
 			ruleID,
 			action.Type,
 			config.ValidActionTypes,
+		))
+	}
+
+	// Validate message and fix_hint templates
+	for _, tmpl := range []struct{ field, text string }{
+		{"message", action.Message},
+		{"fix_hint", action.FixHint},
+	} {
+		if _, err := templates.Compile(tmpl.field, tmpl.text); err != nil {
+			validationErrors = append(validationErrors, errors.Wrapf(
+				ErrInvalidRule,
+				"%s has invalid %s template: %v",
+				ruleID,
+				tmpl.field,
+				err,
+			))
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return combineErrors(validationErrors)
+	}
+
+	return nil
+}
+
+// validateRuleRewrite validates the rewrite of a rule with the rewrite action.
+func (*Validator) validateRuleRewrite(rule *config.RuleConfig, ruleID string) error {
+	if rule.Action.GetActionType() != "rewrite" {
+		return nil
+	}
+
+	rewrite := rule.Action.Rewrite
+	if rewrite == nil ||
+		(len(rewrite.AddFlags) == 0 && rewrite.ReplacePrefix == "" && rewrite.InsertRemote == "") {
+		return errors.Wrapf(
+			ErrInvalidRule,
+			"%s has rewrite action without add_flags, replace_prefix or insert_remote",
+			ruleID,
 		)
 	}
 
+	var validationErrors []error
+
+	if len(rewrite.AddFlags) > 0 &&
+		(rule.Match == nil || (rule.Match.CommandName == "" && rule.Match.GitSubcommand == "")) {
+		validationErrors = append(validationErrors, errors.Wrapf(
+			ErrInvalidRule,
+			"%s has rewrite add_flags without a command_name or git_subcommand match condition",
+			ruleID,
+		))
+	}
+
+	fields := []struct{ field, text string }{
+		{"rewrite.replace_prefix_with", rewrite.ReplacePrefixWith},
+		{"rewrite.insert_remote", rewrite.InsertRemote},
+	}
+
+	for _, flag := range rewrite.AddFlags {
+		fields = append(fields, struct{ field, text string }{"rewrite.add_flags", flag})
+	}
+
+	for _, tmpl := range fields {
+		if _, err := templates.Compile(tmpl.field, tmpl.text); err != nil {
+			validationErrors = append(validationErrors, errors.Wrapf(
+				ErrInvalidRule,
+				"%s has invalid %s template: %v",
+				ruleID,
+				tmpl.field,
+				err,
+			))
+		}
+	}
+
+	if len(validationErrors) > 0 {
+		return combineErrors(validationErrors)
+	}
+
 	return nil
 }
 
@@ -173,6 +173,41 @@ var _ = Describe("Validator", func() {
 			})
 		})
 
+		Context("when rules have nested match blocks", func() {
+			It("should pass with any, all and not blocks", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "nested-rule",
+							Match: &config.RuleMatchConfig{
+								ValidatorType: "git.push",
+								Any: []*config.RuleMatchConfig{
+									{BranchPattern: "main"},
+									{Remote: "upstream"},
+								},
+								Not: &config.RuleMatchConfig{FilePattern: "docs/**"},
+							},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should accept a match section with only nested blocks", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "only-nested-rule",
+							Match: &config.RuleMatchConfig{
+								All: []*config.RuleMatchConfig{{ToolType: "Bash"}},
+							},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+
 		Context("when rules have invalid configuration", func() {
 			It("should fail when rule has no match section", func() {
 				err := validator.validateRulesConfig(&config.RulesConfig{
@@ -251,6 +286,177 @@ var _ = Describe("Validator", func() {
 				Expect(err.Error()).To(ContainSubstring("invalid-action"))
 			})
 
+			It("should fail when a nested match block is empty", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "empty-nested-rule",
+							Match: &config.RuleMatchConfig{
+								Any: []*config.RuleMatchConfig{
+									{BranchPattern: "main"},
+									{},
+								},
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`rule["empty-nested-rule"].any[1]`))
+				Expect(err.Error()).To(ContainSubstring("empty match block"))
+			})
+
+			It("should fail when a nested match block has invalid fields", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "invalid-nested-rule",
+							Match: &config.RuleMatchConfig{
+								All: []*config.RuleMatchConfig{
+									{
+										Not: &config.RuleMatchConfig{ToolType: "InvalidTool"},
+									},
+								},
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`rule["invalid-nested-rule"].all[0].not`))
+				Expect(err.Error()).To(ContainSubstring("invalid tool_type"))
+			})
+
+			It("should fail when has_flag is not a flag", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "invalid-flag-rule",
+							Match: &config.RuleMatchConfig{
+								GitSubcommand: "push",
+								HasFlags:      []string{"--force", "force"},
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(`invalid flag "force"`))
+			})
+
+			It("should fail when behind_upstream is negative", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "negative-count-rule",
+							Match: &config.RuleMatchConfig{
+								GitSubcommand:  "push",
+								BehindUpstream: -1,
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("negative ahead_of_upstream or behind_upstream"))
+			})
+
+			It("should fail when time_window is invalid", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "invalid-window-rule",
+							Match: &config.RuleMatchConfig{
+								GitSubcommand: "push",
+								TimeWindow: &config.TimeWindowConfig{
+									Days:  []string{"mon-fri"},
+									Hours: "9-17",
+								},
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid time_window"))
+			})
+
+			It("should fail when message or fix_hint is an invalid template", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "invalid-template-rule",
+							Match: &config.RuleMatchConfig{
+								ValidatorType: "git.push",
+							},
+							Action: &config.RuleActionConfig{
+								Message: "Push to {{.Branch}} blocked",
+								FixHint: "Use {{.Branch",
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid fix_hint template"))
+				Expect(err.Error()).NotTo(ContainSubstring("invalid message template"))
+			})
+
+			It("should fail when a rewrite action has nothing to rewrite", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "empty-rewrite-rule",
+							Match: &config.RuleMatchConfig{
+								GitSubcommand: "push",
+							},
+							Action: &config.RuleActionConfig{
+								Type: "rewrite",
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("rewrite action without add_flags"))
+			})
+
+			It("should fail when rewrite add_flags has no command selection", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "unselected-rewrite-rule",
+							Match: &config.RuleMatchConfig{
+								CommandPattern: "rsync",
+							},
+							Action: &config.RuleActionConfig{
+								Type: "rewrite",
+								Rewrite: &config.RuleRewriteConfig{
+									AddFlags: []string{"--dry-run"},
+								},
+							},
+						},
+					},
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).
+					To(ContainSubstring("add_flags without a command_name or git_subcommand"))
+			})
+
+			It("should pass with a valid rewrite action", func() {
+				err := validator.validateRulesConfig(&config.RulesConfig{
+					Rules: []config.RuleConfig{
+						{
+							Name: "rewrite-rule",
+							Match: &config.RuleMatchConfig{
+								GitSubcommand: "push",
+							},
+							Action: &config.RuleActionConfig{
+								Type: "rewrite",
+								Rewrite: &config.RuleRewriteConfig{
+									AddFlags:     []string{"--force-with-lease"},
+									InsertRemote: "{{.Remote}}",
+								},
+							},
+						},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
 			It("should report multiple errors", func() {
 				err := validator.validateRulesConfig(&config.RulesConfig{
 					Rules: []config.RuleConfig{
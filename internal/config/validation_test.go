@@ -65,6 +65,48 @@ var _ = Describe("Validator", func() {
 		})
 	})
 
+	Describe("validateGlobalConfig", func() {
+		It("should accept valid output formats", func() {
+			for _, format := range config.ValidOutputFormats {
+				cfg := &config.Config{
+					Global: &config.GlobalConfig{OutputFormat: format},
+				}
+				Expect(validator.Validate(cfg)).To(Succeed())
+			}
+		})
+
+		It("should reject unknown output format", func() {
+			cfg := &config.Config{
+				Global: &config.GlobalConfig{OutputFormat: "yaml"},
+			}
+
+			err := validator.validateGlobalConfig(cfg.Global)
+			Expect(err).To(HaveOccurred())
+			Expect(errors.Is(err, ErrInvalidOption)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("output_format"))
+			Expect(validator.Validate(cfg)).To(MatchError(ErrInvalidConfig))
+		})
+
+		It("should accept zero max shell nesting depth", func() {
+			depth := 0
+			cfg := &config.Config{
+				Global: &config.GlobalConfig{MaxShellNestingDepth: &depth},
+			}
+			Expect(validator.Validate(cfg)).To(Succeed())
+		})
+
+		It("should reject negative max shell nesting depth", func() {
+			depth := -1
+			cfg := &config.Config{
+				Global: &config.GlobalConfig{MaxShellNestingDepth: &depth},
+			}
+
+			err := validator.validateGlobalConfig(cfg.Global)
+			Expect(errors.Is(err, ErrInvalidOption)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("max_shell_nesting_depth"))
+		})
+	})
+
 	Describe("validateGitConfig", func() {
 		It("should pass with nil config", func() {
 			cfg := &config.Config{
@@ -537,6 +579,97 @@ var _ = Describe("Validator", func() {
 		})
 	})
 
+	Describe("validateShellConfig", func() {
+		It("should pass with valid destructive config", func() {
+			cfg := &config.Config{
+				Validators: &config.ValidatorsConfig{
+					Shell: &config.ShellConfig{
+						Destructive: &config.DestructiveValidatorConfig{
+							Severities:   map[string]config.Severity{"git-clean": config.SeverityWarning},
+							AllowedPaths: []string{"/tmp/**", "~/.cache/**"},
+						},
+					},
+				},
+			}
+			err := validator.Validate(cfg)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail with unknown pattern severity", func() {
+			cfg := &config.ShellConfig{
+				Destructive: &config.DestructiveValidatorConfig{
+					Severities: map[string]config.Severity{"git-clean": config.SeverityUnknown},
+				},
+			}
+
+			err := validator.validateShellConfig(cfg)
+			Expect(errors.Is(err, ErrInvalidSeverity)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("severities.git-clean"))
+		})
+
+		It("should fail with invalid allowed path glob", func() {
+			cfg := &config.ShellConfig{
+				Destructive: &config.DestructiveValidatorConfig{
+					AllowedPaths: []string{"/tmp/[abc"},
+				},
+			}
+
+			err := validator.validateShellConfig(cfg)
+			Expect(errors.Is(err, ErrInvalidOption)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("allowed_paths"))
+		})
+	})
+
+	Describe("validatePathsConfig", func() {
+		It("should pass with the default config", func() {
+			err := validator.validatePathsConfig(DefaultPathsValidatorConfig())
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should pass with an outside project rule without paths", func() {
+			cfg := &config.PathsValidatorConfig{
+				Deny: []config.PathRuleConfig{
+					{OutsideProject: true, Access: []string{config.PathAccessWrite}},
+				},
+			}
+
+			err := validator.validatePathsConfig(cfg)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail with a rule without paths", func() {
+			cfg := &config.PathsValidatorConfig{
+				Allow: []config.PathRuleConfig{{Suggestion: "nothing"}},
+			}
+
+			err := validator.validatePathsConfig(cfg)
+			Expect(errors.Is(err, ErrInvalidOption)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("allow[0]"))
+		})
+
+		It("should fail with an invalid glob", func() {
+			cfg := &config.PathsValidatorConfig{
+				Deny: []config.PathRuleConfig{{Paths: []string{"~/.ssh/[id"}}},
+			}
+
+			err := validator.validatePathsConfig(cfg)
+			Expect(errors.Is(err, ErrInvalidOption)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("deny[0]"))
+		})
+
+		It("should fail with an unknown access type", func() {
+			cfg := &config.PathsValidatorConfig{
+				Deny: []config.PathRuleConfig{
+					{Paths: []string{"/etc/**"}, Access: []string{"execute"}},
+				},
+			}
+
+			err := validator.validatePathsConfig(cfg)
+			Expect(errors.Is(err, ErrInvalidOption)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring(`got "execute"`))
+		})
+	})
+
 	Describe("validateBaseConfig", func() {
 		It("should reject invalid severity", func() {
 			cfg := &config.Config{
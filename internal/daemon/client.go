@@ -0,0 +1,99 @@
+package daemon
+
+import (
+	"encoding/json"
+	"net"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	// defaultDialTimeout bounds connecting to the daemon. It is short because
+	// the client falls back to in-process validation on failure.
+	defaultDialTimeout = 200 * time.Millisecond
+)
+
+var (
+	// ErrUnavailable is returned when the daemon cannot be reached.
+	ErrUnavailable = errors.New("daemon unavailable")
+
+	// ErrRequestFailed is returned when the daemon could not validate a request.
+	ErrRequestFailed = errors.New("daemon request failed")
+)
+
+// Client forwards hook invocations to a daemon over a Unix socket.
+type Client struct {
+	socketPath  string
+	dialTimeout time.Duration
+	timeout     time.Duration
+}
+
+// ClientOption configures the Client.
+type ClientOption func(*Client)
+
+// WithDialTimeout sets the timeout for connecting to the daemon.
+func WithDialTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.dialTimeout = timeout
+		}
+	}
+}
+
+// WithTimeout sets the deadline for a request round trip.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *Client) {
+		if timeout > 0 {
+			c.timeout = timeout
+		}
+	}
+}
+
+// NewClient creates a new Client for the daemon listening on socketPath.
+func NewClient(socketPath string, opts ...ClientOption) *Client {
+	c := &Client{
+		socketPath:  socketPath,
+		dialTimeout: defaultDialTimeout,
+		timeout:     defaultRequestTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Do sends the request to the daemon and returns its response. It returns an
+// error wrapping ErrUnavailable if the daemon cannot be reached, and one
+// wrapping ErrRequestFailed if the daemon rejected the request.
+func (c *Client) Do(req *Request) (*Response, error) {
+	conn, err := net.DialTimeout("unix", c.socketPath, c.dialTimeout)
+	if err != nil {
+		return nil, errors.Wrap(ErrUnavailable, err.Error())
+	}
+
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+		return nil, errors.Wrap(err, "failed to set connection deadline")
+	}
+
+	req.Version = ProtocolVersion
+
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, errors.Wrap(err, "failed to send request")
+	}
+
+	var resp Response
+	if err := json.NewDecoder(conn).Decode(&resp); err != nil {
+		return nil, errors.Wrap(err, "failed to read response")
+	}
+
+	if resp.Error != "" {
+		return nil, errors.Wrap(ErrRequestFailed, resp.Error)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,155 @@
+// Package daemon provides a long-lived klaudiush server on a Unix socket and a
+// client that forwards hook invocations to it.
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+)
+
+const (
+	// ProtocolVersion is the version of the request/response protocol.
+	// The server rejects requests with a different version.
+	ProtocolVersion = 2
+
+	// SocketFile is the default socket file name inside the global config directory.
+	SocketFile = "daemon.sock"
+
+	// envPrefix is the prefix of environment variables that affect configuration.
+	envPrefix = "KLAUDIUSH_"
+)
+
+// Flags are the root command flags that affect how configuration is loaded.
+type Flags struct {
+	ConfigPath   string   `json:"config_path,omitempty"`
+	GlobalConfig string   `json:"global_config,omitempty"`
+	Disable      []string `json:"disable,omitempty"`
+	OutputFormat string   `json:"output_format,omitempty"`
+}
+
+// Map converts the flags to a map for the config provider.
+func (f *Flags) Map() map[string]any {
+	flags := make(map[string]any)
+
+	if f.ConfigPath != "" {
+		flags["config_path"] = f.ConfigPath
+	}
+
+	if f.GlobalConfig != "" {
+		flags["global_config"] = f.GlobalConfig
+	}
+
+	if len(f.Disable) > 0 {
+		flags["disable"] = f.Disable
+	}
+
+	if f.OutputFormat != "" {
+		flags["output-format"] = f.OutputFormat
+	}
+
+	return flags
+}
+
+// key returns a stable string identifying the flag values.
+func (f *Flags) key() string {
+	return strings.Join([]string{
+		f.ConfigPath,
+		f.GlobalConfig,
+		strings.Join(f.Disable, ","),
+		f.OutputFormat,
+	}, "\x00")
+}
+
+// Request is a single hook invocation forwarded by the client.
+type Request struct {
+	// Version is the protocol version of the client.
+	Version int `json:"version"`
+
+	// WorkDir is the working directory of the hook process.
+	WorkDir string `json:"work_dir"`
+
+	// HookType is the raw --hook-type flag value.
+	HookType string `json:"hook_type,omitempty"`
+
+	// Flags are the configuration flags of the hook process.
+	Flags Flags `json:"flags"`
+
+	// Env holds the KLAUDIUSH_* environment variables of the hook process.
+	Env map[string]string `json:"env,omitempty"`
+
+	// ClientEnv holds all environment variables of the hook process, for the
+	// env conditions of rules.
+	ClientEnv map[string]string `json:"client_env,omitempty"`
+
+	// Payload is the raw hook JSON read from stdin.
+	Payload []byte `json:"payload"`
+}
+
+// Response is the outcome of a forwarded hook invocation.
+type Response struct {
+	// Error is set when the server could not validate the request. The client
+	// falls back to in-process validation in that case.
+	Error string `json:"error,omitempty"`
+
+	// Empty is true when the payload was empty and the invocation is allowed.
+	Empty bool `json:"empty,omitempty"`
+
+	// EventType is the resolved hook event type.
+	EventType string `json:"event_type,omitempty"`
+
+	// OutputFormat is the configured hook decision output format.
+	OutputFormat string `json:"output_format,omitempty"`
+
+	// Errors are the validation errors returned by the dispatcher.
+	Errors []*dispatcher.ValidationError `json:"errors,omitempty"`
+}
+
+// DefaultSocketPath returns the default socket path for the given home directory.
+func DefaultSocketPath(homeDir string) string {
+	return filepath.Join(homeDir, internalconfig.GlobalConfigDir, SocketFile)
+}
+
+// ConfigEnv returns the KLAUDIUSH_* environment variables of the current process.
+func ConfigEnv() map[string]string {
+	env := make(map[string]string)
+
+	for key, value := range ProcessEnv() {
+		if strings.HasPrefix(key, envPrefix) {
+			env[key] = value
+		}
+	}
+
+	return env
+}
+
+// ProcessEnv returns all environment variables of the current process.
+func ProcessEnv() map[string]string {
+	env := make(map[string]string)
+
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+
+	return env
+}
+
+// sameEnv reports whether two environment maps hold the same variables.
+func sameEnv(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for k, v := range a {
+		if other, ok := b[k]; !ok || other != v {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,405 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	gitpkg "github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/parser"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+const (
+	// defaultRequestTimeout bounds reading a request and writing its response.
+	defaultRequestTimeout = 60 * time.Second
+
+	// socketDirMode is the permission mode for the socket directory.
+	socketDirMode = 0o700
+
+	// socketFileMode restricts the socket to the current user.
+	socketFileMode = 0o600
+)
+
+var (
+	// ErrAlreadyRunning is returned when another daemon is listening on the socket.
+	ErrAlreadyRunning = errors.New("daemon already running")
+
+	// ErrVersionMismatch is returned when the client uses a different protocol version.
+	ErrVersionMismatch = errors.New("protocol version mismatch")
+
+	// ErrEnvMismatch is returned when the client's KLAUDIUSH_* environment
+	// differs from the daemon's, so the daemon cannot load the same config.
+	ErrEnvMismatch = errors.New("environment mismatch")
+)
+
+// Server validates hook requests forwarded over a Unix socket. It keeps a
+// runtime per working directory and flag set, and rebuilds it when one of its
+// config files changes. Requests are handled one at a time because validators
+// run git and linters relative to the process working directory.
+type Server struct {
+	socketPath string
+	homeDir    string
+	env        map[string]string
+	timeout    time.Duration
+	log        logger.Logger
+
+	mu       sync.Mutex
+	runtimes map[string]*cachedRuntime
+	current  *cachedRuntime
+	workDir  string
+}
+
+// cachedRuntime is a runtime together with the config it was built from.
+type cachedRuntime struct {
+	runtime *app.Runtime
+	cfg     *config.Config
+	stamp   string
+
+	// includes are the files included by the rules of the config.
+	includes []string
+}
+
+// ServerOption configures the Server.
+type ServerOption func(*Server)
+
+// WithServerLogger sets the logger for the server.
+func WithServerLogger(log logger.Logger) ServerOption {
+	return func(s *Server) {
+		if log != nil {
+			s.log = log
+		}
+	}
+}
+
+// WithHomeDir sets the home directory used to locate the global config.
+func WithHomeDir(dir string) ServerOption {
+	return func(s *Server) {
+		if dir != "" {
+			s.homeDir = dir
+		}
+	}
+}
+
+// WithRequestTimeout sets the deadline for handling a single connection.
+func WithRequestTimeout(timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		if timeout > 0 {
+			s.timeout = timeout
+		}
+	}
+}
+
+// NewServer creates a new Server listening on socketPath.
+func NewServer(socketPath string, opts ...ServerOption) (*Server, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get home directory")
+	}
+
+	s := &Server{
+		socketPath: socketPath,
+		homeDir:    homeDir,
+		env:        ConfigEnv(),
+		timeout:    defaultRequestTimeout,
+		log:        logger.NewNoOpLogger(),
+		runtimes:   make(map[string]*cachedRuntime),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// ListenAndServe listens on the socket and serves requests until ctx is done.
+// The socket file is removed on return.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if rmErr := os.Remove(s.socketPath); rmErr != nil && !os.IsNotExist(rmErr) {
+			s.log.Error("failed to remove socket", "path", s.socketPath, "error", rmErr)
+		}
+	}()
+
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+
+	s.log.Info("daemon listening", "socket", s.socketPath)
+
+	var wg sync.WaitGroup
+
+	defer wg.Wait()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				s.log.Info("daemon stopped")
+
+				return nil
+			}
+
+			return errors.Wrap(err, "failed to accept connection")
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			s.handleConn(conn)
+		}()
+	}
+}
+
+// listen creates the socket, replacing a stale socket file left by a dead daemon.
+func (s *Server) listen() (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(s.socketPath), socketDirMode); err != nil {
+		return nil, errors.Wrap(err, "failed to create socket directory")
+	}
+
+	if _, err := os.Stat(s.socketPath); err == nil {
+		if conn, dialErr := net.Dial("unix", s.socketPath); dialErr == nil {
+			_ = conn.Close()
+
+			return nil, errors.Wrapf(ErrAlreadyRunning, "socket %s", s.socketPath)
+		}
+
+		if err := os.Remove(s.socketPath); err != nil {
+			return nil, errors.Wrap(err, "failed to remove stale socket")
+		}
+	}
+
+	listener, err := net.Listen("unix", s.socketPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen on socket")
+	}
+
+	if err := os.Chmod(s.socketPath, socketFileMode); err != nil {
+		_ = listener.Close()
+
+		return nil, errors.Wrap(err, "failed to set socket permissions")
+	}
+
+	return listener, nil
+}
+
+// handleConn reads a single request from the connection and writes its response.
+func (s *Server) handleConn(conn net.Conn) {
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(s.timeout)); err != nil {
+		s.log.Error("failed to set connection deadline", "error", err)
+
+		return
+	}
+
+	var req Request
+	if err := json.NewDecoder(conn).Decode(&req); err != nil {
+		s.log.Error("failed to decode request", "error", err)
+
+		return
+	}
+
+	if err := json.NewEncoder(conn).Encode(s.Handle(&req)); err != nil {
+		s.log.Error("failed to write response", "error", err)
+	}
+}
+
+// Handle validates a single request. Errors are reported in Response.Error so
+// the client can fall back to in-process validation.
+func (s *Server) Handle(req *Request) (resp *Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	defer func() {
+		if r := recover(); r != nil {
+			s.log.Error("panic while handling request", "panic", r)
+
+			resp = &Response{Error: fmt.Sprintf("panic: %v", r)}
+		}
+	}()
+
+	resp = &Response{}
+
+	if err := s.handle(req, resp); err != nil {
+		s.log.Info("request rejected", "error", err)
+
+		return &Response{Error: err.Error()}
+	}
+
+	return resp
+}
+
+// handle validates the request, filling in resp.
+func (s *Server) handle(req *Request, resp *Response) error {
+	if req.Version != ProtocolVersion {
+		return errors.Wrapf(ErrVersionMismatch, "client %d, server %d", req.Version, ProtocolVersion)
+	}
+
+	if !sameEnv(req.Env, s.env) {
+		return ErrEnvMismatch
+	}
+
+	if err := s.enterWorkDir(req.WorkDir); err != nil {
+		return err
+	}
+
+	cached, err := s.runtimeFor(req)
+	if err != nil {
+		return err
+	}
+
+	hookCtx, err := app.ParseHookInput(req.HookType, bytes.NewReader(req.Payload))
+	if err != nil {
+		if errors.Is(err, parser.ErrEmptyInput) {
+			resp.Empty = true
+
+			return nil
+		}
+
+		return errors.Wrap(err, "failed to parse input")
+	}
+
+	hookCtx.Env = req.ClientEnv
+
+	errs := cached.runtime.Dispatch(context.Background(), hookCtx)
+
+	// Persist state after every request so in-process fallback sees it
+	if err := cached.runtime.Save(); err != nil {
+		s.log.Info("failed to save runtime state", "error", err)
+	}
+
+	resp.EventType = hookCtx.EventType.String()
+	resp.OutputFormat = cached.cfg.GetGlobal().GetOutputFormat()
+	resp.Errors = errs
+
+	s.log.Info("request handled",
+		"event", hookCtx.EventType,
+		"tool", hookCtx.ToolName,
+		"errorCount", len(errs),
+	)
+
+	return nil
+}
+
+// enterWorkDir changes to the request's working directory. The git repository
+// cache is reset when the directory differs from the previous request.
+func (s *Server) enterWorkDir(workDir string) error {
+	if workDir == "" {
+		return errors.New("missing working directory")
+	}
+
+	if err := os.Chdir(workDir); err != nil {
+		return errors.Wrap(err, "failed to change working directory")
+	}
+
+	if workDir != s.workDir {
+		gitpkg.ResetRepositoryCache()
+
+		s.workDir = workDir
+	}
+
+	return nil
+}
+
+// runtimeFor returns the runtime for the request's working directory and
+// flags, (re)building it when its config files changed. Persisted state is
+// reloaded when switching between runtimes.
+func (s *Server) runtimeFor(req *Request) (*cachedRuntime, error) {
+	loader, err := internalconfig.NewKoanfLoaderWithDirs(s.homeDir, req.WorkDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create config loader")
+	}
+
+	key := req.WorkDir + "\x00" + req.Flags.key()
+
+	cached, ok := s.runtimes[key]
+	if ok && cached.stamp == configStamp(loader, cached.includes) {
+		if cached != s.current {
+			cached.runtime.LoadState()
+
+			s.current = cached
+		}
+
+		return cached, nil
+	}
+
+	// Config files are stamped before loading, so edits made during the
+	// load trigger another reload
+	configFiles := fileStamp(configPaths(loader))
+
+	cfg, err := loader.Load(req.Flags.Map())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load configuration")
+	}
+
+	includes := loader.IncludePaths()
+	stamp := configFiles + "|" + fileStamp(includes)
+
+	rt, err := app.New(cfg, s.log, app.WithSkipInvalidRules())
+	if err != nil {
+		return nil, err
+	}
+
+	s.log.Info("runtime built",
+		"work_dir", req.WorkDir,
+		"reload", ok,
+	)
+
+	cached = &cachedRuntime{runtime: rt, cfg: cfg, stamp: stamp, includes: includes}
+	s.runtimes[key] = cached
+	s.current = cached
+
+	return cached, nil
+}
+
+// configStamp fingerprints the config files the loader reads and the files
+// included by their rules, so that edits, creations and deletions are all
+// detected.
+func configStamp(loader *internalconfig.KoanfLoader, includes []string) string {
+	return fileStamp(configPaths(loader)) + "|" + fileStamp(includes)
+}
+
+// configPaths returns the config files the loader reads.
+func configPaths(loader *internalconfig.KoanfLoader) []string {
+	return append([]string{loader.GlobalConfigPath()}, loader.ProjectConfigPaths()...)
+}
+
+// fileStamp fingerprints the modification time and size of files.
+func fileStamp(paths []string) string {
+	parts := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			parts = append(parts, path+":-")
+
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%s:%d:%d", path, info.ModTime().UnixNano(), info.Size()))
+	}
+
+	return strings.Join(parts, ";")
+}
@@ -0,0 +1,284 @@
+package daemon_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/daemon"
+)
+
+const pushPayload = `{
+  "tool_name": "Bash",
+  "tool_input": {"command": "git push origin main"}
+}`
+
+func ruleConfig(action string) string {
+	return `[[rules.rules]]
+name = "no-push"
+
+[rules.rules.match]
+validator_type = "git.push"
+command_pattern = "git push*"
+
+[rules.rules.action]
+type = "` + action + `"
+message = "pushes are not allowed"
+`
+}
+
+var _ = Describe("Server", func() {
+	var (
+		homeDir string
+		workDir string
+		origWd  string
+		server  *daemon.Server
+	)
+
+	writeProjectConfig := func(content string) {
+		dir := filepath.Join(workDir, ".klaudiush")
+		Expect(os.MkdirAll(dir, 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "config.toml"), []byte(content), 0o600)).To(Succeed())
+	}
+
+	newRequest := func(payload string) *daemon.Request {
+		return &daemon.Request{
+			Version: daemon.ProtocolVersion,
+			WorkDir: workDir,
+			Env:     daemon.ConfigEnv(),
+			Payload: []byte(payload),
+		}
+	}
+
+	BeforeEach(func() {
+		var err error
+
+		origWd, err = os.Getwd()
+		Expect(err).NotTo(HaveOccurred())
+
+		homeDir = GinkgoT().TempDir()
+		workDir = GinkgoT().TempDir()
+
+		server, err = daemon.NewServer(
+			filepath.Join(homeDir, "daemon.sock"),
+			daemon.WithHomeDir(homeDir),
+		)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.Chdir(origWd)).To(Succeed())
+	})
+
+	Describe("Handle", func() {
+		It("should validate the payload with the project config", func() {
+			writeProjectConfig(ruleConfig("block"))
+
+			resp := server.Handle(newRequest(pushPayload))
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.EventType).To(Equal("PreToolUse"))
+			Expect(resp.OutputFormat).To(Equal("text"))
+			Expect(resp.Errors).To(HaveLen(1))
+			Expect(resp.Errors[0].ShouldBlock).To(BeTrue())
+			Expect(resp.Errors[0].Message).To(ContainSubstring("pushes are not allowed"))
+		})
+
+		It("should reload the config when a config file changes", func() {
+			writeProjectConfig(ruleConfig("block"))
+
+			resp := server.Handle(newRequest(pushPayload))
+			Expect(resp.Errors).To(HaveLen(1))
+			Expect(resp.Errors[0].ShouldBlock).To(BeTrue())
+
+			writeProjectConfig(ruleConfig("warn") + "\n# reloaded\n")
+
+			resp = server.Handle(newRequest(pushPayload))
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.Errors).To(HaveLen(1))
+			Expect(resp.Errors[0].ShouldBlock).To(BeFalse())
+		})
+
+		It("should reload the config when an included file changes", func() {
+			writeProjectConfig(`[rules]
+include = ["./rules.toml"]
+`)
+
+			rulesPath := filepath.Join(workDir, ".klaudiush", "rules.toml")
+			Expect(os.WriteFile(rulesPath, []byte(ruleConfig("block")), 0o600)).To(Succeed())
+
+			resp := server.Handle(newRequest(pushPayload))
+			Expect(resp.Errors).To(HaveLen(1))
+			Expect(resp.Errors[0].ShouldBlock).To(BeTrue())
+
+			Expect(os.WriteFile(rulesPath, []byte(ruleConfig("warn")+"\n# reloaded\n"), 0o600)).
+				To(Succeed())
+
+			resp = server.Handle(newRequest(pushPayload))
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.Errors).To(HaveLen(1))
+			Expect(resp.Errors[0].ShouldBlock).To(BeFalse())
+		})
+
+		It("should see repository changes between requests", func() {
+			runGit := func(args ...string) {
+				cmd := exec.Command("git", args...)
+				cmd.Dir = workDir
+				cmd.Env = append(os.Environ(),
+					"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+					"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com",
+				)
+				out, err := cmd.CombinedOutput()
+				Expect(err).NotTo(HaveOccurred(), string(out))
+			}
+
+			runGit("init", "-q", "-b", "main")
+			runGit("commit", "-q", "--allow-empty", "-m", "initial")
+
+			writeProjectConfig(`[[rules.rules]]
+name = "no-echo-on-main"
+
+[rules.rules.match]
+branch_pattern = "main"
+command_pattern = "echo*"
+
+[rules.rules.action]
+type = "block"
+message = "not on main"
+`)
+
+			echoPayload := `{"tool_name": "Bash", "tool_input": {"command": "echo hi"}}`
+
+			resp := server.Handle(newRequest(echoPayload))
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.Errors).NotTo(BeEmpty())
+			Expect(resp.Errors[0].Message).To(ContainSubstring("not on main"))
+
+			runGit("checkout", "-q", "-b", "feature")
+
+			resp = server.Handle(newRequest(echoPayload))
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.Errors).To(BeEmpty())
+		})
+
+		It("should evaluate env conditions against the client environment", func() {
+			writeProjectConfig(`[[rules.rules]]
+name = "no-echo-outside-ci"
+
+[rules.rules.match]
+command_pattern = "echo*"
+env = { KLAUDIUSH_TEST_CI = "!true" }
+
+[rules.rules.action]
+type = "block"
+message = "only in CI"
+`)
+
+			echoPayload := `{"tool_name": "Bash", "tool_input": {"command": "echo hi"}}`
+
+			req := newRequest(echoPayload)
+			req.ClientEnv = map[string]string{"KLAUDIUSH_TEST_CI": "true"}
+
+			resp := server.Handle(req)
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.Errors).To(BeEmpty())
+
+			req.ClientEnv = map[string]string{}
+
+			resp = server.Handle(req)
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.Errors).NotTo(BeEmpty())
+			Expect(resp.Errors[0].Message).To(ContainSubstring("only in CI"))
+		})
+
+		It("should report empty input", func() {
+			resp := server.Handle(newRequest(""))
+			Expect(resp.Error).To(BeEmpty())
+			Expect(resp.Empty).To(BeTrue())
+		})
+
+		It("should reject a different protocol version", func() {
+			req := newRequest(pushPayload)
+			req.Version = daemon.ProtocolVersion + 1
+
+			resp := server.Handle(req)
+			Expect(resp.Error).To(ContainSubstring("protocol version mismatch"))
+		})
+
+		It("should reject a different KLAUDIUSH_* environment", func() {
+			req := newRequest(pushPayload)
+			req.Env = map[string]string{"KLAUDIUSH_TEST_ONLY": "1"}
+
+			resp := server.Handle(req)
+			Expect(resp.Error).To(ContainSubstring("environment mismatch"))
+		})
+
+		It("should reject a missing working directory", func() {
+			req := newRequest(pushPayload)
+			req.WorkDir = ""
+
+			resp := server.Handle(req)
+			Expect(resp.Error).To(ContainSubstring("missing working directory"))
+		})
+
+		It("should report invalid configuration", func() {
+			writeProjectConfig("[global\n")
+
+			resp := server.Handle(newRequest(pushPayload))
+			Expect(resp.Error).To(ContainSubstring("failed to load configuration"))
+		})
+	})
+
+	Describe("ListenAndServe", func() {
+		It("should serve requests over the socket and remove it on shutdown", func() {
+			// Keep the socket path short to stay under the Unix socket path limit
+			socketDir, err := os.MkdirTemp("", "kd")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(os.RemoveAll, socketDir)
+
+			socket := filepath.Join(socketDir, "d.sock")
+
+			server, err = daemon.NewServer(socket, daemon.WithHomeDir(homeDir))
+			Expect(err).NotTo(HaveOccurred())
+
+			writeProjectConfig(ruleConfig("block"))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+
+			go func() {
+				done <- server.ListenAndServe(ctx)
+			}()
+
+			client := daemon.NewClient(socket)
+
+			var resp *daemon.Response
+
+			Eventually(func() error {
+				resp, err = client.Do(newRequest(pushPayload))
+
+				return err
+			}).WithTimeout(5 * time.Second).Should(Succeed())
+
+			Expect(resp.Errors).To(HaveLen(1))
+			Expect(resp.Errors[0].ShouldBlock).To(BeTrue())
+
+			cancel()
+			Eventually(done).WithTimeout(5 * time.Second).Should(Receive(BeNil()))
+			Expect(socket).NotTo(BeAnExistingFile())
+		})
+	})
+})
+
+var _ = Describe("Client", func() {
+	It("should report an unavailable daemon", func() {
+		client := daemon.NewClient(filepath.Join(GinkgoT().TempDir(), "missing.sock"))
+
+		_, err := client.Do(&daemon.Request{})
+		Expect(err).To(MatchError(daemon.ErrUnavailable))
+	})
+})
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -66,12 +67,28 @@ type ValidationError struct {
 	// ShouldBlock indicates whether this error should block the operation.
 	ShouldBlock bool
 
+	// ShouldAsk indicates whether the user should be asked to confirm the operation.
+	ShouldAsk bool
+
 	// Reference is the URL that uniquely identifies this error type.
 	// Format: https://klaudiu.sh/{CODE} (e.g., https://klaudiu.sh/GIT001).
 	Reference validator.Reference
 
 	// FixHint provides a short suggestion for fixing the issue.
 	FixHint string
+
+	// UpdatedInput is the rewritten tool input to run instead of the
+	// original one (see AppliedRewrite).
+	UpdatedInput map[string]any
+
+	// Feedback indicates a finding of the opt-in PostToolUse file validation,
+	// fed back to the model (see HasFeedback).
+	Feedback bool
+}
+
+// IsRewrite returns true if the error rewrites the tool input.
+func (e *ValidationError) IsRewrite() bool {
+	return e.UpdatedInput != nil
 }
 
 // Error implements the error interface.
@@ -96,6 +113,7 @@ type Dispatcher struct {
 	exceptionChecker   ExceptionChecker
 	sessionTracker     SessionTracker
 	sessionAuditLogger SessionAuditLogger
+	parserOpts         []parser.BashParserOption
 }
 
 // NewDispatcher creates a new Dispatcher with sequential execution.
@@ -141,6 +159,14 @@ func WithSessionTracker(tracker SessionTracker) DispatcherOption {
 	}
 }
 
+// WithMaxNestingDepth sets the depth up to which shell scripts nested in Bash
+// commands are parsed for file writes.
+func WithMaxNestingDepth(depth int) DispatcherOption {
+	return func(d *Dispatcher) {
+		d.parserOpts = []parser.BashParserOption{parser.WithMaxNestingDepth(depth)}
+	}
+}
+
 // WithSessionAuditLogger sets the session audit logger for the dispatcher.
 func WithSessionAuditLogger(auditLogger SessionAuditLogger) DispatcherOption {
 	return func(d *Dispatcher) {
@@ -179,7 +205,7 @@ func (d *Dispatcher) Dispatch(ctx context.Context, hookCtx *hook.Context) []*Val
 	)
 
 	// Check if session tracking is enabled and session is poisoned
-	if d.sessionTracker != nil && d.sessionTracker.IsEnabled() && hookCtx.HasSessionID() {
+	if d.tracksSession(hookCtx) {
 		if poisoned, info := d.sessionTracker.IsPoisoned(hookCtx.SessionID); poisoned {
 			d.logger.Info("session is poisoned",
 				"session_id", hookCtx.SessionID,
@@ -200,14 +226,34 @@ func (d *Dispatcher) Dispatch(ctx context.Context, hookCtx *hook.Context) []*Val
 	// Run validators on the main context
 	validationErrors := d.runValidators(ctx, hookCtx)
 
-	// If this is a Bash PreToolUse, also validate synthetic Write contexts for file writes
+	// If this is a Bash PreToolUse, also validate synthetic Write contexts for
+	// file writes, and the nesting depth of the command
 	if hookCtx.EventType == hook.EventTypePreToolUse && hookCtx.ToolName == hook.ToolTypeBash {
-		syntheticErrors := d.validateBashFileWrites(ctx, hookCtx)
+		syntheticErrors := d.validateBashCommand(ctx, hookCtx)
 		validationErrors = append(validationErrors, syntheticErrors...)
 	}
 
+	// Claude Code is already continuing because of a blocked stop, so blocking
+	// it again would loop forever
+	if hookCtx.StopHookActive && isStopEvent(hookCtx.EventType) {
+		validationErrors = d.releaseStopBlocks(validationErrors)
+	}
+
+	if rewrite := AppliedRewrite(validationErrors); rewrite != nil {
+		validationErrors = d.validateRewrite(ctx, hookCtx, rewrite, validationErrors)
+	}
+
+	if rewrite := AppliedRewrite(validationErrors); rewrite != nil {
+		d.logger.Info("tool input rewritten",
+			"validator", shortName(rewrite.Validator),
+			"message", rewrite.Message,
+		)
+
+		d.logRewriteAuditEntry(hookCtx, rewrite)
+	}
+
 	// Poison session if there are blocking errors, otherwise record command
-	if d.sessionTracker != nil && d.sessionTracker.IsEnabled() && hookCtx.HasSessionID() {
+	if d.tracksSession(hookCtx) {
 		if ShouldBlock(validationErrors) {
 			codes := extractSessionPoisonCodes(validationErrors)
 			message := extractSessionPoisonMessage(validationErrors)
@@ -236,6 +282,95 @@ func (d *Dispatcher) Dispatch(ctx context.Context, hookCtx *hook.Context) []*Val
 	return validationErrors
 }
 
+// validateRewrite validates the rewritten tool input, so that a rewrite cannot
+// turn a call into one the validators would block. If the rewritten input is
+// blocked or needs confirmation, the rewrite is discarded and the errors of
+// the rewritten input are returned instead of errs.
+func (d *Dispatcher) validateRewrite(
+	ctx context.Context,
+	hookCtx *hook.Context,
+	rewrite *ValidationError,
+	errs []*ValidationError,
+) []*ValidationError {
+	rewritten, err := hookCtx.WithToolInput(rewrite.UpdatedInput)
+	if err != nil {
+		d.logger.Info("discarding rewrite with invalid tool input",
+			"validator", shortName(rewrite.Validator),
+			"error", err,
+		)
+
+		return withoutRewrites(errs)
+	}
+
+	rewrittenErrs := d.runValidators(ctx, rewritten)
+
+	if rewritten.ToolName == hook.ToolTypeBash {
+		rewrittenErrs = append(rewrittenErrs, d.validateBashCommand(ctx, rewritten)...)
+	}
+
+	if !ShouldBlock(rewrittenErrs) && !ShouldAsk(rewrittenErrs) {
+		return errs
+	}
+
+	d.logger.Info("discarding rewrite rejected by validators",
+		"validator", shortName(rewrite.Validator),
+	)
+
+	return withoutRewrites(rewrittenErrs)
+}
+
+// withoutRewrites returns the errors that are not rewrites.
+func withoutRewrites(errs []*ValidationError) []*ValidationError {
+	result := make([]*ValidationError, 0, len(errs))
+
+	for _, err := range errs {
+		if !err.IsRewrite() {
+			result = append(result, err)
+		}
+	}
+
+	return result
+}
+
+// tracksSession returns true if session state applies to this hook context.
+// Only tool events are tracked, so lifecycle events (prompts, stops, session
+// start/end) are neither blocked by nor recorded in a poisoned session.
+func (d *Dispatcher) tracksSession(hookCtx *hook.Context) bool {
+	return d.sessionTracker != nil &&
+		d.sessionTracker.IsEnabled() &&
+		hookCtx.HasSessionID() &&
+		hookCtx.IsToolEvent()
+}
+
+// isStopEvent returns true for the events fired when the agent finishes.
+func isStopEvent(eventType hook.EventType) bool {
+	return eventType == hook.EventTypeStop || eventType == hook.EventTypeSubagentStop
+}
+
+// releaseStopBlocks turns blocking errors and confirmation requests into
+// warnings, which are reported to the user as a system message instead of
+// blocking the stop.
+func (d *Dispatcher) releaseStopBlocks(errs []*ValidationError) []*ValidationError {
+	released := make([]*ValidationError, 0, len(errs))
+
+	for _, verr := range errs {
+		if verr.ShouldBlock || verr.ShouldAsk {
+			d.logger.Info("stop hook already active, not blocking stop",
+				"validator", shortName(verr.Validator),
+			)
+
+			warning := *verr
+			warning.ShouldBlock = false
+			warning.ShouldAsk = false
+			verr = &warning
+		}
+
+		released = append(released, verr)
+	}
+
+	return released
+}
+
 // runValidators runs validators on a context and returns validation errors.
 func (d *Dispatcher) runValidators(ctx context.Context, hookCtx *hook.Context) []*ValidationError {
 	validators := d.registry.FindValidators(hookCtx)
@@ -263,12 +398,23 @@ func (d *Dispatcher) runValidators(ctx context.Context, hookCtx *hook.Context) [
 	for _, verr := range validationErrors {
 		name := shortName(verr.Validator)
 
-		if verr.ShouldBlock {
+		switch {
+		case verr.ShouldBlock:
 			d.logger.Error("validator failed",
 				"validator", name,
 				"message", verr.Message,
 			)
-		} else {
+		case verr.ShouldAsk:
+			d.logger.Info("validator asked for confirmation",
+				"validator", name,
+				"message", verr.Message,
+			)
+		case verr.IsRewrite():
+			d.logger.Info("validator rewrote tool input",
+				"validator", name,
+				"message", verr.Message,
+			)
+		default:
 			d.logger.Info("validator warned",
 				"validator", name,
 				"message", verr.Message,
@@ -309,14 +455,16 @@ func (d *Dispatcher) applyExceptionChecking(
 	return result
 }
 
-// validateBashFileWrites parses Bash commands for file writes and validates them
-// as synthetic Write operations.
-func (d *Dispatcher) validateBashFileWrites(
+// validateBashCommand parses a Bash command, blocking it when it nests shell
+// scripts deeper than the maximum depth, as the commands of those scripts are
+// not seen by any validator or rule. Its file writes are validated as
+// synthetic Write operations.
+func (d *Dispatcher) validateBashCommand(
 	ctx context.Context,
 	bashCtx *hook.Context,
 ) []*ValidationError {
 	// Parse the bash command
-	bashParser := parser.NewBashParser()
+	bashParser := parser.NewBashParser(d.parserOpts...)
 
 	result, err := bashParser.Parse(bashCtx.GetCommand())
 	if err != nil {
@@ -327,6 +475,41 @@ func (d *Dispatcher) validateBashFileWrites(
 		return nil
 	}
 
+	var depthErrors []*ValidationError
+
+	if result.DepthExceeded {
+		d.logger.Info("bash command nests scripts deeper than the maximum depth")
+
+		depthErrors = append(depthErrors, createNestingDepthError())
+	}
+
+	return append(depthErrors, d.validateBashFileWrites(ctx, bashCtx, result)...)
+}
+
+// nestingDepthValidator is the validator name for commands nesting shell
+// scripts deeper than the maximum depth.
+const nestingDepthValidator = "shell-nesting-depth"
+
+// createNestingDepthError creates the validation error of a Bash command
+// nesting shell scripts deeper than the maximum depth.
+func createNestingDepthError() *ValidationError {
+	return &ValidationError{
+		Validator: nestingDepthValidator,
+		Message: "Blocked: the command nests shell scripts (bash -c, eval) deeper than " +
+			"max_shell_nesting_depth, so they cannot be validated",
+		ShouldBlock: true,
+		Reference:   validator.RefShellNestingDepth,
+		FixHint:     validator.GetSuggestion(validator.RefShellNestingDepth),
+	}
+}
+
+// validateBashFileWrites validates the file writes of a parsed Bash command
+// as synthetic Write operations.
+func (d *Dispatcher) validateBashFileWrites(
+	ctx context.Context,
+	bashCtx *hook.Context,
+	result *parser.ParseResult,
+) []*ValidationError {
 	// No file writes found
 	if len(result.FileWrites) == 0 {
 		return nil
@@ -345,7 +528,7 @@ func (d *Dispatcher) validateBashFileWrites(
 			ToolName:  hook.ToolTypeWrite,
 			ToolInput: hook.ToolInput{
 				FilePath: fw.Path,
-				Content:  fw.Content,
+				Content:  d.fileWriteContent(bashCtx, &fw),
 			},
 		}
 
@@ -354,14 +537,71 @@ func (d *Dispatcher) validateBashFileWrites(
 			"operation", fw.Operation,
 		)
 
-		// Run validators on the synthetic context
-		errors := d.runValidators(ctx, syntheticCtx)
-		allErrors = append(allErrors, errors...)
+		// Run validators on the synthetic context. Its tool input is not the
+		// one Claude Code runs, so it cannot be rewritten.
+		for _, verr := range d.runValidators(ctx, syntheticCtx) {
+			if !verr.IsRewrite() {
+				allErrors = append(allErrors, verr)
+			}
+		}
 	}
 
 	return allErrors
 }
 
+// maxEditedFileSize is the size of the largest file whose content after an
+// in-place edit is computed.
+const maxEditedFileSize = 1 << 20
+
+// fileWriteContent returns the content a Bash file write leaves in the file:
+// the written content, or for in-place edits like sed -i the current content
+// of the file with the edit applied. Empty if it is not known.
+func (d *Dispatcher) fileWriteContent(bashCtx *hook.Context, fw *parser.FileWrite) string {
+	if fw.Edit == nil {
+		return fw.Content
+	}
+
+	path := resolveWritePath(bashCtx, fw)
+
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > maxEditedFileSize {
+		return ""
+	}
+
+	// Path comes from the validated command, read only to lint the result
+	original, err := os.ReadFile(path) //nolint:gosec // G304: path is from the command
+	if err != nil {
+		d.logger.Debug("failed to read file edited in place",
+			"file", path,
+			"error", err,
+		)
+
+		return ""
+	}
+
+	return fw.Edit.Apply(string(original))
+}
+
+// resolveWritePath returns the path of a Bash file write, resolving relative
+// paths against the working directory of its command and of the session.
+func resolveWritePath(bashCtx *hook.Context, fw *parser.FileWrite) string {
+	if filepath.IsAbs(fw.Path) {
+		return fw.Path
+	}
+
+	dir := fw.WorkingDirectory
+	if !filepath.IsAbs(dir) {
+		base := bashCtx.Cwd
+		if base == "" {
+			base, _ = os.Getwd()
+		}
+
+		dir = filepath.Join(base, dir)
+	}
+
+	return filepath.Join(dir, fw.Path)
+}
+
 // checkUnpoisonAcknowledgment checks if the current command contains an unpoison token
 // that acknowledges all poison codes. If all codes are acknowledged, it unpoisons
 // the session and returns true. Otherwise, returns false.
@@ -456,38 +696,77 @@ func (d *Dispatcher) logSessionAuditEntry(
 	source string,
 	poisonMessage string,
 ) {
+	entry := newSessionAuditEntry(hookCtx, action)
+	entry.PoisonCodes = codes
+	entry.Source = source
+	entry.PoisonMessage = poisonMessage
+
+	d.writeSessionAuditEntry(entry)
+}
+
+// logRewriteAuditEntry logs the rewrite of a tool input if audit logging is
+// enabled. Unlike poison entries, rewrites are logged without session tracking.
+func (d *Dispatcher) logRewriteAuditEntry(hookCtx *hook.Context, rewrite *ValidationError) {
+	entry := newSessionAuditEntry(hookCtx, session.AuditActionRewrite)
+	entry.Validator = shortName(rewrite.Validator)
+	entry.RewriteMessage = rewrite.Message
+	entry.Rewrite = rewrittenFields(hookCtx, rewrite.UpdatedInput)
+
+	d.writeSessionAuditEntry(entry)
+}
+
+// writeSessionAuditEntry writes a session audit entry if audit logging is enabled.
+func (d *Dispatcher) writeSessionAuditEntry(entry *session.AuditEntry) {
 	if d.sessionAuditLogger == nil || !d.sessionAuditLogger.IsEnabled() {
 		return
 	}
 
-	// Truncate command to prevent sensitive data leakage
-	command := hookCtx.GetCommand()
-
-	const maxCommandLength = 500
-	if len(command) > maxCommandLength {
-		command = command[:maxCommandLength] + "..."
+	if err := d.sessionAuditLogger.Log(entry); err != nil {
+		d.logger.Error("failed to log session audit entry",
+			"action", entry.Action.String(),
+			"error", err,
+		)
 	}
+}
 
+// newSessionAuditEntry creates a session audit entry for the hook context.
+func newSessionAuditEntry(hookCtx *hook.Context, action session.AuditAction) *session.AuditEntry {
 	// Get working directory
 	workingDir, _ := os.Getwd()
 
-	entry := &session.AuditEntry{
-		Timestamp:     time.Now(),
-		Action:        action,
-		SessionID:     hookCtx.SessionID,
-		PoisonCodes:   codes,
-		Source:        source,
-		Command:       command,
-		PoisonMessage: poisonMessage,
-		WorkingDir:    workingDir,
+	return &session.AuditEntry{
+		Timestamp:  time.Now(),
+		Action:     action,
+		SessionID:  hookCtx.SessionID,
+		Command:    truncateAuditValue(hookCtx.GetCommand()),
+		WorkingDir: workingDir,
 	}
+}
 
-	if err := d.sessionAuditLogger.Log(entry); err != nil {
-		d.logger.Error("failed to log session audit entry",
-			"action", action.String(),
-			"error", err,
-		)
+// truncateAuditValue truncates a logged value to prevent sensitive data leakage.
+func truncateAuditValue(value string) string {
+	const maxAuditValueLength = 500
+	if len(value) > maxAuditValueLength {
+		return value[:maxAuditValueLength] + "..."
 	}
+
+	return value
+}
+
+// rewrittenFields returns the string fields of the updated input that differ
+// from the tool input of the hook context, truncated.
+func rewrittenFields(hookCtx *hook.Context, updatedInput map[string]any) map[string]string {
+	original := hookCtx.GetRawToolInput()
+
+	fields := make(map[string]string)
+
+	for key, value := range updatedInput {
+		if s, ok := value.(string); ok && original[key] != s {
+			fields[key] = truncateAuditValue(s)
+		}
+	}
+
+	return fields
 }
 
 // ShouldBlock returns true if any validation error should block the operation.
@@ -501,20 +780,71 @@ func ShouldBlock(errors []*ValidationError) bool {
 	return false
 }
 
-// categorizeErrors separates validation errors into blocking errors and warnings.
-func categorizeErrors(errors []*ValidationError) (blocking, warnings []*ValidationError) {
+// ShouldAsk returns true if any validation error asks for confirmation.
+// Blocking errors take precedence, so callers should check ShouldBlock first.
+func ShouldAsk(errors []*ValidationError) bool {
+	for _, err := range errors {
+		if err.ShouldAsk {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasFeedback returns true if any validation error is PostToolUse feedback
+// on a written file.
+func HasFeedback(errors []*ValidationError) bool {
+	for _, err := range errors {
+		if err.Feedback {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AppliedRewrite returns the validation error whose updated input replaces
+// the tool input: the first rewrite, unless the operation is blocked. Later
+// rewrites are reported but not applied, as they rewrite the original input.
+func AppliedRewrite(errors []*ValidationError) *ValidationError {
+	if ShouldBlock(errors) {
+		return nil
+	}
+
+	for _, err := range errors {
+		if err.IsRewrite() {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// categorizeErrors separates validation errors into blocking errors,
+// confirmation requests, rewrites and warnings.
+func categorizeErrors(
+	errors []*ValidationError,
+) (blocking, asks, rewrites, warnings []*ValidationError) {
 	blockingErrors := make([]*ValidationError, 0)
+	askErrors := make([]*ValidationError, 0)
+	rewriteErrors := make([]*ValidationError, 0)
 	warningErrors := make([]*ValidationError, 0)
 
 	for _, err := range errors {
-		if err.ShouldBlock {
+		switch {
+		case err.ShouldBlock:
 			blockingErrors = append(blockingErrors, err)
-		} else {
+		case err.ShouldAsk:
+			askErrors = append(askErrors, err)
+		case err.IsRewrite():
+			rewriteErrors = append(rewriteErrors, err)
+		default:
 			warningErrors = append(warningErrors, err)
 		}
 	}
 
-	return blockingErrors, warningErrors
+	return blockingErrors, askErrors, rewriteErrors, warningErrors
 }
 
 // formatErrorList formats a list of errors with a header.
@@ -566,6 +896,16 @@ func formatErrorMetadata(builder *strings.Builder, err *ValidationError) {
 		builder.WriteString("\n")
 	}
 
+	for _, field := range []string{"command", "file_path", "path"} {
+		if value, ok := err.UpdatedInput[field].(string); ok {
+			builder.WriteString("   Rewritten " + strings.ReplaceAll(field, "_", " ") + ": ")
+			builder.WriteString(value)
+			builder.WriteString("\n")
+
+			break
+		}
+	}
+
 	if err.Reference != "" {
 		builder.WriteString("   Reference: ")
 		builder.WriteString(string(err.Reference))
@@ -598,9 +938,11 @@ func FormatErrors(errors []*ValidationError) string {
 		return ""
 	}
 
-	blockingErrors, warnings := categorizeErrors(errors)
+	blockingErrors, asks, rewrites, warnings := categorizeErrors(errors)
 
 	result := formatErrorList("❌ Validation Failed:", blockingErrors)
+	result += formatErrorList("❓ Confirmation Required:", asks)
+	result += formatErrorList(rewriteHeader, rewrites)
 	result += formatErrorList("⚠️  Warnings:", warnings)
 
 	return result
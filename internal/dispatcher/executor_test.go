@@ -137,6 +137,18 @@ var _ = Describe("Executor", func() {
 				Expect(result[0].ShouldBlock).To(BeFalse())
 				Expect(result[1].ShouldBlock).To(BeTrue())
 			})
+
+			It("should propagate confirmation requests", func() {
+				validators := []validator.Validator{
+					newTestValidator("v1", validator.CategoryCPU, validator.Ask("confirm")),
+				}
+
+				result := executor.Execute(context.Background(), hookCtx, validators)
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].ShouldBlock).To(BeFalse())
+				Expect(result[0].ShouldAsk).To(BeTrue())
+				Expect(dispatcher.ShouldAsk(result)).To(BeTrue())
+			})
 		})
 
 		Context("with context cancellation", func() {
@@ -0,0 +1,93 @@
+package dispatcher_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// writeRecorder is a test validator that records the Write contexts it sees.
+type writeRecorder struct {
+	mu     sync.Mutex
+	inputs []hook.ToolInput
+}
+
+func (*writeRecorder) Name() string {
+	return "file.recorder"
+}
+
+func (v *writeRecorder) Validate(_ context.Context, hookCtx *hook.Context) *validator.Result {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.inputs = append(v.inputs, hookCtx.ToolInput)
+
+	return validator.Pass()
+}
+
+func (*writeRecorder) Category() validator.ValidatorCategory {
+	return validator.CategoryCPU
+}
+
+var _ = Describe("Dispatcher Bash file writes", func() {
+	var (
+		disp     *dispatcher.Dispatcher
+		recorder *writeRecorder
+		tempDir  string
+	)
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+		recorder = &writeRecorder{}
+
+		reg := validator.NewRegistry()
+		reg.Register(recorder, validator.ToolTypeIs(hook.ToolTypeWrite))
+
+		disp = dispatcher.NewDispatcher(reg, logger.NewNoOpLogger())
+	})
+
+	dispatch := func(command string) {
+		disp.Dispatch(context.Background(), &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			Cwd:       tempDir,
+			ToolInput: hook.ToolInput{Command: command},
+		})
+	}
+
+	It("validates the content of files written by redirects", func() {
+		dispatch("cat > notes.md <<'EOF'\n# Notes\nEOF")
+
+		Expect(recorder.inputs).To(ConsistOf(hook.ToolInput{FilePath: "notes.md", Content: "# Notes\n"}))
+	})
+
+	It("validates the content of files edited in place by sed", func() {
+		Expect(os.MkdirAll(filepath.Join(tempDir, "docs"), 0o755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(tempDir, "docs", "README.md"), []byte("# Old title\n"), 0o600)).
+			To(Succeed())
+
+		dispatch("cd docs && sed -i 's/Old/New/' README.md")
+
+		Expect(recorder.inputs).To(ConsistOf(hook.ToolInput{FilePath: "README.md", Content: "# New title\n"}))
+	})
+
+	It("validates files edited in place without content when the edit is unknown", func() {
+		Expect(os.WriteFile(filepath.Join(tempDir, "README.md"), []byte("# Title\n"), 0o600)).To(Succeed())
+
+		dispatch("sed -i '/Title/d' README.md missing.md")
+
+		Expect(recorder.inputs).To(ConsistOf(
+			hook.ToolInput{FilePath: "README.md"},
+			hook.ToolInput{FilePath: "missing.md"},
+		))
+	})
+})
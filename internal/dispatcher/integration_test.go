@@ -341,6 +341,40 @@ var _ = Describe("Dispatcher Exception Integration", func() {
 		})
 	})
 
+	Context("with an active stop hook", func() {
+		BeforeEach(func() {
+			reg = validator.NewRegistry()
+			reg.Register(
+				&mockBlockingValidator{name: "validate-stop", reference: "https://klaudiu.sh/TEST001"},
+				validator.EventTypeIs(hook.EventTypeStop),
+			)
+
+			disp = dispatcher.NewDispatcher(reg, log)
+		})
+
+		It("blocks the first stop", func() {
+			errs := disp.Dispatch(context.Background(), &hook.Context{
+				EventType: hook.EventTypeStop,
+			})
+
+			Expect(dispatcher.ShouldBlock(errs)).To(BeTrue())
+		})
+
+		It("reports the block as a system message when Claude is already continuing", func() {
+			errs := disp.Dispatch(context.Background(), &hook.Context{
+				EventType:      hook.EventTypeStop,
+				StopHookActive: true,
+			})
+
+			Expect(errs).To(HaveLen(1))
+			Expect(dispatcher.ShouldBlock(errs)).To(BeFalse())
+
+			resp := dispatcher.BuildHookResponse(hook.EventTypeStop, errs)
+			Expect(resp.Decision).To(BeEmpty())
+			Expect(resp.SystemMessage).To(ContainSubstring("validation blocked"))
+		})
+	})
+
 	Context("ShouldBlock helper", func() {
 		It("returns true when any error blocks", func() {
 			errors := []*dispatcher.ValidationError{
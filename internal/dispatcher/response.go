@@ -0,0 +1,166 @@
+package dispatcher
+
+import (
+	"strings"
+
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+const (
+	// systemMessagePrefix prefixes the user-facing summary in hook responses.
+	systemMessagePrefix = "klaudiush: "
+
+	// rewriteHeader is the header of the rewrites of the tool input.
+	rewriteHeader = "✏️  Rewritten:"
+)
+
+// BuildHookResponse maps validation errors onto Claude Code's JSON hook response.
+//
+// For PreToolUse, blocking errors become a "deny" permission decision with the
+// formatted errors as the reason, and confirmation requests (without blocking
+// errors) become an "ask" decision. For PostToolUse, UserPromptSubmit, Stop and
+// SubagentStop, blocking errors become a top-level "block" decision. Warnings are
+// reported as additional context where the event supports it so that they reach
+// the model, as are confirmation requests on events that cannot prompt the user.
+// SessionStart reports everything as additional context, and the remaining
+// events only support a user-facing system message. klaudiush never emits "allow" on its own, as that
+// would bypass Claude Code's permission prompt.
+//
+// A rewrite of a PreToolUse call that is not blocked is returned as the updated tool input,
+// alongside any "ask" decision, so Claude Code runs the rewritten call instead.
+//
+// Returns an empty response if there are no validation errors.
+func BuildHookResponse(eventType hook.EventType, errs []*ValidationError) *hook.Response {
+	resp := &hook.Response{}
+
+	if len(errs) == 0 {
+		return resp
+	}
+
+	blocking, asks, rewrites, warnings := categorizeErrors(errs)
+
+	reason := strings.TrimSpace(formatErrorList("❌ Validation Failed:", blocking))
+	askReason := strings.TrimSpace(formatErrorList("❓ Confirmation Required:", asks))
+	warningContext := joinNonEmpty(
+		strings.TrimSpace(formatErrorList(rewriteHeader, rewrites)),
+		strings.TrimSpace(formatErrorList("⚠️  Warnings:", warnings)),
+	)
+
+	resp.SystemMessage = buildSystemMessage(blocking, asks, rewrites, warnings)
+
+	switch eventType {
+	case hook.EventTypePreToolUse:
+		output := &hook.HookSpecificOutput{
+			HookEventName:     eventType.String(),
+			AdditionalContext: warningContext,
+		}
+
+		switch {
+		case len(blocking) > 0:
+			output.PermissionDecision = hook.PermissionDecisionDeny
+			output.PermissionDecisionReason = reason
+		case len(asks) > 0:
+			output.PermissionDecision = hook.PermissionDecisionAsk
+			output.PermissionDecisionReason = askReason
+		}
+
+		if rewrite := AppliedRewrite(errs); rewrite != nil {
+			output.UpdatedInput = rewrite.UpdatedInput
+		}
+
+		resp.HookSpecificOutput = output
+
+	case hook.EventTypePostToolUse, hook.EventTypeUserPromptSubmit:
+		if len(blocking) > 0 {
+			resp.Decision = hook.DecisionBlock
+			resp.Reason = reason
+		}
+
+		// These events cannot prompt the user, so confirmation requests are feedback
+		warningContext = joinNonEmpty(askReason, warningContext)
+
+		if warningContext != "" {
+			resp.HookSpecificOutput = &hook.HookSpecificOutput{
+				HookEventName:     eventType.String(),
+				AdditionalContext: warningContext,
+			}
+		}
+
+	case hook.EventTypeStop, hook.EventTypeSubagentStop:
+		// Blocking a stop makes Claude continue with the reason as instructions
+		if len(blocking) > 0 {
+			resp.Decision = hook.DecisionBlock
+			resp.Reason = reason
+		}
+
+		if nonBlocking := joinNonEmpty(askReason, warningContext); nonBlocking != "" {
+			resp.SystemMessage = nonBlocking
+		}
+
+	case hook.EventTypeSessionStart:
+		// Session start output is injected into the model context
+		resp.HookSpecificOutput = &hook.HookSpecificOutput{
+			HookEventName:     eventType.String(),
+			AdditionalContext: strings.TrimSpace(FormatErrors(errs)),
+		}
+
+	default:
+		// Events without decision control only support a user-facing message.
+		resp.SystemMessage = strings.TrimSpace(FormatErrors(errs))
+	}
+
+	return resp
+}
+
+// buildSystemMessage builds a short user-facing summary of the validation outcome.
+func buildSystemMessage(blocking, asks, rewrites, warnings []*ValidationError) string {
+	if len(blocking) > 0 {
+		return systemMessagePrefix + "blocked by " + joinValidatorNames(blocking)
+	}
+
+	if len(asks) > 0 {
+		return systemMessagePrefix + "confirmation requested by " + joinValidatorNames(asks)
+	}
+
+	if len(rewrites) > 0 {
+		return systemMessagePrefix + "rewritten by " + joinValidatorNames(rewrites)
+	}
+
+	if len(warnings) > 0 {
+		return systemMessagePrefix + "warnings from " + joinValidatorNames(warnings)
+	}
+
+	return ""
+}
+
+// joinValidatorNames returns a comma-separated list of unique short validator names.
+func joinValidatorNames(errs []*ValidationError) string {
+	names := make([]string, 0, len(errs))
+	seen := make(map[string]bool, len(errs))
+
+	for _, err := range errs {
+		name := shortName(err.Validator)
+		if seen[name] {
+			continue
+		}
+
+		seen[name] = true
+
+		names = append(names, name)
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// joinNonEmpty joins non-empty sections with a blank line.
+func joinNonEmpty(sections ...string) string {
+	nonEmpty := make([]string, 0, len(sections))
+
+	for _, section := range sections {
+		if section != "" {
+			nonEmpty = append(nonEmpty, section)
+		}
+	}
+
+	return strings.Join(nonEmpty, "\n\n")
+}
@@ -0,0 +1,253 @@
+package dispatcher_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+var _ = Describe("BuildHookResponse", func() {
+	var (
+		blockingErr *dispatcher.ValidationError
+		askErr      *dispatcher.ValidationError
+		warningErr  *dispatcher.ValidationError
+		rewriteErr  *dispatcher.ValidationError
+	)
+
+	BeforeEach(func() {
+		blockingErr = &dispatcher.ValidationError{
+			Validator:   "validate-commit",
+			Message:     "missing signoff",
+			ShouldBlock: true,
+		}
+		askErr = &dispatcher.ValidationError{
+			Validator: "validate-push",
+			Message:   "force-pushing rewrites history",
+			ShouldAsk: true,
+		}
+		warningErr = &dispatcher.ValidationError{
+			Validator:   "validate-markdown",
+			Message:     "formatting issues",
+			ShouldBlock: false,
+		}
+		rewriteErr = &dispatcher.ValidationError{
+			Validator:    "validate-push",
+			Message:      "pushing with lease",
+			UpdatedInput: map[string]any{"command": "git push --force-with-lease"},
+		}
+	})
+
+	It("returns an empty response when there are no errors", func() {
+		resp := dispatcher.BuildHookResponse(hook.EventTypePreToolUse, nil)
+		Expect(resp.IsEmpty()).To(BeTrue())
+	})
+
+	Context("PreToolUse", func() {
+		It("denies on blocking errors", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{blockingErr},
+			)
+
+			Expect(resp.HookSpecificOutput).NotTo(BeNil())
+			Expect(resp.HookSpecificOutput.HookEventName).To(Equal("PreToolUse"))
+			Expect(resp.HookSpecificOutput.PermissionDecision).
+				To(Equal(hook.PermissionDecisionDeny))
+			Expect(resp.HookSpecificOutput.PermissionDecisionReason).
+				To(ContainSubstring("missing signoff"))
+			Expect(resp.HookSpecificOutput.AdditionalContext).To(BeEmpty())
+			Expect(resp.SystemMessage).To(Equal("klaudiush: blocked by commit"))
+		})
+
+		It("reports warnings as additional context without a decision", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{warningErr},
+			)
+
+			Expect(resp.HookSpecificOutput).NotTo(BeNil())
+			Expect(resp.HookSpecificOutput.PermissionDecision).To(BeEmpty())
+			Expect(resp.HookSpecificOutput.AdditionalContext).
+				To(ContainSubstring("formatting issues"))
+			Expect(resp.SystemMessage).To(Equal("klaudiush: warnings from markdown"))
+		})
+
+		It("keeps warnings separate from the deny reason", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{blockingErr, warningErr},
+			)
+
+			Expect(resp.HookSpecificOutput.PermissionDecisionReason).
+				NotTo(ContainSubstring("formatting issues"))
+			Expect(resp.HookSpecificOutput.AdditionalContext).
+				To(ContainSubstring("formatting issues"))
+		})
+
+		It("asks for confirmation when nothing blocks", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{askErr, warningErr},
+			)
+
+			Expect(resp.HookSpecificOutput.PermissionDecision).
+				To(Equal(hook.PermissionDecisionAsk))
+			Expect(resp.HookSpecificOutput.PermissionDecisionReason).
+				To(ContainSubstring("force-pushing rewrites history"))
+			Expect(resp.HookSpecificOutput.AdditionalContext).
+				To(ContainSubstring("formatting issues"))
+			Expect(resp.SystemMessage).
+				To(Equal("klaudiush: confirmation requested by push"))
+		})
+
+		It("prefers deny over ask", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{askErr, blockingErr},
+			)
+
+			Expect(resp.HookSpecificOutput.PermissionDecision).
+				To(Equal(hook.PermissionDecisionDeny))
+		})
+
+		It("returns the rewritten tool input without a decision", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{rewriteErr, warningErr},
+			)
+
+			Expect(resp.HookSpecificOutput.PermissionDecision).To(BeEmpty())
+			Expect(resp.HookSpecificOutput.UpdatedInput).
+				To(HaveKeyWithValue("command", "git push --force-with-lease"))
+			Expect(resp.HookSpecificOutput.AdditionalContext).
+				To(ContainSubstring("pushing with lease"))
+			Expect(resp.HookSpecificOutput.AdditionalContext).
+				To(ContainSubstring("formatting issues"))
+			Expect(resp.SystemMessage).To(Equal("klaudiush: rewritten by push"))
+		})
+
+		It("asks for confirmation of the rewritten tool input", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{askErr, rewriteErr},
+			)
+
+			Expect(resp.HookSpecificOutput.PermissionDecision).
+				To(Equal(hook.PermissionDecisionAsk))
+			Expect(resp.HookSpecificOutput.UpdatedInput).NotTo(BeNil())
+		})
+
+		It("does not rewrite blocked tool calls", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{rewriteErr, blockingErr},
+			)
+
+			Expect(resp.HookSpecificOutput.PermissionDecision).
+				To(Equal(hook.PermissionDecisionDeny))
+			Expect(resp.HookSpecificOutput.UpdatedInput).To(BeNil())
+		})
+
+		It("never emits an allow decision", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePreToolUse,
+				[]*dispatcher.ValidationError{warningErr},
+			)
+
+			data, err := json.Marshal(resp)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring(`"allow"`))
+		})
+	})
+
+	Context("PostToolUse", func() {
+		It("uses a top-level block decision", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePostToolUse,
+				[]*dispatcher.ValidationError{blockingErr},
+			)
+
+			Expect(resp.Decision).To(Equal(hook.DecisionBlock))
+			Expect(resp.Reason).To(ContainSubstring("missing signoff"))
+			Expect(resp.HookSpecificOutput).To(BeNil())
+		})
+
+		It("reports confirmation requests as additional context", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypePostToolUse,
+				[]*dispatcher.ValidationError{askErr},
+			)
+
+			Expect(resp.Decision).To(BeEmpty())
+			Expect(resp.HookSpecificOutput).NotTo(BeNil())
+			Expect(resp.HookSpecificOutput.AdditionalContext).
+				To(ContainSubstring("force-pushing rewrites history"))
+		})
+	})
+
+	Context("UserPromptSubmit", func() {
+		It("blocks the prompt", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypeUserPromptSubmit,
+				[]*dispatcher.ValidationError{blockingErr},
+			)
+
+			Expect(resp.Decision).To(Equal(hook.DecisionBlock))
+			Expect(resp.Reason).To(ContainSubstring("missing signoff"))
+		})
+	})
+
+	Context("Stop", func() {
+		It("blocks stopping with the reason as instructions", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypeStop,
+				[]*dispatcher.ValidationError{blockingErr},
+			)
+
+			Expect(resp.Decision).To(Equal(hook.DecisionBlock))
+			Expect(resp.Reason).To(ContainSubstring("missing signoff"))
+			Expect(resp.HookSpecificOutput).To(BeNil())
+		})
+
+		It("reports warnings as a system message", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypeSubagentStop,
+				[]*dispatcher.ValidationError{warningErr},
+			)
+
+			Expect(resp.Decision).To(BeEmpty())
+			Expect(resp.SystemMessage).To(ContainSubstring("formatting issues"))
+		})
+	})
+
+	Context("SessionStart", func() {
+		It("injects results as additional context", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypeSessionStart,
+				[]*dispatcher.ValidationError{warningErr},
+			)
+
+			Expect(resp.HookSpecificOutput).NotTo(BeNil())
+			Expect(resp.HookSpecificOutput.HookEventName).To(Equal("SessionStart"))
+			Expect(resp.HookSpecificOutput.AdditionalContext).
+				To(ContainSubstring("formatting issues"))
+		})
+	})
+
+	Context("Notification", func() {
+		It("only sets a system message", func() {
+			resp := dispatcher.BuildHookResponse(
+				hook.EventTypeNotification,
+				[]*dispatcher.ValidationError{blockingErr},
+			)
+
+			Expect(resp.Decision).To(BeEmpty())
+			Expect(resp.HookSpecificOutput).To(BeNil())
+			Expect(resp.SystemMessage).To(ContainSubstring("missing signoff"))
+		})
+	})
+})
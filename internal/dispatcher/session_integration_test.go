@@ -1038,8 +1038,128 @@ EOF`,
 		// Should not have logged anything
 		Expect(auditLogger.entries).To(BeEmpty())
 	})
+
+	It("logs audit entry on rewrite without session tracking", func() {
+		reg.Register(
+			&mockRewriteValidator{name: "validate-git-push"},
+			validator.ToolTypeIs(hook.ToolTypeBash),
+		)
+
+		disp = dispatcher.NewDispatcherWithOptions(
+			reg,
+			log,
+			dispatcher.NewSequentialExecutor(log),
+			dispatcher.WithSessionAuditLogger(auditLogger),
+		)
+
+		hookCtx := &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			RawJSON:   `{"tool_input":{"command":"git push","description":"Push"}}`,
+			ToolInput: hook.ToolInput{
+				Command: "git push",
+			},
+		}
+
+		errs := disp.Dispatch(ctx, hookCtx)
+		Expect(dispatcher.AppliedRewrite(errs)).NotTo(BeNil())
+
+		Expect(auditLogger.entries).To(HaveLen(1))
+
+		entry := auditLogger.entries[0]
+		Expect(entry.Action).To(Equal(session.AuditActionRewrite))
+		Expect(entry.Command).To(Equal("git push"))
+		Expect(entry.Validator).To(Equal("git-push"))
+		Expect(entry.RewriteMessage).To(Equal("added lease"))
+		Expect(entry.Rewrite).To(Equal(map[string]string{
+			"command": "git push --force-with-lease",
+		}))
+	})
+
+	It("does not log a rewrite of a blocked tool call", func() {
+		reg.Register(
+			&mockRewriteValidator{name: "validate-git-push"},
+			validator.ToolTypeIs(hook.ToolTypeBash),
+		)
+		reg.Register(
+			&mockBlockingValidator{name: "test-blocker"},
+			validator.ToolTypeIs(hook.ToolTypeBash),
+		)
+
+		disp = dispatcher.NewDispatcherWithOptions(
+			reg,
+			log,
+			dispatcher.NewSequentialExecutor(log),
+			dispatcher.WithSessionAuditLogger(auditLogger),
+		)
+
+		hookCtx := &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			ToolInput: hook.ToolInput{
+				Command: "git push",
+			},
+		}
+
+		errs := disp.Dispatch(ctx, hookCtx)
+		Expect(dispatcher.AppliedRewrite(errs)).To(BeNil())
+		Expect(auditLogger.entries).To(BeEmpty())
+	})
+
+	It("discards a rewrite whose rewritten input is blocked", func() {
+		reg.Register(
+			&mockRewriteValidator{name: "validate-git-push"},
+			validator.ToolTypeIs(hook.ToolTypeBash),
+		)
+		reg.Register(
+			&mockBlockingValidator{name: "test-blocker"},
+			validator.CommandContains("--force-with-lease"),
+		)
+
+		disp = dispatcher.NewDispatcherWithOptions(
+			reg,
+			log,
+			dispatcher.NewSequentialExecutor(log),
+			dispatcher.WithSessionAuditLogger(auditLogger),
+		)
+
+		hookCtx := &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			RawJSON:   `{"tool_input":{"command":"git push","description":"Push"}}`,
+			ToolInput: hook.ToolInput{
+				Command: "git push",
+			},
+		}
+
+		errs := disp.Dispatch(ctx, hookCtx)
+		Expect(dispatcher.AppliedRewrite(errs)).To(BeNil())
+		Expect(dispatcher.ShouldBlock(errs)).To(BeTrue())
+		Expect(errs).To(HaveLen(1))
+		Expect(auditLogger.entries).To(BeEmpty())
+	})
 })
 
+// mockRewriteValidator is a test validator that rewrites the command.
+type mockRewriteValidator struct {
+	name string
+}
+
+func (v *mockRewriteValidator) Name() string {
+	return v.name
+}
+
+func (*mockRewriteValidator) Validate(_ context.Context, hookCtx *hook.Context) *validator.Result {
+	return validator.Rewrite("added lease", map[string]any{
+		"command":     hookCtx.GetCommand() + " --force-with-lease",
+		"description": "Push",
+	})
+}
+
+func (*mockRewriteValidator) Category() validator.ValidatorCategory {
+	return validator.CategoryCPU
+}
+
 // mockSessionAuditLogger is a mock implementation of SessionAuditLogger.
 type mockSessionAuditLogger struct {
 	entries []*session.AuditEntry
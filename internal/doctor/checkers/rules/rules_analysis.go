@@ -0,0 +1,98 @@
+package ruleschecker
+
+import (
+	"context"
+	"fmt"
+
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/config/factory"
+	"github.com/smykla-labs/klaudiush/internal/doctor"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+)
+
+// AnalysisChecker checks rules for conflicts, shadowing and unknown validator
+// types across rules.
+type AnalysisChecker struct {
+	loader    ConfigLoader
+	loaderErr error
+	findings  []rules.LintFinding
+}
+
+// NewAnalysisChecker creates a new rules analysis checker.
+func NewAnalysisChecker() *AnalysisChecker {
+	loader, err := internalconfig.NewKoanfLoader()
+	if err != nil {
+		return &AnalysisChecker{
+			loaderErr: err,
+		}
+	}
+
+	return &AnalysisChecker{
+		loader: loader,
+	}
+}
+
+// NewAnalysisCheckerWithLoader creates an AnalysisChecker with a custom loader (for testing).
+func NewAnalysisCheckerWithLoader(loader ConfigLoader) *AnalysisChecker {
+	return &AnalysisChecker{
+		loader: loader,
+	}
+}
+
+// Name returns the name of the check.
+func (*AnalysisChecker) Name() string {
+	return "Rules analysis"
+}
+
+// Category returns the category of the check.
+func (*AnalysisChecker) Category() doctor.Category {
+	return doctor.CategoryConfig
+}
+
+// GetFindings returns the findings of the last check.
+func (c *AnalysisChecker) GetFindings() []rules.LintFinding {
+	return c.findings
+}
+
+// Check analyzes the configured rules.
+func (c *AnalysisChecker) Check(_ context.Context) doctor.CheckResult {
+	c.findings = nil
+
+	if c.loaderErr != nil {
+		return doctor.FailError("Rules analysis",
+			fmt.Sprintf("config loader initialization failed: %v", c.loaderErr))
+	}
+
+	if !c.loader.HasProjectConfig() {
+		return doctor.Skip("Rules analysis", "No project config found")
+	}
+
+	// Invalid rules are reported by the rules validation check
+	cfg, err := c.loader.Load(nil)
+	if err != nil {
+		return doctor.Skip("Rules analysis", "Config load failed (see config check)")
+	}
+
+	if cfg.Rules == nil || len(cfg.Rules.Rules) == 0 {
+		return doctor.Pass("Rules analysis", "No rules configured")
+	}
+
+	enabled := factory.ConvertRuleConfigs(cfg.Rules.Rules)
+	c.findings = rules.Lint(enabled)
+
+	if len(c.findings) == 0 {
+		return doctor.Pass("Rules analysis", fmt.Sprintf("%d rule(s) analyzed", len(enabled)))
+	}
+
+	details := make([]string, 0, len(c.findings)+1)
+
+	for _, finding := range c.findings {
+		details = append(details, finding.Message)
+	}
+
+	details = append(details, "Run 'klaudiush debug rules --lint' for the full analysis")
+
+	return doctor.FailWarning("Rules analysis",
+		fmt.Sprintf("%d rule conflict(s) found", len(c.findings))).
+		WithDetails(details...)
+}
@@ -0,0 +1,113 @@
+package ruleschecker
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"go.uber.org/mock/gomock"
+
+	"github.com/smykla-labs/klaudiush/internal/doctor"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+)
+
+var _ = Describe("AnalysisChecker", func() {
+	var (
+		ctrl       *gomock.Controller
+		mockLoader *MockConfigLoader
+		checker    *AnalysisChecker
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		mockLoader = NewMockConfigLoader(ctrl)
+		checker = NewAnalysisCheckerWithLoader(mockLoader)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	rulesConfig := func(ruleConfigs ...config.RuleConfig) *config.Config {
+		return &config.Config{Rules: &config.RulesConfig{Rules: ruleConfigs}}
+	}
+
+	It("should return correct name and category", func() {
+		Expect(checker.Name()).To(Equal("Rules analysis"))
+		Expect(checker.Category()).To(Equal(doctor.CategoryConfig))
+	})
+
+	It("should skip when no project config exists", func() {
+		mockLoader.EXPECT().HasProjectConfig().Return(false)
+
+		result := checker.Check(ctx)
+
+		Expect(result.Status).To(Equal(doctor.StatusSkipped))
+	})
+
+	It("should skip when config load fails", func() {
+		mockLoader.EXPECT().HasProjectConfig().Return(true)
+		mockLoader.EXPECT().Load(nil).Return(nil, context.DeadlineExceeded)
+
+		result := checker.Check(ctx)
+
+		Expect(result.Status).To(Equal(doctor.StatusSkipped))
+		Expect(result.Message).To(ContainSubstring("Config load failed"))
+	})
+
+	It("should pass when rules do not conflict", func() {
+		mockLoader.EXPECT().HasProjectConfig().Return(true)
+		mockLoader.EXPECT().Load(nil).Return(rulesConfig(
+			config.RuleConfig{
+				Name:   "block-main",
+				Match:  &config.RuleMatchConfig{ValidatorType: "git.push", BranchPattern: "main"},
+				Action: &config.RuleActionConfig{Type: "block"},
+			},
+			config.RuleConfig{
+				Name:   "warn-docs",
+				Match:  &config.RuleMatchConfig{FilePattern: "docs/**"},
+				Action: &config.RuleActionConfig{Type: "warn"},
+			},
+		), nil)
+
+		result := checker.Check(ctx)
+
+		Expect(result.Status).To(Equal(doctor.StatusPass))
+		Expect(result.Message).To(Equal("2 rule(s) analyzed"))
+		Expect(checker.GetFindings()).To(BeEmpty())
+	})
+
+	It("should warn about conflicting rules", func() {
+		mockLoader.EXPECT().HasProjectConfig().Return(true)
+		mockLoader.EXPECT().Load(nil).Return(rulesConfig(
+			config.RuleConfig{
+				Name:     "allow-push",
+				Priority: 100,
+				Match:    &config.RuleMatchConfig{ValidatorType: "git.push"},
+				Action:   &config.RuleActionConfig{Type: "allow"},
+			},
+			config.RuleConfig{
+				Name:   "block-main",
+				Match:  &config.RuleMatchConfig{ValidatorType: "git.push", BranchPattern: "main"},
+				Action: &config.RuleActionConfig{Type: "block"},
+			},
+			config.RuleConfig{
+				Name:   "typo",
+				Match:  &config.RuleMatchConfig{ValidatorType: "git.comit"},
+				Action: &config.RuleActionConfig{Type: "block"},
+			},
+		), nil)
+
+		result := checker.Check(ctx)
+
+		Expect(result.IsWarning()).To(BeTrue())
+		Expect(result.Message).To(Equal("2 rule conflict(s) found"))
+		Expect(result.Details).To(ContainElement(ContainSubstring(`"block-main" never blocks`)))
+		Expect(result.Details).To(ContainElement(ContainSubstring(`"git.comit"`)))
+		Expect(checker.GetFindings()).To(HaveLen(2))
+		Expect(checker.GetFindings()[0].Kind).To(Equal(rules.LintUnknownValidatorType))
+	})
+})
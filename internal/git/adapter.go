@@ -80,3 +80,13 @@ func (a *RepositoryAdapter) GetBranchRemote(branch string) (string, error) {
 func (a *RepositoryAdapter) GetRemotes() (map[string]string, error) {
 	return a.repo.GetRemotes()
 }
+
+// GetAheadBehind returns how many commits the branch is ahead of and behind its upstream
+func (a *RepositoryAdapter) GetAheadBehind(branch string) (int, int, error) {
+	return a.repo.GetAheadBehind(branch)
+}
+
+// GetLastCommitAuthor returns the author of the HEAD commit
+func (a *RepositoryAdapter) GetLastCommitAuthor() (string, error) {
+	return a.repo.GetLastCommitAuthor()
+}
@@ -172,6 +172,17 @@ type mockRepository struct {
 	remotes          map[string]string
 	remotesErr       error
 	getRemotesCalled bool
+
+	// GetAheadBehind
+	ahead                int
+	behind               int
+	aheadBehindErr       error
+	getAheadBehindCalled bool
+
+	// GetLastCommitAuthor
+	lastCommitAuthor          string
+	lastCommitAuthorErr       error
+	getLastCommitAuthorCalled bool
 }
 
 func (m *mockRepository) IsInRepo() bool {
@@ -294,3 +305,13 @@ var _ = Describe("NewSDKRunnerForPath", func() {
 		})
 	})
 })
+
+func (m *mockRepository) GetAheadBehind(string) (int, int, error) {
+	m.getAheadBehindCalled = true
+	return m.ahead, m.behind, m.aheadBehindErr
+}
+
+func (m *mockRepository) GetLastCommitAuthor() (string, error) {
+	m.getLastCommitAuthorCalled = true
+	return m.lastCommitAuthor, m.lastCommitAuthorErr
+}
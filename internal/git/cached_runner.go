@@ -3,14 +3,21 @@ package git
 
 import (
 	"sync"
+	"sync/atomic"
 )
 
-// CachedRunner wraps a Runner and caches results for the duration of its lifetime.
+// CachedRunner wraps a Runner and caches results until Reset is called.
 // Use this for request-scoped caching where multiple validators need git status
 // without redundant calls. The cache is per-instance, not global.
 type CachedRunner struct {
 	delegate Runner
 
+	// cache holds the results since the last reset
+	cache atomic.Pointer[runnerCache]
+}
+
+// runnerCache holds the cached results of a CachedRunner.
+type runnerCache struct {
 	// Status-derived caches (all populated from a single status call)
 	statusOnce   sync.Once
 	staged       []string
@@ -45,6 +52,15 @@ type CachedRunner struct {
 	// Branch remote cache (per branch name)
 	branchRemoteMu    sync.RWMutex
 	branchRemoteCache map[string]branchRemoteCacheEntry
+
+	// Ahead/behind cache (per branch name)
+	aheadBehindMu    sync.Mutex
+	aheadBehindCache map[string]aheadBehindCacheEntry
+
+	// Last commit author cache
+	lastAuthorOnce sync.Once
+	lastAuthor     string
+	lastAuthorErr  error
 }
 
 type remoteURLCacheEntry struct {
@@ -57,100 +73,129 @@ type branchRemoteCacheEntry struct {
 	err    error
 }
 
+type aheadBehindCacheEntry struct {
+	ahead  int
+	behind int
+	err    error
+}
+
 // NewCachedRunner creates a new CachedRunner that wraps the given Runner.
-// The cached runner memoizes results for the duration of its lifetime.
+// The cached runner memoizes results until Reset is called.
 //
 //nolint:ireturn,nolintlint // Factory function intentionally returns interface
 func NewCachedRunner(delegate Runner) Runner {
-	return &CachedRunner{
-		delegate:          delegate,
+	c := &CachedRunner{delegate: delegate}
+	c.Reset()
+
+	return c
+}
+
+// Reset drops all cached results, so the next calls query the delegate again.
+// Long-lived owners (the daemon runtime) call it before every request, as the
+// repository state changes between requests. Calls in progress keep using the
+// results from before the reset.
+func (c *CachedRunner) Reset() {
+	c.cache.Store(&runnerCache{
 		remoteURLCache:    make(map[string]remoteURLCacheEntry),
 		branchRemoteCache: make(map[string]branchRemoteCacheEntry),
-	}
+		aheadBehindCache:  make(map[string]aheadBehindCacheEntry),
+	})
 }
 
 // IsInRepo checks if we're in a git repository.
 // Result is cached.
 func (c *CachedRunner) IsInRepo() bool {
-	c.isInRepoOnce.Do(func() {
-		c.isInRepo = c.delegate.IsInRepo()
+	cache := c.cache.Load()
+
+	cache.isInRepoOnce.Do(func() {
+		cache.isInRepo = c.delegate.IsInRepo()
 	})
 
-	return c.isInRepo
+	return cache.isInRepo
 }
 
 // ensureStatus populates all status-derived caches from a single delegate call.
-func (c *CachedRunner) ensureStatus() {
-	c.statusOnce.Do(func() {
-		c.staged, c.statusErr = c.delegate.GetStagedFiles()
-		if c.statusErr != nil {
+func (c *CachedRunner) ensureStatus(cache *runnerCache) {
+	cache.statusOnce.Do(func() {
+		cache.staged, cache.statusErr = c.delegate.GetStagedFiles()
+		if cache.statusErr != nil {
 			return
 		}
 
-		c.modified, c.statusErr = c.delegate.GetModifiedFiles()
-		if c.statusErr != nil {
+		cache.modified, cache.statusErr = c.delegate.GetModifiedFiles()
+		if cache.statusErr != nil {
 			return
 		}
 
-		c.untracked, c.statusErr = c.delegate.GetUntrackedFiles()
-		c.statusCached = c.statusErr == nil
+		cache.untracked, cache.statusErr = c.delegate.GetUntrackedFiles()
+		cache.statusCached = cache.statusErr == nil
 	})
 }
 
 // GetStagedFiles returns the list of staged files.
 // Result is cached along with modified and untracked files.
 func (c *CachedRunner) GetStagedFiles() ([]string, error) {
-	c.ensureStatus()
+	cache := c.cache.Load()
+
+	c.ensureStatus(cache)
 
-	if c.statusErr != nil && c.staged == nil {
-		return nil, c.statusErr
+	if cache.statusErr != nil && cache.staged == nil {
+		return nil, cache.statusErr
 	}
 
-	return c.staged, nil
+	return cache.staged, nil
 }
 
 // GetModifiedFiles returns the list of modified but unstaged files.
 // Result is cached along with staged and untracked files.
 func (c *CachedRunner) GetModifiedFiles() ([]string, error) {
-	c.ensureStatus()
+	cache := c.cache.Load()
 
-	if c.statusErr != nil && c.modified == nil {
-		return nil, c.statusErr
+	c.ensureStatus(cache)
+
+	if cache.statusErr != nil && cache.modified == nil {
+		return nil, cache.statusErr
 	}
 
-	return c.modified, nil
+	return cache.modified, nil
 }
 
 // GetUntrackedFiles returns the list of untracked files.
 // Result is cached along with staged and modified files.
 func (c *CachedRunner) GetUntrackedFiles() ([]string, error) {
-	c.ensureStatus()
+	cache := c.cache.Load()
+
+	c.ensureStatus(cache)
 
-	if c.statusErr != nil && c.untracked == nil {
-		return nil, c.statusErr
+	if cache.statusErr != nil && cache.untracked == nil {
+		return nil, cache.statusErr
 	}
 
-	return c.untracked, nil
+	return cache.untracked, nil
 }
 
 // GetRepoRoot returns the git repository root directory.
 // Result is cached.
 func (c *CachedRunner) GetRepoRoot() (string, error) {
-	c.repoRootOnce.Do(func() {
-		c.repoRoot, c.repoRootErr = c.delegate.GetRepoRoot()
+	cache := c.cache.Load()
+
+	cache.repoRootOnce.Do(func() {
+		cache.repoRoot, cache.repoRootErr = c.delegate.GetRepoRoot()
 	})
 
-	return c.repoRoot, c.repoRootErr
+	return cache.repoRoot, cache.repoRootErr
 }
 
 // GetCurrentBranch returns the current branch name.
 // Result is cached.
 func (c *CachedRunner) GetCurrentBranch() (string, error) {
-	c.branchOnce.Do(func() {
-		c.branch, c.branchErr = c.delegate.GetCurrentBranch()
+	cache := c.cache.Load()
+
+	cache.branchOnce.Do(func() {
+		cache.branch, cache.branchErr = c.delegate.GetCurrentBranch()
 	})
 
-	return c.branch, c.branchErr
+	return cache.branch, cache.branchErr
 }
 
 // GetRemoteURL returns the URL for the given remote.
@@ -158,27 +203,29 @@ func (c *CachedRunner) GetCurrentBranch() (string, error) {
 //
 //nolint:dupl // Similar pattern to GetBranchRemote but different types
 func (c *CachedRunner) GetRemoteURL(remote string) (string, error) {
+	cache := c.cache.Load()
+
 	// Check cache first with read lock
-	c.remoteURLMu.RLock()
-	entry, ok := c.remoteURLCache[remote]
-	c.remoteURLMu.RUnlock()
+	cache.remoteURLMu.RLock()
+	entry, ok := cache.remoteURLCache[remote]
+	cache.remoteURLMu.RUnlock()
 
 	if ok {
 		return entry.url, entry.err
 	}
 
 	// Cache miss - use write lock for fetch + store to prevent multiple calls
-	c.remoteURLMu.Lock()
-	defer c.remoteURLMu.Unlock()
+	cache.remoteURLMu.Lock()
+	defer cache.remoteURLMu.Unlock()
 
 	// Double-check after acquiring write lock (another goroutine may have populated)
-	if entry, ok := c.remoteURLCache[remote]; ok {
+	if entry, ok := cache.remoteURLCache[remote]; ok {
 		return entry.url, entry.err
 	}
 
 	// Fetch from delegate while holding write lock
 	url, err := c.delegate.GetRemoteURL(remote)
-	c.remoteURLCache[remote] = remoteURLCacheEntry{url: url, err: err}
+	cache.remoteURLCache[remote] = remoteURLCacheEntry{url: url, err: err}
 
 	return url, err
 }
@@ -188,27 +235,29 @@ func (c *CachedRunner) GetRemoteURL(remote string) (string, error) {
 //
 //nolint:dupl // Similar pattern to GetRemoteURL but different types
 func (c *CachedRunner) GetBranchRemote(branch string) (string, error) {
+	cache := c.cache.Load()
+
 	// Check cache first with read lock
-	c.branchRemoteMu.RLock()
-	entry, ok := c.branchRemoteCache[branch]
-	c.branchRemoteMu.RUnlock()
+	cache.branchRemoteMu.RLock()
+	entry, ok := cache.branchRemoteCache[branch]
+	cache.branchRemoteMu.RUnlock()
 
 	if ok {
 		return entry.remote, entry.err
 	}
 
 	// Cache miss - use write lock for fetch + store to prevent multiple calls
-	c.branchRemoteMu.Lock()
-	defer c.branchRemoteMu.Unlock()
+	cache.branchRemoteMu.Lock()
+	defer cache.branchRemoteMu.Unlock()
 
 	// Double-check after acquiring write lock (another goroutine may have populated)
-	if entry, ok := c.branchRemoteCache[branch]; ok {
+	if entry, ok := cache.branchRemoteCache[branch]; ok {
 		return entry.remote, entry.err
 	}
 
 	// Fetch from delegate while holding write lock
 	rem, err := c.delegate.GetBranchRemote(branch)
-	c.branchRemoteCache[branch] = branchRemoteCacheEntry{remote: rem, err: err}
+	cache.branchRemoteCache[branch] = branchRemoteCacheEntry{remote: rem, err: err}
 
 	return rem, err
 }
@@ -216,11 +265,44 @@ func (c *CachedRunner) GetBranchRemote(branch string) (string, error) {
 // GetRemotes returns the list of all remotes with their URLs.
 // Result is cached.
 func (c *CachedRunner) GetRemotes() (map[string]string, error) {
-	c.remotesOnce.Do(func() {
-		c.remotes, c.remotesErr = c.delegate.GetRemotes()
+	cache := c.cache.Load()
+
+	cache.remotesOnce.Do(func() {
+		cache.remotes, cache.remotesErr = c.delegate.GetRemotes()
+	})
+
+	return cache.remotes, cache.remotesErr
+}
+
+// GetAheadBehind returns how many commits the branch is ahead of and behind
+// its upstream branch.
+// Results are cached per branch name.
+func (c *CachedRunner) GetAheadBehind(branch string) (int, int, error) {
+	cache := c.cache.Load()
+
+	cache.aheadBehindMu.Lock()
+	defer cache.aheadBehindMu.Unlock()
+
+	if entry, ok := cache.aheadBehindCache[branch]; ok {
+		return entry.ahead, entry.behind, entry.err
+	}
+
+	ahead, behind, err := c.delegate.GetAheadBehind(branch)
+	cache.aheadBehindCache[branch] = aheadBehindCacheEntry{ahead: ahead, behind: behind, err: err}
+
+	return ahead, behind, err
+}
+
+// GetLastCommitAuthor returns the author of the HEAD commit.
+// Result is cached.
+func (c *CachedRunner) GetLastCommitAuthor() (string, error) {
+	cache := c.cache.Load()
+
+	cache.lastAuthorOnce.Do(func() {
+		cache.lastAuthor, cache.lastAuthorErr = c.delegate.GetLastCommitAuthor()
 	})
 
-	return c.remotes, c.remotesErr
+	return cache.lastAuthor, cache.lastAuthorErr
 }
 
 // Ensure CachedRunner implements Runner.
@@ -342,6 +342,34 @@ var _ = Describe("CachedRunner", func() {
 		})
 	})
 
+	Describe("Reset", func() {
+		It("queries the delegate again after a reset", func() {
+			gomock.InOrder(
+				mockRunner.EXPECT().GetCurrentBranch().Return("main", nil),
+				mockRunner.EXPECT().GetCurrentBranch().Return("feature", nil),
+			)
+			mockRunner.EXPECT().GetStagedFiles().Return(nil, nil).Times(2)
+			mockRunner.EXPECT().GetModifiedFiles().Return(nil, nil).Times(2)
+			mockRunner.EXPECT().GetUntrackedFiles().Return(nil, nil).Times(2)
+
+			branch, err := cached.GetCurrentBranch()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(branch).To(Equal("main"))
+
+			_, err = cached.GetStagedFiles()
+			Expect(err).NotTo(HaveOccurred())
+
+			cached.(*git.CachedRunner).Reset()
+
+			branch, err = cached.GetCurrentBranch()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(branch).To(Equal("feature"))
+
+			_, err = cached.GetStagedFiles()
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
 	Describe("Concurrent access", func() {
 		It("handles concurrent calls to IsInRepo", func() {
 			mockRunner.EXPECT().IsInRepo().Return(true).Times(1)
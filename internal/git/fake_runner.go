@@ -12,6 +12,9 @@ type FakeRunner struct {
 	Remotes        map[string]string
 	CurrentBranch  string
 	BranchRemotes  map[string]string
+	Ahead          map[string]int
+	Behind         map[string]int
+	LastAuthor     string
 	Err            error
 }
 
@@ -31,7 +34,10 @@ func NewFakeRunner() *FakeRunner {
 		BranchRemotes: map[string]string{
 			"main": "origin",
 		},
-		Err: nil,
+		Ahead:      map[string]int{},
+		Behind:     map[string]int{},
+		LastAuthor: "Mock User <mock@example.com>",
+		Err:        nil,
 	}
 }
 
@@ -120,6 +126,29 @@ func (f *FakeRunner) GetRemotes() (map[string]string, error) {
 	return f.Remotes, nil
 }
 
+// GetAheadBehind returns how many commits the branch is ahead of and behind
+// its upstream branch. Branches without a tracking remote have no upstream.
+func (f *FakeRunner) GetAheadBehind(branch string) (int, int, error) {
+	if f.Err != nil {
+		return 0, 0, f.Err
+	}
+
+	if _, ok := f.BranchRemotes[branch]; !ok {
+		return 0, 0, &FakeRunnerError{Msg: "branch has no upstream"}
+	}
+
+	return f.Ahead[branch], f.Behind[branch], nil
+}
+
+// GetLastCommitAuthor returns the author of the HEAD commit.
+func (f *FakeRunner) GetLastCommitAuthor() (string, error) {
+	if f.Err != nil {
+		return "", f.Err
+	}
+
+	return f.LastAuthor, nil
+}
+
 // FakeRunnerError is a simple error type for testing.
 type FakeRunnerError struct {
 	Msg string
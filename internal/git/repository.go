@@ -8,6 +8,7 @@ import (
 	"github.com/cockroachdb/errors"
 	"github.com/go-git/go-git/v6"
 	"github.com/go-git/go-git/v6/plumbing"
+	"github.com/go-git/go-git/v6/plumbing/object"
 )
 
 // gitEnvVarsToUnset lists git environment variables that must be cleared before
@@ -66,6 +67,13 @@ type Repository interface {
 
 	// GetRemotes returns the list of all remotes with their URLs
 	GetRemotes() (map[string]string, error)
+
+	// GetAheadBehind returns how many commits the given branch is ahead of
+	// and behind its upstream branch
+	GetAheadBehind(branch string) (ahead, behind int, err error)
+
+	// GetLastCommitAuthor returns the author of the HEAD commit as "Name <email>"
+	GetLastCommitAuthor() (string, error)
 }
 
 // SDKRepository implements Repository using go-git SDK
@@ -304,3 +312,118 @@ func (r *SDKRepository) GetRemotes() (map[string]string, error) {
 
 	return result, nil
 }
+
+// GetAheadBehind returns how many commits the branch is ahead of and behind
+// its upstream branch
+func (r *SDKRepository) GetAheadBehind(branch string) (int, int, error) {
+	local, err := r.repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return 0, 0, errors.Wrapf(ErrBranchNotFound, "branch %q", branch)
+		}
+
+		return 0, 0, errors.Wrap(err, "failed to lookup branch")
+	}
+
+	cfg, err := r.repo.Config()
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to get config")
+	}
+
+	branchCfg, ok := cfg.Branches[branch]
+	if !ok || branchCfg.Remote == "" || branchCfg.Merge == "" {
+		return 0, 0, errors.Wrapf(ErrNoTracking, "branch %q", branch)
+	}
+
+	upstreamName := plumbing.NewRemoteReferenceName(branchCfg.Remote, branchCfg.Merge.Short())
+	if branchCfg.Remote == "." {
+		upstreamName = branchCfg.Merge
+	}
+
+	upstream, err := r.repo.Reference(upstreamName, true)
+	if err != nil {
+		return 0, 0, errors.Wrapf(ErrNoTracking, "upstream %q of branch %q", upstreamName, branch)
+	}
+
+	localCommit, err := r.repo.CommitObject(local.Hash())
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to get commit %s", local.Hash())
+	}
+
+	upstreamCommit, err := r.repo.CommitObject(upstream.Hash())
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to get commit %s", upstream.Hash())
+	}
+
+	bases, err := localCommit.MergeBase(upstreamCommit)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "failed to compute merge base")
+	}
+
+	baseHashes := make([]plumbing.Hash, 0, len(bases))
+	for _, base := range bases {
+		baseHashes = append(baseHashes, base.Hash)
+	}
+
+	localCommits, err := commitsUntil(localCommit, baseHashes)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	upstreamCommits, err := commitsUntil(upstreamCommit, baseHashes)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	ahead, behind := 0, 0
+
+	for hash := range localCommits {
+		if !upstreamCommits[hash] {
+			ahead++
+		}
+	}
+
+	for hash := range upstreamCommits {
+		if !localCommits[hash] {
+			behind++
+		}
+	}
+
+	return ahead, behind, nil
+}
+
+// commitsUntil returns the set of commits reachable from the given commit
+// without walking past the merge bases
+func commitsUntil(commit *object.Commit, bases []plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	seen := make(map[plumbing.Hash]bool)
+
+	err := object.NewCommitPreorderIter(commit, nil, bases).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to walk history")
+	}
+
+	return seen, nil
+}
+
+// GetLastCommitAuthor returns the author of the HEAD commit as "Name <email>"
+func (r *SDKRepository) GetLastCommitAuthor() (string, error) {
+	head, err := r.repo.Head()
+	if err != nil {
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return "", ErrNoHead
+		}
+
+		return "", errors.Wrap(err, "failed to get HEAD")
+	}
+
+	commit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get HEAD commit")
+	}
+
+	return commit.Author.Name + " <" + commit.Author.Email + ">", nil
+}
@@ -701,3 +701,129 @@ var _ = Describe("DiscoverRepository with linked worktrees", func() {
 		Expect(url).To(Equal("https://github.com/upstream/repo.git"))
 	})
 })
+
+var _ = Describe("SDKRepository upstream and history", func() {
+	var (
+		tempDir string
+		repo    *git.Repository
+		branch  string
+		commits []plumbing.Hash
+		err     error
+	)
+
+	testAuthor := &object.Signature{
+		Name:  "Test User",
+		Email: "test@klaudiu.sh",
+	}
+
+	setRef := func(name plumbing.ReferenceName, hash plumbing.Hash) {
+		Expect(repo.Storer.SetReference(plumbing.NewHashReference(name, hash))).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		tempDir, err = os.MkdirTemp("", "upstream-repo-*")
+		Expect(err).NotTo(HaveOccurred())
+
+		repo, err = git.PlainInit(tempDir, false)
+		Expect(err).NotTo(HaveOccurred())
+
+		worktree, wtErr := repo.Worktree()
+		Expect(wtErr).NotTo(HaveOccurred())
+
+		commits = nil
+
+		for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+			Expect(os.WriteFile(filepath.Join(tempDir, name), []byte(name), 0o644)).To(Succeed())
+
+			_, err = worktree.Add(name)
+			Expect(err).NotTo(HaveOccurred())
+
+			hash, commitErr := worktree.Commit("Add "+name, &git.CommitOptions{Author: testAuthor})
+			Expect(commitErr).NotTo(HaveOccurred())
+
+			commits = append(commits, hash)
+		}
+
+		head, headErr := repo.Head()
+		Expect(headErr).NotTo(HaveOccurred())
+		branch = head.Name().Short()
+
+		cfg, cfgErr := repo.Config()
+		Expect(cfgErr).NotTo(HaveOccurred())
+		cfg.Branches[branch] = &config.Branch{
+			Name:   branch,
+			Remote: "origin",
+			Merge:  head.Name(),
+		}
+		Expect(repo.SetConfig(cfg)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(tempDir)
+	})
+
+	It("should count commits ahead of upstream", func() {
+		setRef(plumbing.NewRemoteReferenceName("origin", branch), commits[0])
+
+		sdkRepo, openErr := internalgit.OpenRepository(tempDir)
+		Expect(openErr).NotTo(HaveOccurred())
+
+		ahead, behind, abErr := sdkRepo.GetAheadBehind(branch)
+		Expect(abErr).NotTo(HaveOccurred())
+		Expect(ahead).To(Equal(2))
+		Expect(behind).To(Equal(0))
+	})
+
+	It("should count commits behind upstream", func() {
+		setRef(plumbing.NewRemoteReferenceName("origin", branch), commits[2])
+		setRef(plumbing.NewBranchReferenceName(branch), commits[1])
+
+		sdkRepo, openErr := internalgit.OpenRepository(tempDir)
+		Expect(openErr).NotTo(HaveOccurred())
+
+		ahead, behind, abErr := sdkRepo.GetAheadBehind(branch)
+		Expect(abErr).NotTo(HaveOccurred())
+		Expect(ahead).To(Equal(0))
+		Expect(behind).To(Equal(1))
+	})
+
+	It("should count diverged commits down to the merge base", func() {
+		setRef(plumbing.NewRemoteReferenceName("origin", branch), commits[2])
+
+		worktree, wtErr := repo.Worktree()
+		Expect(wtErr).NotTo(HaveOccurred())
+		Expect(worktree.Reset(&git.ResetOptions{Commit: commits[1], Mode: git.HardReset})).
+			To(Succeed())
+
+		Expect(os.WriteFile(filepath.Join(tempDir, "d.txt"), []byte("d"), 0o644)).To(Succeed())
+		_, err = worktree.Add("d.txt")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = worktree.Commit("Add d.txt", &git.CommitOptions{Author: testAuthor})
+		Expect(err).NotTo(HaveOccurred())
+
+		sdkRepo, openErr := internalgit.OpenRepository(tempDir)
+		Expect(openErr).NotTo(HaveOccurred())
+
+		ahead, behind, abErr := sdkRepo.GetAheadBehind(branch)
+		Expect(abErr).NotTo(HaveOccurred())
+		Expect(ahead).To(Equal(1))
+		Expect(behind).To(Equal(1))
+	})
+
+	It("should return ErrNoTracking without an upstream", func() {
+		sdkRepo, openErr := internalgit.OpenRepository(tempDir)
+		Expect(openErr).NotTo(HaveOccurred())
+
+		_, _, abErr := sdkRepo.GetAheadBehind(branch)
+		Expect(abErr).To(MatchError(internalgit.ErrNoTracking))
+	})
+
+	It("should return the last commit author", func() {
+		sdkRepo, openErr := internalgit.OpenRepository(tempDir)
+		Expect(openErr).NotTo(HaveOccurred())
+
+		author, authorErr := sdkRepo.GetLastCommitAuthor()
+		Expect(authorErr).NotTo(HaveOccurred())
+		Expect(author).To(Equal("Test User <test@klaudiu.sh>"))
+	})
+})
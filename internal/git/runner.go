@@ -30,4 +30,11 @@ type Runner interface {
 
 	// GetRemotes returns the list of all remotes with their URLs
 	GetRemotes() (map[string]string, error)
+
+	// GetAheadBehind returns how many commits the given branch is ahead of
+	// and behind its upstream branch
+	GetAheadBehind(branch string) (ahead, behind int, err error)
+
+	// GetLastCommitAuthor returns the author of the HEAD commit as "Name <email>"
+	GetLastCommitAuthor() (string, error)
 }
@@ -39,6 +39,22 @@ func (m *MockRunner) EXPECT() *MockRunnerMockRecorder {
 	return m.recorder
 }
 
+// GetAheadBehind mocks base method.
+func (m *MockRunner) GetAheadBehind(branch string) (int, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAheadBehind", branch)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetAheadBehind indicates an expected call of GetAheadBehind.
+func (mr *MockRunnerMockRecorder) GetAheadBehind(branch any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAheadBehind", reflect.TypeOf((*MockRunner)(nil).GetAheadBehind), branch)
+}
+
 // GetBranchRemote mocks base method.
 func (m *MockRunner) GetBranchRemote(branch string) (string, error) {
 	m.ctrl.T.Helper()
@@ -69,6 +85,21 @@ func (mr *MockRunnerMockRecorder) GetCurrentBranch() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBranch", reflect.TypeOf((*MockRunner)(nil).GetCurrentBranch))
 }
 
+// GetLastCommitAuthor mocks base method.
+func (m *MockRunner) GetLastCommitAuthor() (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastCommitAuthor")
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastCommitAuthor indicates an expected call of GetLastCommitAuthor.
+func (mr *MockRunnerMockRecorder) GetLastCommitAuthor() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastCommitAuthor", reflect.TypeOf((*MockRunner)(nil).GetLastCommitAuthor))
+}
+
 // GetModifiedFiles mocks base method.
 func (m *MockRunner) GetModifiedFiles() ([]string, error) {
 	m.ctrl.T.Helper()
@@ -21,14 +21,23 @@ var (
 
 // JSONInput represents the raw JSON input structure.
 type JSONInput struct {
-	ToolName         string          `json:"tool_name,omitempty"`
-	Tool             string          `json:"tool,omitempty"`
-	ToolInput        json.RawMessage `json:"tool_input,omitempty"`
-	Command          string          `json:"command,omitempty"`
-	NotificationType string          `json:"notification_type,omitempty"`
-	SessionID        string          `json:"session_id,omitempty"`
-	ToolUseID        string          `json:"tool_use_id,omitempty"`
-	TranscriptPath   string          `json:"transcript_path,omitempty"`
+	HookEventName      string          `json:"hook_event_name,omitempty"`
+	ToolName           string          `json:"tool_name,omitempty"`
+	Tool               string          `json:"tool,omitempty"`
+	ToolInput          json.RawMessage `json:"tool_input,omitempty"`
+	Command            string          `json:"command,omitempty"`
+	NotificationType   string          `json:"notification_type,omitempty"`
+	Message            string          `json:"message,omitempty"`
+	SessionID          string          `json:"session_id,omitempty"`
+	ToolUseID          string          `json:"tool_use_id,omitempty"`
+	TranscriptPath     string          `json:"transcript_path,omitempty"`
+	Cwd                string          `json:"cwd,omitempty"`
+	Prompt             string          `json:"prompt,omitempty"`
+	StopHookActive     bool            `json:"stop_hook_active,omitempty"`
+	Source             string          `json:"source,omitempty"`
+	Reason             string          `json:"reason,omitempty"`
+	Trigger            string          `json:"trigger,omitempty"`
+	CustomInstructions string          `json:"custom_instructions,omitempty"`
 }
 
 // JSONParser parses JSON input from stdin or environment variable.
@@ -44,6 +53,7 @@ func NewJSONParser(reader io.Reader) *JSONParser {
 }
 
 // Parse parses the JSON input and extracts the hook context.
+// If eventType is EventTypeUnknown, it is derived from hook_event_name in the payload.
 func (p *JSONParser) Parse(eventType hook.EventType) (*hook.Context, error) {
 	// Try reading from stdin
 	jsonBytes, err := io.ReadAll(p.reader)
@@ -94,15 +104,31 @@ func (p *JSONParser) Parse(eventType hook.EventType) (*hook.Context, error) {
 		parsedToolType = hook.ToolTypeUnknown
 	}
 
+	// Fall back to the event name reported in the payload
+	if eventType == hook.EventTypeUnknown && input.HookEventName != "" {
+		if parsedEventType, eventErr := hook.EventTypeString(input.HookEventName); eventErr == nil {
+			eventType = parsedEventType
+		}
+	}
+
 	ctx := &hook.Context{
-		EventType:        eventType,
-		ToolName:         parsedToolType,
-		ToolInput:        toolInput,
-		NotificationType: input.NotificationType,
-		RawJSON:          string(jsonBytes),
-		SessionID:        input.SessionID,
-		ToolUseID:        input.ToolUseID,
-		TranscriptPath:   input.TranscriptPath,
+		EventType:          eventType,
+		ToolName:           parsedToolType,
+		ToolInput:          toolInput,
+		NotificationType:   input.NotificationType,
+		RawJSON:            string(jsonBytes),
+		SessionID:          input.SessionID,
+		ToolUseID:          input.ToolUseID,
+		TranscriptPath:     input.TranscriptPath,
+		HookEventName:      input.HookEventName,
+		Cwd:                input.Cwd,
+		Prompt:             input.Prompt,
+		Message:            input.Message,
+		StopHookActive:     input.StopHookActive,
+		Source:             input.Source,
+		Reason:             input.Reason,
+		Trigger:            input.Trigger,
+		CustomInstructions: input.CustomInstructions,
 	}
 
 	return ctx, nil
@@ -103,6 +103,112 @@ var _ = Describe("JSONParser", func() {
 		})
 	})
 
+	Describe("Parse with lifecycle event fields", func() {
+		It("parses UserPromptSubmit fields", func() {
+			input := `{
+				"session_id": "abc-123",
+				"hook_event_name": "UserPromptSubmit",
+				"cwd": "/home/user/project",
+				"prompt": "deploy to production"
+			}`
+
+			p := parser.NewJSONParser(bytes.NewReader([]byte(input)))
+			ctx, err := p.Parse(hook.EventTypeUserPromptSubmit)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ctx.EventType).To(Equal(hook.EventTypeUserPromptSubmit))
+			Expect(ctx.HookEventName).To(Equal("UserPromptSubmit"))
+			Expect(ctx.Cwd).To(Equal("/home/user/project"))
+			Expect(ctx.Prompt).To(Equal("deploy to production"))
+			Expect(ctx.ToolName).To(Equal(hook.ToolTypeUnknown))
+			Expect(ctx.IsToolEvent()).To(BeFalse())
+		})
+
+		It("parses Stop, SessionStart, SessionEnd and PreCompact fields", func() {
+			input := `{
+				"stop_hook_active": true,
+				"source": "resume",
+				"reason": "logout",
+				"trigger": "manual",
+				"custom_instructions": "keep the plan"
+			}`
+
+			p := parser.NewJSONParser(bytes.NewReader([]byte(input)))
+			ctx, err := p.Parse(hook.EventTypeStop)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ctx.StopHookActive).To(BeTrue())
+			Expect(ctx.Source).To(Equal("resume"))
+			Expect(ctx.Reason).To(Equal("logout"))
+			Expect(ctx.Trigger).To(Equal("manual"))
+			Expect(ctx.CustomInstructions).To(Equal("keep the plan"))
+		})
+
+		It("derives the event type from hook_event_name when unknown", func() {
+			input := `{"hook_event_name": "SubagentStop"}`
+
+			p := parser.NewJSONParser(bytes.NewReader([]byte(input)))
+			ctx, err := p.Parse(hook.EventTypeUnknown)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ctx.EventType).To(Equal(hook.EventTypeSubagentStop))
+		})
+
+		It("prefers the explicit event type over hook_event_name", func() {
+			input := `{"hook_event_name": "Stop", "tool_name": "Bash"}`
+
+			p := parser.NewJSONParser(bytes.NewReader([]byte(input)))
+			ctx, err := p.Parse(hook.EventTypePreToolUse)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ctx.EventType).To(Equal(hook.EventTypePreToolUse))
+		})
+	})
+
+	Describe("Parse with MultiEdit input", func() {
+		It("parses the edits array", func() {
+			input := `{
+				"tool_name": "MultiEdit",
+				"tool_input": {
+					"file_path": "/path/to/file.go",
+					"edits": [
+						{"old_string": "foo", "new_string": "bar"},
+						{"old_string": "baz", "new_string": "qux", "replace_all": true}
+					]
+				}
+			}`
+
+			p := parser.NewJSONParser(bytes.NewReader([]byte(input)))
+			ctx, err := p.Parse(hook.EventTypePreToolUse)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ctx.ToolName).To(Equal(hook.ToolTypeMultiEdit))
+			Expect(ctx.IsEditTool()).To(BeTrue())
+			Expect(ctx.GetEdits()).To(Equal([]hook.Edit{
+				{OldString: "foo", NewString: "bar"},
+				{OldString: "baz", NewString: "qux", ReplaceAll: true},
+			}))
+			Expect(ctx.GetNewContent()).To(Equal("bar\nqux"))
+		})
+
+		It("returns the Edit old_string/new_string pair as a single edit", func() {
+			input := `{
+				"tool_name": "Edit",
+				"tool_input": {
+					"file_path": "/path/to/file.go",
+					"old_string": "foo",
+					"new_string": "bar"
+				}
+			}`
+
+			p := parser.NewJSONParser(bytes.NewReader([]byte(input)))
+			ctx, err := p.Parse(hook.EventTypePreToolUse)
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ctx.GetEdits()).To(Equal([]hook.Edit{{OldString: "foo", NewString: "bar"}}))
+		})
+	})
+
 	Describe("Backward compatibility", func() {
 		It("works with inputs without session fields", func() {
 			input := `{
@@ -37,14 +37,20 @@ func NewValidatorAdapter(
 func (a *ValidatorAdapter) Validate(ctx context.Context, hookCtx *hook.Context) *validator.Result {
 	// Convert hook context to plugin request
 	req := &plugin.ValidateRequest{
-		EventType: hookCtx.EventType.String(),
-		ToolName:  hookCtx.ToolName.String(),
-		Command:   hookCtx.GetCommand(),
-		FilePath:  hookCtx.GetFilePath(),
-		Content:   hookCtx.GetContent(),
-		OldString: hookCtx.ToolInput.OldString,
-		NewString: hookCtx.ToolInput.NewString,
-		Pattern:   hookCtx.ToolInput.Pattern,
+		EventType:      hookCtx.EventType.String(),
+		ToolName:       hookCtx.ToolName.String(),
+		Command:        hookCtx.GetCommand(),
+		FilePath:       hookCtx.GetFilePath(),
+		Content:        hookCtx.GetContent(),
+		OldString:      hookCtx.ToolInput.OldString,
+		NewString:      hookCtx.ToolInput.NewString,
+		Pattern:        hookCtx.ToolInput.Pattern,
+		Prompt:         hookCtx.Prompt,
+		Cwd:            hookCtx.Cwd,
+		StopHookActive: hookCtx.StopHookActive,
+		Source:         hookCtx.Source,
+		Reason:         hookCtx.Reason,
+		Trigger:        hookCtx.Trigger,
 	}
 
 	// Call the plugin
@@ -63,6 +69,7 @@ func (a *ValidatorAdapter) Validate(ctx context.Context, hookCtx *hook.Context)
 		Passed:      resp.Passed,
 		Message:     resp.Message,
 		ShouldBlock: resp.ShouldBlock,
+		ShouldAsk:   resp.ShouldAsk && !resp.ShouldBlock,
 		Details:     resp.Details,
 	}
 
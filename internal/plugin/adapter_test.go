@@ -242,6 +242,43 @@ var _ = Describe("ValidatorAdapter", func() {
 			Expect(result.Message).To(Equal("warning message"))
 		})
 
+		It("should convert ask response", func() {
+			mockPlugin.EXPECT().
+				Validate(gomock.Any(), gomock.Any()).
+				Return(pluginapi.AskResponse("confirm this"), nil)
+
+			hookCtx := &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+			}
+
+			result := adapter.Validate(ctx, hookCtx)
+
+			Expect(result).NotTo(BeNil())
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.ShouldBlock).To(BeFalse())
+			Expect(result.ShouldAsk).To(BeTrue())
+		})
+
+		It("should let block take precedence over ask", func() {
+			resp := pluginapi.FailResponse("blocked")
+			resp.ShouldAsk = true
+
+			mockPlugin.EXPECT().
+				Validate(gomock.Any(), gomock.Any()).
+				Return(resp, nil)
+
+			hookCtx := &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+			}
+
+			result := adapter.Validate(ctx, hookCtx)
+
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(result.ShouldAsk).To(BeFalse())
+		})
+
 		It("should preserve plugin's DocLink as reference", func() {
 			mockPlugin.EXPECT().
 				Validate(gomock.Any(), gomock.Any()).
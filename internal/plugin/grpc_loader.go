@@ -430,15 +430,21 @@ func (a *grpcPluginAdapter) toProtoRequest(
 	}
 
 	return &pluginv1.ValidateRequest{
-		EventType: req.EventType,
-		ToolName:  req.ToolName,
-		Command:   req.Command,
-		FilePath:  req.FilePath,
-		Content:   req.Content,
-		OldString: req.OldString,
-		NewString: req.NewString,
-		Pattern:   req.Pattern,
-		Config:    configMap,
+		EventType:      req.EventType,
+		ToolName:       req.ToolName,
+		Command:        req.Command,
+		FilePath:       req.FilePath,
+		Content:        req.Content,
+		OldString:      req.OldString,
+		NewString:      req.NewString,
+		Pattern:        req.Pattern,
+		Config:         configMap,
+		Prompt:         req.Prompt,
+		Cwd:            req.Cwd,
+		StopHookActive: req.StopHookActive,
+		Source:         req.Source,
+		Reason:         req.Reason,
+		Trigger:        req.Trigger,
 	}, nil
 }
 
@@ -449,6 +455,7 @@ func (*grpcPluginAdapter) fromProtoResponse(
 	return &plugin.ValidateResponse{
 		Passed:      resp.GetPassed(),
 		ShouldBlock: resp.GetShouldBlock(),
+		ShouldAsk:   resp.GetShouldAsk(),
 		Message:     resp.GetMessage(),
 		ErrorCode:   resp.GetErrorCode(),
 		FixHint:     resp.GetFixHint(),
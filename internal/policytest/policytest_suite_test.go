@@ -0,0 +1,13 @@
+package policytest_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestPolicyTest(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "PolicyTest Suite")
+}
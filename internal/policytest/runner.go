@@ -0,0 +1,212 @@
+package policytest
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// Outcome is what a case actually produced.
+type Outcome struct {
+	Decision   string   `json:"decision"`
+	Validators []string `json:"validators"`
+	References []string `json:"references"`
+}
+
+// Diff is a mismatch between an expected and an actual value.
+type Diff struct {
+	Field    string `json:"field"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// CaseResult is the result of running a single case.
+type CaseResult struct {
+	Name    string   `json:"name"`
+	Passed  bool     `json:"passed"`
+	Diffs   []Diff   `json:"diffs,omitempty"`
+	Outcome *Outcome `json:"outcome,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// Report is the result of running a suite.
+type Report struct {
+	Path    string        `json:"path"`
+	Results []*CaseResult `json:"results"`
+}
+
+// Failed returns the number of cases that did not pass.
+func (r *Report) Failed() int {
+	failed := 0
+
+	for _, result := range r.Results {
+		if !result.Passed {
+			failed++
+		}
+	}
+
+	return failed
+}
+
+// Runner runs suites against a configuration.
+type Runner struct {
+	cfg *config.Config
+	log logger.Logger
+}
+
+// RunnerOption configures the Runner.
+type RunnerOption func(*Runner)
+
+// WithLogger sets the logger passed to the runtimes built for each case.
+func WithLogger(log logger.Logger) RunnerOption {
+	return func(r *Runner) {
+		if log != nil {
+			r.log = log
+		}
+	}
+}
+
+// NewRunner creates a new Runner for the given configuration.
+func NewRunner(cfg *config.Config, opts ...RunnerOption) *Runner {
+	r := &Runner{
+		cfg: cfg,
+		log: logger.NewNoOpLogger(),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run runs every case of the suite. Each case gets a dry-run runtime whose git
+// validators and rules see the case's stubbed repository state.
+func (r *Runner) Run(ctx context.Context, suite *Suite) *Report {
+	report := &Report{
+		Path:    suite.Path,
+		Results: make([]*CaseResult, 0, len(suite.Cases)),
+	}
+
+	for _, c := range suite.Cases {
+		report.Results = append(report.Results, r.runCase(ctx, c))
+	}
+
+	return report
+}
+
+func (r *Runner) runCase(ctx context.Context, c *Case) *CaseResult {
+	result := &CaseResult{Name: c.Name}
+
+	hookCtx, err := c.hookContext()
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	rt, err := app.New(r.cfg, r.log, app.WithDryRun(), app.WithGitRunner(c.Git.fakeRunner()))
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	errs := rt.Dispatch(ctx, hookCtx)
+
+	outcome := &Outcome{
+		Decision:   string(app.Decide(errs)),
+		Validators: []string{},
+		References: []string{},
+	}
+
+	for _, e := range errs {
+		if !slices.Contains(outcome.Validators, e.Validator) {
+			outcome.Validators = append(outcome.Validators, e.Validator)
+		}
+
+		if e.Reference != "" && !slices.Contains(outcome.References, string(e.Reference)) {
+			outcome.References = append(outcome.References, string(e.Reference))
+		}
+	}
+
+	result.Outcome = outcome
+	result.Diffs = c.Expect.diff(outcome)
+	result.Passed = len(result.Diffs) == 0
+
+	return result
+}
+
+// hookContext builds the hook context of the case's tool call.
+func (c *Case) hookContext() (*hook.Context, error) {
+	switch {
+	case c.Bash != "":
+		return &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			ToolInput: hook.ToolInput{Command: c.Bash},
+		}, nil
+
+	case c.Write != "":
+		return &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeWrite,
+			ToolInput: hook.ToolInput{FilePath: c.Write, Content: c.Content},
+		}, nil
+
+	default:
+		hookCtx, err := app.ParseHookInput("", strings.NewReader(c.Payload))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse payload")
+		}
+
+		return hookCtx, nil
+	}
+}
+
+// diff compares the expectation with the outcome.
+func (e *Expectation) diff(outcome *Outcome) []Diff {
+	var diffs []Diff
+
+	if e.Decision != outcome.Decision {
+		diffs = append(diffs, Diff{
+			Field:    "decision",
+			Expected: e.Decision,
+			Actual:   outcome.Decision,
+		})
+	}
+
+	if e.Validator != "" && !slices.Contains(outcome.Validators, e.Validator) {
+		diffs = append(diffs, Diff{
+			Field:    "validator",
+			Expected: e.Validator,
+			Actual:   formatList(outcome.Validators),
+		})
+	}
+
+	if e.Reference != "" && !slices.Contains(outcome.References, e.Reference) {
+		diffs = append(diffs, Diff{
+			Field:    "reference",
+			Expected: e.Reference,
+			Actual:   formatList(outcome.References),
+		})
+	}
+
+	return diffs
+}
+
+// formatList formats values for a diff, showing an empty list as (none).
+func formatList(values []string) string {
+	if len(values) == 0 {
+		return "(none)"
+	}
+
+	return strings.Join(values, ", ")
+}
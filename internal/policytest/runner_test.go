@@ -0,0 +1,204 @@
+package policytest_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/policytest"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+)
+
+var _ = Describe("Runner", func() {
+	var cfg *config.Config
+
+	BeforeEach(func() {
+		enabled := true
+
+		cfg = internalconfig.DefaultConfig()
+		cfg.Rules = &config.RulesConfig{
+			Enabled: &enabled,
+			Rules: []config.RuleConfig{
+				{
+					Name: "block-org-origin",
+					Match: &config.RuleMatchConfig{
+						ValidatorType: "git.push",
+						RepoPattern:   "**/myorg/**",
+						Remote:        "origin",
+					},
+					Action: &config.RuleActionConfig{
+						Type:      "block",
+						Message:   "push to upstream",
+						Reference: "ORG001",
+					},
+				},
+			},
+		}
+	})
+
+	run := func(c *policytest.Case) *policytest.CaseResult {
+		report := policytest.NewRunner(cfg).Run(
+			context.Background(),
+			&policytest.Suite{Cases: []*policytest.Case{c}},
+		)
+		Expect(report.Results).To(HaveLen(1))
+
+		return report.Results[0]
+	}
+
+	It("should match rules against the stubbed repository", func() {
+		result := run(&policytest.Case{
+			Name: "org origin",
+			Bash: "git push origin feat",
+			Git:  &policytest.GitState{RepoRoot: "/src/myorg/app", Branch: "feat"},
+			Expect: policytest.Expectation{
+				Decision:  "block",
+				Validator: "validate-git-push",
+				Reference: "ORG001",
+			},
+		})
+
+		Expect(result.Error).To(BeEmpty())
+		Expect(result.Passed).To(BeTrue())
+		Expect(result.Diffs).To(BeEmpty())
+	})
+
+	It("should use the tracking remote when the command names none", func() {
+		result := run(&policytest.Case{
+			Name: "implicit remote",
+			Bash: "git push",
+			Git: &policytest.GitState{
+				RepoRoot: "/src/myorg/app",
+				Branch:   "feat",
+				Remote:   "origin",
+			},
+			Expect: policytest.Expectation{Decision: "block", Reference: "ORG001"},
+		})
+
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("should report diffs for mismatches", func() {
+		result := run(&policytest.Case{
+			Name:   "personal repo",
+			Bash:   "git push origin feat",
+			Git:    &policytest.GitState{RepoRoot: "/src/personal/app"},
+			Expect: policytest.Expectation{Decision: "block", Reference: "ORG001"},
+		})
+
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.Outcome.Decision).To(Equal("allow"))
+		Expect(result.Diffs).To(ConsistOf(
+			policytest.Diff{Field: "decision", Expected: "block", Actual: "allow"},
+			policytest.Diff{Field: "reference", Expected: "ORG001", Actual: "(none)"},
+		))
+	})
+
+	It("should run payload cases", func() {
+		result := run(&policytest.Case{
+			Name: "payload",
+			Payload: `{"hook_event_name":"PreToolUse","tool_name":"Bash",` +
+				`"tool_input":{"command":"git push origin feat"}}`,
+			Git:    &policytest.GitState{RepoRoot: "/src/myorg/app"},
+			Expect: policytest.Expectation{Decision: "block"},
+		})
+
+		Expect(result.Passed).To(BeTrue())
+	})
+
+	It("should count failed cases", func() {
+		report := policytest.NewRunner(cfg).Run(context.Background(), &policytest.Suite{
+			Cases: []*policytest.Case{
+				{Name: "ok", Bash: "ls", Expect: policytest.Expectation{Decision: "allow"}},
+				{Name: "bad", Bash: "ls", Expect: policytest.Expectation{Decision: "block"}},
+			},
+		})
+
+		Expect(report.Failed()).To(Equal(1))
+	})
+})
+
+var _ = Describe("Load", func() {
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+
+		return path
+	}
+
+	It("should load TOML suites", func() {
+		suite, err := policytest.Load(write("suite.toml", `
+[[cases]]
+name = "push"
+bash = "git push"
+
+[cases.git]
+branch = "main"
+staged_files = ["a.go"]
+
+[cases.expect]
+decision = "block"
+reference = "GIT019"
+`))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(suite.Cases).To(HaveLen(1))
+		Expect(suite.Cases[0].Git.Branch).To(Equal("main"))
+		Expect(suite.Cases[0].Git.StagedFiles).To(Equal([]string{"a.go"}))
+		Expect(suite.Cases[0].Expect.Reference).To(Equal("GIT019"))
+	})
+
+	It("should load YAML suites", func() {
+		suite, err := policytest.Load(write("suite.yml", `
+cases:
+  - name: write
+    write: /tmp/a.md
+    content: "# Title"
+    expect:
+      decision: allow
+`))
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(suite.Cases[0].Write).To(Equal("/tmp/a.md"))
+	})
+
+	It("should reject unknown formats", func() {
+		_, err := policytest.Load(write("suite.json", `{}`))
+		Expect(err).To(MatchError(policytest.ErrUnsupportedFormat))
+	})
+
+	It("should reject cases with several tool calls", func() {
+		_, err := policytest.Load(write("suite.toml", `
+[[cases]]
+name = "both"
+bash = "ls"
+write = "a.md"
+
+[cases.expect]
+decision = "allow"
+`))
+		Expect(err).To(MatchError(policytest.ErrInvalidCase))
+	})
+
+	It("should reject unknown decisions", func() {
+		_, err := policytest.Load(write("suite.toml", `
+[[cases]]
+name = "typo"
+bash = "ls"
+
+[cases.expect]
+decision = "deny"
+`))
+		Expect(err).To(MatchError(ContainSubstring(`unknown decision "deny"`)))
+	})
+})
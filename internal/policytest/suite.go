@@ -0,0 +1,214 @@
+// Package policytest runs declarative test suites against a klaudiush
+// configuration, asserting the decision each tool call gets.
+package policytest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/pelletier/go-toml/v2"
+	"go.yaml.in/yaml/v3"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	"github.com/smykla-labs/klaudiush/internal/git"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for suite files that are neither TOML nor YAML.
+	ErrUnsupportedFormat = errors.New("unsupported suite format")
+
+	// ErrInvalidCase is returned when a test case is malformed.
+	ErrInvalidCase = errors.New("invalid test case")
+)
+
+// Suite is a set of policy test cases.
+type Suite struct {
+	// Path is the file the suite was loaded from.
+	Path string `toml:"-" yaml:"-"`
+
+	// Cases are the test cases, run in order.
+	Cases []*Case `toml:"cases" yaml:"cases"`
+}
+
+// Case is a single tool call together with the repository state it runs in
+// and the expected outcome. Exactly one of Bash, Write and Payload is set.
+type Case struct {
+	// Name identifies the case in reports.
+	Name string `toml:"name" yaml:"name"`
+
+	// Bash is the command of a PreToolUse Bash call.
+	Bash string `toml:"bash,omitempty" yaml:"bash,omitempty"`
+
+	// Write is the file path of a PreToolUse Write call.
+	Write string `toml:"write,omitempty" yaml:"write,omitempty"`
+
+	// Content is the content of the Write call.
+	Content string `toml:"content,omitempty" yaml:"content,omitempty"`
+
+	// Payload is a raw hook JSON payload.
+	Payload string `toml:"payload,omitempty" yaml:"payload,omitempty"`
+
+	// Git is the stubbed repository state. Unset fields keep the defaults
+	// of git.NewFakeRunner.
+	Git *GitState `toml:"git,omitempty" yaml:"git,omitempty"`
+
+	// Expect is the expected outcome.
+	Expect Expectation `toml:"expect" yaml:"expect"`
+}
+
+// GitState describes the repository a case runs in.
+type GitState struct {
+	// InRepo is false to run outside of a repository.
+	InRepo *bool `toml:"in_repo,omitempty" yaml:"in_repo,omitempty"`
+
+	// RepoRoot is the repository root directory.
+	RepoRoot string `toml:"repo_root,omitempty" yaml:"repo_root,omitempty"`
+
+	// Branch is the current branch.
+	Branch string `toml:"branch,omitempty" yaml:"branch,omitempty"`
+
+	// Remote is the tracking remote of the current branch.
+	Remote string `toml:"remote,omitempty" yaml:"remote,omitempty"`
+
+	// Remotes maps remote names to URLs.
+	Remotes map[string]string `toml:"remotes,omitempty" yaml:"remotes,omitempty"`
+
+	// StagedFiles are the staged files.
+	StagedFiles []string `toml:"staged_files,omitempty" yaml:"staged_files,omitempty"`
+
+	// ModifiedFiles are the modified but unstaged files.
+	ModifiedFiles []string `toml:"modified_files,omitempty" yaml:"modified_files,omitempty"`
+
+	// UntrackedFiles are the untracked files.
+	UntrackedFiles []string `toml:"untracked_files,omitempty" yaml:"untracked_files,omitempty"`
+}
+
+// Expectation is the expected outcome of a case. Empty fields are not checked.
+type Expectation struct {
+	// Decision is one of allow, warn, rewrite, ask and block.
+	Decision string `toml:"decision" yaml:"decision"`
+
+	// Validator is the name of a validator expected to report an error.
+	Validator string `toml:"validator,omitempty" yaml:"validator,omitempty"`
+
+	// Reference is a reference code expected on one of the errors.
+	Reference string `toml:"reference,omitempty" yaml:"reference,omitempty"`
+}
+
+// Load reads a suite from a TOML (.toml) or YAML (.yaml, .yml) file.
+func Load(path string) (*Suite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read suite")
+	}
+
+	var suite Suite
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		err = toml.Unmarshal(data, &suite)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &suite)
+	default:
+		return nil, errors.Wrapf(ErrUnsupportedFormat, "%s", path)
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse suite %s", path)
+	}
+
+	suite.Path = path
+
+	if err := suite.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "suite %s", path)
+	}
+
+	return &suite, nil
+}
+
+// Validate checks that every case has a name, a single tool call and a
+// known expected decision.
+func (s *Suite) Validate() error {
+	for i, c := range s.Cases {
+		if err := c.validate(); err != nil {
+			return errors.Wrapf(err, "case %d", i+1)
+		}
+	}
+
+	return nil
+}
+
+func (c *Case) validate() error {
+	if c.Name == "" {
+		return errors.Wrap(ErrInvalidCase, "missing name")
+	}
+
+	calls := 0
+
+	for _, set := range []bool{c.Bash != "", c.Write != "", c.Payload != ""} {
+		if set {
+			calls++
+		}
+	}
+
+	if calls != 1 {
+		return errors.Wrapf(ErrInvalidCase, "%q: exactly one of bash, write and payload is required", c.Name)
+	}
+
+	if c.Expect.Decision == "" {
+		return errors.Wrapf(ErrInvalidCase, "%q: missing expected decision", c.Name)
+	}
+
+	if !app.Decision(c.Expect.Decision).IsValid() {
+		return errors.Wrapf(ErrInvalidCase, "%q: unknown decision %q", c.Name, c.Expect.Decision)
+	}
+
+	return nil
+}
+
+// fakeRunner returns a git.FakeRunner holding the case's repository state.
+func (g *GitState) fakeRunner() *git.FakeRunner {
+	runner := git.NewFakeRunner()
+
+	if g == nil {
+		return runner
+	}
+
+	if g.InRepo != nil {
+		runner.InRepo = *g.InRepo
+	}
+
+	if g.RepoRoot != "" {
+		runner.RepoRoot = g.RepoRoot
+	}
+
+	if g.Remotes != nil {
+		runner.Remotes = g.Remotes
+	}
+
+	if g.Branch != "" {
+		runner.CurrentBranch = g.Branch
+	}
+
+	if g.Remote != "" {
+		runner.BranchRemotes = map[string]string{runner.CurrentBranch: g.Remote}
+	} else if g.Branch != "" {
+		runner.BranchRemotes = map[string]string{g.Branch: "origin"}
+	}
+
+	if g.StagedFiles != nil {
+		runner.StagedFiles = g.StagedFiles
+	}
+
+	if g.ModifiedFiles != nil {
+		runner.ModifiedFiles = g.ModifiedFiles
+	}
+
+	if g.UntrackedFiles != nil {
+		runner.UntrackedFiles = g.UntrackedFiles
+	}
+
+	return runner
+}
@@ -0,0 +1,137 @@
+// Package projectpath resolves the paths used by tool calls against the
+// working directory, the home directory and the project root.
+package projectpath
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+// Resolver resolves the paths of one tool call. The project root is looked
+// up on first use, as most paths do not need it.
+type Resolver struct {
+	gitRunner git.Runner
+	cwd       string
+	home      string
+
+	root    string
+	rootSet bool
+}
+
+// NewResolver creates the resolver of a hook context. The working directory
+// is the one of the hook context, or the process's; the project root is the
+// root of the git repository of the runner, or the working directory outside
+// repositories and without a runner. Both directories and the home directory
+// have symlinks resolved.
+func NewResolver(gitRunner git.Runner, hookCtx *hook.Context) *Resolver {
+	cwd := hookCtx.Cwd
+	if cwd == "" {
+		cwd, _ = os.Getwd()
+	}
+
+	home, _ := os.UserHomeDir()
+
+	return &Resolver{
+		gitRunner: gitRunner,
+		cwd:       ResolveSymlinks(cwd),
+		home:      ResolveSymlinks(home),
+	}
+}
+
+// Cwd returns the working directory.
+func (r *Resolver) Cwd() string {
+	return r.cwd
+}
+
+// Home returns the home directory, or an empty string if it is not known.
+func (r *Resolver) Home() string {
+	return r.home
+}
+
+// ProjectRoot returns the root of the git repository, or the working
+// directory outside repositories.
+func (r *Resolver) ProjectRoot() string {
+	if r.rootSet {
+		return r.root
+	}
+
+	r.root = r.cwd
+	r.rootSet = true
+
+	if r.gitRunner != nil && r.gitRunner.IsInRepo() {
+		if root, err := r.gitRunner.GetRepoRoot(); err == nil && root != "" {
+			r.root = ResolveSymlinks(root)
+		}
+	}
+
+	return r.root
+}
+
+// InProject reports whether an absolute path is the project root or inside it.
+func (r *Resolver) InProject(path string) bool {
+	rel, err := filepath.Rel(r.ProjectRoot(), path)
+
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
+
+// Absolute returns the absolute path of a path used in dir, with a leading
+// ~ expanded and ".." resolved. An empty or relative dir is relative to the
+// working directory.
+func (r *Resolver) Absolute(dir, path string) string {
+	base := r.ExpandHome(dir)
+	if !filepath.IsAbs(base) {
+		base = filepath.Join(r.cwd, base)
+	}
+
+	path = r.ExpandHome(path)
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+
+	return filepath.Join(base, path)
+}
+
+// ExpandHome replaces a leading ~ with the home directory.
+func (r *Resolver) ExpandHome(path string) string {
+	if r.home == "" {
+		return path
+	}
+
+	if path == "~" {
+		return r.home
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		return filepath.Join(r.home, rest)
+	}
+
+	return path
+}
+
+// ResolveSymlinks returns a path with symlinks resolved. Paths that do not
+// exist yet, like files about to be written, are resolved up to their
+// longest existing parent. Empty paths are returned as is.
+func ResolveSymlinks(path string) string {
+	if path == "" {
+		return path
+	}
+
+	path = filepath.Clean(path)
+
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+
+	dir, base := filepath.Split(path)
+
+	dir = filepath.Clean(dir)
+	if dir == path || dir == "." {
+		return path
+	}
+
+	return filepath.Join(ResolveSymlinks(dir), base)
+}
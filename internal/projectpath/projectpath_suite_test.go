@@ -0,0 +1,13 @@
+package projectpath_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestProjectPath(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "ProjectPath Suite")
+}
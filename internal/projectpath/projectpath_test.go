@@ -0,0 +1,81 @@
+package projectpath_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/projectpath"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+var _ = Describe("Resolver", func() {
+	var runner *git.FakeRunner
+
+	BeforeEach(func() {
+		runner = git.NewFakeRunner()
+		runner.RepoRoot = "/repo"
+
+		GinkgoT().Setenv("HOME", "/home/dev")
+	})
+
+	newResolver := func(cwd string) *projectpath.Resolver {
+		return projectpath.NewResolver(runner, &hook.Context{Cwd: cwd})
+	}
+
+	It("resolves paths against the directory they are used in", func() {
+		r := newResolver("/repo/cmd")
+
+		Expect(r.Absolute("", "main.go")).To(Equal("/repo/cmd/main.go"))
+		Expect(r.Absolute("web", "../dist")).To(Equal("/repo/cmd/dist"))
+		Expect(r.Absolute("/srv", "app")).To(Equal("/srv/app"))
+		Expect(r.Absolute("~", ".cache")).To(Equal("/home/dev/.cache"))
+		Expect(r.Absolute("", "/etc/../tmp")).To(Equal("/tmp"))
+	})
+
+	It("expands a leading ~ only", func() {
+		r := newResolver("/repo")
+
+		Expect(r.ExpandHome("~")).To(Equal("/home/dev"))
+		Expect(r.ExpandHome("~/.ssh")).To(Equal("/home/dev/.ssh"))
+		Expect(r.ExpandHome("~dev/.ssh")).To(Equal("~dev/.ssh"))
+		Expect(r.ExpandHome("a/~")).To(Equal("a/~"))
+	})
+
+	It("uses the repository root as the project root", func() {
+		r := newResolver("/repo/cmd")
+
+		Expect(r.ProjectRoot()).To(Equal("/repo"))
+		Expect(r.InProject("/repo")).To(BeTrue())
+		Expect(r.InProject("/repo/internal")).To(BeTrue())
+		Expect(r.InProject("/repository")).To(BeFalse())
+		Expect(r.InProject("/")).To(BeFalse())
+	})
+
+	It("uses the working directory outside repositories", func() {
+		runner.InRepo = false
+
+		Expect(newResolver("/work").ProjectRoot()).To(Equal("/work"))
+		Expect(projectpath.NewResolver(nil, &hook.Context{Cwd: "/work"}).ProjectRoot()).To(Equal("/work"))
+	})
+
+	Describe("ResolveSymlinks", func() {
+		It("resolves the existing parents of paths that do not exist", func() {
+			dir, err := filepath.EvalSymlinks(GinkgoT().TempDir())
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(os.Mkdir(filepath.Join(dir, "real"), 0o755)).To(Succeed())
+			Expect(os.Symlink(filepath.Join(dir, "real"), filepath.Join(dir, "link"))).To(Succeed())
+
+			Expect(projectpath.ResolveSymlinks(filepath.Join(dir, "link", "new", "file.txt"))).
+				To(Equal(filepath.Join(dir, "real", "new", "file.txt")))
+		})
+
+		It("returns empty paths as is", func() {
+			Expect(projectpath.ResolveSymlinks("")).To(BeEmpty())
+		})
+	})
+})
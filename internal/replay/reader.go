@@ -0,0 +1,201 @@
+// Package replay re-runs recorded tool calls against two configurations and
+// reports the calls whose decision would change.
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+// maxLineSize bounds a single JSONL line. Transcript lines can hold large
+// tool results.
+const maxLineSize = 16 * 1024 * 1024
+
+// ToolCall is a recorded tool call.
+type ToolCall struct {
+	// Source is the file and line the call was read from.
+	Source string `json:"source"`
+
+	// Cwd is the recorded working directory, if any.
+	Cwd string `json:"cwd,omitempty"`
+
+	// Branch is the recorded git branch, if any.
+	Branch string `json:"branch,omitempty"`
+
+	// Context is the hook context of the call.
+	Context *hook.Context `json:"-"`
+}
+
+// Summary returns a short description of the call.
+func (c *ToolCall) Summary() string {
+	switch {
+	case c.Context.GetCommand() != "":
+		return c.Context.ToolName.String() + ": " + c.Context.GetCommand()
+	case c.Context.GetFilePath() != "":
+		return c.Context.ToolName.String() + ": " + c.Context.GetFilePath()
+	default:
+		return c.Context.ToolName.String()
+	}
+}
+
+// record is the subset of the fields of the supported line formats that
+// replay needs.
+type record struct {
+	// Claude Code transcripts.
+	Type      string `json:"type"`
+	SessionID string `json:"sessionId"`
+	Cwd       string `json:"cwd"`
+	GitBranch string `json:"gitBranch"`
+	Message   *struct {
+		Content json.RawMessage `json:"content"`
+	} `json:"message"`
+
+	// Hook payloads.
+	ToolName string `json:"tool_name"`
+
+	// Session and exception audit logs.
+	Command    string `json:"command"`
+	WorkingDir string `json:"working_dir"`
+}
+
+// contentBlock is a block of a transcript message.
+type contentBlock struct {
+	Type  string          `json:"type"`
+	ID    string          `json:"id"`
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
+}
+
+// ReadFile reads the tool calls recorded in a file. See Read.
+func ReadFile(path string) ([]*ToolCall, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open file")
+	}
+	defer file.Close()
+
+	return Read(file, path)
+}
+
+// Read reads tool calls from JSONL. Each line may be a Claude Code transcript
+// entry (tool_use blocks of assistant messages), a hook payload, or a session
+// or exception audit log entry (its command, replayed as a Bash call). Other
+// lines are skipped. Audit logs truncate long commands, so those calls may not
+// replay exactly.
+func Read(r io.Reader, name string) ([]*ToolCall, error) {
+	var calls []*ToolCall
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		source := fmt.Sprintf("%s:%d", name, lineNum)
+
+		lineCalls, err := parseLine(line, source)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s", source)
+		}
+
+		calls = append(calls, lineCalls...)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "failed to read %s", name)
+	}
+
+	return calls, nil
+}
+
+// parseLine returns the tool calls recorded in a single line.
+func parseLine(line []byte, source string) ([]*ToolCall, error) {
+	var rec record
+	if err := json.Unmarshal(line, &rec); err != nil {
+		return nil, errors.Wrap(err, "invalid JSON")
+	}
+
+	switch {
+	case rec.Type == "assistant" && rec.Message != nil:
+		return parseTranscriptEntry(&rec, source)
+
+	case rec.ToolName != "":
+		hookCtx, err := app.ParseHookInput("", bytes.NewReader(line))
+		if err != nil {
+			return nil, err
+		}
+
+		return []*ToolCall{{Source: source, Cwd: hookCtx.Cwd, Context: hookCtx}}, nil
+
+	case rec.Command != "":
+		return []*ToolCall{{
+			Source: source,
+			Cwd:    rec.WorkingDir,
+			Context: &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: rec.Command},
+			},
+		}}, nil
+
+	default:
+		return nil, nil
+	}
+}
+
+// parseTranscriptEntry returns the tool_use blocks of a transcript message as
+// PreToolUse calls.
+func parseTranscriptEntry(rec *record, source string) ([]*ToolCall, error) {
+	var blocks []contentBlock
+
+	// Content is either a string or a list of blocks
+	if err := json.Unmarshal(rec.Message.Content, &blocks); err != nil {
+		return nil, nil //nolint:nilerr // string content holds no tool calls
+	}
+
+	var calls []*ToolCall
+
+	for _, block := range blocks {
+		if block.Type != "tool_use" {
+			continue
+		}
+
+		payload, err := json.Marshal(map[string]any{
+			"hook_event_name": hook.EventTypePreToolUse.String(),
+			"session_id":      rec.SessionID,
+			"cwd":             rec.Cwd,
+			"tool_use_id":     block.ID,
+			"tool_name":       block.Name,
+			"tool_input":      block.Input,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to build hook payload")
+		}
+
+		hookCtx, err := app.ParseHookInput("", bytes.NewReader(payload))
+		if err != nil {
+			return nil, errors.Wrapf(err, "tool call %s", block.ID)
+		}
+
+		calls = append(calls, &ToolCall{
+			Source:  source,
+			Cwd:     rec.Cwd,
+			Branch:  rec.GitBranch,
+			Context: hookCtx,
+		})
+	}
+
+	return calls, nil
+}
@@ -0,0 +1,156 @@
+package replay_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/replay"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+var _ = Describe("Read", func() {
+	It("should read tool_use blocks from transcripts", func() {
+		calls, err := replay.Read(strings.NewReader(`
+{"type":"user","message":{"role":"user","content":"hi"}}
+{"type":"assistant","cwd":"/src/app","message":{"content":"plain text"}}
+{"type":"assistant","cwd":"/src/app","gitBranch":"feat","message":{"content":[{"type":"text","text":"ok"},{"type":"tool_use","id":"t1","name":"Write","input":{"file_path":"a.md","content":"# A"}}]}}
+`), "t.jsonl")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(HaveLen(1))
+		Expect(calls[0].Source).To(Equal("t.jsonl:4"))
+		Expect(calls[0].Cwd).To(Equal("/src/app"))
+		Expect(calls[0].Branch).To(Equal("feat"))
+		Expect(calls[0].Context.EventType).To(Equal(hook.EventTypePreToolUse))
+		Expect(calls[0].Context.ToolName).To(Equal(hook.ToolTypeWrite))
+		Expect(calls[0].Context.ToolUseID).To(Equal("t1"))
+		Expect(calls[0].Summary()).To(Equal("Write: a.md"))
+	})
+
+	It("should read hook payloads and audit log entries", func() {
+		calls, err := replay.Read(strings.NewReader(
+			`{"hook_event_name":"PreToolUse","tool_name":"Bash","tool_input":{"command":"ls"},"cwd":"/a"}
+{"timestamp":"2025-01-01T00:00:00Z","error_code":"GIT010","command":"git commit -m x","working_dir":"/b"}
+`), "log.jsonl")
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(calls).To(HaveLen(2))
+		Expect(calls[0].Summary()).To(Equal("Bash: ls"))
+		Expect(calls[0].Cwd).To(Equal("/a"))
+		Expect(calls[1].Summary()).To(Equal("Bash: git commit -m x"))
+		Expect(calls[1].Cwd).To(Equal("/b"))
+	})
+
+	It("should report the line of invalid JSON", func() {
+		_, err := replay.Read(strings.NewReader("{}\n{oops\n"), "bad.jsonl")
+		Expect(err).To(MatchError(ContainSubstring("bad.jsonl:2")))
+	})
+})
+
+var _ = Describe("Replayer", func() {
+	var baseline, candidate *config.Config
+
+	pushRule := func(action string) config.RuleConfig {
+		return config.RuleConfig{
+			Name: "push-rule",
+			Match: &config.RuleMatchConfig{
+				ValidatorType: "git.push",
+				RepoPattern:   "**/myorg/**",
+			},
+			Action: &config.RuleActionConfig{Type: action, Message: "push", Reference: "ORG001"},
+		}
+	}
+
+	bash := func(cwd, command string) *replay.ToolCall {
+		return &replay.ToolCall{
+			Source: "test",
+			Cwd:    cwd,
+			Context: &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: command},
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		baseline = internalconfig.DefaultConfig()
+		candidate = internalconfig.DefaultConfig()
+	})
+
+	It("should classify changed decisions", func() {
+		baseline.Rules = &config.RulesConfig{Rules: []config.RuleConfig{pushRule("warn")}}
+		candidate.Rules = &config.RulesConfig{Rules: []config.RuleConfig{pushRule("block")}}
+
+		report, err := replay.NewReplayer(baseline, candidate).Run(context.Background(), []*replay.ToolCall{
+			bash("/src/myorg/app", "git push origin feat"),
+			bash("/src/personal/app", "git push origin feat"),
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Total).To(Equal(2))
+		Expect(report.Changes).To(HaveLen(1))
+		Expect(report.Changes[0].Kind).To(Equal(replay.ChangeBlocked))
+		Expect(report.Changes[0].Before).To(Equal(&replay.Outcome{
+			Decision: "warn",
+			Errors:   []string{"validate-git-push ORG001"},
+		}))
+		Expect(report.Changes[0].After.Decision).To(Equal("block"))
+	})
+
+	It("should report calls that are no longer blocked", func() {
+		baseline.Rules = &config.RulesConfig{Rules: []config.RuleConfig{pushRule("block")}}
+		candidate.Rules = &config.RulesConfig{Rules: []config.RuleConfig{pushRule("ask")}}
+
+		report, err := replay.NewReplayer(baseline, candidate).Run(context.Background(), []*replay.ToolCall{
+			bash("/src/myorg/app", "git push origin feat"),
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Count(replay.ChangeAllowed)).To(Equal(1))
+	})
+
+	It("should replay calls on their recorded branch", func() {
+		branchRule := func(action string) config.RuleConfig {
+			return config.RuleConfig{
+				Name:   "main-rule",
+				Match:  &config.RuleMatchConfig{BranchPattern: "main", CommandPattern: "make*"},
+				Action: &config.RuleActionConfig{Type: action, Message: "main"},
+			}
+		}
+
+		baseline.Rules = &config.RulesConfig{Rules: []config.RuleConfig{branchRule("warn")}}
+		candidate.Rules = &config.RulesConfig{Rules: []config.RuleConfig{branchRule("block")}}
+
+		onMain := bash("/src/app", "make deploy")
+		onMain.Branch = "main"
+		onFeature := bash("/src/app", "make deploy")
+		onFeature.Branch = "feature"
+
+		report, err := replay.NewReplayer(baseline, candidate).Run(context.Background(), []*replay.ToolCall{
+			onMain,
+			onFeature,
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Changes).To(HaveLen(1))
+		Expect(report.Changes[0].Call).To(BeIdenticalTo(onMain))
+	})
+
+	It("should report changed warnings", func() {
+		baseline.Rules = &config.RulesConfig{Rules: []config.RuleConfig{pushRule("warn")}}
+
+		report, err := replay.NewReplayer(baseline, candidate).Run(context.Background(), []*replay.ToolCall{
+			bash("/src/myorg/app", "git push origin feat"),
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Count(replay.ChangeOther)).To(Equal(1))
+		Expect(report.Changes[0].After.Decision).To(Equal("allow"))
+	})
+})
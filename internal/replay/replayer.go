@@ -0,0 +1,228 @@
+package replay
+
+import (
+	"context"
+	"os"
+	"slices"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/internal/app"
+	"github.com/smykla-labs/klaudiush/internal/dispatcher"
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// ChangeKind classifies how a decision changed.
+type ChangeKind string
+
+// Change kinds.
+const (
+	// ChangeBlocked is a call that the candidate config newly blocks.
+	ChangeBlocked ChangeKind = "blocked"
+
+	// ChangeAllowed is a call that the baseline config blocked and the
+	// candidate config lets through, possibly with a warning.
+	ChangeAllowed ChangeKind = "allowed"
+
+	// ChangeOther is any other change, e.g. a warning that appeared,
+	// disappeared or now asks for confirmation.
+	ChangeOther ChangeKind = "changed"
+)
+
+// Outcome is the result of validating a call with one config.
+type Outcome struct {
+	Decision string   `json:"decision"`
+	Errors   []string `json:"errors"`
+}
+
+// Change is a call whose outcome differs between the configs.
+type Change struct {
+	Call    *ToolCall  `json:"call"`
+	Summary string     `json:"summary"`
+	Kind    ChangeKind `json:"kind"`
+	Before  *Outcome   `json:"before"`
+	After   *Outcome   `json:"after"`
+}
+
+// Report is the result of a replay.
+type Report struct {
+	Total   int       `json:"total"`
+	Changes []*Change `json:"changes"`
+}
+
+// Count returns the number of changes of the given kind.
+func (r *Report) Count(kind ChangeKind) int {
+	count := 0
+
+	for _, change := range r.Changes {
+		if change.Kind == kind {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Replayer validates recorded calls with a baseline and a candidate config.
+type Replayer struct {
+	baseline  *config.Config
+	candidate *config.Config
+	log       logger.Logger
+	runtimes  map[runtimeKey]*runtimePair
+}
+
+// runtimeKey identifies the stubbed git state of recorded calls.
+type runtimeKey struct {
+	cwd    string
+	branch string
+}
+
+// runtimePair holds the baseline and candidate runtimes for a directory and branch.
+type runtimePair struct {
+	baseline  *app.Runtime
+	candidate *app.Runtime
+}
+
+// Option configures the Replayer.
+type Option func(*Replayer)
+
+// WithLogger sets the logger passed to the runtimes.
+func WithLogger(log logger.Logger) Option {
+	return func(r *Replayer) {
+		if log != nil {
+			r.log = log
+		}
+	}
+}
+
+// NewReplayer creates a new Replayer comparing candidate against baseline.
+func NewReplayer(baseline, candidate *config.Config, opts ...Option) *Replayer {
+	r := &Replayer{
+		baseline:  baseline,
+		candidate: candidate,
+		log:       logger.NewNoOpLogger(),
+		runtimes:  make(map[runtimeKey]*runtimePair),
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Run validates every call with both configs. Runtimes are dry-run, so no
+// state is changed, and git state is stubbed with the call's recorded working
+// directory as repository root and its recorded branch as current branch, so
+// repository and branch rules match as they did when the call was made.
+func (r *Replayer) Run(ctx context.Context, calls []*ToolCall) (*Report, error) {
+	report := &Report{
+		Total:   len(calls),
+		Changes: []*Change{},
+	}
+
+	for _, call := range calls {
+		pair, err := r.runtimesFor(call.Cwd, call.Branch)
+		if err != nil {
+			return nil, err
+		}
+
+		before := outcome(pair.baseline.Dispatch(ctx, call.Context))
+		after := outcome(pair.candidate.Dispatch(ctx, call.Context))
+
+		kind, changed := classify(before, after)
+		if !changed {
+			continue
+		}
+
+		report.Changes = append(report.Changes, &Change{
+			Call:    call,
+			Summary: call.Summary(),
+			Kind:    kind,
+			Before:  before,
+			After:   after,
+		})
+	}
+
+	return report, nil
+}
+
+// runtimesFor returns the runtimes for calls made in the given directory on
+// the given branch. Without a recorded branch, the stubbed default is used.
+func (r *Replayer) runtimesFor(cwd, branch string) (*runtimePair, error) {
+	if cwd == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to get working directory")
+		}
+
+		cwd = wd
+	}
+
+	key := runtimeKey{cwd: cwd, branch: branch}
+
+	if pair, ok := r.runtimes[key]; ok {
+		return pair, nil
+	}
+
+	runner := git.NewFakeRunner()
+	runner.RepoRoot = cwd
+
+	if branch != "" {
+		runner.CurrentBranch = branch
+	}
+
+	baseline, err := app.New(r.baseline, r.log, app.WithDryRun(), app.WithGitRunner(runner))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build baseline runtime")
+	}
+
+	candidate, err := app.New(r.candidate, r.log, app.WithDryRun(), app.WithGitRunner(runner))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to build candidate runtime")
+	}
+
+	pair := &runtimePair{baseline: baseline, candidate: candidate}
+	r.runtimes[key] = pair
+
+	return pair, nil
+}
+
+// outcome summarizes validation errors.
+func outcome(errs []*dispatcher.ValidationError) *Outcome {
+	o := &Outcome{
+		Decision: string(app.Decide(errs)),
+		Errors:   make([]string, 0, len(errs)),
+	}
+
+	for _, e := range errs {
+		desc := e.Validator
+		if e.Reference != "" {
+			desc += " " + string(e.Reference)
+		}
+
+		o.Errors = append(o.Errors, desc)
+	}
+
+	slices.Sort(o.Errors)
+
+	return o
+}
+
+// classify reports whether the outcome changed and how.
+func classify(before, after *Outcome) (ChangeKind, bool) {
+	block := string(app.DecisionBlock)
+
+	switch {
+	case after.Decision == block && before.Decision != block:
+		return ChangeBlocked, true
+	case before.Decision == block && after.Decision != block:
+		return ChangeAllowed, true
+	case before.Decision != after.Decision || !slices.Equal(before.Errors, after.Errors):
+		return ChangeOther, true
+	default:
+		return "", false
+	}
+}
@@ -19,9 +19,19 @@ type RuleValidatorAdapter struct {
 	// This is optional and allows validators to provide git-specific data.
 	GitContextProvider func() *GitContext
 
+	// HookGitContextProvider is like GitContextProvider but receives the hook
+	// context, so the git context can reflect the command (e.g. its remote).
+	// It takes precedence over GitContextProvider.
+	HookGitContextProvider func(hookCtx *hook.Context) *GitContext
+
 	// FileContextProvider is called to get file context for rule matching.
 	// This is optional and allows validators to provide file-specific data.
 	FileContextProvider func() *FileContext
+
+	// RuleFilter is called once per hook context and returns the filter that
+	// restricts which rules are evaluated for it. This is optional; all rules
+	// are evaluated if nil.
+	RuleFilter func(hookCtx *hook.Context) func(rule *Rule) bool
 }
 
 // AdapterOption configures a RuleValidatorAdapter.
@@ -41,6 +51,13 @@ func WithGitContextProvider(provider func() *GitContext) AdapterOption {
 	}
 }
 
+// WithHookGitContextProvider sets the hook-aware git context provider.
+func WithHookGitContextProvider(provider func(hookCtx *hook.Context) *GitContext) AdapterOption {
+	return func(a *RuleValidatorAdapter) {
+		a.HookGitContextProvider = provider
+	}
+}
+
 // WithFileContextProvider sets the file context provider.
 func WithFileContextProvider(provider func() *FileContext) AdapterOption {
 	return func(a *RuleValidatorAdapter) {
@@ -48,6 +65,13 @@ func WithFileContextProvider(provider func() *FileContext) AdapterOption {
 	}
 }
 
+// WithRuleFilter sets the rule filter.
+func WithRuleFilter(filter func(hookCtx *hook.Context) func(rule *Rule) bool) AdapterOption {
+	return func(a *RuleValidatorAdapter) {
+		a.RuleFilter = filter
+	}
+}
+
 // NewRuleValidatorAdapter creates a new adapter for the given engine and validator type.
 func NewRuleValidatorAdapter(
 	engine *RuleEngine,
@@ -90,9 +114,15 @@ func (a *RuleValidatorAdapter) CheckRules(
 		matchCtx.Command = hookCtx.GetCommand()
 	}
 
-	// Get git context if provider is set.
-	if a.GitContextProvider != nil {
-		matchCtx.GitContext = a.GitContextProvider()
+	// Get git context if provider is set. It is built on first use, as most
+	// tool calls match no rule with git conditions.
+	switch {
+	case a.HookGitContextProvider != nil:
+		matchCtx.gitContextProvider = func() *GitContext {
+			return a.HookGitContextProvider(hookCtx)
+		}
+	case a.GitContextProvider != nil:
+		matchCtx.gitContextProvider = a.GitContextProvider
 	}
 
 	// Get file context if provider is set.
@@ -100,6 +130,10 @@ func (a *RuleValidatorAdapter) CheckRules(
 		matchCtx.FileContext = a.FileContextProvider()
 	}
 
+	if a.RuleFilter != nil {
+		matchCtx.RuleFilter = a.RuleFilter(hookCtx)
+	}
+
 	// Evaluate rules.
 	result := a.engine.Evaluate(ctx, matchCtx)
 
@@ -148,8 +182,20 @@ func (a *RuleValidatorAdapter) CheckRulesWithContext(
 	return a.convertResult(result)
 }
 
-// convertResult converts a RuleResult to a validator.Result.
-func (*RuleValidatorAdapter) convertResult(result *RuleResult) *validator.Result {
+// convertResult converts a RuleResult to a validator.Result. The rule's fix
+// hint, if any, replaces the suggestion registered for its reference.
+func (a *RuleValidatorAdapter) convertResult(result *RuleResult) *validator.Result {
+	res := a.actionResult(result)
+
+	if res != nil && !res.Passed && result.FixHint != "" {
+		res.FixHint = result.FixHint
+	}
+
+	return res
+}
+
+// actionResult converts a RuleResult to a validator.Result for its action.
+func (*RuleValidatorAdapter) actionResult(result *RuleResult) *validator.Result {
 	switch result.Action {
 	case ActionBlock:
 		if result.Reference != "" {
@@ -171,9 +217,22 @@ func (*RuleValidatorAdapter) convertResult(result *RuleResult) *validator.Result
 
 		return validator.Warn(result.Message)
 
+	case ActionAsk:
+		if result.Reference != "" {
+			return validator.AskWithRef(
+				validator.Reference(result.Reference),
+				result.Message,
+			)
+		}
+
+		return validator.Ask(result.Message)
+
 	case ActionAllow:
 		return validator.Pass()
 
+	case ActionRewrite:
+		return validator.Rewrite(result.Message, result.UpdatedInput)
+
 	default:
 		return nil
 	}
@@ -131,6 +131,53 @@ var _ = Describe("RuleValidatorAdapter", func() {
 			})
 		})
 
+		Context("with ask rule", func() {
+			BeforeEach(func() {
+				ruleList := []*rules.Rule{
+					{
+						Name:    "ask-force-push",
+						Enabled: true,
+						Match: &rules.RuleMatch{
+							Remote: "origin",
+						},
+						Action: &rules.RuleAction{
+							Type:      rules.ActionAsk,
+							Message:   "force-pushing rewrites history",
+							Reference: "GIT099",
+						},
+					},
+				}
+
+				var err error
+				engine, err = rules.NewRuleEngine(ruleList)
+				Expect(err).NotTo(HaveOccurred())
+
+				adapter = rules.NewRuleValidatorAdapter(
+					engine,
+					rules.ValidatorGitPush,
+				)
+			})
+
+			It("should return ask result when rule asks", func() {
+				hookCtx := &hook.Context{}
+
+				adapter.GitContextProvider = func() *rules.GitContext {
+					return &rules.GitContext{
+						Remote: "origin",
+					}
+				}
+
+				result := adapter.CheckRules(ctx, hookCtx)
+				Expect(result).NotTo(BeNil())
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.ShouldBlock).To(BeFalse())
+				Expect(result.ShouldAsk).To(BeTrue())
+				Expect(result.Message).To(Equal("force-pushing rewrites history"))
+				Expect(string(result.Reference)).To(Equal("GIT099"))
+				Expect(result.String()).To(Equal("ASK"))
+			})
+		})
+
 		Context("with allow rule", func() {
 			BeforeEach(func() {
 				ruleList := []*rules.Rule{
@@ -356,6 +403,59 @@ var _ = Describe("RuleValidatorAdapter", func() {
 			Expect(result.ShouldBlock).To(BeTrue())
 		})
 
+		It("should only build the git context for rules with git conditions", func() {
+			ruleList := []*rules.Rule{
+				{
+					Name:    "command-rule",
+					Enabled: true,
+					Match:   &rules.RuleMatch{CommandPattern: "terraform*"},
+					Action:  &rules.RuleAction{Type: rules.ActionBlock, Message: "terraform"},
+				},
+			}
+
+			engine, _ = rules.NewRuleEngine(ruleList)
+
+			calls := 0
+			adapter = rules.NewRuleValidatorAdapter(
+				engine,
+				rules.ValidatorCustom,
+				rules.WithHookGitContextProvider(func(*hook.Context) *rules.GitContext {
+					calls++
+
+					return &rules.GitContext{Branch: "main"}
+				}),
+			)
+
+			Expect(adapter.CheckRules(ctx, &hook.Context{
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: "ls"},
+			})).To(BeNil())
+			Expect(calls).To(BeZero())
+
+			engine, _ = rules.NewRuleEngine([]*rules.Rule{
+				{
+					Name:    "branch-rule",
+					Enabled: true,
+					Match:   &rules.RuleMatch{BranchPattern: "main", RepoPattern: "**"},
+					Action:  &rules.RuleAction{Type: rules.ActionBlock, Message: "main"},
+				},
+			})
+			adapter = rules.NewRuleValidatorAdapter(
+				engine,
+				rules.ValidatorCustom,
+				rules.WithHookGitContextProvider(func(*hook.Context) *rules.GitContext {
+					calls++
+
+					return &rules.GitContext{Branch: "main", RepoRoot: "/src/app"}
+				}),
+			)
+
+			result := adapter.CheckRules(ctx, &hook.Context{})
+			Expect(result).NotTo(BeNil())
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(calls).To(Equal(1))
+		})
+
 		It("should use FileContextProvider", func() {
 			ruleList := []*rules.Rule{
 				{
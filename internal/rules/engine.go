@@ -5,6 +5,7 @@ import (
 
 	"github.com/smykla-labs/klaudiush/pkg/hook"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
 )
 
 // RuleEngine is the main implementation of the Engine interface.
@@ -16,8 +17,28 @@ type RuleEngine struct {
 	// Configuration options.
 	stopOnFirstMatch bool
 	defaultAction    ActionType
+
+	// environment is used for match contexts that do not set their own.
+	environment *Environment
+
+	// observers are notified of every rule match.
+	observers []MatchObserver
+
+	// stats records rule matches, if set.
+	stats *Stats
+
+	// parserOpts configures the parser of the match contexts' commands.
+	parserOpts []parser.BashParserOption
+
+	// invalidRuleHandler is called with the rules that fail to compile, which
+	// are then skipped. If nil, an invalid rule fails engine creation.
+	invalidRuleHandler func(rule *Rule, err error)
 }
 
+// MatchObserver is notified when a rule matches. Observers may be called
+// concurrently when validators run in parallel.
+type MatchObserver func(matchCtx *MatchContext, result *RuleResult)
+
 // EngineOption configures a RuleEngine.
 type EngineOption func(*RuleEngine)
 
@@ -42,6 +63,38 @@ func WithEngineDefaultAction(action ActionType) EngineOption {
 	}
 }
 
+// WithEnvironment sets the clock, environment variables and hostname used for
+// time_window, env and hostname_pattern conditions (e.g. a fixed clock in
+// tests).
+func WithEnvironment(env *Environment) EngineOption {
+	return func(e *RuleEngine) {
+		e.environment = env
+	}
+}
+
+// WithEngineStats records rule matches in stats.
+func WithEngineStats(stats *Stats) EngineOption {
+	return func(e *RuleEngine) {
+		e.stats = stats
+	}
+}
+
+// WithEngineMaxNestingDepth sets the depth up to which shell scripts nested
+// in commands are parsed for command conditions.
+func WithEngineMaxNestingDepth(depth int) EngineOption {
+	return func(e *RuleEngine) {
+		e.parserOpts = []parser.BashParserOption{parser.WithMaxNestingDepth(depth)}
+	}
+}
+
+// WithInvalidRuleHandler skips rules that fail to compile instead of failing
+// engine creation, passing each of them to handler with its error.
+func WithInvalidRuleHandler(handler func(rule *Rule, err error)) EngineOption {
+	return func(e *RuleEngine) {
+		e.invalidRuleHandler = handler
+	}
+}
+
 // NewRuleEngine creates a new RuleEngine with the given rules.
 func NewRuleEngine(rules []*Rule, opts ...EngineOption) (*RuleEngine, error) {
 	engine := &RuleEngine{
@@ -61,7 +114,7 @@ func NewRuleEngine(rules []*Rule, opts ...EngineOption) (*RuleEngine, error) {
 	}
 
 	// Add rules to registry.
-	if err := engine.registry.AddAll(rules); err != nil {
+	if err := engine.addRules(rules); err != nil {
 		return nil, err
 	}
 
@@ -70,13 +123,43 @@ func NewRuleEngine(rules []*Rule, opts ...EngineOption) (*RuleEngine, error) {
 		engine.registry,
 		WithStopOnFirstMatch(engine.stopOnFirstMatch),
 		WithDefaultAction(engine.defaultAction),
+		WithStats(engine.stats),
 	)
 
 	return engine, nil
 }
 
+// addRules adds rules to the registry, skipping invalid ones if an invalid
+// rule handler is set.
+func (e *RuleEngine) addRules(rules []*Rule) error {
+	if e.invalidRuleHandler == nil {
+		return e.registry.AddAll(rules)
+	}
+
+	for _, rule := range rules {
+		if err := e.registry.Add(rule); err != nil {
+			e.invalidRuleHandler(rule, err)
+		}
+	}
+
+	return nil
+}
+
 // Evaluate evaluates rules against the given match context.
 func (e *RuleEngine) Evaluate(_ context.Context, matchCtx *MatchContext) *RuleResult {
+	if matchCtx.Environment == nil {
+		matchCtx.Environment = e.environment
+
+		// Env conditions see the hook process, not the daemon serving it
+		if matchCtx.HookContext != nil && matchCtx.HookContext.Env != nil {
+			matchCtx.Environment = e.environment.withEnv(matchCtx.HookContext.Env)
+		}
+	}
+
+	if matchCtx.parserOpts == nil {
+		matchCtx.parserOpts = e.parserOpts
+	}
+
 	result := e.evaluator.Evaluate(matchCtx)
 
 	if result.Matched {
@@ -85,6 +168,10 @@ func (e *RuleEngine) Evaluate(_ context.Context, matchCtx *MatchContext) *RuleRe
 			"action", result.Action,
 			"validator", matchCtx.ValidatorType,
 		)
+
+		for _, observer := range e.observers {
+			observer(matchCtx, result)
+		}
 	}
 
 	return result
@@ -113,6 +200,19 @@ func (e *RuleEngine) EvaluateHook(
 	return e.Evaluate(ctx, matchCtx)
 }
 
+// AddObserver registers an observer notified whenever a rule matches.
+// Observers must be registered before the engine is used for evaluation.
+func (e *RuleEngine) AddObserver(observer MatchObserver) {
+	if observer != nil {
+		e.observers = append(e.observers, observer)
+	}
+}
+
+// Stats returns the rule stats, or nil if matches are not recorded.
+func (e *RuleEngine) Stats() *Stats {
+	return e.stats
+}
+
 // AddRule adds a rule to the engine.
 func (e *RuleEngine) AddRule(rule *Rule) error {
 	return e.registry.Add(rule)
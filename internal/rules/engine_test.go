@@ -55,6 +55,34 @@ var _ = Describe("RuleEngine", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should skip invalid rules with an invalid rule handler", func() {
+			ruleList := []*rules.Rule{
+				{
+					Name:    "broken",
+					Enabled: true,
+					Match:   &rules.RuleMatch{RepoPattern: "[invalid"},
+					Action:  &rules.RuleAction{Type: rules.ActionBlock},
+				},
+				{
+					Name:    "valid",
+					Enabled: true,
+					Action:  &rules.RuleAction{Type: rules.ActionBlock},
+				},
+			}
+
+			var skipped []string
+
+			var err error
+			engine, err = rules.NewRuleEngine(ruleList, rules.WithInvalidRuleHandler(
+				func(rule *rules.Rule, _ error) {
+					skipped = append(skipped, rule.Name)
+				},
+			))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(engine.Size()).To(Equal(1))
+			Expect(skipped).To(ConsistOf("broken"))
+		})
+
 		It("should create engine with empty rules", func() {
 			var err error
 			engine, err = rules.NewRuleEngine(nil)
@@ -170,6 +198,31 @@ var _ = Describe("RuleEngine", func() {
 			result := engine.Evaluate(ctx, matchCtx)
 			Expect(result.Matched).To(BeFalse())
 		})
+
+		It("should notify observers of matches only", func() {
+			var observed []string
+
+			engine.AddObserver(func(matchCtx *rules.MatchContext, result *rules.RuleResult) {
+				observed = append(observed, string(matchCtx.ValidatorType)+":"+result.Rule.Name)
+			})
+
+			engine.Evaluate(ctx, &rules.MatchContext{
+				ValidatorType: rules.ValidatorGitPush,
+				GitContext: &rules.GitContext{
+					RepoRoot: "/home/user/myorg/project",
+					Remote:   "origin",
+				},
+			})
+			engine.Evaluate(ctx, &rules.MatchContext{
+				ValidatorType: rules.ValidatorGitPush,
+				GitContext: &rules.GitContext{
+					RepoRoot: "/home/user/personal/project",
+					Remote:   "origin",
+				},
+			})
+
+			Expect(observed).To(Equal([]string{"git.push:block-org-origin"}))
+		})
 	})
 
 	Describe("EvaluateHook", func() {
@@ -373,5 +426,28 @@ var _ = Describe("RuleEngine", func() {
 			Expect(result.Matched).To(BeFalse())
 			Expect(result.Action).To(Equal(rules.ActionBlock))
 		})
+
+		It("should parse nested scripts up to the max nesting depth option", func() {
+			ruleList := []*rules.Rule{
+				{
+					Name:    "block-rm",
+					Enabled: true,
+					Match:   &rules.RuleMatch{CommandName: "rm"},
+					Action:  &rules.RuleAction{Type: rules.ActionBlock},
+				},
+			}
+
+			nested := func() *rules.MatchContext {
+				return &rules.MatchContext{Command: `bash -c "rm -rf build"`}
+			}
+
+			engine, err := rules.NewRuleEngine(ruleList)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(engine.Evaluate(ctx, nested()).Matched).To(BeTrue())
+
+			engine, err = rules.NewRuleEngine(ruleList, rules.WithEngineMaxNestingDepth(0))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(engine.Evaluate(ctx, nested()).Matched).To(BeFalse())
+		})
 	})
 })
@@ -0,0 +1,237 @@
+package rules
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+)
+
+const (
+	minutesPerHour = 60
+	minutesPerDay  = 24 * minutesPerHour
+	daysPerWeek    = 7
+)
+
+// ErrInvalidTimeWindow is returned when a time window cannot be parsed.
+var ErrInvalidTimeWindow = errors.New("invalid time window")
+
+// weekdays maps day names and their abbreviations to weekdays.
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Environment provides the clock, environment variables and hostname used by
+// the time_window, env and hostname_pattern conditions. Nil fields fall back
+// to the system clock, environment and hostname.
+type Environment struct {
+	// Now returns the current time.
+	Now func() time.Time
+
+	// LookupEnv returns the value of an environment variable.
+	LookupEnv func(key string) (string, bool)
+
+	// Hostname returns the host name of the machine.
+	Hostname func() (string, error)
+}
+
+// now returns the current time.
+func (e *Environment) now() time.Time {
+	if e == nil || e.Now == nil {
+		return time.Now()
+	}
+
+	return e.Now()
+}
+
+// getenv returns the value of an environment variable, or "" if it is unset.
+func (e *Environment) getenv(key string) string {
+	lookup := os.LookupEnv
+	if e != nil && e.LookupEnv != nil {
+		lookup = e.LookupEnv
+	}
+
+	value, _ := lookup(key)
+
+	return value
+}
+
+// withEnv returns a copy of the environment that looks up variables in env.
+func (e *Environment) withEnv(env map[string]string) *Environment {
+	result := &Environment{}
+	if e != nil {
+		*result = *e
+	}
+
+	result.LookupEnv = func(key string) (string, bool) {
+		value, ok := env[key]
+
+		return value, ok
+	}
+
+	return result
+}
+
+// hostname returns the host name of the machine.
+func (e *Environment) hostname() (string, error) {
+	if e == nil || e.Hostname == nil {
+		return os.Hostname()
+	}
+
+	return e.Hostname()
+}
+
+// TimeWindow is a weekly recurring window of time.
+type TimeWindow struct {
+	// Days lists the days of the window, as names ("mon", "friday") or
+	// ranges ("mon-fri", "sat-sun"). The window covers every day if empty.
+	Days []string
+
+	// Hours is the "HH:MM-HH:MM" time range of the window. The start is
+	// inclusive and the end exclusive. A range whose end is before its start
+	// ends on the next day ("22:00-06:00"). The window covers whole days if
+	// empty.
+	Hours string
+
+	// Timezone is the IANA name of the time zone of the window (e.g.
+	// "Europe/Warsaw"). Local time is used if empty.
+	Timezone string
+}
+
+// Validate returns an error if the time window cannot be parsed.
+func (tw *TimeWindow) Validate() error {
+	_, err := tw.compile()
+
+	return err
+}
+
+// timeWindow is a parsed TimeWindow.
+type timeWindow struct {
+	days     [daysPerWeek]bool
+	start    int
+	end      int
+	location *time.Location
+}
+
+// compile parses the time window.
+func (tw *TimeWindow) compile() (*timeWindow, error) {
+	w := &timeWindow{start: 0, end: minutesPerDay, location: time.Local}
+
+	if len(tw.Days) == 0 {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	}
+
+	for _, spec := range tw.Days {
+		if err := w.addDays(spec); err != nil {
+			return nil, err
+		}
+	}
+
+	if tw.Hours != "" {
+		startStr, endStr, ok := strings.Cut(tw.Hours, "-")
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidTimeWindow, "hours %q must be HH:MM-HH:MM", tw.Hours)
+		}
+
+		var err error
+
+		if w.start, err = parseClock(startStr); err != nil {
+			return nil, err
+		}
+
+		if w.start == minutesPerDay {
+			return nil, errors.Wrapf(ErrInvalidTimeWindow, "hours %q cannot start at 24:00", tw.Hours)
+		}
+
+		if w.end, err = parseClock(endStr); err != nil {
+			return nil, err
+		}
+
+		if w.start == w.end {
+			return nil, errors.Wrapf(ErrInvalidTimeWindow, "hours %q is an empty range", tw.Hours)
+		}
+	}
+
+	if tw.Timezone != "" {
+		location, err := time.LoadLocation(tw.Timezone)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidTimeWindow, "unknown timezone %q", tw.Timezone)
+		}
+
+		w.location = location
+	}
+
+	return w, nil
+}
+
+// addDays adds a day or a day range ("mon-fri") to the window.
+func (w *timeWindow) addDays(spec string) error {
+	firstStr, lastStr, isRange := strings.Cut(strings.ToLower(strings.TrimSpace(spec)), "-")
+
+	first, ok := weekdays[firstStr]
+	if !ok {
+		return errors.Wrapf(ErrInvalidTimeWindow, "unknown day %q", spec)
+	}
+
+	last := first
+
+	if isRange {
+		if last, ok = weekdays[lastStr]; !ok {
+			return errors.Wrapf(ErrInvalidTimeWindow, "unknown day %q", spec)
+		}
+	}
+
+	for day := first; ; day = (day + 1) % daysPerWeek {
+		w.days[day] = true
+
+		if day == last {
+			return nil
+		}
+	}
+}
+
+// parseClock parses a "HH:MM" time of day as minutes since midnight. "24:00"
+// is allowed as the end of a range.
+func parseClock(s string) (int, error) {
+	hourStr, minuteStr, ok := strings.Cut(strings.TrimSpace(s), ":")
+
+	hour, hourErr := strconv.Atoi(hourStr)
+	minute, minuteErr := strconv.Atoi(minuteStr)
+
+	minutes := hour*minutesPerHour + minute
+
+	if !ok || hourErr != nil || minuteErr != nil || hour < 0 || minute < 0 ||
+		minute >= minutesPerHour || minutes > minutesPerDay {
+		return 0, errors.Wrapf(ErrInvalidTimeWindow, "invalid time %q (must be HH:MM)", s)
+	}
+
+	return minutes, nil
+}
+
+// contains reports whether t falls within the window. The days of a window
+// that ends on the next day are the days it starts on.
+func (w *timeWindow) contains(t time.Time) bool {
+	t = t.In(w.location)
+	minute := t.Hour()*minutesPerHour + t.Minute()
+	day := t.Weekday()
+
+	if w.start < w.end {
+		return w.days[day] && minute >= w.start && minute < w.end
+	}
+
+	if minute >= w.start {
+		return w.days[day]
+	}
+
+	return minute < w.end && w.days[(day+daysPerWeek-1)%daysPerWeek]
+}
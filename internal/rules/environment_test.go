@@ -0,0 +1,153 @@
+package rules_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/rules"
+)
+
+var _ = Describe("Environment conditions", func() {
+	// 2026-03-04 is a Wednesday.
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	matchAt := func(match *rules.RuleMatch, now time.Time) bool {
+		matcher, err := rules.BuildMatcher(match)
+		Expect(err).NotTo(HaveOccurred())
+
+		return matcher.Match(&rules.MatchContext{
+			Environment: &rules.Environment{Now: func() time.Time { return now }},
+		})
+	}
+
+	// Windows use UTC, so the results do not depend on the local time zone.
+	Describe("TimeWindow", func() {
+		workHours := &rules.TimeWindow{Days: []string{"mon-fri"}, Hours: "09:00-17:00", Timezone: "UTC"}
+		nightShift := &rules.TimeWindow{Days: []string{"fri"}, Hours: "22:00-06:00", Timezone: "UTC"}
+
+		DescribeTable("should match weekday and hour ranges",
+			func(window *rules.TimeWindow, now time.Time, expected bool) {
+				Expect(matchAt(&rules.RuleMatch{TimeWindow: window}, now)).To(Equal(expected))
+			},
+			Entry("inside working hours", workHours, at(4, 9, 0), true),
+			Entry("end is exclusive", workHours, at(4, 17, 0), false),
+			Entry("before working hours", workHours, at(4, 8, 59), false),
+			Entry("on the weekend", workHours, at(7, 12, 0), false),
+			Entry("wrapping day range",
+				&rules.TimeWindow{Days: []string{"sat-mon"}, Timezone: "UTC"}, at(2, 12, 0), true),
+			Entry("full day names",
+				&rules.TimeWindow{Days: []string{"Wednesday"}, Timezone: "UTC"}, at(4, 0, 0), true),
+			Entry("overnight hours on the start day", nightShift, at(6, 23, 30), true),
+			Entry("overnight hours on the next day", nightShift, at(7, 5, 59), true),
+			Entry("overnight hours from the day before", nightShift, at(6, 5, 0), false),
+			Entry("hours until midnight",
+				&rules.TimeWindow{Hours: "18:00-24:00", Timezone: "UTC"}, at(4, 23, 59), true),
+		)
+
+		It("should apply the timezone", func() {
+			window := &rules.TimeWindow{Hours: "09:00-17:00", Timezone: "Asia/Tokyo"}
+
+			// 01:00 UTC is 10:00 in Tokyo.
+			Expect(matchAt(&rules.RuleMatch{TimeWindow: window}, at(4, 1, 0))).To(BeTrue())
+			Expect(matchAt(&rules.RuleMatch{TimeWindow: window}, at(4, 10, 0))).To(BeFalse())
+		})
+
+		DescribeTable("should reject invalid windows",
+			func(window *rules.TimeWindow, message string) {
+				err := window.Validate()
+				Expect(err).To(MatchError(rules.ErrInvalidTimeWindow))
+				Expect(err.Error()).To(ContainSubstring(message))
+
+				_, err = rules.BuildMatcher(&rules.RuleMatch{TimeWindow: window})
+				Expect(err).To(HaveOccurred())
+			},
+			Entry("unknown day", &rules.TimeWindow{Days: []string{"mon-fry"}}, `unknown day "mon-fry"`),
+			Entry("missing range", &rules.TimeWindow{Hours: "09:00"}, "must be HH:MM-HH:MM"),
+			Entry("invalid time", &rules.TimeWindow{Hours: "09:00-25:00"}, `invalid time "25:00"`),
+			Entry("empty range", &rules.TimeWindow{Hours: "09:00-09:00"}, "empty range"),
+			Entry("unknown timezone", &rules.TimeWindow{Timezone: "Mars/Olympus"}, "unknown timezone"),
+		)
+	})
+
+	Describe("Env", func() {
+		env := map[string]string{"CI": "true", "KLAUDIUSH_PROFILE": "spike"}
+
+		matchEnv := func(match *rules.RuleMatch) bool {
+			matcher, err := rules.BuildMatcher(match)
+			Expect(err).NotTo(HaveOccurred())
+
+			return matcher.Match(&rules.MatchContext{
+				Environment: &rules.Environment{
+					LookupEnv: func(key string) (string, bool) {
+						value, ok := env[key]
+
+						return value, ok
+					},
+				},
+			})
+		}
+
+		It("should require every variable to match", func() {
+			Expect(matchEnv(&rules.RuleMatch{Env: map[string]string{"KLAUDIUSH_PROFILE": "spike"}})).
+				To(BeTrue())
+			Expect(matchEnv(&rules.RuleMatch{
+				Env: map[string]string{"CI": "true", "KLAUDIUSH_PROFILE": "prod"},
+			})).To(BeFalse())
+		})
+
+		It("should treat unset variables as empty", func() {
+			Expect(matchEnv(&rules.RuleMatch{Env: map[string]string{"DEPLOY_TOKEN": "!true"}})).
+				To(BeTrue())
+			Expect(matchEnv(&rules.RuleMatch{Env: map[string]string{"CI": "!true"}})).To(BeFalse())
+		})
+	})
+
+	Describe("HostnamePattern", func() {
+		matchHost := func(pattern string, hostname func() (string, error)) bool {
+			matcher, err := rules.BuildMatcher(&rules.RuleMatch{HostnamePattern: pattern})
+			Expect(err).NotTo(HaveOccurred())
+
+			return matcher.Match(&rules.MatchContext{
+				Environment: &rules.Environment{Hostname: hostname},
+			})
+		}
+
+		It("should match the host name", func() {
+			hostname := func() (string, error) { return "build-07.ci.example.com", nil }
+
+			Expect(matchHost("build-*.ci.example.com", hostname)).To(BeTrue())
+			Expect(matchHost("dev-*", hostname)).To(BeFalse())
+		})
+
+		It("should not match when the host name is unavailable", func() {
+			Expect(matchHost("*", func() (string, error) { return "", errors.New("no hostname") })).
+				To(BeFalse())
+		})
+	})
+
+	It("should use the engine environment", func() {
+		engine, err := rules.NewRuleEngine([]*rules.Rule{
+			{
+				Name:    "freeze",
+				Enabled: true,
+				Match: &rules.RuleMatch{
+					TimeWindow: &rules.TimeWindow{Days: []string{"sat-sun"}, Timezone: "UTC"},
+				},
+				Action: &rules.RuleAction{Type: rules.ActionBlock, Message: "weekend freeze"},
+			},
+		}, rules.WithEnvironment(&rules.Environment{
+			Now: func() time.Time { return at(7, 12, 0) },
+		}))
+		Expect(err).NotTo(HaveOccurred())
+
+		result := engine.Evaluate(context.Background(), &rules.MatchContext{})
+		Expect(result.Matched).To(BeTrue())
+		Expect(result.Message).To(Equal("weekend freeze"))
+	})
+})
@@ -10,6 +10,9 @@ type Evaluator struct {
 
 	// defaultAction is the action to take when no rules match.
 	defaultAction ActionType
+
+	// stats records rule matches, if set.
+	stats *Stats
 }
 
 // EvaluatorOption configures an Evaluator.
@@ -29,6 +32,13 @@ func WithDefaultAction(action ActionType) EvaluatorOption {
 	}
 }
 
+// WithStats records the matches of Evaluate in stats.
+func WithStats(stats *Stats) EvaluatorOption {
+	return func(e *Evaluator) {
+		e.stats = stats
+	}
+}
+
 // NewEvaluator creates a new rule evaluator.
 func NewEvaluator(registry *Registry, opts ...EvaluatorOption) *Evaluator {
 	e := &Evaluator{
@@ -64,15 +74,17 @@ func (e *Evaluator) Evaluate(ctx *MatchContext) *RuleResult {
 	}
 
 	// Rules are already sorted by priority (highest first).
-	for _, compiled := range rules {
-		if compiled.Matcher.Match(ctx) {
-			return &RuleResult{
-				Matched:   true,
-				Rule:      compiled.Rule,
-				Action:    compiled.Rule.Action.Type,
-				Message:   compiled.Rule.Action.Message,
-				Reference: compiled.Rule.Action.Reference,
-			}
+	for i, compiled := range rules {
+		ctx.Captures = nil
+
+		if !ctx.accepts(compiled.Rule) || !compiled.Matcher.Match(ctx) {
+			continue
+		}
+
+		if result := compiled.result(ctx); result != nil {
+			e.record(ctx, result, rules[i+1:])
+
+			return result
 		}
 	}
 
@@ -83,6 +95,38 @@ func (e *Evaluator) Evaluate(ctx *MatchContext) *RuleResult {
 	}
 }
 
+// record records a match in the stats. Under stop_on_first_match, the rules
+// after the matched rule are matched as well, to record the rules it shadows.
+// Rules that would query git state, the environment or the host, or build a
+// git context no rule needed yet, are skipped to keep matches cheap.
+func (e *Evaluator) record(ctx *MatchContext, result *RuleResult, rest []*CompiledRule) {
+	if e.stats == nil {
+		return
+	}
+
+	e.stats.RecordHit(result.Rule.Name, result.Action)
+
+	if !e.stopOnFirstMatch {
+		return
+	}
+
+	captures := ctx.Captures
+
+	for _, compiled := range rest {
+		if compiled.cost == costLookup || (compiled.cost == costGitContext && !ctx.hasGit()) {
+			continue
+		}
+
+		ctx.Captures = nil
+
+		if ctx.accepts(compiled.Rule) && compiled.Matcher.Match(ctx) {
+			e.stats.RecordShadowed(compiled.Rule.Name, result.Rule.Name)
+		}
+	}
+
+	ctx.Captures = captures
+}
+
 // EvaluateAll evaluates all enabled rules and returns all matching results.
 // Results are ordered by priority (highest first).
 func (e *Evaluator) EvaluateAll(ctx *MatchContext) []*RuleResult {
@@ -98,14 +142,14 @@ func (e *Evaluator) EvaluateAll(ctx *MatchContext) []*RuleResult {
 	var results []*RuleResult
 
 	for _, compiled := range rules {
-		if compiled.Matcher.Match(ctx) {
-			results = append(results, &RuleResult{
-				Matched:   true,
-				Rule:      compiled.Rule,
-				Action:    compiled.Rule.Action.Type,
-				Message:   compiled.Rule.Action.Message,
-				Reference: compiled.Rule.Action.Reference,
-			})
+		ctx.Captures = nil
+
+		if !ctx.accepts(compiled.Rule) || !compiled.Matcher.Match(ctx) {
+			continue
+		}
+
+		if result := compiled.result(ctx); result != nil {
+			results = append(results, result)
 		}
 	}
 
@@ -127,7 +171,7 @@ func (e *Evaluator) FindMatchingRules(ctx *MatchContext) []*Rule {
 	var matching []*Rule
 
 	for _, compiled := range rules {
-		if compiled.Matcher.Match(ctx) {
+		if ctx.accepts(compiled.Rule) && compiled.Matcher.Match(ctx) {
 			matching = append(matching, compiled.Rule)
 		}
 	}
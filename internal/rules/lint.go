@@ -0,0 +1,428 @@
+package rules
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+// LintKind classifies a lint finding.
+type LintKind string
+
+// Lint finding kinds.
+const (
+	// LintConflictingActions is two rules with identical match conditions
+	// but different actions. Only the first evaluated one takes effect.
+	LintConflictingActions LintKind = "conflicting_actions"
+
+	// LintUnreachableBlock is a block rule that never takes effect because
+	// an allow rule evaluated before it matches everything it matches.
+	LintUnreachableBlock LintKind = "unreachable_block"
+
+	// LintOverlappingPatterns is two rules with different actions whose
+	// patterns for the same condition match a common value.
+	LintOverlappingPatterns LintKind = "overlapping_patterns"
+
+	// LintUnknownValidatorType is a validator_type that matches no
+	// validator.
+	LintUnknownValidatorType LintKind = "unknown_validator_type"
+)
+
+// patternFields are the RuleMatch fields holding a single pattern, by field
+// name, with their config keys.
+var patternFields = map[string]string{
+	"RepoPattern":          "repo_pattern",
+	"BranchPattern":        "branch_pattern",
+	"FilePattern":          "file_pattern",
+	"ContentPattern":       "content_pattern",
+	"CommandPattern":       "command_pattern",
+	"ArgPattern":           "arg_pattern",
+	"StagedFilePattern":    "staged_file_pattern",
+	"ModifiedFilePattern":  "modified_file_pattern",
+	"UntrackedFilePattern": "untracked_file_pattern",
+	"HostnamePattern":      "hostname_pattern",
+}
+
+// LintFinding is a problem found across rules.
+type LintFinding struct {
+	Kind    LintKind `json:"kind"`
+	Rules   []string `json:"rules"`
+	Message string   `json:"message"`
+}
+
+// Lint analyzes enabled rules for conflicts, shadowing and unknown validator
+// types. Rules are analyzed in evaluation order: by priority (highest first),
+// then by name. The analysis is conservative: it only reports conflicts it
+// can prove, so rules combining many conditions may hide some.
+func Lint(rules []*Rule) []LintFinding {
+	ordered := make([]*Rule, 0, len(rules))
+
+	for _, rule := range rules {
+		if rule.Enabled && rule.Action != nil {
+			ordered = append(ordered, rule)
+		}
+	}
+
+	slices.SortStableFunc(ordered, func(a, b *Rule) int {
+		if result := cmp.Compare(b.Priority, a.Priority); result != 0 {
+			return result
+		}
+
+		return cmp.Compare(a.Name, b.Name)
+	})
+
+	var findings []LintFinding
+
+	for _, rule := range ordered {
+		if finding, ok := lintValidatorType(rule); ok {
+			findings = append(findings, finding)
+		}
+	}
+
+	for i, first := range ordered {
+		for _, second := range ordered[i+1:] {
+			if finding, ok := lintPair(first, second); ok {
+				findings = append(findings, finding)
+			}
+		}
+	}
+
+	return findings
+}
+
+// lintValidatorType reports a validator_type of the rule, or of its nested
+// match blocks, that matches no known validator type.
+func lintValidatorType(rule *Rule) (LintFinding, bool) {
+	for _, validatorType := range validatorTypes(rule.Match) {
+		known := slices.ContainsFunc(KnownValidatorTypes, func(known ValidatorType) bool {
+			return validatorTypeCovers(validatorType, known)
+		})
+
+		if !known {
+			return LintFinding{
+				Kind:  LintUnknownValidatorType,
+				Rules: []string{rule.Name},
+				Message: fmt.Sprintf(
+					"rule %q has validator_type %q, which matches no validator (rule will never match)",
+					rule.Name,
+					validatorType,
+				),
+			}, true
+		}
+	}
+
+	return LintFinding{}, false
+}
+
+// validatorTypes returns the validator types of a match block and its nested
+// match blocks.
+func validatorTypes(match *RuleMatch) []ValidatorType {
+	if match == nil {
+		return nil
+	}
+
+	var types []ValidatorType
+
+	if match.ValidatorType != "" {
+		types = append(types, match.ValidatorType)
+	}
+
+	for _, nested := range slices.Concat(match.Any, match.All, []*RuleMatch{match.Not}) {
+		types = append(types, validatorTypes(nested)...)
+	}
+
+	return types
+}
+
+// lintPair reports a conflict between two rules, the first of which is
+// evaluated first.
+func lintPair(first, second *Rule) (LintFinding, bool) {
+	if first.Action.Type == second.Action.Type {
+		return LintFinding{}, false
+	}
+
+	names := []string{first.Name, second.Name}
+
+	if reflect.DeepEqual(first.Match, second.Match) {
+		return LintFinding{
+			Kind:  LintConflictingActions,
+			Rules: names,
+			Message: fmt.Sprintf(
+				"rules %q (%s) and %q (%s) have identical match conditions but different actions; only %q takes effect",
+				first.Name, first.Action.Type, second.Name, second.Action.Type, first.Name,
+			),
+		}, true
+	}
+
+	if first.Action.Type == ActionAllow && second.Action.Type == ActionBlock &&
+		matchCovers(first.Match, second.Match) {
+		return LintFinding{
+			Kind:  LintUnreachableBlock,
+			Rules: names,
+			Message: fmt.Sprintf(
+				"allow rule %q (priority %d) matches everything block rule %q (priority %d) matches, so %q never blocks",
+				first.Name, first.Priority, second.Name, second.Priority, second.Name,
+			),
+		}, true
+	}
+
+	if key, firstPattern, secondPattern, ok := overlappingPatterns(first.Match, second.Match); ok {
+		return LintFinding{
+			Kind:  LintOverlappingPatterns,
+			Rules: names,
+			Message: fmt.Sprintf(
+				"rules %q (%s) and %q (%s) have overlapping %s patterns %q and %q; %q takes precedence where both match",
+				first.Name, first.Action.Type, second.Name, second.Action.Type,
+				key, firstPattern, secondPattern, first.Name,
+			),
+		}, true
+	}
+
+	return LintFinding{}, false
+}
+
+// matchCovers reports whether a matches everything b matches. Every condition
+// of a must be a condition of b with the same value, a pattern matching b's
+// literal value, or a validator type wildcard covering b's validator type.
+// Both must apply to the same event type, PreToolUse if unset, and a
+// case-sensitive a cannot cover a case-insensitive b.
+func matchCovers(a, b *RuleMatch) bool {
+	if a == nil {
+		a = &RuleMatch{}
+	}
+
+	if b == nil {
+		b = &RuleMatch{}
+	}
+
+	if !strings.EqualFold(eventTypeOrDefault(a.EventType), eventTypeOrDefault(b.EventType)) {
+		return false
+	}
+
+	if b.CaseInsensitive && !a.CaseInsensitive {
+		return false
+	}
+
+	va := reflect.ValueOf(*a)
+	vb := reflect.ValueOf(*b)
+
+	for i := range va.NumField() {
+		name := va.Type().Field(i).Name
+
+		fa := va.Field(i)
+		if fa.IsZero() || name == "EventType" || name == "CaseInsensitive" {
+			continue
+		}
+
+		fb := vb.Field(i)
+		if fb.IsZero() {
+			return false
+		}
+
+		switch {
+		case name == "ValidatorType":
+			if !validatorTypeCovers(a.ValidatorType, b.ValidatorType) {
+				return false
+			}
+		case patternFields[name] != "":
+			if !patternCovers(fa.String(), fb.String()) {
+				return false
+			}
+		default:
+			if !reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// eventTypeOrDefault returns the event type of a condition, PreToolUse if it
+// is unset.
+func eventTypeOrDefault(eventType string) string {
+	if eventType == "" {
+		return hook.EventTypePreToolUse.String()
+	}
+
+	return eventType
+}
+
+// overlappingPatterns returns the config key and the patterns of a pattern
+// condition on which a and b differ but match a common value. All other
+// conditions the two share must be equal, or overlapping validator types.
+func overlappingPatterns(a, b *RuleMatch) (key, aPattern, bPattern string, found bool) {
+	if a == nil || b == nil {
+		return "", "", "", false
+	}
+
+	va := reflect.ValueOf(*a)
+	vb := reflect.ValueOf(*b)
+
+	for i := range va.NumField() {
+		fa := va.Field(i)
+		fb := vb.Field(i)
+
+		if fa.IsZero() || fb.IsZero() || reflect.DeepEqual(fa.Interface(), fb.Interface()) {
+			continue
+		}
+
+		name := va.Type().Field(i).Name
+
+		switch {
+		case name == "ValidatorType":
+			if !validatorTypeCovers(a.ValidatorType, b.ValidatorType) &&
+				!validatorTypeCovers(b.ValidatorType, a.ValidatorType) {
+				return "", "", "", false
+			}
+		case patternFields[name] != "":
+			if !patternsOverlap(fa.String(), fb.String()) {
+				return "", "", "", false
+			}
+
+			if !found {
+				key, aPattern, bPattern, found = patternFields[name], fa.String(), fb.String(), true
+			}
+		default:
+			return "", "", "", false
+		}
+	}
+
+	return key, aPattern, bPattern, found
+}
+
+// validatorTypeCovers reports whether validator type a matches every
+// validator b matches.
+func validatorTypeCovers(a, b ValidatorType) bool {
+	if a == "" || a == ValidatorAll || a == b {
+		return true
+	}
+
+	prefix, ok := strings.CutSuffix(string(a), ".*")
+
+	return ok && strings.HasPrefix(string(b), prefix+".")
+}
+
+// patternCovers reports whether pattern a matches everything pattern b
+// matches: b is the same pattern or a literal value that a matches, or a
+// matches anything.
+func patternCovers(a, b string) bool {
+	if a == b || a == "**" || a == ".*" {
+		return true
+	}
+
+	if IsNegated(b) || DetectPatternType(b) != PatternTypeGlob || strings.ContainsAny(b, "*?[{\\") {
+		return false
+	}
+
+	pattern, err := CompilePattern(a)
+
+	return err == nil && pattern.Match(b)
+}
+
+// patternsOverlap reports whether some value matches both patterns. A value
+// matching one pattern is derived from each and checked against both.
+func patternsOverlap(a, b string) bool {
+	if IsNegated(a) || IsNegated(b) {
+		return false
+	}
+
+	patternA, errA := CompilePattern(a)
+	patternB, errB := CompilePattern(b)
+
+	if errA != nil || errB != nil {
+		return false
+	}
+
+	for _, pattern := range []string{a, b} {
+		example, ok := patternExample(pattern)
+		if ok && patternA.Match(example) && patternB.Match(example) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// patternExample returns a value that the pattern likely matches.
+func patternExample(pattern string) (string, bool) {
+	if DetectPatternType(pattern) == PatternTypeRegex {
+		re, err := syntax.Parse(pattern, syntax.Perl)
+		if err != nil {
+			return "", false
+		}
+
+		var sb strings.Builder
+
+		writeRegexExample(&sb, re.Simplify())
+
+		return sb.String(), true
+	}
+
+	return globExample(pattern)
+}
+
+// globExample replaces the wildcards of a glob pattern with a letter.
+func globExample(pattern string) (string, bool) {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			sb.WriteByte('a')
+
+			for i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+			}
+		case '?':
+			sb.WriteByte('a')
+		case '\\':
+			if i+1 < len(pattern) {
+				i++
+				sb.WriteByte(pattern[i])
+			}
+		case '[', '{':
+			// Character classes and alternatives are not expanded.
+			return "", false
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), true
+}
+
+// writeRegexExample writes a shortest string matching the simplified regex.
+func writeRegexExample(sb *strings.Builder, re *syntax.Regexp) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) > 0 && utf8.ValidRune(re.Rune[0]) {
+			sb.WriteRune(re.Rune[0])
+		}
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte('a')
+	case syntax.OpCapture, syntax.OpPlus:
+		writeRegexExample(sb, re.Sub[0])
+	case syntax.OpRepeat:
+		for range re.Min {
+			writeRegexExample(sb, re.Sub[0])
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeRegexExample(sb, sub)
+		}
+	case syntax.OpAlternate:
+		writeRegexExample(sb, re.Sub[0])
+	default:
+		// Anchors, empty matches, and optional or repeated subexpressions
+		// match the empty string.
+	}
+}
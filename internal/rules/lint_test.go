@@ -0,0 +1,189 @@
+package rules_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/rules"
+)
+
+var _ = Describe("Lint", func() {
+	newRule := func(name string, priority int, action rules.ActionType, match *rules.RuleMatch) *rules.Rule {
+		return &rules.Rule{
+			Name:     name,
+			Enabled:  true,
+			Priority: priority,
+			Match:    match,
+			Action:   &rules.RuleAction{Type: action},
+		}
+	}
+
+	kinds := func(findings []rules.LintFinding) []rules.LintKind {
+		result := make([]rules.LintKind, 0, len(findings))
+		for _, finding := range findings {
+			result = append(result, finding.Kind)
+		}
+
+		return result
+	}
+
+	It("should report identical conditions with different actions", func() {
+		findings := rules.Lint([]*rules.Rule{
+			newRule("warn-main", 10, rules.ActionWarn, &rules.RuleMatch{
+				ValidatorType: rules.ValidatorGitPush, BranchPattern: "main",
+			}),
+			newRule("block-main", 10, rules.ActionBlock, &rules.RuleMatch{
+				ValidatorType: rules.ValidatorGitPush, BranchPattern: "main",
+			}),
+		})
+
+		Expect(findings).To(HaveLen(1))
+		Expect(findings[0].Kind).To(Equal(rules.LintConflictingActions))
+		Expect(findings[0].Rules).To(Equal([]string{"block-main", "warn-main"}))
+		Expect(findings[0].Message).To(ContainSubstring(`only "block-main" takes effect`))
+	})
+
+	DescribeTable("should report block rules shadowed by a higher-priority allow rule",
+		func(allow, block *rules.RuleMatch) {
+			findings := rules.Lint([]*rules.Rule{
+				newRule("block", 10, rules.ActionBlock, block),
+				newRule("allow", 100, rules.ActionAllow, allow),
+			})
+
+			Expect(kinds(findings)).To(Equal([]rules.LintKind{rules.LintUnreachableBlock}))
+			Expect(findings[0].Rules).To(Equal([]string{"allow", "block"}))
+		},
+		Entry("fewer conditions",
+			&rules.RuleMatch{ValidatorType: rules.ValidatorGitPush},
+			&rules.RuleMatch{ValidatorType: rules.ValidatorGitPush, Remote: "origin"},
+		),
+		Entry("validator type wildcard",
+			&rules.RuleMatch{ValidatorType: rules.ValidatorGitAll, BranchPattern: "main"},
+			&rules.RuleMatch{ValidatorType: rules.ValidatorGitPush, BranchPattern: "main"},
+		),
+		Entry("pattern matching a literal",
+			&rules.RuleMatch{BranchPattern: "release/*"},
+			&rules.RuleMatch{BranchPattern: "release/v1"},
+		),
+		Entry("no match conditions", nil, &rules.RuleMatch{FilePattern: "**/.env"}),
+		Entry("default event type",
+			&rules.RuleMatch{CommandPattern: "rm *"},
+			&rules.RuleMatch{CommandPattern: "rm -rf", EventType: "PreToolUse"},
+		),
+		Entry("case-insensitive allow rule",
+			&rules.RuleMatch{BranchPattern: "main", CaseInsensitive: true},
+			&rules.RuleMatch{BranchPattern: "main"},
+		),
+	)
+
+	DescribeTable("should not report block rules for other events or cases",
+		func(allow, block *rules.RuleMatch) {
+			findings := rules.Lint([]*rules.Rule{
+				newRule("block", 10, rules.ActionBlock, block),
+				newRule("allow", 100, rules.ActionAllow, allow),
+			})
+
+			Expect(kinds(findings)).NotTo(ContainElement(rules.LintUnreachableBlock))
+		},
+		Entry("block rule for another event type",
+			&rules.RuleMatch{CommandPattern: "rm *"},
+			&rules.RuleMatch{CommandPattern: "rm -rf", EventType: "PostToolUse"},
+		),
+		Entry("allow rule without conditions and block rule for another event type",
+			nil,
+			&rules.RuleMatch{EventType: "PostToolUse", FilePattern: "**/.env"},
+		),
+		Entry("case-insensitive block rule",
+			&rules.RuleMatch{BranchPattern: "main"},
+			&rules.RuleMatch{BranchPattern: "main", CaseInsensitive: true},
+		),
+	)
+
+	It("should not report block rules the allow rule does not cover", func() {
+		findings := rules.Lint([]*rules.Rule{
+			newRule("allow", 100, rules.ActionAllow, &rules.RuleMatch{
+				ValidatorType: rules.ValidatorGitPush, Remote: "origin",
+			}),
+			newRule("block", 10, rules.ActionBlock, &rules.RuleMatch{
+				ValidatorType: rules.ValidatorGitPush,
+			}),
+			newRule("block-later", 200, rules.ActionBlock, &rules.RuleMatch{
+				ValidatorType: rules.ValidatorGitPush, Remote: "origin", BranchPattern: "main",
+			}),
+		})
+
+		Expect(findings).To(BeEmpty())
+	})
+
+	DescribeTable("should report overlapping glob and regex patterns",
+		func(first, second string, expected bool) {
+			findings := rules.Lint([]*rules.Rule{
+				newRule("warn-files", 10, rules.ActionWarn, &rules.RuleMatch{FilePattern: first}),
+				newRule("block-files", 5, rules.ActionBlock, &rules.RuleMatch{FilePattern: second}),
+			})
+
+			if !expected {
+				Expect(findings).To(BeEmpty())
+
+				return
+			}
+
+			Expect(kinds(findings)).To(Equal([]rules.LintKind{rules.LintOverlappingPatterns}))
+			Expect(findings[0].Message).To(ContainSubstring("overlapping file_pattern patterns"))
+		},
+		Entry("glob and regex", "**/*.go", `^src/.*\.go$`, true),
+		Entry("two globs", "src/**", "src/*_test.go", true),
+		Entry("regex alternatives", `\.(tf|tfvars)$`, "*.tfvars", true),
+		Entry("disjoint globs", "**/*.go", "**/*.md", false),
+		Entry("disjoint glob and regex", "docs/**", `^src/.*\.go$`, false),
+	)
+
+	It("should not report overlapping patterns of rules with other differing conditions", func() {
+		findings := rules.Lint([]*rules.Rule{
+			newRule("warn", 10, rules.ActionWarn, &rules.RuleMatch{
+				ToolType: "Write", FilePattern: "**/*.go",
+			}),
+			newRule("block", 5, rules.ActionBlock, &rules.RuleMatch{
+				ToolType: "Edit", FilePattern: "src/*.go",
+			}),
+		})
+
+		Expect(findings).To(BeEmpty())
+	})
+
+	DescribeTable("should check validator types",
+		func(validatorType rules.ValidatorType, known bool) {
+			findings := rules.Lint([]*rules.Rule{
+				newRule("rule", 0, rules.ActionBlock, &rules.RuleMatch{
+					Any: []*rules.RuleMatch{{ValidatorType: validatorType}},
+				}),
+			})
+
+			if known {
+				Expect(findings).To(BeEmpty())
+
+				return
+			}
+
+			Expect(kinds(findings)).To(Equal([]rules.LintKind{rules.LintUnknownValidatorType}))
+			Expect(findings[0].Message).To(ContainSubstring(string(validatorType)))
+		},
+		Entry("registered validator", rules.ValidatorGitPush, true),
+		Entry("category wildcard", rules.ValidatorFileAll, true),
+		Entry("everything", rules.ValidatorAll, true),
+		Entry("misspelled validator", rules.ValidatorType("git.psuh"), false),
+		Entry("unknown category", rules.ValidatorType("docker.*"), false),
+	)
+
+	It("should skip disabled rules", func() {
+		disabled := newRule("warn-main", 10, rules.ActionWarn, &rules.RuleMatch{BranchPattern: "main"})
+		disabled.Enabled = false
+
+		findings := rules.Lint([]*rules.Rule{
+			disabled,
+			newRule("block-main", 10, rules.ActionBlock, &rules.RuleMatch{BranchPattern: "main"}),
+		})
+
+		Expect(findings).To(BeEmpty())
+	})
+})
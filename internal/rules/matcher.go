@@ -1,9 +1,14 @@
 package rules
 
 import (
+	"fmt"
+	"maps"
+	"path"
+	"slices"
 	"strings"
 
 	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
 )
 
 // RepoPatternMatcher matches against the repository root path.
@@ -54,11 +59,12 @@ func NewRepoMultiPatternMatcher(
 
 // Match returns true if the repo root matches the pattern.
 func (m *RepoPatternMatcher) Match(ctx *MatchContext) bool {
-	if ctx.GitContext == nil || ctx.GitContext.RepoRoot == "" {
+	gitCtx := ctx.Git()
+	if gitCtx == nil || gitCtx.RepoRoot == "" {
 		return false
 	}
 
-	return m.pattern.Match(ctx.GitContext.RepoRoot)
+	return matchCapture(ctx, m.pattern, gitCtx.RepoRoot)
 }
 
 // Name returns the matcher name.
@@ -66,6 +72,18 @@ func (m *RepoPatternMatcher) Name() string {
 	return "repo_pattern:" + m.pattern.String()
 }
 
+// matchCapture matches s against the pattern and records its named capture
+// groups in the match context on success.
+func matchCapture(ctx *MatchContext, pattern Pattern, s string) bool {
+	if !pattern.Match(s) {
+		return false
+	}
+
+	ctx.capture(pattern, s)
+
+	return true
+}
+
 // RemoteMatcher matches against the git remote name.
 type RemoteMatcher struct {
 	remote string
@@ -78,11 +96,12 @@ func NewRemoteMatcher(remote string) *RemoteMatcher {
 
 // Match returns true if the remote matches exactly.
 func (m *RemoteMatcher) Match(ctx *MatchContext) bool {
-	if ctx.GitContext == nil {
+	gitCtx := ctx.Git()
+	if gitCtx == nil {
 		return false
 	}
 
-	return ctx.GitContext.Remote == m.remote
+	return gitCtx.Remote == m.remote
 }
 
 // Name returns the matcher name.
@@ -138,11 +157,12 @@ func NewBranchMultiPatternMatcher(
 
 // Match returns true if the branch matches the pattern.
 func (m *BranchPatternMatcher) Match(ctx *MatchContext) bool {
-	if ctx.GitContext == nil || ctx.GitContext.Branch == "" {
+	gitCtx := ctx.Git()
+	if gitCtx == nil || gitCtx.Branch == "" {
 		return false
 	}
 
-	return m.pattern.Match(ctx.GitContext.Branch)
+	return matchCapture(ctx, m.pattern, gitCtx.Branch)
 }
 
 // Name returns the matcher name.
@@ -201,13 +221,13 @@ func (m *FilePatternMatcher) Match(ctx *MatchContext) bool {
 	if ctx.FileContext == nil || ctx.FileContext.Path == "" {
 		// Fall back to hook context file path.
 		if ctx.HookContext != nil {
-			return m.pattern.Match(ctx.HookContext.GetFilePath())
+			return matchCapture(ctx, m.pattern, ctx.HookContext.GetFilePath())
 		}
 
 		return false
 	}
 
-	return m.pattern.Match(ctx.FileContext.Path)
+	return matchCapture(ctx, m.pattern, ctx.FileContext.Path)
 }
 
 // Name returns the matcher name.
@@ -318,15 +338,15 @@ func NewContentMultiPatternMatcher(
 // Match returns true if the file content matches the pattern.
 func (m *ContentPatternMatcher) Match(ctx *MatchContext) bool {
 	if ctx.FileContext == nil || ctx.FileContext.Content == "" {
-		// Fall back to hook context content.
+		// Fall back to hook context content (Write content or Edit/MultiEdit new strings).
 		if ctx.HookContext != nil {
-			return m.pattern.Match(ctx.HookContext.GetContent())
+			return matchCapture(ctx, m.pattern, ctx.HookContext.GetNewContent())
 		}
 
 		return false
 	}
 
-	return m.pattern.Match(ctx.FileContext.Content)
+	return matchCapture(ctx, m.pattern, ctx.FileContext.Content)
 }
 
 // Name returns the matcher name.
@@ -383,11 +403,11 @@ func NewCommandMultiPatternMatcher(
 // Match returns true if the command matches the pattern.
 func (m *CommandPatternMatcher) Match(ctx *MatchContext) bool {
 	if ctx.Command != "" {
-		return m.pattern.Match(ctx.Command)
+		return matchCapture(ctx, m.pattern, ctx.Command)
 	}
 
 	if ctx.HookContext != nil {
-		return m.pattern.Match(ctx.HookContext.GetCommand())
+		return matchCapture(ctx, m.pattern, ctx.HookContext.GetCommand())
 	}
 
 	return false
@@ -398,6 +418,517 @@ func (m *CommandPatternMatcher) Name() string {
 	return "command_pattern:" + m.pattern.String()
 }
 
+// CommandStructureMatcher matches against the commands parsed from a bash
+// command, including commands in chains, pipelines, subshells and command
+// substitutions. It matches if a single parsed command satisfies all of its
+// conditions.
+type CommandStructureMatcher struct {
+	name          Pattern
+	gitSubcommand string
+	flags         []string
+	flagMode      MultiPatternMode
+	arg           Pattern
+	inPipeline    *bool
+	dynamicArgs   *bool
+}
+
+// NewCommandStructureMatcher creates a matcher for the structured command
+// conditions of match. Returns nil if it has none.
+func NewCommandStructureMatcher(
+	match *RuleMatch,
+	opts PatternOptions,
+) (*CommandStructureMatcher, error) {
+	flags := match.HasFlags
+	if len(flags) == 0 && match.HasFlag != "" {
+		flags = []string{match.HasFlag}
+	}
+
+	if match.CommandName == "" && match.GitSubcommand == "" && len(flags) == 0 &&
+		match.ArgPattern == "" && match.InPipeline == nil && match.DynamicArgs == nil {
+		return nil, nil //nolint:nilnil // no conditions is valid
+	}
+
+	m := &CommandStructureMatcher{
+		gitSubcommand: match.GitSubcommand,
+		flags:         flags,
+		flagMode:      parsePatternMode(match.FlagMode),
+		inPipeline:    match.InPipeline,
+		dynamicArgs:   match.DynamicArgs,
+	}
+
+	if match.CommandName != "" {
+		pattern, err := CompilePatternWithOptions(match.CommandName, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		m.name = pattern
+	}
+
+	if match.ArgPattern != "" {
+		pattern, err := CompilePatternWithOptions(match.ArgPattern, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		m.arg = pattern
+	}
+
+	return m, nil
+}
+
+// Match returns true if any parsed command satisfies all conditions.
+func (m *CommandStructureMatcher) Match(ctx *MatchContext) bool {
+	commands := ctx.ParsedCommands()
+
+	for i := range commands {
+		if m.matchCommand(ctx, &commands[i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchCommand returns true if the command satisfies all conditions. The
+// capture groups of the matching argument are recorded on success.
+func (m *CommandStructureMatcher) matchCommand(ctx *MatchContext, cmd *parser.Command) bool {
+	if m.name != nil && !m.name.Match(cmd.Name) && !m.name.Match(path.Base(cmd.Name)) {
+		return false
+	}
+
+	if m.gitSubcommand != "" {
+		gitCmd, err := parser.ParseGitCommand(*cmd)
+		if err != nil || gitCmd.Subcommand != m.gitSubcommand {
+			return false
+		}
+	}
+
+	if len(m.flags) > 0 && !m.matchFlags(cmd.Name, cmd.Args) {
+		return false
+	}
+
+	argIdx := -1
+	if m.arg != nil {
+		if argIdx = slices.IndexFunc(cmd.Args, m.arg.Match); argIdx < 0 {
+			return false
+		}
+	}
+
+	if m.inPipeline != nil && cmd.InPipeline != *m.inPipeline {
+		return false
+	}
+
+	if m.dynamicArgs != nil && cmd.Unresolved != *m.dynamicArgs {
+		return false
+	}
+
+	if argIdx >= 0 {
+		ctx.capture(m.arg, cmd.Args[argIdx])
+	}
+
+	return true
+}
+
+// matchFlags checks the flags according to the flag mode.
+func (m *CommandStructureMatcher) matchFlags(name string, args []string) bool {
+	hasFlag := func(flag string) bool {
+		return slices.ContainsFunc(args, func(arg string) bool {
+			return argHasFlag(name, arg, flag)
+		})
+	}
+
+	if m.flagMode == MultiPatternAll {
+		for _, flag := range m.flags {
+			if !hasFlag(flag) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return slices.ContainsFunc(m.flags, hasFlag)
+}
+
+// singleDashLongOptionCommands use single-dash long options (find -name,
+// java -jar), so their arguments are never split into combined short flags.
+var singleDashLongOptionCommands = map[string]bool{
+	"clang": true,
+	"find":  true,
+	"gcc":   true,
+	"go":    true,
+	"java":  true,
+	"javac": true,
+	"ld":    true,
+}
+
+// argHasFlag reports whether the argument of the named command is the flag,
+// sets it with a value (--flag=value), or contains it in combined short flags
+// (-rf contains -f). Only all-letter arguments of commands that do not use
+// single-dash long options are treated as combined short flags.
+func argHasFlag(name, arg, flag string) bool {
+	if arg == flag || strings.HasPrefix(arg, flag+"=") {
+		return true
+	}
+
+	isShortFlag := len(flag) == 2 && flag[0] == '-' && flag[1] != '-'
+	isCombined := len(arg) > 2 && arg[0] == '-' && arg[1] != '-' &&
+		!singleDashLongOptionCommands[path.Base(name)] && isLetters(arg[1:])
+
+	return isShortFlag && isCombined && strings.IndexByte(arg[1:], flag[1]) >= 0
+}
+
+// isLetters reports whether s consists of ASCII letters only.
+func isLetters(s string) bool {
+	for _, c := range []byte(s) {
+		if (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Name returns the matcher name.
+func (m *CommandStructureMatcher) Name() string {
+	var parts []string
+
+	if m.name != nil {
+		parts = append(parts, "name="+m.name.String())
+	}
+
+	if m.gitSubcommand != "" {
+		parts = append(parts, "git="+m.gitSubcommand)
+	}
+
+	if len(m.flags) > 0 {
+		parts = append(parts, "flags="+strings.Join(m.flags, ","))
+	}
+
+	if m.arg != nil {
+		parts = append(parts, "arg="+m.arg.String())
+	}
+
+	if m.inPipeline != nil {
+		parts = append(parts, fmt.Sprintf("in_pipeline=%t", *m.inPipeline))
+	}
+
+	if m.dynamicArgs != nil {
+		parts = append(parts, fmt.Sprintf("dynamic_args=%t", *m.dynamicArgs))
+	}
+
+	return "command:" + strings.Join(parts, " ")
+}
+
+// GitStateMatcher matches against the working-tree state of the repository.
+// The state is only queried when the matcher is evaluated, so rules whose
+// other conditions do not match never touch it. It does not match if the
+// state is unavailable.
+type GitStateMatcher struct {
+	staged    Pattern
+	modified  Pattern
+	untracked Pattern
+	dirty     *bool
+	minAhead  int
+	minBehind int
+	author    Pattern
+}
+
+// NewGitStateMatcher creates a matcher for the working-tree conditions of
+// match. Returns nil if it has none.
+func NewGitStateMatcher(match *RuleMatch, opts PatternOptions) (*GitStateMatcher, error) {
+	if match.StagedFilePattern == "" && match.ModifiedFilePattern == "" &&
+		match.UntrackedFilePattern == "" && match.DirtyTree == nil &&
+		match.AheadOfUpstream == 0 && match.BehindUpstream == 0 && match.LastCommitAuthor == "" {
+		return nil, nil //nolint:nilnil // no conditions is valid
+	}
+
+	m := &GitStateMatcher{
+		dirty:     match.DirtyTree,
+		minAhead:  match.AheadOfUpstream,
+		minBehind: match.BehindUpstream,
+	}
+
+	for _, p := range []struct {
+		pattern string
+		target  *Pattern
+	}{
+		{match.StagedFilePattern, &m.staged},
+		{match.ModifiedFilePattern, &m.modified},
+		{match.UntrackedFilePattern, &m.untracked},
+		{match.LastCommitAuthor, &m.author},
+	} {
+		if p.pattern == "" {
+			continue
+		}
+
+		pattern, err := CompilePatternWithOptions(p.pattern, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		*p.target = pattern
+	}
+
+	return m, nil
+}
+
+// Match returns true if the working-tree state satisfies all conditions.
+func (m *GitStateMatcher) Match(ctx *MatchContext) bool {
+	gitCtx := ctx.Git()
+	if gitCtx == nil || gitCtx.State == nil {
+		return false
+	}
+
+	state := gitCtx.State
+
+	if m.staged != nil && !matchAnyFile(ctx, m.staged, state.GetStagedFiles) {
+		return false
+	}
+
+	if m.modified != nil && !matchAnyFile(ctx, m.modified, state.GetModifiedFiles) {
+		return false
+	}
+
+	if m.untracked != nil && !matchAnyFile(ctx, m.untracked, state.GetUntrackedFiles) {
+		return false
+	}
+
+	if m.dirty != nil {
+		dirty, ok := isDirty(state)
+		if !ok || dirty != *m.dirty {
+			return false
+		}
+	}
+
+	if m.minAhead > 0 || m.minBehind > 0 {
+		branch, err := localBranch(gitCtx.Branch, state)
+		if err != nil {
+			return false
+		}
+
+		ahead, behind, err := state.GetAheadBehind(branch)
+		if err != nil || ahead < m.minAhead || behind < m.minBehind {
+			return false
+		}
+	}
+
+	if m.author != nil {
+		author, err := state.GetLastCommitAuthor()
+		if err != nil || !matchCapture(ctx, m.author, author) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// localBranch returns the local branch of the branch in the git context,
+// which may be HEAD or a refspec taken from the command (e.g. "git push
+// origin HEAD:main"): the source side of a refspec, or the current branch
+// for HEAD.
+func localBranch(branch string, state GitState) (string, error) {
+	src, _, _ := strings.Cut(strings.TrimPrefix(branch, "+"), ":")
+	src = strings.TrimPrefix(src, "refs/heads/")
+
+	if src == "" || src == "HEAD" {
+		return state.GetCurrentBranch()
+	}
+
+	return src, nil
+}
+
+// matchAnyFile returns true if any of the listed files matches the pattern,
+// recording the capture groups of the first match.
+func matchAnyFile(ctx *MatchContext, pattern Pattern, list func() ([]string, error)) bool {
+	files, err := list()
+	if err != nil {
+		return false
+	}
+
+	idx := slices.IndexFunc(files, pattern.Match)
+	if idx < 0 {
+		return false
+	}
+
+	ctx.capture(pattern, files[idx])
+
+	return true
+}
+
+// isDirty reports whether tracked files have staged or unstaged changes. The
+// second result is false if the state is unavailable.
+func isDirty(state GitState) (bool, bool) {
+	staged, err := state.GetStagedFiles()
+	if err != nil {
+		return false, false
+	}
+
+	if len(staged) > 0 {
+		return true, true
+	}
+
+	modified, err := state.GetModifiedFiles()
+	if err != nil {
+		return false, false
+	}
+
+	return len(modified) > 0, true
+}
+
+// Name returns the matcher name.
+func (m *GitStateMatcher) Name() string {
+	var parts []string
+
+	if m.staged != nil {
+		parts = append(parts, "staged="+m.staged.String())
+	}
+
+	if m.modified != nil {
+		parts = append(parts, "modified="+m.modified.String())
+	}
+
+	if m.untracked != nil {
+		parts = append(parts, "untracked="+m.untracked.String())
+	}
+
+	if m.dirty != nil {
+		parts = append(parts, fmt.Sprintf("dirty=%t", *m.dirty))
+	}
+
+	if m.minAhead > 0 {
+		parts = append(parts, fmt.Sprintf("ahead>=%d", m.minAhead))
+	}
+
+	if m.minBehind > 0 {
+		parts = append(parts, fmt.Sprintf("behind>=%d", m.minBehind))
+	}
+
+	if m.author != nil {
+		parts = append(parts, "author="+m.author.String())
+	}
+
+	return "git_state:" + strings.Join(parts, " ")
+}
+
+// TimeWindowMatcher matches if the current time is within a time window.
+type TimeWindowMatcher struct {
+	spec   *TimeWindow
+	window *timeWindow
+}
+
+// NewTimeWindowMatcher creates a matcher for the time window.
+func NewTimeWindowMatcher(spec *TimeWindow) (*TimeWindowMatcher, error) {
+	window, err := spec.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	return &TimeWindowMatcher{spec: spec, window: window}, nil
+}
+
+// Match returns true if the current time is within the window.
+func (m *TimeWindowMatcher) Match(ctx *MatchContext) bool {
+	return m.window.contains(ctx.Environment.now())
+}
+
+// Name returns the matcher name.
+func (m *TimeWindowMatcher) Name() string {
+	parts := []string{"days=" + strings.Join(m.spec.Days, ",")}
+
+	if m.spec.Hours != "" {
+		parts = append(parts, "hours="+m.spec.Hours)
+	}
+
+	if m.spec.Timezone != "" {
+		parts = append(parts, "tz="+m.spec.Timezone)
+	}
+
+	return "time_window:" + strings.Join(parts, " ")
+}
+
+// EnvMatcher matches against environment variables. Every variable must
+// match its pattern; unset variables have an empty value.
+type EnvMatcher struct {
+	names    []string
+	patterns map[string]Pattern
+}
+
+// NewEnvMatcher creates a matcher for environment variable patterns.
+func NewEnvMatcher(env map[string]string, opts PatternOptions) (*EnvMatcher, error) {
+	m := &EnvMatcher{
+		names:    slices.Sorted(maps.Keys(env)),
+		patterns: make(map[string]Pattern, len(env)),
+	}
+
+	for name, patternStr := range env {
+		pattern, err := CompilePatternWithOptions(patternStr, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		m.patterns[name] = pattern
+	}
+
+	return m, nil
+}
+
+// Match returns true if every variable matches its pattern.
+func (m *EnvMatcher) Match(ctx *MatchContext) bool {
+	for _, name := range m.names {
+		if !matchCapture(ctx, m.patterns[name], ctx.Environment.getenv(name)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Name returns the matcher name.
+func (m *EnvMatcher) Name() string {
+	parts := make([]string, 0, len(m.names))
+
+	for _, name := range m.names {
+		parts = append(parts, name+"="+m.patterns[name].String())
+	}
+
+	return "env:" + strings.Join(parts, " ")
+}
+
+// HostnamePatternMatcher matches against the host name of the machine.
+type HostnamePatternMatcher struct {
+	pattern Pattern
+}
+
+// NewHostnamePatternMatcher creates a matcher for host name patterns.
+func NewHostnamePatternMatcher(
+	patternStr string,
+	opts PatternOptions,
+) (*HostnamePatternMatcher, error) {
+	pattern, err := CompilePatternWithOptions(patternStr, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HostnamePatternMatcher{pattern: pattern}, nil
+}
+
+// Match returns true if the host name matches the pattern.
+func (m *HostnamePatternMatcher) Match(ctx *MatchContext) bool {
+	hostname, err := ctx.Environment.hostname()
+	if err != nil {
+		return false
+	}
+
+	return matchCapture(ctx, m.pattern, hostname)
+}
+
+// Name returns the matcher name.
+func (m *HostnamePatternMatcher) Name() string {
+	return "hostname_pattern:" + m.pattern.String()
+}
+
 // ValidatorTypeMatcher matches against validator type.
 type ValidatorTypeMatcher struct {
 	validatorType ValidatorType
@@ -580,6 +1111,43 @@ func (m *CompositeMatcher) Name() string {
 	}
 }
 
+// matcherCost classifies the lookups a matcher makes beyond the match context.
+type matcherCost int
+
+const (
+	// costNone means the matcher only reads the match context.
+	costNone matcherCost = iota
+
+	// costGitContext means the matcher reads the git context, which is built
+	// on first use.
+	costGitContext
+
+	// costLookup means the matcher queries the working tree, the environment
+	// or the host on every match.
+	costLookup
+)
+
+// costOf returns the most expensive lookup the matcher or any matcher it
+// combines makes.
+func costOf(matcher Matcher) matcherCost {
+	switch m := matcher.(type) {
+	case *GitStateMatcher, *EnvMatcher, *HostnamePatternMatcher:
+		return costLookup
+	case *RepoPatternMatcher, *RemoteMatcher, *BranchPatternMatcher:
+		return costGitContext
+	case *CompositeMatcher:
+		cost := costNone
+
+		for _, child := range m.matchers {
+			cost = max(cost, costOf(child))
+		}
+
+		return cost
+	default:
+		return costNone
+	}
+}
+
 // matcherBuilder is a helper for building matchers with error handling.
 type matcherBuilder struct {
 	matchers []Matcher
@@ -662,6 +1230,139 @@ func (b *matcherBuilder) addAdvancedPatternMatcher(
 	b.matchers = append(b.matchers, m)
 }
 
+// addCommandStructureMatcher adds a matcher for the structured command
+// conditions, if any are set.
+func (b *matcherBuilder) addCommandStructureMatcher(match *RuleMatch) {
+	if b.err != nil {
+		return
+	}
+
+	m, err := NewCommandStructureMatcher(match, b.opts)
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	if m != nil {
+		b.matchers = append(b.matchers, m)
+	}
+}
+
+// addGitStateMatcher adds a matcher for the working-tree conditions, if any
+// are set.
+func (b *matcherBuilder) addGitStateMatcher(match *RuleMatch) {
+	if b.err != nil {
+		return
+	}
+
+	m, err := NewGitStateMatcher(match, b.opts)
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	if m != nil {
+		b.matchers = append(b.matchers, m)
+	}
+}
+
+// addEnvironmentMatchers adds matchers for the time_window, env and
+// hostname_pattern conditions, if set.
+func (b *matcherBuilder) addEnvironmentMatchers(match *RuleMatch) {
+	if b.err != nil {
+		return
+	}
+
+	if match.TimeWindow != nil {
+		m, err := NewTimeWindowMatcher(match.TimeWindow)
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		b.matchers = append(b.matchers, m)
+	}
+
+	if len(match.Env) > 0 {
+		m, err := NewEnvMatcher(match.Env, b.opts)
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		b.matchers = append(b.matchers, m)
+	}
+
+	if match.HostnamePattern != "" {
+		m, err := NewHostnamePatternMatcher(match.HostnamePattern, b.opts)
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		b.matchers = append(b.matchers, m)
+	}
+}
+
+// addNestedMatchers adds composite matchers for the any, all and not blocks.
+// Each nested block is built on its own, with its own pattern options.
+func (b *matcherBuilder) addNestedMatchers(match *RuleMatch) {
+	if b.err != nil {
+		return
+	}
+
+	if len(match.Any) > 0 {
+		matchers, err := buildNestedMatchers(match.Any)
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		b.matchers = append(b.matchers, NewOrMatcher(matchers...))
+	}
+
+	if len(match.All) > 0 {
+		matchers, err := buildNestedMatchers(match.All)
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		b.matchers = append(b.matchers, NewAndMatcher(matchers...))
+	}
+
+	if match.Not != nil {
+		matchers, err := buildNestedMatchers([]*RuleMatch{match.Not})
+		if err != nil {
+			b.err = err
+			return
+		}
+
+		b.matchers = append(b.matchers, NewNotMatcher(matchers[0]))
+	}
+}
+
+// buildNestedMatchers builds a matcher for each nested match block. An empty
+// block matches everything, like a rule without conditions.
+func buildNestedMatchers(matches []*RuleMatch) ([]Matcher, error) {
+	matchers := make([]Matcher, 0, len(matches))
+
+	for _, match := range matches {
+		m, err := BuildMatcher(match)
+		if err != nil {
+			return nil, err
+		}
+
+		if m == nil {
+			m = &AlwaysMatcher{}
+		}
+
+		matchers = append(matchers, m)
+	}
+
+	return matchers, nil
+}
+
 // result returns the final matcher or error.
 //
 //nolint:nilnil,ireturn // returning nil, nil is intentional; interface for polymorphism
@@ -840,6 +1541,19 @@ func buildMatcherLegacy(match *RuleMatch) (Matcher, error) {
 	b.addPatternMatcher(match.ContentPattern, wrapContentMatcher)
 	b.addPatternMatcher(match.CommandPattern, wrapCommandMatcher)
 
+	// Add structured command conditions.
+	b.addCommandStructureMatcher(match)
+
+	// Add time, environment and host conditions.
+	b.addEnvironmentMatchers(match)
+
+	// Add nested match blocks.
+	b.addNestedMatchers(match)
+
+	// Add working-tree conditions last, so they are only queried when all
+	// other conditions match.
+	b.addGitStateMatcher(match)
+
 	return b.result()
 }
 
@@ -883,6 +1597,19 @@ func buildMatcherAdvanced(match *RuleMatch) (Matcher, error) {
 	b.addAdvancedPatternMatcher(match.CommandPattern, match.CommandPatterns,
 		wrapCommandMatcherWithOpts, wrapCommandMultiMatcher)
 
+	// Add structured command conditions.
+	b.addCommandStructureMatcher(match)
+
+	// Add time, environment and host conditions.
+	b.addEnvironmentMatchers(match)
+
+	// Add nested match blocks.
+	b.addNestedMatchers(match)
+
+	// Add working-tree conditions last, so they are only queried when all
+	// other conditions match.
+	b.addGitStateMatcher(match)
+
 	return b.result()
 }
 
@@ -106,6 +106,29 @@ func (p *RegexPattern) String() string {
 	return p.pattern
 }
 
+// Captures returns the named capture groups of the first match in s.
+func (p *RegexPattern) Captures(s string) map[string]string {
+	names := p.compiled.SubexpNames()
+	if len(names) <= 1 {
+		return nil
+	}
+
+	submatches := p.compiled.FindStringSubmatch(s)
+	if submatches == nil {
+		return nil
+	}
+
+	captures := make(map[string]string)
+
+	for i, name := range names {
+		if name != "" {
+			captures[name] = submatches[i]
+		}
+	}
+
+	return captures
+}
+
 // CompilePattern compiles a pattern string, auto-detecting the pattern type.
 // Supports negation via ! prefix (e.g., "!*.tmp" matches anything except *.tmp).
 // Returns the compiled Pattern or an error if compilation fails.
@@ -359,6 +382,44 @@ func (p *MultiPattern) String() string {
 	return p.repr
 }
 
+// Captures returns the named capture groups of all sub-patterns matching s.
+func (p *MultiPattern) Captures(s string) map[string]string {
+	var captures map[string]string
+
+	for _, pattern := range p.patterns {
+		for name, value := range patternCaptures(pattern, s) {
+			if captures == nil {
+				captures = make(map[string]string)
+			}
+
+			captures[name] = value
+		}
+	}
+
+	return captures
+}
+
+// CapturingPattern is a Pattern that can extract named capture groups, e.g.
+// a regex like "(?P<ticket>[A-Z]+-[0-9]+)".
+type CapturingPattern interface {
+	Pattern
+
+	// Captures returns the named capture groups of a match in s, or nil if
+	// there are none.
+	Captures(s string) map[string]string
+}
+
+// patternCaptures returns the named capture groups of the pattern in s, if
+// the pattern supports them.
+func patternCaptures(pattern Pattern, s string) map[string]string {
+	capturing, ok := pattern.(CapturingPattern)
+	if !ok {
+		return nil
+	}
+
+	return capturing.Captures(s)
+}
+
 // CompileMultiPattern compiles multiple pattern strings into a single MultiPattern.
 //
 //nolint:ireturn // interface for polymorphism
@@ -4,6 +4,7 @@ import (
 	"cmp"
 	"slices"
 	"sync"
+	"text/template"
 
 	"github.com/cockroachdb/errors"
 )
@@ -15,6 +16,51 @@ type CompiledRule struct {
 
 	// Matcher is the compiled matcher for this rule.
 	Matcher Matcher
+
+	// message and fixHint are the compiled action templates, nil for plain
+	// text.
+	message *template.Template
+	fixHint *template.Template
+
+	// rewrite is the compiled rewrite of the rewrite action.
+	rewrite *compiledRewrite
+
+	// cost is the most expensive lookup the matcher makes.
+	cost matcherCost
+}
+
+// result builds the result of the rule matching ctx, rendering the action
+// templates. Returns nil for a rewrite action that changes nothing, so that
+// the rule is treated as not matching.
+func (c *CompiledRule) result(ctx *MatchContext) *RuleResult {
+	action := c.Rule.Action
+
+	var updatedInput map[string]any
+
+	if c.rewrite != nil {
+		if updatedInput = c.rewrite.apply(ctx); updatedInput == nil {
+			return nil
+		}
+	}
+
+	result := &RuleResult{
+		Matched:   true,
+		Rule:      c.Rule,
+		Action:    action.Type,
+		Message:   action.Message,
+		Reference: action.Reference,
+		FixHint:   action.FixHint,
+
+		UpdatedInput: updatedInput,
+	}
+
+	if c.message != nil || c.fixHint != nil {
+		data := TemplateData(ctx)
+		result.Message = renderActionTemplate(c.message, action.Message, data)
+		result.FixHint = renderActionTemplate(c.fixHint, action.FixHint, data)
+	}
+
+	return result
 }
 
 // Registry stores compiled rules sorted by priority.
@@ -56,16 +102,29 @@ func (r *Registry) Add(rule *Rule) error {
 		matcher = &AlwaysMatcher{}
 	}
 
+	compiled := &CompiledRule{Rule: rule, Matcher: matcher, cost: costOf(matcher)}
+
+	if compiled.message, err = compileActionTemplate("message", rule.Action.Message); err != nil {
+		return err
+	}
+
+	if compiled.fixHint, err = compileActionTemplate("fix_hint", rule.Action.FixHint); err != nil {
+		return err
+	}
+
+	if rule.Action.Type == ActionRewrite {
+		if compiled.rewrite, err = compileRewrite(rule); err != nil {
+			return errors.Wrap(err, "failed to compile rule rewrite")
+		}
+	}
+
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
 	// Check for duplicate name and update if exists.
 	for i, existing := range r.rules {
 		if existing.Rule.Name == rule.Name {
-			r.rules[i] = &CompiledRule{
-				Rule:    rule,
-				Matcher: matcher,
-			}
+			r.rules[i] = compiled
 
 			r.sortRulesLocked()
 
@@ -74,10 +133,7 @@ func (r *Registry) Add(rule *Rule) error {
 	}
 
 	// Add new rule.
-	r.rules = append(r.rules, &CompiledRule{
-		Rule:    rule,
-		Matcher: matcher,
-	})
+	r.rules = append(r.rules, compiled)
 
 	r.sortRulesLocked()
 
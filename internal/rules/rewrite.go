@@ -0,0 +1,294 @@
+package rules
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+// ErrInvalidRewrite is returned when a rewrite action cannot be compiled.
+var ErrInvalidRewrite = errors.New("invalid rewrite")
+
+// Tool input fields changed by rewrites.
+const (
+	toolInputCommand  = "command"
+	toolInputFilePath = "file_path"
+	toolInputPath     = "path"
+)
+
+// remoteSubcommands are the git subcommands that take a remote as their
+// first positional argument.
+var remoteSubcommands = []string{"push", "fetch", "pull"}
+
+// RuleRewrite specifies how a rule with the rewrite action transforms the
+// tool input. Values are text/templates like RuleAction.Message.
+//
+// Bash commands are edited on their syntax tree: only the simple commands
+// selected by the rule's command_name and git_subcommand conditions (all
+// commands if it has neither) are changed.
+type RuleRewrite struct {
+	// AddFlags are flags added to the selected commands that do not have
+	// them, after the subcommand of git commands and after the name of other
+	// commands.
+	AddFlags []string
+
+	// ReplacePrefix is a path prefix replaced with ReplacePrefixWith in the
+	// arguments and file redirections of the selected commands, or in the
+	// file path of file tools. It only matches whole path components, so
+	// "/tmp" replaces "/tmp/x" but not "/tmpfiles".
+	ReplacePrefix string
+
+	// ReplacePrefixWith replaces ReplacePrefix.
+	ReplacePrefixWith string
+
+	// InsertRemote is the remote added to selected git push, fetch and pull
+	// commands that have no positional arguments.
+	InsertRemote string
+}
+
+// IsEmpty returns true if the rewrite changes nothing.
+func (r *RuleRewrite) IsEmpty() bool {
+	return r == nil ||
+		(len(r.AddFlags) == 0 && r.ReplacePrefix == "" && r.InsertRemote == "")
+}
+
+// compiledRewrite is a rewrite action with its compiled templates and the
+// command selection of its rule.
+type compiledRewrite struct {
+	spec          *RuleRewrite
+	name          Pattern
+	gitSubcommand string
+
+	addFlags   []*template.Template
+	prefixWith *template.Template
+	remote     *template.Template
+}
+
+// compileRewrite compiles the rewrite action of a rule.
+func compileRewrite(rule *Rule) (*compiledRewrite, error) {
+	spec := rule.Action.Rewrite
+	if spec.IsEmpty() {
+		return nil, errors.Wrap(ErrInvalidRewrite, "rewrite action has nothing to rewrite")
+	}
+
+	r := &compiledRewrite{spec: spec}
+
+	if match := rule.Match; match != nil {
+		r.gitSubcommand = match.GitSubcommand
+
+		if match.CommandName != "" {
+			pattern, err := CompilePatternWithOptions(
+				match.CommandName,
+				PatternOptions{CaseInsensitive: match.CaseInsensitive},
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			r.name = pattern
+		}
+	}
+
+	if len(spec.AddFlags) > 0 && r.name == nil && r.gitSubcommand == "" {
+		return nil, errors.Wrap(
+			ErrInvalidRewrite,
+			"add_flags requires a command_name or git_subcommand match condition",
+		)
+	}
+
+	for _, flag := range spec.AddFlags {
+		tmpl, err := compileActionTemplate("add_flags", flag)
+		if err != nil {
+			return nil, err
+		}
+
+		r.addFlags = append(r.addFlags, tmpl)
+	}
+
+	var err error
+
+	if r.prefixWith, err = compileActionTemplate("replace_prefix_with", spec.ReplacePrefixWith); err != nil {
+		return nil, err
+	}
+
+	if r.remote, err = compileActionTemplate("insert_remote", spec.InsertRemote); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// rewriteValues are the rendered values of a rewrite.
+type rewriteValues struct {
+	addFlags   []string
+	prefix     string
+	prefixWith string
+	remote     string
+}
+
+// apply returns the tool input of the PreToolUse hook context with the
+// rewrite applied, or nil if it changes nothing.
+func (r *compiledRewrite) apply(ctx *MatchContext) map[string]any {
+	hookCtx := ctx.HookContext
+	if hookCtx == nil || hookCtx.EventType != hook.EventTypePreToolUse {
+		return nil
+	}
+
+	data := TemplateData(ctx)
+	values := &rewriteValues{
+		prefix:     r.spec.ReplacePrefix,
+		prefixWith: renderActionTemplate(r.prefixWith, r.spec.ReplacePrefixWith, data),
+		remote:     renderActionTemplate(r.remote, r.spec.InsertRemote, data),
+	}
+
+	for i, tmpl := range r.addFlags {
+		values.addFlags = append(values.addFlags, renderActionTemplate(tmpl, r.spec.AddFlags[i], data))
+	}
+
+	changes := make(map[string]any)
+
+	switch {
+	case hookCtx.ToolName == hook.ToolTypeBash:
+		command, changed, err := parser.EditCommands(
+			hookCtx.GetCommand(),
+			func(cmd *parser.CommandEdit) error {
+				return r.editCommand(cmd, values)
+			},
+		)
+		if err == nil && changed {
+			changes[toolInputCommand] = command
+		}
+
+	case values.prefix != "":
+		replaced, ok := replacePathPrefix(hookCtx.GetFilePath(), values.prefix, values.prefixWith)
+		if !ok {
+			break
+		}
+
+		field := toolInputFilePath
+		if hookCtx.ToolInput.FilePath == "" {
+			field = toolInputPath
+		}
+
+		changes[field] = replaced
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	// Keep the fields klaudiush does not parse
+	input := hookCtx.GetRawToolInput()
+	maps.Copy(input, changes)
+
+	return input
+}
+
+// editCommand applies the rewrite to a command it selects.
+func (r *compiledRewrite) editCommand(edit *parser.CommandEdit, values *rewriteValues) error {
+	cmd := edit.Command()
+
+	if r.name != nil && !r.name.Match(cmd.Name) && !r.name.Match(path.Base(cmd.Name)) {
+		return nil
+	}
+
+	var gitCmd *parser.GitCommand
+
+	if path.Base(cmd.Name) == "git" {
+		cmd.Name = "git"
+		gitCmd, _ = parser.ParseGitCommand(cmd)
+	}
+
+	if r.gitSubcommand != "" && (gitCmd == nil || gitCmd.Subcommand != r.gitSubcommand) {
+		return nil
+	}
+
+	args := edit.Args()
+
+	if values.prefix != "" {
+		if err := replaceCommandPrefix(edit, values); err != nil {
+			return err
+		}
+	}
+
+	if values.remote != "" && gitCmd != nil && len(gitCmd.Args) == 0 &&
+		slices.Contains(remoteSubcommands, gitCmd.Subcommand) {
+		if err := edit.InsertArgs(edit.NumArgs(), values.remote); err != nil {
+			return err
+		}
+	}
+
+	var missing []string
+
+	for _, flag := range values.addFlags {
+		hasFlag := slices.ContainsFunc(args, func(arg string) bool {
+			return argHasFlag(cmd.Name, arg, flag)
+		})
+
+		if flag != "" && !hasFlag {
+			missing = append(missing, flag)
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// Flags go after the git subcommand, or after the command name
+	insertAt := 0
+	if gitCmd != nil {
+		insertAt = slices.Index(args, gitCmd.Subcommand) + 1
+	}
+
+	return edit.InsertArgs(insertAt, missing...)
+}
+
+// replaceCommandPrefix replaces the path prefix in the arguments and file
+// redirections of a command.
+func replaceCommandPrefix(edit *parser.CommandEdit, values *rewriteValues) error {
+	for i := range edit.NumArgs() {
+		arg, ok := edit.Arg(i)
+		if !ok {
+			continue
+		}
+
+		if replaced, ok := replacePathPrefix(arg, values.prefix, values.prefixWith); ok {
+			if err := edit.SetArg(i, replaced); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i := range edit.NumRedirects() {
+		target, ok := edit.RedirectTarget(i)
+		if !ok {
+			continue
+		}
+
+		if replaced, ok := replacePathPrefix(target, values.prefix, values.prefixWith); ok {
+			if err := edit.SetRedirectTarget(i, replaced); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// replacePathPrefix replaces the prefix of a path if it covers whole path
+// components: "/tmp" replaces "/tmp" and "/tmp/x" but not "/tmpfiles".
+func replacePathPrefix(value, prefix, with string) (string, bool) {
+	rest, ok := strings.CutPrefix(value, prefix)
+	if !ok || (rest != "" && !strings.HasSuffix(prefix, "/") && !strings.HasPrefix(rest, "/")) {
+		return "", false
+	}
+
+	return with + rest, true
+}
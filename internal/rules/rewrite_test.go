@@ -0,0 +1,199 @@
+package rules_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+var _ = Describe("Rewrite action", func() {
+	var ctx context.Context
+
+	evaluate := func(rule *rules.Rule, matchCtx *rules.MatchContext) *rules.RuleResult {
+		engine, err := rules.NewRuleEngine([]*rules.Rule{rule})
+		Expect(err).NotTo(HaveOccurred())
+
+		return engine.Evaluate(ctx, matchCtx)
+	}
+
+	bashContext := func(command string) *rules.MatchContext {
+		return &rules.MatchContext{
+			HookContext: &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: command},
+				RawJSON:   `{"tool_input":{"command":"` + command + `","description":"Run it"}}`,
+			},
+			Command: command,
+		}
+	}
+
+	rewriteRule := func(match *rules.RuleMatch, rewrite *rules.RuleRewrite) *rules.Rule {
+		return &rules.Rule{
+			Name:    "rewrite",
+			Enabled: true,
+			Match:   match,
+			Action: &rules.RuleAction{
+				Type:    rules.ActionRewrite,
+				Message: "rewritten",
+				Rewrite: rewrite,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("adds flags after the git subcommand and keeps other input fields", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{GitSubcommand: "push"},
+				&rules.RuleRewrite{AddFlags: []string{"--force-with-lease"}},
+			),
+			bashContext("git push -u origin main && echo done"),
+		)
+
+		Expect(result.Matched).To(BeTrue())
+		Expect(result.Action).To(Equal(rules.ActionRewrite))
+		Expect(result.UpdatedInput).To(Equal(map[string]any{
+			"command":     "git push --force-with-lease -u origin main && echo done",
+			"description": "Run it",
+		}))
+	})
+
+	It("does not add flags the command already has", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{GitSubcommand: "push"},
+				&rules.RuleRewrite{AddFlags: []string{"--force-with-lease"}},
+			),
+			bashContext("git push --force-with-lease origin main"),
+		)
+
+		Expect(result.Matched).To(BeFalse())
+	})
+
+	It("only edits the selected commands", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{CommandName: "rsync"},
+				&rules.RuleRewrite{AddFlags: []string{"--dry-run"}},
+			),
+			bashContext("ls /src; rsync -a /src /dst"),
+		)
+
+		Expect(result.UpdatedInput).
+			To(HaveKeyWithValue("command", "ls /src\nrsync --dry-run -a /src /dst"))
+	})
+
+	It("inserts a remote into git push without positional arguments", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{GitSubcommand: "push"},
+				&rules.RuleRewrite{InsertRemote: "{{.Remote}}"},
+			),
+			&rules.MatchContext{
+				HookContext: &hook.Context{
+					EventType: hook.EventTypePreToolUse,
+					ToolName:  hook.ToolTypeBash,
+					ToolInput: hook.ToolInput{Command: "git push"},
+				},
+				Command:    "git push",
+				GitContext: &rules.GitContext{Remote: "upstream"},
+			},
+		)
+
+		Expect(result.UpdatedInput).To(HaveKeyWithValue("command", "git push upstream"))
+	})
+
+	It("replaces path prefixes in command arguments, quoting them", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{CommandName: "cp"},
+				&rules.RuleRewrite{ReplacePrefix: "/tmp/", ReplacePrefixWith: "/scratch/my dir/"},
+			),
+			bashContext("cp a.txt /tmp/a.txt"),
+		)
+
+		Expect(result.UpdatedInput).
+			To(HaveKeyWithValue("command", "cp a.txt '/scratch/my dir/a.txt'"))
+	})
+
+	It("replaces path prefixes in file redirections", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{CommandName: "echo"},
+				&rules.RuleRewrite{ReplacePrefix: "/tmp", ReplacePrefixWith: "/scratch"},
+			),
+			bashContext("echo hi > /tmp/out.txt"),
+		)
+
+		Expect(result.UpdatedInput).To(HaveKeyWithValue("command", "echo hi >/scratch/out.txt"))
+	})
+
+	It("replaces only whole path components", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{CommandName: "cp"},
+				&rules.RuleRewrite{ReplacePrefix: "/tmp", ReplacePrefixWith: "/scratch"},
+			),
+			bashContext("cp /tmpfiles/a.txt /tmp"),
+		)
+
+		Expect(result.UpdatedInput).To(HaveKeyWithValue("command", "cp /tmpfiles/a.txt /scratch"))
+	})
+
+	It("replaces the path prefix of file tools", func() {
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{ToolType: "Write"},
+				&rules.RuleRewrite{ReplacePrefix: "/tmp/", ReplacePrefixWith: "/scratch/"},
+			),
+			&rules.MatchContext{
+				HookContext: &hook.Context{
+					EventType: hook.EventTypePreToolUse,
+					ToolName:  hook.ToolTypeWrite,
+					ToolInput: hook.ToolInput{FilePath: "/tmp/out.txt", Content: "data"},
+				},
+			},
+		)
+
+		Expect(result.UpdatedInput).To(Equal(map[string]any{
+			"file_path": "/scratch/out.txt",
+			"content":   "data",
+		}))
+	})
+
+	It("does not match outside PreToolUse", func() {
+		matchCtx := bashContext("git push")
+		matchCtx.HookContext.EventType = hook.EventTypePostToolUse
+
+		result := evaluate(
+			rewriteRule(
+				&rules.RuleMatch{GitSubcommand: "push"},
+				&rules.RuleRewrite{AddFlags: []string{"--force-with-lease"}},
+			),
+			matchCtx,
+		)
+
+		Expect(result.Matched).To(BeFalse())
+	})
+
+	DescribeTable("rejects invalid rewrites",
+		func(match *rules.RuleMatch, rewrite *rules.RuleRewrite) {
+			_, err := rules.NewRuleEngine([]*rules.Rule{rewriteRule(match, rewrite)})
+			Expect(err).To(MatchError(ContainSubstring("invalid rewrite")))
+		},
+		Entry("missing rewrite", &rules.RuleMatch{CommandName: "ls"}, nil),
+		Entry("empty rewrite", &rules.RuleMatch{CommandName: "ls"}, &rules.RuleRewrite{}),
+		Entry("add_flags without command selection",
+			&rules.RuleMatch{CommandPattern: "ls"},
+			&rules.RuleRewrite{AddFlags: []string{"-a"}},
+		),
+	)
+})
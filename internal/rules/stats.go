@@ -0,0 +1,505 @@
+package rules
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// File permission constants for the stats file.
+const (
+	// statsFilePermissions is the permission mode for the stats file.
+	statsFilePermissions = 0o600
+
+	// statsDirPermissions is the permission mode for the stats directory.
+	statsDirPermissions = 0o700
+)
+
+// Lock timing constants for the stats file.
+const (
+	// statsLockTimeout is how long Save waits for the stats file lock.
+	statsLockTimeout = 2 * time.Second
+
+	// statsLockStale is the age after which a lock file is considered left
+	// behind by a crashed process.
+	statsLockStale = 10 * time.Second
+
+	// statsLockRetry is the interval between attempts to take the lock.
+	statsLockRetry = 10 * time.Millisecond
+)
+
+// ErrStatsLockTimeout is returned when the stats file lock cannot be taken.
+var ErrStatsLockTimeout = errors.New("timed out waiting for the rule stats lock")
+
+// RuleStats holds the recorded matches of a rule.
+type RuleStats struct {
+	// Hits is the number of times the rule matched.
+	Hits int `json:"hits"`
+
+	// LastHit is the time the rule last matched.
+	LastHit time.Time `json:"last_hit,omitzero"`
+
+	// Actions counts the matches by action.
+	Actions map[ActionType]int `json:"actions,omitempty"`
+
+	// ShadowedBy counts, by rule name, the times the rule matched but a
+	// higher-priority rule matched first under stop_on_first_match.
+	ShadowedBy map[string]int `json:"shadowed_by,omitempty"`
+}
+
+// Shadowed returns the number of times the rule was shadowed.
+func (s *RuleStats) Shadowed() int {
+	total := 0
+
+	for _, count := range s.ShadowedBy {
+		total += count
+	}
+
+	return total
+}
+
+// clone returns a deep copy of the stats.
+func (s *RuleStats) clone() *RuleStats {
+	c := *s
+	c.Actions = maps.Clone(s.Actions)
+	c.ShadowedBy = maps.Clone(s.ShadowedBy)
+
+	return &c
+}
+
+// StatsState is the persisted state of rule stats.
+type StatsState struct {
+	// Since is the time recording started.
+	Since time.Time `json:"since"`
+
+	// LastUpdated is the time of the last recorded match.
+	LastUpdated time.Time `json:"last_updated,omitzero"`
+
+	// Rules holds the stats of every rule that matched, by rule name.
+	Rules map[string]*RuleStats `json:"rules"`
+}
+
+// merge adds the matches recorded in delta.
+func (s *StatsState) merge(delta *StatsState) {
+	if delta.LastUpdated.After(s.LastUpdated) {
+		s.LastUpdated = delta.LastUpdated
+	}
+
+	for name, d := range delta.Rules {
+		stats, ok := s.Rules[name]
+		if !ok {
+			s.Rules[name] = d.clone()
+
+			continue
+		}
+
+		stats.Hits += d.Hits
+
+		if d.LastHit.After(stats.LastHit) {
+			stats.LastHit = d.LastHit
+		}
+
+		stats.Actions = mergeCounts(stats.Actions, d.Actions)
+		stats.ShadowedBy = mergeCounts(stats.ShadowedBy, d.ShadowedBy)
+	}
+}
+
+// mergeCounts adds the counts of delta to counts.
+func mergeCounts[K comparable](counts, delta map[K]int) map[K]int {
+	if len(delta) == 0 {
+		return counts
+	}
+
+	if counts == nil {
+		counts = make(map[K]int, len(delta))
+	}
+
+	for key, count := range delta {
+		counts[key] += count
+	}
+
+	return counts
+}
+
+// Stats records rule matches and persists them to a state file. It is safe
+// for concurrent use. Save merges the matches recorded since the last Load
+// or Save into the state file under a lock, so that concurrent hook
+// processes do not lose each other's matches.
+type Stats struct {
+	mu    sync.Mutex
+	state *StatsState
+	dirty bool
+
+	// pending holds the matches recorded since the last Load or Save.
+	pending *StatsState
+
+	// stateFile is the path of the state file, which may start with "~/".
+	stateFile string
+
+	logger logger.Logger
+
+	// now returns the current time. Used for testing to control time.
+	now func() time.Time
+}
+
+// StatsOption configures Stats.
+type StatsOption func(*Stats)
+
+// WithStatsLogger sets the logger.
+func WithStatsLogger(log logger.Logger) StatsOption {
+	return func(s *Stats) {
+		if log != nil {
+			s.logger = log
+		}
+	}
+}
+
+// WithStatsTimeFunc sets a custom time function for testing.
+func WithStatsTimeFunc(fn func() time.Time) StatsOption {
+	return func(s *Stats) {
+		if fn != nil {
+			s.now = fn
+		}
+	}
+}
+
+// NewStats creates rule stats persisted to stateFile.
+func NewStats(stateFile string, opts ...StatsOption) *Stats {
+	s := &Stats{
+		stateFile: stateFile,
+		logger:    logger.NewNoOpLogger(),
+		now:       time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.state = s.freshState()
+	s.pending = s.freshState()
+
+	return s
+}
+
+// RecordHit records a match of the named rule.
+func (s *Stats) RecordHit(rule string, action ActionType) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	for _, state := range []*StatsState{s.state, s.pending} {
+		stats := state.rule(rule)
+		stats.Hits++
+		stats.LastHit = now
+
+		if stats.Actions == nil {
+			stats.Actions = make(map[ActionType]int)
+		}
+
+		stats.Actions[action]++
+
+		state.LastUpdated = now
+	}
+
+	s.dirty = true
+}
+
+// RecordShadowed records that the named rule matched, but the rule named by
+// matched first.
+func (s *Stats) RecordShadowed(rule, by string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	for _, state := range []*StatsState{s.state, s.pending} {
+		stats := state.rule(rule)
+		if stats.ShadowedBy == nil {
+			stats.ShadowedBy = make(map[string]int)
+		}
+
+		stats.ShadowedBy[by]++
+
+		state.LastUpdated = now
+	}
+
+	s.dirty = true
+}
+
+// rule returns the stats of the named rule, creating them if needed.
+func (s *StatsState) rule(rule string) *RuleStats {
+	stats, ok := s.Rules[rule]
+	if !ok {
+		stats = &RuleStats{}
+		s.Rules[rule] = stats
+	}
+
+	return stats
+}
+
+// State returns a copy of the current state.
+func (s *Stats) State() *StatsState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := *s.state
+	state.Rules = make(map[string]*RuleStats, len(s.state.Rules))
+
+	for name, stats := range s.state.Rules {
+		state.Rules[name] = stats.clone()
+	}
+
+	return &state
+}
+
+// StateFile returns the path of the state file, with "~" expanded.
+func (s *Stats) StateFile() string {
+	path := s.stateFile
+	if len(path) > 1 && path[0] == '~' && path[1] == '/' {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	return path
+}
+
+// Load replaces the current state with the state file. A missing or
+// unreadable state file starts fresh stats.
+func (s *Stats) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.state = s.freshState()
+	s.pending = s.freshState()
+	s.dirty = false
+
+	state, err := s.readStateFile(s.StateFile())
+	if err != nil || state == nil {
+		return err
+	}
+
+	s.state = state
+
+	return nil
+}
+
+// readStateFile reads the state file. Returns nil if it does not exist or
+// cannot be parsed. Must be called with mu held.
+func (s *Stats) readStateFile(path string) (*StatsState, error) {
+	// Path comes from trusted configuration, not user input.
+	data, err := os.ReadFile(path) //nolint:gosec // G304: path is from config
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil //nolint:nilnil // a missing file starts fresh
+		}
+
+		return nil, errors.Wrap(err, "reading rule stats file")
+	}
+
+	var state StatsState
+	if err := json.Unmarshal(data, &state); err != nil {
+		s.logger.Debug("failed to parse rule stats file, starting fresh",
+			"path", path,
+			"error", err.Error(),
+		)
+
+		return nil, nil //nolint:nilnil // a corrupt file starts fresh
+	}
+
+	if state.Rules == nil {
+		state.Rules = make(map[string]*RuleStats)
+	}
+
+	if state.Since.IsZero() {
+		state.Since = s.state.Since
+	}
+
+	return &state, nil
+}
+
+// Save merges the matches recorded since the last Load or Save into the
+// state file, if there are any.
+func (s *Stats) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.dirty {
+		return nil
+	}
+
+	path := s.StateFile()
+
+	if err := os.MkdirAll(filepath.Dir(path), statsDirPermissions); err != nil {
+		return errors.Wrap(err, "creating rule stats directory")
+	}
+
+	unlock, err := lockStateFile(path)
+	if err != nil {
+		return err
+	}
+
+	defer unlock()
+
+	state, err := s.readStateFile(path)
+	if err != nil {
+		return err
+	}
+
+	if state == nil {
+		state = &StatsState{Since: s.state.Since, Rules: make(map[string]*RuleStats)}
+	}
+
+	state.merge(s.pending)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshaling rule stats")
+	}
+
+	// Write to temp file first for atomic operation
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, statsFilePermissions); err != nil {
+		return errors.Wrap(err, "writing temp rule stats file")
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return errors.Wrap(err, "renaming rule stats file")
+	}
+
+	s.state = state
+	s.pending = s.freshState()
+	s.dirty = false
+
+	s.logger.Debug("saved rule stats", "path", path)
+
+	return nil
+}
+
+// lockStateFile takes an exclusive lock on the state file by creating a lock
+// file next to it, and returns a function releasing it. Stale lock files left
+// by crashed processes are removed.
+func lockStateFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(statsLockTimeout)
+
+	for {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, statsFilePermissions)
+		if err == nil {
+			_ = file.Close()
+
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+
+		if !os.IsExist(err) {
+			return nil, errors.Wrap(err, "creating rule stats lock file")
+		}
+
+		if info, statErr := os.Stat(lockPath); statErr == nil &&
+			time.Since(info.ModTime()) > statsLockStale {
+			_ = os.Remove(lockPath)
+
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.Wrapf(ErrStatsLockTimeout, "lock file %s", lockPath)
+		}
+
+		time.Sleep(statsLockRetry)
+	}
+}
+
+// freshState returns empty stats starting now.
+func (s *Stats) freshState() *StatsState {
+	return &StatsState{
+		Since: s.now(),
+		Rules: make(map[string]*RuleStats),
+	}
+}
+
+// RuleStatsEntry is a rule in a StatsReport.
+type RuleStatsEntry struct {
+	Name     string `json:"name"`
+	Priority int    `json:"priority"`
+	*RuleStats
+}
+
+// StatsReport summarizes the recorded stats of the configured rules.
+type StatsReport struct {
+	// Since is the time recording started.
+	Since time.Time `json:"since"`
+
+	// Hot lists the rules that matched, most hits first.
+	Hot []*RuleStatsEntry `json:"hot"`
+
+	// Dead lists the rules that did not match within the dead period,
+	// least recently matched first.
+	Dead []*RuleStatsEntry `json:"dead"`
+
+	// Shadowed lists the rules that matched but were shadowed by a
+	// higher-priority rule, most shadowed first.
+	Shadowed []*RuleStatsEntry `json:"shadowed"`
+}
+
+// NewStatsReport builds a report of the stats of rules. Rules that have not
+// matched since now-deadAfter are dead. Stats of rules that are no longer
+// configured are ignored.
+func NewStatsReport(rules []*Rule, state *StatsState, now time.Time, deadAfter time.Duration) *StatsReport {
+	report := &StatsReport{
+		Since:    state.Since,
+		Hot:      []*RuleStatsEntry{},
+		Dead:     []*RuleStatsEntry{},
+		Shadowed: []*RuleStatsEntry{},
+	}
+
+	cutoff := now.Add(-deadAfter)
+
+	for _, rule := range rules {
+		stats, ok := state.Rules[rule.Name]
+		if !ok {
+			stats = &RuleStats{}
+		}
+
+		entry := &RuleStatsEntry{Name: rule.Name, Priority: rule.Priority, RuleStats: stats}
+
+		if stats.Hits > 0 {
+			report.Hot = append(report.Hot, entry)
+		}
+
+		if rule.Enabled && stats.LastHit.Before(cutoff) {
+			report.Dead = append(report.Dead, entry)
+		}
+
+		if len(stats.ShadowedBy) > 0 {
+			report.Shadowed = append(report.Shadowed, entry)
+		}
+	}
+
+	slices.SortStableFunc(report.Hot, func(a, b *RuleStatsEntry) int {
+		return cmp.Compare(b.Hits, a.Hits)
+	})
+
+	slices.SortStableFunc(report.Dead, func(a, b *RuleStatsEntry) int {
+		return a.LastHit.Compare(b.LastHit)
+	})
+
+	slices.SortStableFunc(report.Shadowed, func(a, b *RuleStatsEntry) int {
+		return cmp.Compare(b.Shadowed(), a.Shadowed())
+	})
+
+	return report
+}
@@ -0,0 +1,194 @@
+package rules_test
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/rules"
+)
+
+var _ = Describe("Stats", func() {
+	var (
+		now       time.Time
+		statsFile string
+		stats     *rules.Stats
+	)
+
+	clock := func() time.Time { return now }
+
+	newRule := func(name string, priority int, pattern string, action rules.ActionType) *rules.Rule {
+		return &rules.Rule{
+			Name:     name,
+			Enabled:  true,
+			Priority: priority,
+			Match:    &rules.RuleMatch{CommandPattern: pattern},
+			Action:   &rules.RuleAction{Type: action},
+		}
+	}
+
+	evaluate := func(engine *rules.RuleEngine, command string) *rules.RuleResult {
+		return engine.Evaluate(context.Background(), &rules.MatchContext{Command: command})
+	}
+
+	BeforeEach(func() {
+		now = time.Date(2026, time.March, 4, 12, 0, 0, 0, time.UTC)
+		statsFile = filepath.Join(GinkgoT().TempDir(), "stats", "rule_stats.json")
+		stats = rules.NewStats(statsFile, rules.WithStatsTimeFunc(clock))
+	})
+
+	Describe("recording", func() {
+		var engine *rules.RuleEngine
+
+		BeforeEach(func() {
+			var err error
+
+			engine, err = rules.NewRuleEngine([]*rules.Rule{
+				newRule("warn-rm", 100, "rm *", rules.ActionWarn),
+				newRule("block-rm-rf", 50, "rm -rf *", rules.ActionBlock),
+				newRule("no-curl", 0, "curl *", rules.ActionBlock),
+			}, rules.WithEngineStats(stats))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should record hits, actions and last hit times", func() {
+			evaluate(engine, "rm build")
+			now = now.Add(time.Hour)
+			evaluate(engine, "rm build")
+			evaluate(engine, "ls")
+
+			state := stats.State()
+			Expect(state.Rules).To(HaveLen(1))
+			Expect(state.Rules["warn-rm"].Hits).To(Equal(2))
+			Expect(state.Rules["warn-rm"].LastHit).To(Equal(now))
+			Expect(state.Rules["warn-rm"].Actions).To(Equal(map[rules.ActionType]int{rules.ActionWarn: 2}))
+		})
+
+		It("should record rules shadowed by the first match", func() {
+			result := evaluate(engine, "rm -rf build")
+			Expect(result.Rule.Name).To(Equal("warn-rm"))
+
+			state := stats.State()
+			Expect(state.Rules["block-rm-rf"].Hits).To(BeZero())
+			Expect(state.Rules["block-rm-rf"].ShadowedBy).To(Equal(map[string]int{"warn-rm": 1}))
+			Expect(state.Rules).NotTo(HaveKey("no-curl"))
+		})
+
+		It("should not match rules with lookups to record shadowing", func() {
+			lookups := 0
+			hostRule := newRule("block-rm-on-host", 50, "rm *", rules.ActionBlock)
+			hostRule.Match.HostnamePattern = "*"
+
+			engine, err := rules.NewRuleEngine([]*rules.Rule{
+				newRule("warn-rm", 100, "rm *", rules.ActionWarn),
+				hostRule,
+			}, rules.WithEngineStats(stats), rules.WithEnvironment(&rules.Environment{
+				Hostname: func() (string, error) {
+					lookups++
+
+					return "dev", nil
+				},
+			}))
+			Expect(err).NotTo(HaveOccurred())
+
+			evaluate(engine, "rm build")
+			Expect(lookups).To(BeZero())
+			Expect(stats.State().Rules).NotTo(HaveKey("block-rm-on-host"))
+		})
+
+		It("should not record shadowed rules without stop_on_first_match", func() {
+			engine, err := rules.NewRuleEngine([]*rules.Rule{
+				newRule("warn-rm", 100, "rm *", rules.ActionWarn),
+				newRule("block-rm-rf", 50, "rm -rf *", rules.ActionBlock),
+			}, rules.WithEngineStats(stats), rules.WithEngineStopOnFirstMatch(false))
+			Expect(err).NotTo(HaveOccurred())
+
+			evaluate(engine, "rm -rf build")
+			Expect(stats.State().Rules).NotTo(HaveKey("block-rm-rf"))
+		})
+	})
+
+	Describe("persistence", func() {
+		It("should save and load the state", func() {
+			stats.RecordHit("warn-rm", rules.ActionWarn)
+			Expect(stats.Save()).To(Succeed())
+
+			loaded := rules.NewStats(statsFile, rules.WithStatsTimeFunc(clock))
+			Expect(loaded.Load()).To(Succeed())
+			Expect(loaded.State()).To(Equal(stats.State()))
+		})
+
+		It("should merge the matches of concurrent processes", func() {
+			other := rules.NewStats(statsFile, rules.WithStatsTimeFunc(clock))
+			Expect(other.Load()).To(Succeed())
+
+			stats.RecordHit("warn-rm", rules.ActionWarn)
+			other.RecordHit("warn-rm", rules.ActionBlock)
+			other.RecordShadowed("block-rm-rf", "warn-rm")
+
+			Expect(stats.Save()).To(Succeed())
+			Expect(other.Save()).To(Succeed())
+
+			loaded := rules.NewStats(statsFile, rules.WithStatsTimeFunc(clock))
+			Expect(loaded.Load()).To(Succeed())
+
+			state := loaded.State()
+			Expect(state.Rules["warn-rm"].Hits).To(Equal(2))
+			Expect(state.Rules["warn-rm"].Actions).To(Equal(map[rules.ActionType]int{
+				rules.ActionWarn:  1,
+				rules.ActionBlock: 1,
+			}))
+			Expect(state.Rules["block-rm-rf"].ShadowedBy).To(Equal(map[string]int{"warn-rm": 1}))
+			Expect(statsFile + ".lock").NotTo(BeAnExistingFile())
+		})
+
+		It("should not write the state file without new matches", func() {
+			Expect(stats.Save()).To(Succeed())
+			Expect(statsFile).NotTo(BeAnExistingFile())
+		})
+
+		It("should start fresh without a state file", func() {
+			stats.RecordHit("warn-rm", rules.ActionWarn)
+			Expect(stats.Load()).To(Succeed())
+			Expect(stats.State().Rules).To(BeEmpty())
+			Expect(stats.State().Since).To(Equal(now))
+		})
+	})
+
+	Describe("NewStatsReport", func() {
+		It("should report hot, dead and shadowed rules", func() {
+			configured := []*rules.Rule{
+				newRule("warn-rm", 100, "rm *", rules.ActionWarn),
+				newRule("block-rm-rf", 50, "rm -rf *", rules.ActionBlock),
+				newRule("no-curl", 10, "curl *", rules.ActionBlock),
+				newRule("no-wget", 0, "wget *", rules.ActionBlock),
+			}
+
+			stats.RecordHit("no-curl", rules.ActionBlock)
+			now = now.Add(60 * 24 * time.Hour)
+			stats.RecordHit("warn-rm", rules.ActionWarn)
+			stats.RecordHit("warn-rm", rules.ActionWarn)
+			stats.RecordShadowed("block-rm-rf", "warn-rm")
+			stats.RecordHit("removed-rule", rules.ActionBlock)
+
+			report := rules.NewStatsReport(configured, stats.State(), now, 30*24*time.Hour)
+
+			names := func(entries []*rules.RuleStatsEntry) []string {
+				result := make([]string, 0, len(entries))
+				for _, entry := range entries {
+					result = append(result, entry.Name)
+				}
+
+				return result
+			}
+
+			Expect(names(report.Hot)).To(Equal([]string{"warn-rm", "no-curl"}))
+			Expect(names(report.Dead)).To(Equal([]string{"block-rm-rf", "no-wget", "no-curl"}))
+			Expect(names(report.Shadowed)).To(Equal([]string{"block-rm-rf"}))
+			Expect(report.Shadowed[0].Shadowed()).To(Equal(1))
+		})
+	})
+})
@@ -0,0 +1,89 @@
+package rules
+
+import (
+	"maps"
+	"strings"
+	"text/template"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/internal/templates"
+)
+
+// Template variables available in rule messages and fix hints. Named capture
+// groups of the matching patterns are available by name as well, unless they
+// clash with these.
+const (
+	TemplateVarBranch   = "Branch"
+	TemplateVarRemote   = "Remote"
+	TemplateVarRepoRoot = "RepoRoot"
+	TemplateVarFile     = "File"
+	TemplateVarCommand  = "Command"
+	TemplateVarTool     = "Tool"
+	TemplateVarEvent    = "Event"
+)
+
+// compileActionTemplate compiles a message or fix hint template. Returns nil
+// for text without template actions, which is used verbatim.
+func compileActionTemplate(name, text string) (*template.Template, error) {
+	if !strings.Contains(text, "{{") {
+		return nil, nil //nolint:nilnil // plain text needs no template
+	}
+
+	tmpl, err := templates.Compile(name, text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s template", name)
+	}
+
+	return tmpl, nil
+}
+
+// TemplateData returns the variables available to rule templates for the
+// match context.
+func TemplateData(ctx *MatchContext) map[string]string {
+	data := make(map[string]string)
+
+	maps.Copy(data, ctx.Captures)
+
+	if gitCtx := ctx.Git(); gitCtx != nil {
+		data[TemplateVarBranch] = gitCtx.Branch
+		data[TemplateVarRemote] = gitCtx.Remote
+		data[TemplateVarRepoRoot] = gitCtx.RepoRoot
+	}
+
+	data[TemplateVarCommand] = ctx.Command
+
+	if ctx.FileContext != nil {
+		data[TemplateVarFile] = ctx.FileContext.Path
+	}
+
+	if hookCtx := ctx.HookContext; hookCtx != nil {
+		if data[TemplateVarFile] == "" {
+			data[TemplateVarFile] = hookCtx.GetFilePath()
+		}
+
+		if data[TemplateVarCommand] == "" {
+			data[TemplateVarCommand] = hookCtx.GetCommand()
+		}
+
+		data[TemplateVarTool] = hookCtx.ToolName.String()
+		data[TemplateVarEvent] = hookCtx.EventType.String()
+	}
+
+	return data
+}
+
+// renderActionTemplate renders a compiled template, falling back to the raw
+// text if there is no template or rendering fails.
+func renderActionTemplate(tmpl *template.Template, text string, data map[string]string) string {
+	if tmpl == nil {
+		return text
+	}
+
+	rendered, err := templates.Execute(tmpl, data)
+	if err != nil {
+		return text
+	}
+
+	return rendered
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/smykla-labs/klaudiush/internal/validator"
 	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
 )
 
 // ActionType represents the action to take when a rule matches.
@@ -19,8 +20,15 @@ const (
 	// ActionWarn warns without blocking.
 	ActionWarn ActionType = "warn"
 
+	// ActionAsk asks the user to confirm the operation.
+	ActionAsk ActionType = "ask"
+
 	// ActionAllow explicitly allows the operation.
 	ActionAllow ActionType = "allow"
+
+	// ActionRewrite rewrites the tool input (see RuleRewrite) instead of
+	// blocking the operation.
+	ActionRewrite ActionType = "rewrite"
 )
 
 // ValidatorType identifies a specific validator or group of validators.
@@ -30,32 +38,63 @@ type ValidatorType string
 
 // Common validator type constants.
 const (
-	ValidatorGitPush        ValidatorType = "git.push"
-	ValidatorGitFetch       ValidatorType = "git.fetch"
-	ValidatorGitCommit      ValidatorType = "git.commit"
-	ValidatorGitAdd         ValidatorType = "git.add"
-	ValidatorGitPR          ValidatorType = "git.pr"
-	ValidatorGitMerge       ValidatorType = "git.merge"
-	ValidatorGitBranch      ValidatorType = "git.branch"
-	ValidatorGitNoVerify    ValidatorType = "git.no_verify"
-	ValidatorGitAll         ValidatorType = "git.*"
-	ValidatorGitHubIssue    ValidatorType = "github.issue"
-	ValidatorGitHubAll      ValidatorType = "github.*"
-	ValidatorFileMarkdown   ValidatorType = "file.markdown"
-	ValidatorFileShell      ValidatorType = "file.shell"
-	ValidatorFileTerraform  ValidatorType = "file.terraform"
-	ValidatorFileWorkflow   ValidatorType = "file.workflow"
-	ValidatorFileGofumpt    ValidatorType = "file.gofumpt"
-	ValidatorFilePython     ValidatorType = "file.python"
-	ValidatorFileJavaScript ValidatorType = "file.javascript"
-	ValidatorFileRust       ValidatorType = "file.rust"
-	ValidatorFileAll        ValidatorType = "file.*"
-	ValidatorSecrets        ValidatorType = "secrets.secrets"
-	ValidatorShellBacktick  ValidatorType = "shell.backtick"
-	ValidatorNotification   ValidatorType = "notification.bell"
-	ValidatorAll            ValidatorType = "*"
+	ValidatorGitPush          ValidatorType = "git.push"
+	ValidatorGitFetch         ValidatorType = "git.fetch"
+	ValidatorGitCommit        ValidatorType = "git.commit"
+	ValidatorGitAdd           ValidatorType = "git.add"
+	ValidatorGitPR            ValidatorType = "git.pr"
+	ValidatorGitMerge         ValidatorType = "git.merge"
+	ValidatorGitBranch        ValidatorType = "git.branch"
+	ValidatorGitNoVerify      ValidatorType = "git.no_verify"
+	ValidatorGitAll           ValidatorType = "git.*"
+	ValidatorGitHubIssue      ValidatorType = "github.issue"
+	ValidatorGitHubAll        ValidatorType = "github.*"
+	ValidatorFileMarkdown     ValidatorType = "file.markdown"
+	ValidatorFileShell        ValidatorType = "file.shell"
+	ValidatorFileTerraform    ValidatorType = "file.terraform"
+	ValidatorFileWorkflow     ValidatorType = "file.workflow"
+	ValidatorFileGofumpt      ValidatorType = "file.gofumpt"
+	ValidatorFilePython       ValidatorType = "file.python"
+	ValidatorFileJavaScript   ValidatorType = "file.javascript"
+	ValidatorFileRust         ValidatorType = "file.rust"
+	ValidatorFilePaths        ValidatorType = "file.paths"
+	ValidatorFileAll          ValidatorType = "file.*"
+	ValidatorSecrets          ValidatorType = "secrets.secrets"
+	ValidatorShellBacktick    ValidatorType = "shell.backtick"
+	ValidatorShellDestructive ValidatorType = "shell.destructive"
+	ValidatorNotification     ValidatorType = "notification.bell"
+	ValidatorCustom           ValidatorType = "custom"
+	ValidatorAll              ValidatorType = "*"
 )
 
+// KnownValidatorTypes lists the validator types of the built-in validators
+// and the rules validator, excluding wildcards.
+var KnownValidatorTypes = []ValidatorType{
+	ValidatorGitPush,
+	ValidatorGitFetch,
+	ValidatorGitCommit,
+	ValidatorGitAdd,
+	ValidatorGitPR,
+	ValidatorGitMerge,
+	ValidatorGitBranch,
+	ValidatorGitNoVerify,
+	ValidatorGitHubIssue,
+	ValidatorFileMarkdown,
+	ValidatorFileShell,
+	ValidatorFileTerraform,
+	ValidatorFileWorkflow,
+	ValidatorFileGofumpt,
+	ValidatorFilePython,
+	ValidatorFileJavaScript,
+	ValidatorFileRust,
+	ValidatorFilePaths,
+	ValidatorSecrets,
+	ValidatorShellBacktick,
+	ValidatorShellDestructive,
+	ValidatorNotification,
+	ValidatorCustom,
+}
+
 // Rule represents a single validation rule with match conditions and action.
 type Rule struct {
 	// Name uniquely identifies this rule. Used for override precedence.
@@ -116,6 +155,65 @@ type RuleMatch struct {
 	// CommandPatterns allows multiple command patterns.
 	CommandPatterns []string
 
+	// CommandName matches against the names of the parsed commands.
+	CommandName string
+
+	// GitSubcommand matches against the subcommands of parsed git commands.
+	GitSubcommand string
+
+	// HasFlag requires a parsed command to have the flag.
+	HasFlag string
+
+	// HasFlags allows multiple flags (any/all based on FlagMode).
+	HasFlags []string
+
+	// FlagMode specifies how HasFlags are combined ("any" or "all").
+	FlagMode string
+
+	// ArgPattern matches against the arguments of the parsed commands.
+	ArgPattern string
+
+	// InPipeline requires a parsed command to be (or not be) part of a pipeline.
+	InPipeline *bool
+
+	// DynamicArgs requires a parsed command to have (or not have) expansions
+	// whose values are not known.
+	DynamicArgs *bool
+
+	// StagedFilePattern matches if any staged file matches the pattern.
+	StagedFilePattern string
+
+	// ModifiedFilePattern matches if any modified but unstaged file matches.
+	ModifiedFilePattern string
+
+	// UntrackedFilePattern matches if any untracked file matches the pattern.
+	UntrackedFilePattern string
+
+	// DirtyTree requires tracked files to have (or not have) uncommitted changes.
+	DirtyTree *bool
+
+	// AheadOfUpstream requires the branch to be at least this many commits
+	// ahead of its upstream branch.
+	AheadOfUpstream int
+
+	// BehindUpstream requires the branch to be at least this many commits
+	// behind its upstream branch.
+	BehindUpstream int
+
+	// LastCommitAuthor matches against the author of the HEAD commit
+	// ("Name <email>").
+	LastCommitAuthor string
+
+	// TimeWindow requires the current time to be within the window.
+	TimeWindow *TimeWindow
+
+	// Env maps environment variable names to patterns their values must
+	// match. Unset variables have an empty value.
+	Env map[string]string
+
+	// HostnamePattern matches against the host name of the machine.
+	HostnamePattern string
+
 	// ToolType matches against the hook tool type.
 	ToolType string
 
@@ -127,18 +225,35 @@ type RuleMatch struct {
 
 	// PatternMode specifies how multiple patterns are combined ("any" or "all").
 	PatternMode string
+
+	// Any matches if at least one of the nested match blocks matches.
+	Any []*RuleMatch
+
+	// All matches if every nested match block matches.
+	All []*RuleMatch
+
+	// Not matches if the nested match block does not match.
+	Not *RuleMatch
 }
 
 // RuleAction specifies what happens when a rule matches.
 type RuleAction struct {
-	// Type is the action to take (block, warn, allow).
+	// Type is the action to take (block, warn, ask, allow, rewrite).
 	Type ActionType
 
-	// Message is the human-readable message to display.
+	// Message is the human-readable message to display. It is a text/template
+	// with access to the match context (see TemplateData).
 	Message string
 
 	// Reference is an optional error reference code (e.g., "GIT019").
 	Reference string
+
+	// FixHint is an optional short suggestion for fixing the issue. It is a
+	// text/template like Message.
+	FixHint string
+
+	// Rewrite specifies the rewrite of the rewrite action.
+	Rewrite *RuleRewrite
 }
 
 // RuleResult represents the outcome of rule evaluation.
@@ -152,11 +267,17 @@ type RuleResult struct {
 	// Action is the action to take.
 	Action ActionType
 
-	// Message is the message to display.
+	// Message is the rendered message to display.
 	Message string
 
 	// Reference is the error reference code (if any).
 	Reference string
+
+	// FixHint is the suggestion for fixing the issue (if any).
+	FixHint string
+
+	// UpdatedInput is the rewritten tool input of the rewrite action.
+	UpdatedInput map[string]any
 }
 
 // GitContext contains git-specific data for rule matching.
@@ -172,6 +293,33 @@ type GitContext struct {
 
 	// IsInRepo indicates whether we're inside a git repository.
 	IsInRepo bool
+
+	// State provides the working-tree state of the repository (may be nil).
+	// It is only queried when a rule has a working-tree condition.
+	State GitState
+}
+
+// GitState provides the working-tree state of a repository. Implementations
+// should cache results, as several rules may query the same state.
+type GitState interface {
+	// GetStagedFiles returns the list of staged files.
+	GetStagedFiles() ([]string, error)
+
+	// GetModifiedFiles returns the list of modified but unstaged files.
+	GetModifiedFiles() ([]string, error)
+
+	// GetUntrackedFiles returns the list of untracked files.
+	GetUntrackedFiles() ([]string, error)
+
+	// GetCurrentBranch returns the name of the checked-out branch.
+	GetCurrentBranch() (string, error)
+
+	// GetAheadBehind returns how many commits the branch is ahead of and
+	// behind its upstream branch.
+	GetAheadBehind(branch string) (ahead, behind int, err error)
+
+	// GetLastCommitAuthor returns the author of the HEAD commit.
+	GetLastCommitAuthor() (string, error)
 }
 
 // FileContext contains file-specific data for rule matching.
@@ -188,7 +336,8 @@ type MatchContext struct {
 	// HookContext is the original hook context.
 	HookContext *hook.Context
 
-	// GitContext contains git-related data (may be nil).
+	// GitContext contains git-related data (may be nil). Matchers read it
+	// through Git, which builds it on first use when it is provided lazily.
 	GitContext *GitContext
 
 	// FileContext contains file-related data (may be nil).
@@ -199,6 +348,90 @@ type MatchContext struct {
 
 	// Command is the bash command being executed (if applicable).
 	Command string
+
+	// RuleFilter restricts evaluation to the rules it returns true for.
+	// All rules are evaluated if nil.
+	RuleFilter func(rule *Rule) bool
+
+	// Environment provides the clock, environment variables and hostname
+	// (may be nil, in which case the system ones are used).
+	Environment *Environment
+
+	// Captures holds the named capture groups of the patterns that matched
+	// while evaluating the current rule.
+	Captures map[string]string
+
+	// parsedCommands caches the commands parsed from Command.
+	parsedCommands []parser.Command
+	commandsParsed bool
+
+	// parserOpts configures the parser of ParsedCommands. The engine sets
+	// the ones it was created with.
+	parserOpts []parser.BashParserOption
+
+	// gitContextProvider builds GitContext on first use, so that rules
+	// without git conditions do not pay for git lookups.
+	gitContextProvider func() *GitContext
+}
+
+// capture records the named capture groups of a pattern that matched s.
+func (ctx *MatchContext) capture(pattern Pattern, s string) {
+	for name, value := range patternCaptures(pattern, s) {
+		if ctx.Captures == nil {
+			ctx.Captures = make(map[string]string)
+		}
+
+		ctx.Captures[name] = value
+	}
+}
+
+// Git returns the git context, building it on first use if it is provided
+// lazily. Returns nil if there is no git context.
+func (ctx *MatchContext) Git() *GitContext {
+	if ctx.gitContextProvider != nil {
+		ctx.GitContext = ctx.gitContextProvider()
+		ctx.gitContextProvider = nil
+	}
+
+	return ctx.GitContext
+}
+
+// hasGit reports whether the git context is available without building it.
+func (ctx *MatchContext) hasGit() bool {
+	return ctx.gitContextProvider == nil
+}
+
+// ParsedCommands returns the commands of the bash command, parsed on first
+// use. Returns nil if there is no command or it cannot be parsed.
+func (ctx *MatchContext) ParsedCommands() []parser.Command {
+	if ctx.commandsParsed {
+		return ctx.parsedCommands
+	}
+
+	ctx.commandsParsed = true
+
+	command := ctx.Command
+	if command == "" && ctx.HookContext != nil {
+		command = ctx.HookContext.GetCommand()
+	}
+
+	if command == "" {
+		return nil
+	}
+
+	result, err := parser.NewBashParser(ctx.parserOpts...).Parse(command)
+	if err != nil {
+		return nil
+	}
+
+	ctx.parsedCommands = result.Commands
+
+	return ctx.parsedCommands
+}
+
+// accepts reports whether the rule passes the context's rule filter.
+func (ctx *MatchContext) accepts(rule *Rule) bool {
+	return ctx.RuleFilter == nil || ctx.RuleFilter(rule)
 }
 
 // Engine is the main interface for the rule engine.
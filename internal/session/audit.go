@@ -51,6 +51,9 @@ const (
 
 	// AuditActionUnpoison indicates a session was unpoisoned.
 	AuditActionUnpoison
+
+	// AuditActionRewrite indicates a tool input was rewritten by a rule.
+	AuditActionRewrite
 )
 
 // AuditEntry represents an audit log entry for session operations.
@@ -58,7 +61,7 @@ type AuditEntry struct {
 	// Timestamp is when the action occurred.
 	Timestamp time.Time `json:"timestamp"`
 
-	// Action is the type of operation (poison/unpoison).
+	// Action is the type of operation (poison/unpoison/rewrite).
 	Action AuditAction `json:"action"`
 
 	// SessionID is the Claude Code session identifier.
@@ -80,6 +83,17 @@ type AuditEntry struct {
 	// PoisonMessage is the original error message (for poison actions).
 	PoisonMessage string `json:"poison_message,omitempty"`
 
+	// Validator is the validator whose rule rewrote the tool input (for
+	// rewrite actions).
+	Validator string `json:"validator,omitempty"`
+
+	// RewriteMessage is the message of the rewrite (for rewrite actions).
+	RewriteMessage string `json:"rewrite_message,omitempty"`
+
+	// Rewrite maps the rewritten tool input fields to their new values,
+	// truncated (for rewrite actions).
+	Rewrite map[string]string `json:"rewrite,omitempty"`
+
 	// WorkingDir is the working directory when the action occurred.
 	WorkingDir string `json:"working_dir,omitempty"`
 }
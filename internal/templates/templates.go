@@ -35,3 +35,9 @@ func MustExecute(tmpl *template.Template, data any) string {
 func Parse(name, text string) *template.Template {
 	return template.Must(template.New(name).Funcs(funcMap).Parse(text))
 }
+
+// Compile parses a user-provided template string with the funcMap. Unlike
+// Parse, it returns parse errors, and missing map keys render as empty strings.
+func Compile(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(funcMap).Option("missingkey=zero").Parse(text)
+}
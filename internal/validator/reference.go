@@ -87,7 +87,7 @@ const (
 	RefGitBlockedRemote Reference = ReferenceBaseURL + "/GIT025"
 )
 
-// File-related references (FILE001-FILE009).
+// File-related references (FILE001-FILE010).
 const (
 	// RefShellcheck indicates shellcheck validation failure.
 	RefShellcheck Reference = ReferenceBaseURL + "/FILE001"
@@ -115,6 +115,9 @@ const (
 
 	// RefRustfmtCheck indicates rustfmt Rust code formatting failure.
 	RefRustfmtCheck Reference = ReferenceBaseURL + "/FILE009"
+
+	// RefFileProtectedPath indicates access to a path denied by the paths policy.
+	RefFileProtectedPath Reference = ReferenceBaseURL + "/FILE010"
 )
 
 // Security-related references (SEC001-SEC005).
@@ -135,10 +138,33 @@ const (
 	RefSecretsConnString Reference = ReferenceBaseURL + "/SEC005"
 )
 
-// Shell-related references (SHELL001-SHELL005).
+// Shell-related references (SHELL001-SHELL010).
 const (
 	// RefShellBackticks indicates unescaped backticks in double-quoted strings.
 	RefShellBackticks Reference = ReferenceBaseURL + "/SHELL001"
+
+	// RefShellRecursiveDelete indicates a recursive delete of the project root,
+	// a path outside it or the .git directory.
+	RefShellRecursiveDelete Reference = ReferenceBaseURL + "/SHELL002"
+
+	// RefShellDiscardChanges indicates a git command discarding uncommitted changes.
+	RefShellDiscardChanges Reference = ReferenceBaseURL + "/SHELL003"
+
+	// RefShellPermissions indicates a recursive or world-writable permission change.
+	RefShellPermissions Reference = ReferenceBaseURL + "/SHELL004"
+
+	// RefShellDiskWrite indicates a write to a disk device or a filesystem format.
+	RefShellDiskWrite Reference = ReferenceBaseURL + "/SHELL005"
+
+	// RefShellKillAll indicates killing all processes or shutting the system down.
+	RefShellKillAll Reference = ReferenceBaseURL + "/SHELL006"
+
+	// RefShellDatabaseDrop indicates a command dropping or emptying a database.
+	RefShellDatabaseDrop Reference = ReferenceBaseURL + "/SHELL007"
+
+	// RefShellNestingDepth indicates shell scripts nested deeper than the
+	// configured maximum depth, which cannot be validated.
+	RefShellNestingDepth Reference = ReferenceBaseURL + "/SHELL008"
 )
 
 // GitHub CLI-related references (GH001-GH005).
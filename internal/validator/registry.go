@@ -67,6 +67,13 @@ func EventTypeIs(eventType hook.EventType) Predicate {
 	}
 }
 
+// EventTypeIn returns a predicate that matches any of the given event types.
+func EventTypeIn(eventTypes ...hook.EventType) Predicate {
+	return func(ctx *hook.Context) bool {
+		return slices.Contains(eventTypes, ctx.EventType)
+	}
+}
+
 // ToolTypeIs returns a predicate that matches the given tool type.
 func ToolTypeIs(toolType hook.ToolType) Predicate {
 	return func(ctx *hook.Context) bool {
@@ -106,6 +113,15 @@ func CommandStartsWith(prefix string) Predicate {
 	}
 }
 
+// PromptMatches returns a predicate that matches if the submitted prompt matches the pattern.
+func PromptMatches(pattern string) Predicate {
+	re := regexp.MustCompile(pattern)
+
+	return func(ctx *hook.Context) bool {
+		return re.MatchString(ctx.Prompt)
+	}
+}
+
 // FilePathMatches returns a predicate that matches if the file path matches the pattern.
 func FilePathMatches(pattern string) Predicate {
 	return func(ctx *hook.Context) bool {
@@ -152,9 +168,23 @@ func FileExtensionIn(exts ...string) Predicate {
 	}
 }
 
+// Predicates creates the predicates that parse Bash commands, with the given
+// parser options (e.g. the configured nesting depth). The package-level
+// functions of the same names use the default parser options.
+type Predicates struct {
+	parserOpts []parser.BashParserOption
+}
+
+// NewPredicates creates Predicates parsing Bash commands with the given options.
+func NewPredicates(opts ...parser.BashParserOption) *Predicates {
+	return &Predicates{parserOpts: opts}
+}
+
+var defaultPredicates = NewPredicates()
+
 // BashWritesFileWithExtension returns a predicate that matches if a Bash command writes
 // to a file with any of the given extensions.
-func BashWritesFileWithExtension(exts ...string) Predicate {
+func (p *Predicates) BashWritesFileWithExtension(exts ...string) Predicate {
 	// Normalize extensions
 	normalized := make([]string, len(exts))
 
@@ -173,7 +203,7 @@ func BashWritesFileWithExtension(exts ...string) Predicate {
 		}
 
 		// Parse the bash command
-		bashParser := parser.NewBashParser()
+		bashParser := parser.NewBashParser(p.parserOpts...)
 
 		result, err := bashParser.Parse(ctx.GetCommand())
 		if err != nil {
@@ -245,9 +275,9 @@ func Never() Predicate {
 
 // GitSubcommandIs returns a predicate that matches if any git command in the chain
 // has the given subcommand. This properly handles command chains like "git add && git commit".
-func GitSubcommandIs(subcommand string) Predicate {
+func (p *Predicates) GitSubcommandIs(subcommand string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if gitCmd.Subcommand == subcommand {
@@ -261,9 +291,9 @@ func GitSubcommandIs(subcommand string) Predicate {
 
 // GitSubcommandIn returns a predicate that matches if any git command in the chain
 // has any of the given subcommands.
-func GitSubcommandIn(subcommands ...string) Predicate {
+func (p *Predicates) GitSubcommandIn(subcommands ...string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if slices.Contains(subcommands, gitCmd.Subcommand) {
@@ -276,9 +306,9 @@ func GitSubcommandIn(subcommands ...string) Predicate {
 }
 
 // GitHasFlag returns a predicate that matches if any git command in the chain has the given flag.
-func GitHasFlag(flag string) Predicate {
+func (p *Predicates) GitHasFlag(flag string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if gitCmd.HasFlag(flag) {
@@ -292,9 +322,9 @@ func GitHasFlag(flag string) Predicate {
 
 // GitHasAnyFlag returns a predicate that matches if any git command in the chain
 // has any of the given flags.
-func GitHasAnyFlag(flags ...string) Predicate {
+func (p *Predicates) GitHasAnyFlag(flags ...string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if slices.ContainsFunc(flags, gitCmd.HasFlag) {
@@ -308,9 +338,9 @@ func GitHasAnyFlag(flags ...string) Predicate {
 
 // GitSubcommandWithFlag returns a predicate that matches if any git command in the chain
 // has the given subcommand AND has the given flag.
-func GitSubcommandWithFlag(subcommand, flag string) Predicate {
+func (p *Predicates) GitSubcommandWithFlag(subcommand, flag string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if gitCmd.Subcommand == subcommand && gitCmd.HasFlag(flag) {
@@ -324,9 +354,9 @@ func GitSubcommandWithFlag(subcommand, flag string) Predicate {
 
 // GitSubcommandWithAnyFlag returns a predicate that matches if any git command in the chain
 // has the given subcommand AND has any of the given flags.
-func GitSubcommandWithAnyFlag(subcommand string, flags ...string) Predicate {
+func (p *Predicates) GitSubcommandWithAnyFlag(subcommand string, flags ...string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if gitCmd.Subcommand == subcommand && slices.ContainsFunc(flags, gitCmd.HasFlag) {
@@ -340,9 +370,9 @@ func GitSubcommandWithAnyFlag(subcommand string, flags ...string) Predicate {
 
 // GitSubcommandWithoutFlag returns a predicate that matches if any git command in the chain
 // has the given subcommand AND does NOT have the given flag.
-func GitSubcommandWithoutFlag(subcommand, flag string) Predicate {
+func (p *Predicates) GitSubcommandWithoutFlag(subcommand, flag string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if gitCmd.Subcommand == subcommand && !gitCmd.HasFlag(flag) {
@@ -356,9 +386,9 @@ func GitSubcommandWithoutFlag(subcommand, flag string) Predicate {
 
 // GitSubcommandWithoutAnyFlag returns a predicate that matches if any git command in the chain
 // has the given subcommand AND does NOT have any of the given flags.
-func GitSubcommandWithoutAnyFlag(subcommand string, flags ...string) Predicate {
+func (p *Predicates) GitSubcommandWithoutAnyFlag(subcommand string, flags ...string) Predicate {
 	return func(ctx *hook.Context) bool {
-		gitCmds := parseAllGitFromContext(ctx)
+		gitCmds := p.parseAllGit(ctx)
 
 		for _, gitCmd := range gitCmds {
 			if gitCmd.Subcommand == subcommand && !slices.ContainsFunc(flags, gitCmd.HasFlag) {
@@ -370,15 +400,15 @@ func GitSubcommandWithoutAnyFlag(subcommand string, flags ...string) Predicate {
 	}
 }
 
-// parseAllGitFromContext parses all git commands from a hook context.
+// parseAllGit parses all git commands from a hook context.
 // Returns all git commands found in command chains like "git add && git commit".
 // Returns empty slice if no git commands are found or parsing fails.
-func parseAllGitFromContext(ctx *hook.Context) []*parser.GitCommand {
+func (p *Predicates) parseAllGit(ctx *hook.Context) []*parser.GitCommand {
 	if ctx.ToolName != hook.ToolTypeBash {
 		return nil
 	}
 
-	bashParser := parser.NewBashParser()
+	bashParser := parser.NewBashParser(p.parserOpts...)
 
 	result, err := bashParser.Parse(ctx.GetCommand())
 	if err != nil {
@@ -400,3 +430,51 @@ func parseAllGitFromContext(ctx *hook.Context) []*parser.GitCommand {
 
 	return gitCmds
 }
+
+// Predicates With Default Parser Options
+
+// BashWritesFileWithExtension is Predicates.BashWritesFileWithExtension with
+// the default parser options.
+func BashWritesFileWithExtension(exts ...string) Predicate {
+	return defaultPredicates.BashWritesFileWithExtension(exts...)
+}
+
+// GitSubcommandIs is Predicates.GitSubcommandIs with the default parser options.
+func GitSubcommandIs(subcommand string) Predicate {
+	return defaultPredicates.GitSubcommandIs(subcommand)
+}
+
+// GitSubcommandIn is Predicates.GitSubcommandIn with the default parser options.
+func GitSubcommandIn(subcommands ...string) Predicate {
+	return defaultPredicates.GitSubcommandIn(subcommands...)
+}
+
+// GitHasFlag is Predicates.GitHasFlag with the default parser options.
+func GitHasFlag(flag string) Predicate {
+	return defaultPredicates.GitHasFlag(flag)
+}
+
+// GitHasAnyFlag is Predicates.GitHasAnyFlag with the default parser options.
+func GitHasAnyFlag(flags ...string) Predicate {
+	return defaultPredicates.GitHasAnyFlag(flags...)
+}
+
+// GitSubcommandWithFlag is Predicates.GitSubcommandWithFlag with the default parser options.
+func GitSubcommandWithFlag(subcommand, flag string) Predicate {
+	return defaultPredicates.GitSubcommandWithFlag(subcommand, flag)
+}
+
+// GitSubcommandWithAnyFlag is Predicates.GitSubcommandWithAnyFlag with the default parser options.
+func GitSubcommandWithAnyFlag(subcommand string, flags ...string) Predicate {
+	return defaultPredicates.GitSubcommandWithAnyFlag(subcommand, flags...)
+}
+
+// GitSubcommandWithoutFlag is Predicates.GitSubcommandWithoutFlag with the default parser options.
+func GitSubcommandWithoutFlag(subcommand, flag string) Predicate {
+	return defaultPredicates.GitSubcommandWithoutFlag(subcommand, flag)
+}
+
+// GitSubcommandWithoutAnyFlag is Predicates.GitSubcommandWithoutAnyFlag with the default parser options.
+func GitSubcommandWithoutAnyFlag(subcommand string, flags ...string) Predicate {
+	return defaultPredicates.GitSubcommandWithoutAnyFlag(subcommand, flags...)
+}
@@ -31,15 +31,16 @@ var DefaultSuggestions = map[Reference]string{
 	RefGitBlockedRemote:      "Use an allowed remote instead (see error message for suggested alternatives)",
 
 	// File suggestions
-	RefShellcheck:   "Run 'shellcheck <file>' to see detailed errors",
-	RefTerraformFmt: "Run 'terraform fmt' or 'tofu fmt' to fix formatting",
-	RefTflint:       "Run 'tflint' to see detailed linting issues",
-	RefActionlint:   "Run 'actionlint' to see workflow issues",
-	RefMarkdownLint: "Check markdown formatting and structure",
-	RefGofumpt:      "Run 'gofumpt -w <file>' to auto-fix formatting",
-	RefRuffCheck:    "Run 'ruff check <file>' to see Python code quality issues",
-	RefOxlintCheck:  "Run 'oxlint <file>' to see JavaScript/TypeScript code quality issues",
-	RefRustfmtCheck: "Run 'rustfmt <file>' to auto-fix formatting",
+	RefShellcheck:        "Run 'shellcheck <file>' to see detailed errors",
+	RefTerraformFmt:      "Run 'terraform fmt' or 'tofu fmt' to fix formatting",
+	RefTflint:            "Run 'tflint' to see detailed linting issues",
+	RefActionlint:        "Run 'actionlint' to see workflow issues",
+	RefMarkdownLint:      "Check markdown formatting and structure",
+	RefGofumpt:           "Run 'gofumpt -w <file>' to auto-fix formatting",
+	RefRuffCheck:         "Run 'ruff check <file>' to see Python code quality issues",
+	RefOxlintCheck:       "Run 'oxlint <file>' to see JavaScript/TypeScript code quality issues",
+	RefRustfmtCheck:      "Run 'rustfmt <file>' to auto-fix formatting",
+	RefFileProtectedPath: "Use a path inside the project, or add an allow rule to [validators.file.paths]",
 
 	// Security suggestions
 	RefSecretsAPIKey:     "Remove API key and use environment variables or secret management",
@@ -49,7 +50,14 @@ var DefaultSuggestions = map[Reference]string{
 	RefSecretsConnString: "Use environment variables for database connection strings",
 
 	// Shell suggestions
-	RefShellBackticks: "Use HEREDOC (git commit -m \"$(cat <<'EOF'\\n...\\nEOF\\n)\") or file-based input (--body-file)",
+	RefShellBackticks:       "Use HEREDOC (git commit -m \"$(cat <<'EOF'\\n...\\nEOF\\n)\") or file-based input (--body-file)",
+	RefShellRecursiveDelete: "Delete specific paths inside the project, or add the path to allowed_paths",
+	RefShellDiscardChanges:  "Commit or stash the changes first: git stash push -u",
+	RefShellPermissions:     "Grant only the permissions needed on specific files (e.g., chmod 755 script.sh)",
+	RefShellDiskWrite:       "Write to a regular file instead of a disk device",
+	RefShellKillAll:         "Kill specific processes by PID or name (e.g., pkill -f my-server)",
+	RefShellDatabaseDrop:    "Run destructive SQL manually, or back up the data and use a migration",
+	RefShellNestingDepth:    "Run the nested command directly instead of through bash -c or eval",
 
 	// GitHub CLI suggestions
 	RefGHIssueValidation: "Fix markdown formatting in issue body (empty lines around headings, proper list spacing)",
@@ -4,9 +4,11 @@ package validator
 
 import (
 	"context"
+	"slices"
 
 	"github.com/smykla-labs/klaudiush/pkg/hook"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
 )
 
 // ValidatorCategory represents the type of workload a validator performs.
@@ -69,12 +71,24 @@ type Result struct {
 	// Some validators may only warn without blocking.
 	ShouldBlock bool
 
+	// ShouldAsk indicates whether the user should be asked to confirm the operation.
+	// Used for risky but legitimate operations instead of blocking outright.
+	ShouldAsk bool
+
 	// Reference is the URL that uniquely identifies this error type.
 	// Format: https://klaudiu.sh/{CODE} (e.g., https://klaudiu.sh/GIT001).
 	Reference Reference
 
 	// FixHint provides a short suggestion for fixing the issue.
 	FixHint string
+
+	// UpdatedInput is the rewritten tool input to run instead of the
+	// original one. Set by rules with the rewrite action.
+	UpdatedInput map[string]any
+
+	// Feedback indicates a finding on the file written by a tool that has
+	// already run (PostToolUse), to be fed back to the model.
+	Feedback bool
 }
 
 // Pass creates a passing validation result.
@@ -156,6 +170,40 @@ func WarnWithRef(ref Reference, message string) *Result {
 	}
 }
 
+// Ask creates a failing validation result that asks the user for confirmation.
+func Ask(message string) *Result {
+	return &Result{
+		Passed:      false,
+		Message:     message,
+		ShouldBlock: false,
+		ShouldAsk:   true,
+	}
+}
+
+// AskWithRef creates a confirmation validation result with a reference URL.
+// Automatically populates FixHint from the suggestions registry.
+func AskWithRef(ref Reference, message string) *Result {
+	return &Result{
+		Passed:      false,
+		Message:     message,
+		ShouldBlock: false,
+		ShouldAsk:   true,
+		Reference:   ref,
+		FixHint:     GetSuggestion(ref),
+	}
+}
+
+// Rewrite creates a non-blocking validation result that replaces the tool
+// input with updatedInput.
+func Rewrite(message string, updatedInput map[string]any) *Result {
+	return &Result{
+		Passed:       false,
+		Message:      message,
+		ShouldBlock:  false,
+		UpdatedInput: updatedInput,
+	}
+}
+
 // AddDetail adds a detail to the result.
 func (r *Result) AddDetail(key, value string) *Result {
 	if r.Details == nil {
@@ -177,13 +225,22 @@ func (r *Result) String() string {
 		return "BLOCK"
 	}
 
+	if r.ShouldAsk {
+		return "ASK"
+	}
+
+	if r.UpdatedInput != nil {
+		return "REWRITE"
+	}
+
 	return "WARN"
 }
 
 // BaseValidator provides common validator functionality.
 type BaseValidator struct {
-	name   string
-	logger logger.Logger
+	name       string
+	logger     logger.Logger
+	parserOpts []parser.BashParserOption
 }
 
 // NewBaseValidator creates a new BaseValidator.
@@ -206,6 +263,18 @@ func (v *BaseValidator) Logger() logger.Logger {
 	return v.logger
 }
 
+// SetMaxNestingDepth sets the depth up to which the Bash parsers of the
+// validator parse shell scripts nested in commands.
+func (v *BaseValidator) SetMaxNestingDepth(depth int) {
+	v.parserOpts = []parser.BashParserOption{parser.WithMaxNestingDepth(depth)}
+}
+
+// NewBashParser creates a Bash parser with the validator's parser settings
+// and the given options.
+func (v *BaseValidator) NewBashParser(opts ...parser.BashParserOption) *parser.BashParser {
+	return parser.NewBashParser(append(slices.Clone(v.parserOpts), opts...)...)
+}
+
 // Category returns the default category (CPU) for validators.
 // Validators that perform I/O or Git operations should override this.
 func (*BaseValidator) Category() ValidatorCategory {
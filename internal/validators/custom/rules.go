@@ -0,0 +1,69 @@
+// Package custom provides the validator that applies user-defined rules to
+// every tool call.
+package custom
+
+import (
+	"context"
+
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// RulesValidator evaluates rules for every event and tool, so that rules can
+// govern tool calls no built-in validator handles (e.g. rm, curl, Read).
+type RulesValidator struct {
+	validator.BaseValidator
+	ruleAdapter *rules.RuleValidatorAdapter
+}
+
+// NewRulesValidator creates a new RulesValidator instance.
+func NewRulesValidator(log logger.Logger, ruleAdapter *rules.RuleValidatorAdapter) *RulesValidator {
+	return &RulesValidator{
+		BaseValidator: *validator.NewBaseValidator("validate-rules", log),
+		ruleAdapter:   ruleAdapter,
+	}
+}
+
+// Validate evaluates the rules for the hook context.
+func (v *RulesValidator) Validate(ctx context.Context, hookCtx *hook.Context) *validator.Result {
+	if v.ruleAdapter == nil {
+		return validator.Pass()
+	}
+
+	if result := v.ruleAdapter.CheckRules(ctx, hookCtx); result != nil {
+		return result
+	}
+
+	return validator.Pass()
+}
+
+// NewRuleFilter returns the rule filter for the rules validator. Rules
+// without an event_type only apply to PreToolUse. Unscoped rules (no
+// validator_type, or "*") are skipped when covered matches the hook context,
+// because the built-in validators that handle it evaluate them already.
+// Rules scoped to "custom" are only evaluated by the rules validator.
+func NewRuleFilter(covered validator.Predicate) func(hookCtx *hook.Context) func(rule *rules.Rule) bool {
+	return func(hookCtx *hook.Context) func(rule *rules.Rule) bool {
+		preToolUse := hookCtx.EventType == hook.EventTypePreToolUse
+		uncovered := covered == nil || !covered(hookCtx)
+
+		return func(rule *rules.Rule) bool {
+			if rule.Match == nil {
+				return preToolUse && uncovered
+			}
+
+			if rule.Match.EventType == "" && !preToolUse {
+				return false
+			}
+
+			switch rule.Match.ValidatorType {
+			case "", rules.ValidatorAll:
+				return uncovered
+			default:
+				return true
+			}
+		}
+	}
+}
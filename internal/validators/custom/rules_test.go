@@ -0,0 +1,146 @@
+package custom_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/internal/validators/custom"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+var _ = Describe("RulesValidator", func() {
+	var (
+		ctx     context.Context
+		log     logger.Logger
+		covered validator.Predicate
+	)
+
+	bash := func(command string) *hook.Context {
+		return &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			ToolInput: hook.ToolInput{Command: command},
+		}
+	}
+
+	newValidator := func(ruleList ...*rules.Rule) *custom.RulesValidator {
+		engine, err := rules.NewRuleEngine(ruleList)
+		Expect(err).NotTo(HaveOccurred())
+
+		adapter := rules.NewRuleValidatorAdapter(
+			engine,
+			rules.ValidatorCustom,
+			rules.WithRuleFilter(custom.NewRuleFilter(covered)),
+		)
+
+		return custom.NewRulesValidator(log, adapter)
+	}
+
+	blockRule := func(name string, match *rules.RuleMatch) *rules.Rule {
+		return &rules.Rule{
+			Name:    name,
+			Enabled: true,
+			Match:   match,
+			Action: &rules.RuleAction{
+				Type:    rules.ActionBlock,
+				Message: name + " matched",
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		log = logger.NewNoOpLogger()
+		covered = validator.CommandContains("git commit")
+	})
+
+	It("passes without a rule adapter", func() {
+		v := custom.NewRulesValidator(log, nil)
+
+		Expect(v.Validate(ctx, bash("rm -rf /")).Passed).To(BeTrue())
+	})
+
+	It("passes when no rule matches", func() {
+		v := newValidator(blockRule("no-rm", &rules.RuleMatch{CommandPattern: "rm -rf *"}))
+
+		Expect(v.Validate(ctx, bash("ls -la")).Passed).To(BeTrue())
+	})
+
+	It("applies unscoped rules to commands no built-in validator handles", func() {
+		v := newValidator(blockRule("no-rm", &rules.RuleMatch{CommandPattern: "rm -rf *"}))
+
+		result := v.Validate(ctx, bash("rm -rf build"))
+
+		Expect(result.Passed).To(BeFalse())
+		Expect(result.ShouldBlock).To(BeTrue())
+		Expect(result.Message).To(ContainSubstring("no-rm matched"))
+	})
+
+	It("leaves unscoped rules to built-in validators for commands they handle", func() {
+		v := newValidator(blockRule("no-commit", &rules.RuleMatch{
+			ValidatorType:  rules.ValidatorAll,
+			CommandPattern: "git commit*",
+		}))
+
+		Expect(v.Validate(ctx, bash("git commit -m test")).Passed).To(BeTrue())
+	})
+
+	It("applies custom rules to every tool call", func() {
+		v := newValidator(blockRule("no-commit", &rules.RuleMatch{
+			ValidatorType:  rules.ValidatorCustom,
+			CommandPattern: "git commit*",
+		}))
+
+		Expect(v.Validate(ctx, bash("git commit -m test")).Passed).To(BeFalse())
+	})
+
+	It("matches tool types no built-in validator handles", func() {
+		v := newValidator(blockRule("no-read", &rules.RuleMatch{
+			ValidatorType: rules.ValidatorCustom,
+			ToolType:      "Read",
+		}))
+
+		result := v.Validate(ctx, &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeRead,
+			ToolInput: hook.ToolInput{FilePath: "/etc/passwd"},
+		})
+
+		Expect(result.Passed).To(BeFalse())
+	})
+
+	It("skips rules scoped to other validators", func() {
+		v := newValidator(blockRule("no-push", &rules.RuleMatch{
+			ValidatorType:  rules.ValidatorGitPush,
+			CommandPattern: "*",
+		}))
+
+		Expect(v.Validate(ctx, bash("rm -rf build")).Passed).To(BeTrue())
+	})
+
+	Context("with non-PreToolUse events", func() {
+		notification := &hook.Context{EventType: hook.EventTypeNotification}
+
+		It("skips rules without an event type", func() {
+			v := newValidator(blockRule("everything", &rules.RuleMatch{
+				ValidatorType: rules.ValidatorCustom,
+			}))
+
+			Expect(v.Validate(ctx, notification).Passed).To(BeTrue())
+		})
+
+		It("applies rules scoped to the event", func() {
+			v := newValidator(blockRule("notifications", &rules.RuleMatch{
+				ValidatorType: rules.ValidatorCustom,
+				EventType:     "Notification",
+			}))
+
+			Expect(v.Validate(ctx, notification).Passed).To(BeFalse())
+		})
+	})
+})
@@ -2,8 +2,12 @@ package file
 
 import (
 	"cmp"
+	"os"
 	"strings"
 
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/pkg/hook"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
 )
 
@@ -150,3 +154,303 @@ func EditReachesEOF(content, oldStr string) bool {
 
 	return trimmed == ""
 }
+
+// Replacement is an old/new string pair that can be located in the original
+// file content, used to extract a lintable fragment for one region of an edit.
+type Replacement struct {
+	OldString string
+	NewString string
+}
+
+// changedRegion is a span of the edited content that differs from the original.
+type changedRegion struct {
+	start  int // start offset in the edited content
+	newLen int // length in the edited content
+	oldLen int // length of the replaced span in the original content
+}
+
+func (r changedRegion) end() int {
+	return r.start + r.newLen
+}
+
+// editHunk is a changed region with offsets in both the original and edited content.
+type editHunk struct {
+	oldStart, oldEnd int
+	newStart, newEnd int
+}
+
+// ApplyEdits applies edits sequentially to content, the same way the Edit and
+// MultiEdit tools do, and returns the edited content.
+func ApplyEdits(content string, edits []hook.Edit) (string, error) {
+	edited, _, err := applyEdits(content, edits)
+
+	return edited, err
+}
+
+// ResolveReplacements resolves the edits of an Edit or MultiEdit operation into
+// replacements relative to the original file content, one per changed region.
+//
+// A single Edit is returned as-is. Otherwise, the edits are applied sequentially
+// and every changed region is expanded to full lines until its old string first
+// occurs at the region in the original content, so it can be passed to
+// ExtractEditFragment.
+// Regions whose fragments would overlap given contextLines are merged.
+func ResolveReplacements(
+	original string,
+	edits []hook.Edit,
+	contextLines int,
+) ([]Replacement, error) {
+	if len(edits) == 1 && !edits[0].ReplaceAll {
+		return []Replacement{{OldString: edits[0].OldString, NewString: edits[0].NewString}}, nil
+	}
+
+	edited, regions, err := applyEdits(original, edits)
+	if err != nil {
+		return nil, err
+	}
+
+	hunks := make([]editHunk, 0, len(regions))
+	shift := 0
+
+	for _, r := range regions {
+		hunks = append(hunks, editHunk{
+			oldStart: r.start - shift,
+			oldEnd:   r.start - shift + r.oldLen,
+			newStart: r.start,
+			newEnd:   r.end(),
+		})
+
+		shift += r.newLen - r.oldLen
+	}
+
+	for i := range hunks {
+		expandHunk(edited, hunks, i, 0)
+	}
+
+	// Grow hunks line by line until each old string identifies its own location
+	for {
+		hunks = mergeHunks(edited, hunks, contextLines)
+		changed := false
+
+		for i := range hunks {
+			if isResolvable(original, edited, hunks[i]) {
+				continue
+			}
+
+			if expandHunk(edited, hunks, i, 1) {
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	replacements := make([]Replacement, 0, len(hunks))
+
+	for _, h := range hunks {
+		replacements = append(replacements, Replacement{
+			OldString: original[h.oldStart:h.oldEnd],
+			NewString: edited[h.newStart:h.newEnd],
+		})
+	}
+
+	return replacements, nil
+}
+
+// applyEdits applies edits sequentially and tracks the changed regions of the result.
+func applyEdits(content string, edits []hook.Edit) (string, []changedRegion, error) {
+	var regions []changedRegion
+
+	for i, edit := range edits {
+		if edit.OldString == "" {
+			// An empty old_string is only valid when creating a new file
+			if content != "" {
+				return "", nil, errors.Newf("edit %d: empty old_string", i+1)
+			}
+
+			content = edit.NewString
+			regions = []changedRegion{{start: 0, newLen: len(content)}}
+
+			continue
+		}
+
+		found := false
+		from := 0
+
+		for {
+			idx := strings.Index(content[from:], edit.OldString)
+			if idx == -1 {
+				break
+			}
+
+			pos := from + idx
+			content, regions = replaceAt(content, regions, pos, len(edit.OldString), edit.NewString)
+			found = true
+
+			if !edit.ReplaceAll {
+				break
+			}
+
+			from = pos + len(edit.NewString)
+		}
+
+		if !found {
+			return "", nil, errors.Newf("edit %d: old_string not found in file content", i+1)
+		}
+	}
+
+	return content, regions, nil
+}
+
+// replaceAt replaces oldLen bytes at pos with newStr and updates the changed regions.
+// Regions touching the replaced span are merged into a single region.
+func replaceAt(
+	content string,
+	regions []changedRegion,
+	pos, oldLen int,
+	newStr string,
+) (string, []changedRegion) {
+	end := pos + oldLen
+	delta := len(newStr) - oldLen
+	spanStart, spanEnd := pos, end
+	coveredNew, coveredOld := 0, 0
+
+	var before, after []changedRegion
+
+	for _, r := range regions {
+		switch {
+		case r.end() < pos:
+			before = append(before, r)
+		case r.start > end:
+			r.start += delta
+			after = append(after, r)
+		default:
+			spanStart = min(spanStart, r.start)
+			spanEnd = max(spanEnd, r.end())
+			coveredNew += r.newLen
+			coveredOld += r.oldLen
+		}
+	}
+
+	span := changedRegion{
+		start:  spanStart,
+		newLen: spanEnd - spanStart + delta,
+		oldLen: spanEnd - spanStart - coveredNew + coveredOld,
+	}
+
+	updated := append(append(before, span), after...)
+
+	return content[:pos] + newStr + content[end:], updated
+}
+
+// expandHunk expands hunk i to full lines plus extraLines lines in both directions,
+// without crossing its neighbours. Returns true if the hunk grew.
+func expandHunk(edited string, hunks []editHunk, i, extraLines int) bool {
+	h := hunks[i]
+
+	lower, upper := 0, len(edited)
+	if i > 0 {
+		lower = hunks[i-1].newEnd
+	}
+
+	if i < len(hunks)-1 {
+		upper = hunks[i+1].newStart
+	}
+
+	newStart := h.newStart
+	for n := 0; n <= extraLines && newStart > lower; n++ {
+		from := newStart
+		if n > 0 {
+			from-- // step over the newline ending the previous line
+		}
+
+		newStart = max(lower, strings.LastIndex(edited[:from], "\n")+1)
+	}
+
+	newEnd := h.newEnd
+	for n := 0; n <= extraLines && newEnd < upper; n++ {
+		from := newEnd
+		if n > 0 {
+			from++ // step over the newline ending the current line
+		}
+
+		idx := strings.Index(edited[min(from, len(edited)):], "\n")
+		if idx == -1 {
+			newEnd = upper
+		} else {
+			newEnd = min(upper, from+idx)
+		}
+	}
+
+	// The content between hunks is unchanged, so offsets move by the same amount
+	hunks[i] = editHunk{
+		oldStart: h.oldStart - (h.newStart - newStart),
+		oldEnd:   h.oldEnd + (newEnd - h.newEnd),
+		newStart: newStart,
+		newEnd:   newEnd,
+	}
+
+	return newStart != h.newStart || newEnd != h.newEnd
+}
+
+// mergeHunks merges adjacent hunks separated by no more than 2*contextLines lines,
+// as their fragments would overlap.
+func mergeHunks(edited string, hunks []editHunk, contextLines int) []editHunk {
+	if len(hunks) == 0 {
+		return hunks
+	}
+
+	merged := []editHunk{hunks[0]}
+
+	for _, h := range hunks[1:] {
+		last := &merged[len(merged)-1]
+
+		if strings.Count(edited[last.newEnd:h.newStart], "\n") <= 2*contextLines {
+			last.oldEnd = h.oldEnd
+			last.newEnd = h.newEnd
+
+			continue
+		}
+
+		merged = append(merged, h)
+	}
+
+	return merged
+}
+
+// isResolvable returns true if the hunk has non-empty strings and its old string
+// occurs first at the hunk position in the original content.
+func isResolvable(original, edited string, h editHunk) bool {
+	oldStr := original[h.oldStart:h.oldEnd]
+	if oldStr == "" || h.newStart == h.newEnd {
+		return false
+	}
+
+	return strings.Index(original, oldStr) == h.oldStart
+}
+
+// readEditReplacements reads the file being edited and resolves the edits of the
+// hook context into replacements relative to its original content.
+func readEditReplacements(
+	ctx *hook.Context,
+	filePath string,
+	contextLines int,
+	log logger.Logger,
+) (string, []Replacement, error) {
+	//nolint:gosec // filePath is from Claude Code tool context, not user input
+	originalContent, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Debug("failed to read file for edit validation", "file", filePath, "error", err)
+		return "", nil, err
+	}
+
+	replacements, err := ResolveReplacements(string(originalContent), ctx.GetEdits(), contextLines)
+	if err != nil {
+		log.Debug("failed to resolve edits", "file", filePath, "error", err)
+		return "", nil, err
+	}
+
+	return string(originalContent), replacements, nil
+}
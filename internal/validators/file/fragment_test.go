@@ -1,10 +1,14 @@
 package file_test
 
 import (
+	"fmt"
+	"strings"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
 	"github.com/smykla-labs/klaudiush/internal/validators/file"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
 	"github.com/smykla-labs/klaudiush/pkg/logger"
 )
 
@@ -577,3 +581,120 @@ var _ = Describe("EditReachesEOF", func() {
 		})
 	})
 })
+
+var _ = Describe("ApplyEdits", func() {
+	It("applies edits sequentially", func() {
+		edited, err := file.ApplyEdits("a\nb\nc", []hook.Edit{
+			{OldString: "a", NewString: "foo"},
+			{OldString: "foo", NewString: "bar"},
+			{OldString: "c", NewString: "baz"},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(edited).To(Equal("bar\nb\nbaz"))
+	})
+
+	It("replaces all occurrences when replace_all is set", func() {
+		edited, err := file.ApplyEdits("x\ny\nx", []hook.Edit{
+			{OldString: "x", NewString: "z", ReplaceAll: true},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(edited).To(Equal("z\ny\nz"))
+	})
+
+	It("returns an error when old_string is not found", func() {
+		_, err := file.ApplyEdits("a\nb", []hook.Edit{
+			{OldString: "a", NewString: "c"},
+			{OldString: "missing", NewString: "d"},
+		})
+
+		Expect(err).To(MatchError(ContainSubstring("edit 2")))
+	})
+})
+
+var _ = Describe("ResolveReplacements", func() {
+	lines := func(n int) string {
+		l := make([]string, 0, n)
+		for i := 1; i <= n; i++ {
+			l = append(l, fmt.Sprintf("line %d", i))
+		}
+
+		return strings.Join(l, "\n")
+	}
+
+	It("returns a single Edit as-is", func() {
+		replacements, err := file.ResolveReplacements("a\nb", []hook.Edit{
+			{OldString: "a", NewString: "c"},
+		}, 2)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replacements).To(Equal([]file.Replacement{{OldString: "a", NewString: "c"}}))
+	})
+
+	It("returns one replacement per distant edit", func() {
+		replacements, err := file.ResolveReplacements(lines(20), []hook.Edit{
+			{OldString: "line 3", NewString: "changed 3"},
+			{OldString: "line 17", NewString: "changed 17"},
+		}, 2)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replacements).To(Equal([]file.Replacement{
+			{OldString: "line 3", NewString: "changed 3"},
+			{OldString: "line 17", NewString: "changed 17"},
+		}))
+	})
+
+	It("merges edits whose fragments overlap", func() {
+		replacements, err := file.ResolveReplacements(lines(20), []hook.Edit{
+			{OldString: "line 5", NewString: "changed 5"},
+			{OldString: "line 8", NewString: "changed 8"},
+		}, 2)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replacements).To(Equal([]file.Replacement{{
+			OldString: "line 5\nline 6\nline 7\nline 8",
+			NewString: "changed 5\nline 6\nline 7\nchanged 8",
+		}}))
+	})
+
+	It("resolves edits that change the result of earlier edits", func() {
+		replacements, err := file.ResolveReplacements(lines(20), []hook.Edit{
+			{OldString: "line 10", NewString: "first"},
+			{OldString: "first", NewString: "second"},
+		}, 2)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replacements).To(Equal([]file.Replacement{
+			{OldString: "line 10", NewString: "second"},
+		}))
+	})
+
+	It("expands old strings that are not unique to their own location", func() {
+		content := "func a() {\n\treturn nil\n}\n" + lines(10) + "\nfunc b() {\n\treturn nil\n}"
+
+		replacements, err := file.ResolveReplacements(content, []hook.Edit{
+			{OldString: "return nil", NewString: "return err", ReplaceAll: true},
+		}, 2)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(replacements).To(HaveLen(2))
+		Expect(replacements[0].OldString).To(Equal("\treturn nil"))
+		Expect(replacements[1].OldString).To(Equal("func b() {\n\treturn nil\n}"))
+		Expect(replacements[1].NewString).To(Equal("func b() {\n\treturn err\n}"))
+
+		for _, r := range replacements {
+			fragment := file.ExtractEditFragment(content, r.OldString, r.NewString, 2, logger.NewNoOpLogger())
+			Expect(fragment).NotTo(ContainSubstring("return nil"))
+		}
+	})
+
+	It("returns an error when an edit cannot be applied", func() {
+		_, err := file.ResolveReplacements("a\nb", []hook.Edit{
+			{OldString: "a", NewString: "c"},
+			{OldString: "missing", NewString: "d"},
+		}, 2)
+
+		Expect(err).To(HaveOccurred())
+	})
+})
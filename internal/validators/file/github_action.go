@@ -176,10 +176,10 @@ func (v *WorkflowValidator) Validate(ctx context.Context, hookCtx *hook.Context)
     uses: vendor/custom-action@v1`,
 		}
 
-		return validator.FailWithRef(
+		return asFeedback(hookCtx, validator.FailWithRef(
 			validator.RefActionlint,
 			message,
-		).AddDetail("file", details["file"]).AddDetail("errors", details["errors"]).AddDetail("help", details["help"])
+		).AddDetail("file", details["file"]).AddDetail("errors", details["errors"]).AddDetail("help", details["help"]))
 	}
 
 	return validator.Pass()
@@ -210,13 +210,18 @@ func (*WorkflowValidator) isWorkflowFile(path string) bool {
 func (v *WorkflowValidator) getContent(ctx *hook.Context) (string, error) {
 	log := v.Logger()
 
+	// In PostToolUse, validate the whole file as written to disk
+	if ctx.EventType == hook.EventTypePostToolUse {
+		return readWrittenFile(ctx, log)
+	}
+
 	// Try to get content from tool input (Write operation)
 	if ctx.ToolInput.Content != "" {
 		return ctx.ToolInput.Content, nil
 	}
 
-	// For Edit operations in PreToolUse, read file and apply edit
-	if ctx.EventType == hook.EventTypePreToolUse && ctx.ToolName == hook.ToolTypeEdit {
+	// For Edit and MultiEdit operations in PreToolUse, read file and apply the edits
+	if ctx.EventType == hook.EventTypePreToolUse && ctx.IsEditTool() {
 		filePath := ctx.GetFilePath()
 		if filePath == "" {
 			return "", errNoContent
@@ -230,32 +235,20 @@ func (v *WorkflowValidator) getContent(ctx *hook.Context) (string, error) {
 			return "", err
 		}
 
-		// Apply the edit (replace old_string with new_string)
-		oldStr := ctx.ToolInput.OldString
-		newStr := ctx.ToolInput.NewString
-
-		if oldStr == "" {
-			log.Debug("no old_string in edit operation, cannot validate")
+		edits := ctx.GetEdits()
+		if len(edits) == 0 {
+			log.Debug("no edits in edit operation, cannot validate")
 			return "", errNoContent
 		}
 
-		// Replace first occurrence (Edit tool replaces first match)
-		editedContent := strings.Replace(string(originalContent), oldStr, newStr, 1)
-
-		return editedContent, nil
-	}
-
-	// Try to get from file path (Edit or PostToolUse)
-	filePath := ctx.GetFilePath()
-	if filePath != "" && ctx.EventType == hook.EventTypePostToolUse {
-		// In PostToolUse, we could read the file
-		//nolint:gosec // filePath is from Claude Code tool context, not user input
-		content, err := os.ReadFile(filePath)
+		// Apply the edits in order, as the Edit and MultiEdit tools do
+		editedContent, err := ApplyEdits(string(originalContent), edits)
 		if err != nil {
-			return "", errors.Wrap(err, "reading file")
+			log.Debug("failed to apply edits, cannot validate", "error", err)
+			return "", errNoContent
 		}
 
-		return string(content), nil
+		return editedContent, nil
 	}
 
 	return "", errNoContent
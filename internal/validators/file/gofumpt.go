@@ -71,7 +71,7 @@ func (v *GofumptValidator) Validate(
 	}
 
 	// Get content based on operation type
-	content, err := v.getContent(hookCtx, filePath)
+	content, err := v.getContent(ctx, hookCtx, filePath)
 	if err != nil {
 		log.Debug("failed to get content", "error", err)
 		return validator.Pass()
@@ -97,27 +97,27 @@ func (v *GofumptValidator) Validate(
 
 	log.Debug("gofumpt failed", "output", result.RawOut)
 
-	return validator.FailWithRef(
+	return asFeedback(hookCtx, validator.FailWithRef(
 		validator.RefGofumpt,
 		v.formatGofumptOutput(result.RawOut),
-	)
+	))
 }
 
 // getContent extracts Go code content from context
 func (v *GofumptValidator) getContent(
-	ctx *hook.Context,
+	ctx context.Context,
+	hookCtx *hook.Context,
 	filePath string,
 ) (string, error) {
 	log := v.Logger()
 
-	// For Edit operations, skip validation initially (no fragment support)
-	if ctx.EventType == hook.EventTypePreToolUse && ctx.ToolName == hook.ToolTypeEdit {
-		log.Debug("skipping Edit operations (no fragment support)")
-		return "", os.ErrNotExist
+	// For Edit and MultiEdit operations, check the whole edited file (no fragment support)
+	if hookCtx.EventType == hook.EventTypePreToolUse && hookCtx.IsEditTool() {
+		return v.getEditContent(ctx, hookCtx, filePath)
 	}
 
 	// Get content from context (Write operation)
-	content := ctx.ToolInput.Content
+	content := hookCtx.ToolInput.Content
 	if content != "" {
 		return content, nil
 	}
@@ -138,6 +138,41 @@ func (v *GofumptValidator) getContent(
 	return string(data), nil
 }
 
+// getEditContent applies the edits to the file and returns the edited content.
+// gofumpt cannot format fragments, so the whole file is checked. Files that are not
+// formatted before the edit are skipped to avoid forcing users to fix existing issues.
+func (v *GofumptValidator) getEditContent(
+	ctx context.Context,
+	hookCtx *hook.Context,
+	filePath string,
+) (string, error) {
+	log := v.Logger()
+
+	//nolint:gosec // filePath is from Claude Code tool context, not user input
+	originalContent, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Debug("failed to read file for edit validation", "file", filePath, "error", err)
+		return "", err
+	}
+
+	editedContent, err := ApplyEdits(string(originalContent), hookCtx.GetEdits())
+	if err != nil {
+		log.Debug("failed to apply edits", "file", filePath, "error", err)
+		return "", err
+	}
+
+	lintCtx, cancel := context.WithTimeout(ctx, v.getTimeout())
+	defer cancel()
+
+	opts := v.buildGofumptOptions(filePath)
+	if result := v.checker.CheckWithOptions(lintCtx, string(originalContent), opts); !result.Success {
+		log.Debug("file is not formatted before the edit, skipping", "file", filePath)
+		return "", os.ErrNotExist
+	}
+
+	return editedContent, nil
+}
+
 // buildGofumptOptions creates GofumptOptions with auto-detection from go.mod
 func (v *GofumptValidator) buildGofumptOptions(filePath string) *linters.GofumptOptions {
 	opts := &linters.GofumptOptions{}
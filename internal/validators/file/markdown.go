@@ -28,10 +28,7 @@ const (
 	defaultContextLines = 2
 )
 
-var (
-	errFileValidationNotImpl = errors.New("file-based validation not implemented")
-	errNoContent             = errors.New("no content found")
-)
+var errNoContent = errors.New("no content found")
 
 // MarkdownValidator validates Markdown formatting rules
 type MarkdownValidator struct {
@@ -100,16 +97,12 @@ func (v *MarkdownValidator) Validate(ctx context.Context, hookCtx *hook.Context)
 		return validator.Pass()
 	}
 
-	content, initialState, err := v.getContentWithState(hookCtx)
+	contents, err := v.getContentWithState(hookCtx)
 	if err != nil {
 		log.Debug("skipping markdown validation", "error", err)
 		return validator.Pass()
 	}
 
-	if content == "" {
-		return validator.Pass()
-	}
-
 	timeout := v.getTimeout()
 
 	lintCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -119,9 +112,16 @@ func (v *MarkdownValidator) Validate(ctx context.Context, hookCtx *hook.Context)
 	filePath := hookCtx.GetFilePath()
 	displayPath := getDisplayPath(filePath)
 
-	result := v.linter.LintWithPath(lintCtx, content, initialState, displayPath)
+	for _, mc := range contents {
+		if mc.content == "" {
+			continue
+		}
+
+		result := v.linter.LintWithPath(lintCtx, mc.content, mc.initialState, displayPath)
+		if result.Success {
+			continue
+		}
 
-	if !result.Success {
 		message := "Markdown formatting errors"
 
 		r := validator.FailWithRef(validator.RefMarkdownLint, message).
@@ -136,96 +136,123 @@ func (v *MarkdownValidator) Validate(ctx context.Context, hookCtx *hook.Context)
 			}
 		}
 
-		return r
+		return asFeedback(hookCtx, r)
 	}
 
 	return validator.Pass()
 }
 
+// markdownContent holds markdown content and the state it starts in
+type markdownContent struct {
+	content      string
+	initialState *validators.MarkdownState
+}
+
 // getContentWithState extracts markdown content and detects initial state from context
 func (v *MarkdownValidator) getContentWithState(
 	ctx *hook.Context,
-) (string, *validators.MarkdownState, error) {
+) ([]*markdownContent, error) {
 	log := v.Logger()
 
+	// In PostToolUse, validate the whole file as written to disk
+	if ctx.EventType == hook.EventTypePostToolUse {
+		content, err := readWrittenFile(ctx, log)
+		if err != nil {
+			return nil, err
+		}
+
+		return []*markdownContent{{content: content}}, nil
+	}
+
 	// Try to get content from tool input (Write operation)
 	if ctx.ToolInput.Content != "" {
-		return ctx.ToolInput.Content, nil, nil
+		return []*markdownContent{{content: ctx.ToolInput.Content}}, nil
 	}
 
-	// For Edit operations in PreToolUse, validate only the changed fragment with context
-	// to avoid forcing users to fix all existing linting issues
-	if ctx.EventType == hook.EventTypePreToolUse && ctx.ToolName == hook.ToolTypeEdit {
+	// For Edit and MultiEdit operations in PreToolUse, validate only the changed
+	// fragments with context to avoid forcing users to fix all existing linting issues
+	if ctx.EventType == hook.EventTypePreToolUse && ctx.IsEditTool() {
 		filePath := ctx.GetFilePath()
 		if filePath == "" {
-			return "", nil, errNoContent
+			return nil, errNoContent
 		}
 
-		oldStr := ctx.ToolInput.OldString
-		newStr := ctx.ToolInput.NewString
-
-		if oldStr == "" || newStr == "" {
-			log.Debug("missing old_string or new_string in edit operation")
-			return "", nil, errNoContent
-		}
+		// Read original file to extract context around the edits
+		contextLines := v.getContextLines()
 
-		// Read original file to extract context around the edit
-		//nolint:gosec // filePath is from Claude Code tool context, not user input
-		originalContent, err := os.ReadFile(filePath)
+		originalContent, replacements, err := readEditReplacements(ctx, filePath, contextLines, log)
 		if err != nil {
-			log.Debug("failed to read file for edit validation", "file", filePath, "error", err)
-			return "", nil, err
+			return nil, err
 		}
 
-		// Extract fragment with context lines around the edit
-		contextLines := v.getContextLines()
+		contents := make([]*markdownContent, 0, len(replacements))
+
+		for _, r := range replacements {
+			if mc := v.getFragment(originalContent, r, contextLines); mc != nil {
+				contents = append(contents, mc)
+			}
+		}
 
-		fragment := ExtractEditFragment(
-			string(originalContent),
-			oldStr,
-			newStr,
-			contextLines,
-			log,
-		)
-		if fragment == "" {
-			log.Debug("could not extract edit fragment, skipping validation")
-			return "", nil, errNoContent
+		if len(contents) == 0 {
+			return nil, errNoContent
 		}
 
-		// Detect markdown state at fragment start
-		fragmentStartLine := getFragmentStartLine(string(originalContent), oldStr, contextLines)
-		state := validators.DetectMarkdownState(string(originalContent), fragmentStartLine)
-		state.StartLine = fragmentStartLine
-
-		// Determine if the NEW content (new_string) reaches end of file.
-		// We check if old_string is at or near EOF in the original content.
-		// If old_string ends at EOF, then new_string (which replaces it) also ends at EOF.
-		state.EndsAtEOF = EditReachesEOF(string(originalContent), oldStr)
-
-		log.Debug("fragment initial state",
-			"start_line", fragmentStartLine,
-			"in_code_block", state.InCodeBlock,
-			"ends_at_eof", state.EndsAtEOF,
-			"in_list", state.InList,
-			"list_depth", state.ListItemDepth,
-			"list_stack_len", len(state.ListStack),
-			"last_heading_level", state.LastHeadingLevel,
-		)
-
-		log.Debug("validating edit fragment with context")
-
-		return fragment, &state, nil
+		return contents, nil
+	}
+
+	return nil, errNoContent
+}
+
+// getFragment extracts the fragment of a single replacement together with the
+// markdown state at its start. Returns nil if the fragment cannot be extracted.
+func (v *MarkdownValidator) getFragment(
+	originalContent string,
+	r Replacement,
+	contextLines int,
+) *markdownContent {
+	log := v.Logger()
+
+	if r.OldString == "" || r.NewString == "" {
+		log.Debug("missing old_string or new_string in edit operation")
+		return nil
 	}
 
-	// Try to get from file path (Edit or PostToolUse)
-	filePath := ctx.GetFilePath()
-	if filePath != "" {
-		// In PostToolUse, we could read the file, but for now skip
-		// as the Bash version doesn't handle this case well either
-		return "", nil, errFileValidationNotImpl
+	// Extract fragment with context lines around the edit
+	fragment := ExtractEditFragment(
+		originalContent,
+		r.OldString,
+		r.NewString,
+		contextLines,
+		log,
+	)
+	if fragment == "" {
+		log.Debug("could not extract edit fragment, skipping it")
+		return nil
 	}
 
-	return "", nil, errNoContent
+	// Detect markdown state at fragment start
+	fragmentStartLine := getFragmentStartLine(originalContent, r.OldString, contextLines)
+	state := validators.DetectMarkdownState(originalContent, fragmentStartLine)
+	state.StartLine = fragmentStartLine
+
+	// Determine if the NEW content (new_string) reaches end of file.
+	// We check if old_string is at or near EOF in the original content.
+	// If old_string ends at EOF, then new_string (which replaces it) also ends at EOF.
+	state.EndsAtEOF = EditReachesEOF(originalContent, r.OldString)
+
+	log.Debug("fragment initial state",
+		"start_line", fragmentStartLine,
+		"in_code_block", state.InCodeBlock,
+		"ends_at_eof", state.EndsAtEOF,
+		"in_list", state.InList,
+		"list_depth", state.ListItemDepth,
+		"list_stack_len", len(state.ListStack),
+		"last_heading_level", state.LastHeadingLevel,
+	)
+
+	log.Debug("validating edit fragment with context")
+
+	return &markdownContent{content: fragment, initialState: &state}
 }
 
 // formatTableSuggestion formats a table suggestion for display in error details.
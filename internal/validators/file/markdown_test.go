@@ -2,6 +2,8 @@ package file_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -346,6 +348,44 @@ code
 			})
 		})
 
+		Context("PostToolUse", func() {
+			var filePath string
+
+			BeforeEach(func() {
+				filePath = filepath.Join(GinkgoT().TempDir(), "README.md")
+				ctx.EventType = hook.EventTypePostToolUse
+				ctx.ToolName = hook.ToolTypeEdit
+				ctx.ToolInput.FilePath = filePath
+			})
+
+			It("validates the whole file from disk and reports feedback", func() {
+				content := "Some text\n```bash\ncode\n```\n"
+				Expect(os.WriteFile(filePath, []byte(content), 0o600)).To(Succeed())
+
+				result := v.Validate(context.Background(), ctx)
+				Expect(result.Passed).To(BeFalse())
+				Expect(result.ShouldBlock).To(BeFalse())
+				Expect(result.Feedback).To(BeTrue())
+				Expect(
+					result.Details["errors"],
+				).To(ContainSubstring("Line 2: Code block should have empty line before it"))
+			})
+
+			It("prefers the file on disk over the tool input", func() {
+				Expect(os.WriteFile(filePath, []byte("# Title\n\nText.\n"), 0o600)).To(Succeed())
+				ctx.ToolName = hook.ToolTypeWrite
+				ctx.ToolInput.Content = "Some text\n```bash\ncode\n```\n"
+
+				result := v.Validate(context.Background(), ctx)
+				Expect(result.Passed).To(BeTrue())
+			})
+
+			It("passes when the file does not exist", func() {
+				result := v.Validate(context.Background(), ctx)
+				Expect(result.Passed).To(BeTrue())
+			})
+		})
+
 		Context("complex scenarios", func() {
 			It("handles mixed formatting issues", func() {
 				content := `# Title
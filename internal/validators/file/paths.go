@@ -0,0 +1,332 @@
+package file
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/projectpath"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+// pathAccess is a path read or written by a tool.
+type pathAccess struct {
+	// Path is the path as given to the tool.
+	Path string
+
+	// Dir is the working directory the path is relative to, when it differs
+	// from the session working directory (Bash commands after cd).
+	Dir string
+
+	// Access is the access type, config.PathAccessRead or config.PathAccessWrite.
+	Access string
+}
+
+// pathViolation is a path access denied by a rule.
+type pathViolation struct {
+	pathAccess
+
+	// Resolved is the absolute path, with symlinks and ".." resolved.
+	Resolved string
+
+	// Rule is the deny rule matching the path.
+	Rule *config.PathRuleConfig
+
+	// Reason explains why the rule matches the path.
+	Reason string
+}
+
+// PathsValidator validates the paths read and written by tools against the
+// deny and allow rules of the protected paths policy.
+type PathsValidator struct {
+	validator.BaseValidator
+	gitRunner   git.Runner
+	config      *config.PathsValidatorConfig
+	ruleAdapter *rules.RuleValidatorAdapter
+}
+
+// NewPathsValidator creates a new PathsValidator. The git runner is used to
+// find the project root; without one, the working directory is the project
+// root.
+func NewPathsValidator(
+	log logger.Logger,
+	gitRunner git.Runner,
+	cfg *config.PathsValidatorConfig,
+	ruleAdapter *rules.RuleValidatorAdapter,
+) *PathsValidator {
+	return &PathsValidator{
+		BaseValidator: *validator.NewBaseValidator("validate-paths", log),
+		gitRunner:     gitRunner,
+		config:        cfg,
+		ruleAdapter:   ruleAdapter,
+	}
+}
+
+// Validate checks the paths accessed by a tool against the path rules.
+func (v *PathsValidator) Validate(ctx context.Context, hookCtx *hook.Context) *validator.Result {
+	log := v.Logger()
+	log.Debug("Running protected paths validation")
+
+	// Check rules first if rule adapter is configured
+	if v.ruleAdapter != nil {
+		if result := v.ruleAdapter.CheckRules(ctx, hookCtx); result != nil {
+			return result
+		}
+	}
+
+	if v.config == nil || len(v.config.Deny) == 0 {
+		log.Debug("No deny rules configured, skipping validation")
+		return validator.Pass()
+	}
+
+	accesses := accessedPaths(hookCtx, v.NewBashParser())
+	if len(accesses) == 0 {
+		log.Debug("No paths accessed")
+		return validator.Pass()
+	}
+
+	policy := v.newPathPolicy(hookCtx)
+
+	var violations []pathViolation
+
+	for _, access := range accesses {
+		if violation := policy.check(access); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+
+	if len(violations) == 0 {
+		log.Debug("No protected paths accessed")
+		return validator.Pass()
+	}
+
+	return createPathsResult(violations)
+}
+
+// Category returns the validator category for parallel execution.
+// PathsValidator uses CategoryGit as it may query the repository root.
+func (*PathsValidator) Category() validator.ValidatorCategory {
+	return validator.CategoryGit
+}
+
+// accessedPaths returns the paths read and written by the tool of a hook
+// context: the file path of Write, Edit, MultiEdit and Read, the search path
+// of Grep and Glob, and the file writes and input redirections of Bash
+// commands. Bash commands that cannot be parsed access no paths.
+func accessedPaths(hookCtx *hook.Context, bashParser *parser.BashParser) []pathAccess {
+	switch hookCtx.ToolName {
+	case hook.ToolTypeWrite, hook.ToolTypeEdit, hook.ToolTypeMultiEdit:
+		return pathAccesses(config.PathAccessWrite, hookCtx.GetFilePath())
+	case hook.ToolTypeRead, hook.ToolTypeGrep:
+		return pathAccesses(config.PathAccessRead, hookCtx.GetFilePath())
+	case hook.ToolTypeGlob:
+		return pathAccesses(config.PathAccessRead, hookCtx.GetFilePath(), globPatternBase(hookCtx))
+	case hook.ToolTypeBash:
+		return bashPathAccesses(bashParser, hookCtx.GetCommand())
+	default:
+		return nil
+	}
+}
+
+// pathAccesses returns the accesses of the non-empty paths.
+func pathAccesses(access string, paths ...string) []pathAccess {
+	var accesses []pathAccess
+
+	for _, path := range paths {
+		if path != "" {
+			accesses = append(accesses, pathAccess{Path: path, Access: access})
+		}
+	}
+
+	return accesses
+}
+
+// globPatternBase returns the directory searched by the pattern of the Glob
+// tool, when the pattern is absolute or starts with ~.
+func globPatternBase(hookCtx *hook.Context) string {
+	pattern := hookCtx.ToolInput.Pattern
+	if !filepath.IsAbs(pattern) && !strings.HasPrefix(pattern, "~") {
+		return ""
+	}
+
+	idx := strings.IndexAny(pattern, "*?[{")
+	if idx == -1 {
+		return pattern
+	}
+
+	return filepath.Dir(pattern[:idx+1])
+}
+
+// bashPathAccesses returns the file writes and input redirections of a Bash
+// command.
+func bashPathAccesses(bashParser *parser.BashParser, command string) []pathAccess {
+	if command == "" {
+		return nil
+	}
+
+	result, err := bashParser.Parse(command)
+	if err != nil {
+		return nil
+	}
+
+	accesses := make([]pathAccess, 0, len(result.FileWrites)+len(result.FileReads))
+
+	for _, fw := range result.FileWrites {
+		accesses = append(accesses, pathAccess{
+			Path:   fw.Path,
+			Dir:    fw.WorkingDirectory,
+			Access: config.PathAccessWrite,
+		})
+	}
+
+	for _, fr := range result.FileReads {
+		accesses = append(accesses, pathAccess{
+			Path:   fr.Path,
+			Dir:    fr.WorkingDirectory,
+			Access: config.PathAccessRead,
+		})
+	}
+
+	return accesses
+}
+
+// createPathsResult creates a failing result from violations, with the
+// suggestions of the matching rules as fix hint when they have one.
+func createPathsResult(violations []pathViolation) *validator.Result {
+	lines := make([]string, 0, len(violations))
+	suggestions := make([]string, 0, len(violations))
+	seen := make(map[string]bool)
+
+	for _, violation := range violations {
+		path := violation.Path
+		if violation.Resolved != path {
+			path += " -> " + violation.Resolved
+		}
+
+		lines = append(lines, fmt.Sprintf("%s %s: %s", violation.Access, path, violation.Reason))
+
+		if suggestion := violation.Rule.Suggestion; suggestion != "" && !seen[suggestion] {
+			seen[suggestion] = true
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	result := validator.FailWithRef(
+		validator.RefFileProtectedPath,
+		fmt.Sprintf(
+			"Access to protected path denied (%d path(s)):\n%s",
+			len(violations),
+			strings.Join(lines, "\n"),
+		),
+	)
+
+	if len(suggestions) > 0 {
+		result.FixHint = strings.Join(suggestions, "; ")
+	}
+
+	return result
+}
+
+// pathPolicy matches the paths accessed by one tool call against the path
+// rules.
+type pathPolicy struct {
+	*projectpath.Resolver
+
+	deny  []config.PathRuleConfig
+	allow []config.PathRuleConfig
+}
+
+// newPathPolicy creates the path policy of a hook context.
+func (v *PathsValidator) newPathPolicy(hookCtx *hook.Context) *pathPolicy {
+	return &pathPolicy{
+		Resolver: projectpath.NewResolver(v.gitRunner, hookCtx),
+		deny:     v.config.Deny,
+		allow:    v.config.Allow,
+	}
+}
+
+// check returns the violation of a path access, or nil if it is allowed.
+// Both the path and the path with symlinks resolved are checked, so neither
+// a symlink to a protected path nor a symlink to its directory is allowed.
+func (p *pathPolicy) check(access pathAccess) *pathViolation {
+	abs := p.Absolute(access.Dir, access.Path)
+	resolved := projectpath.ResolveSymlinks(abs)
+
+	forms := []string{abs}
+	if resolved != abs {
+		forms = append(forms, resolved)
+	}
+
+	for _, form := range forms {
+		rule, reason := p.match(p.deny, form, resolved, access.Access)
+		if rule == nil {
+			continue
+		}
+
+		if allowed, _ := p.match(p.allow, form, resolved, access.Access); allowed != nil {
+			continue
+		}
+
+		return &pathViolation{
+			pathAccess: access,
+			Resolved:   resolved,
+			Rule:       rule,
+			Reason:     reason,
+		}
+	}
+
+	return nil
+}
+
+// match returns the first rule matching a path for an access type, and the
+// reason it matches. Paths outside the project are checked with symlinks
+// resolved, as the project root is.
+func (p *pathPolicy) match(
+	rules []config.PathRuleConfig,
+	path, resolved, access string,
+) (*config.PathRuleConfig, string) {
+	for i := range rules {
+		rule := &rules[i]
+
+		if !rule.AppliesTo(access) {
+			continue
+		}
+
+		if rule.OutsideProject && !p.InProject(resolved) {
+			return rule, "outside the project root"
+		}
+
+		for _, pattern := range rule.Paths {
+			if ok, err := doublestar.Match(p.expandPattern(pattern), path); err == nil && ok {
+				return rule, "matches " + pattern
+			}
+		}
+	}
+
+	return nil, ""
+}
+
+// expandPattern returns the absolute glob pattern of a rule path: ~ is the
+// home directory, patterns without a "/" match file names in any directory
+// and other relative patterns are relative to the project root.
+func (p *pathPolicy) expandPattern(pattern string) string {
+	pattern = p.ExpandHome(pattern)
+
+	switch {
+	case filepath.IsAbs(pattern):
+		return pattern
+	case !strings.Contains(pattern, "/"):
+		return "**/" + pattern
+	default:
+		return filepath.Join(p.ProjectRoot(), pattern)
+	}
+}
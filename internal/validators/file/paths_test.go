@@ -0,0 +1,182 @@
+package file_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	internalconfig "github.com/smykla-labs/klaudiush/internal/config"
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/internal/validators/file"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+var _ = Describe("PathsValidator", func() {
+	var (
+		ctx     context.Context
+		runner  *git.FakeRunner
+		cfg     *config.PathsValidatorConfig
+		home    string
+		project string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		tmp, err := filepath.EvalSymlinks(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+
+		home = filepath.Join(tmp, "home")
+		project = filepath.Join(tmp, "project")
+
+		Expect(os.MkdirAll(filepath.Join(home, ".ssh"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(project, "docs"), 0o755)).To(Succeed())
+
+		GinkgoT().Setenv("HOME", home)
+
+		runner = git.NewFakeRunner()
+		runner.RepoRoot = project
+		cfg = internalconfig.DefaultPathsValidatorConfig()
+	})
+
+	validate := func(tool hook.ToolType, input hook.ToolInput) *validator.Result {
+		v := file.NewPathsValidator(logger.NewNoOpLogger(), runner, cfg, nil)
+
+		return v.Validate(ctx, &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  tool,
+			ToolInput: input,
+			Cwd:       project,
+		})
+	}
+
+	DescribeTable("denies protected paths by default",
+		func(tool hook.ToolType, input hook.ToolInput, line string) {
+			result := validate(tool, input)
+
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(result.Reference).To(Equal(validator.RefFileProtectedPath))
+			Expect(result.Message).To(ContainSubstring(line))
+		},
+		Entry("Read of an SSH key", hook.ToolTypeRead,
+			hook.ToolInput{FilePath: "~/.ssh/id_rsa"}, "matches ~/.ssh/**"),
+		Entry("Grep of AWS credentials", hook.ToolTypeGrep,
+			hook.ToolInput{Pattern: "secret", Path: "~/.aws"}, "read ~/.aws"),
+		Entry("Glob in the SSH directory", hook.ToolTypeGlob,
+			hook.ToolInput{Pattern: "~/.ssh/*.pub"}, "read ~/.ssh"),
+		Entry("Read of .env", hook.ToolTypeRead,
+			hook.ToolInput{FilePath: ".env"}, "matches .env"),
+		Entry("Edit of a nested .env file", hook.ToolTypeEdit,
+			hook.ToolInput{FilePath: "services/api/.env.local"}, "matches .env.*"),
+		Entry("Read with ..", hook.ToolTypeRead,
+			hook.ToolInput{FilePath: "docs/../.env"}, "matches .env"),
+		Entry("Write to /etc", hook.ToolTypeWrite,
+			hook.ToolInput{FilePath: "/etc/hosts"}, "write /etc/hosts: matches /etc/**"),
+		Entry("Bash redirection to /etc", hook.ToolTypeBash,
+			hook.ToolInput{Command: "echo '127.0.0.1 app' >> /etc/hosts"}, "write /etc/hosts"),
+		Entry("Bash tee after cd", hook.ToolTypeBash,
+			hook.ToolInput{Command: "cd /etc && echo x | sudo tee hosts"}, "write hosts -> /etc/hosts"),
+		Entry("Bash input redirection", hook.ToolTypeBash,
+			hook.ToolInput{Command: "base64 < ~/.ssh/id_ed25519"}, "read ~/.ssh/id_ed25519"),
+	)
+
+	DescribeTable("allows other paths",
+		func(tool hook.ToolType, input hook.ToolInput) {
+			Expect(validate(tool, input).Passed).To(BeTrue())
+		},
+		Entry("Read of /etc", hook.ToolTypeRead, hook.ToolInput{FilePath: "/etc/hosts"}),
+		Entry("Write of .env.example", hook.ToolTypeWrite, hook.ToolInput{FilePath: ".env.example"}),
+		Entry("Write in the project", hook.ToolTypeWrite, hook.ToolInput{FilePath: "docs/README.md"}),
+		Entry("Write outside the project", hook.ToolTypeWrite, hook.ToolInput{FilePath: "/srv/app/out.txt"}),
+		Entry("Grep without a path", hook.ToolTypeGrep, hook.ToolInput{Pattern: "TODO"}),
+		Entry("Glob with a relative pattern", hook.ToolTypeGlob, hook.ToolInput{Pattern: "**/*.go"}),
+		Entry("Bash redirection in the project", hook.ToolTypeBash,
+			hook.ToolInput{Command: "go test ./... > test.log 2>&1"}),
+		Entry("Bash input redirection of /etc", hook.ToolTypeBash,
+			hook.ToolInput{Command: "wc -l < /etc/hosts"}),
+	)
+
+	Context("with symlinks", func() {
+		It("denies paths through a symlinked directory", func() {
+			Expect(os.Symlink(filepath.Join(home, ".ssh"), filepath.Join(project, "keys"))).To(Succeed())
+
+			result := validate(hook.ToolTypeRead, hook.ToolInput{FilePath: "keys/id_rsa"})
+
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(result.Message).To(ContainSubstring(
+				"read keys/id_rsa -> " + filepath.Join(home, ".ssh", "id_rsa") + ": matches ~/.ssh/**",
+			))
+		})
+
+		It("denies a symlink to a protected file", func() {
+			Expect(os.WriteFile(filepath.Join(project, ".env"), []byte("TOKEN=x\n"), 0o600)).To(Succeed())
+			Expect(os.Symlink(filepath.Join(project, ".env"), filepath.Join(project, "settings"))).
+				To(Succeed())
+
+			Expect(validate(hook.ToolTypeRead, hook.ToolInput{FilePath: "settings"}).ShouldBlock).
+				To(BeTrue())
+		})
+	})
+
+	Context("with configured rules", func() {
+		It("replaces the default deny rules", func() {
+			cfg.Deny = []config.PathRuleConfig{{
+				Paths:      []string{"/tmp/**", "/var/tmp/**"},
+				Access:     []string{config.PathAccessWrite},
+				Suggestion: "Use the project-local tmp/ directory instead",
+			}}
+
+			result := validate(hook.ToolTypeBash, hook.ToolInput{Command: "make > /tmp/build.log"})
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(result.FixHint).To(Equal("Use the project-local tmp/ directory instead"))
+
+			Expect(validate(hook.ToolTypeRead, hook.ToolInput{FilePath: "~/.ssh/id_rsa"}).Passed).
+				To(BeTrue())
+		})
+
+		It("denies paths outside the project root", func() {
+			cfg.Deny = append(cfg.Deny, config.PathRuleConfig{
+				OutsideProject: true,
+				Access:         []string{config.PathAccessWrite},
+			})
+			cfg.Allow = append(cfg.Allow, config.PathRuleConfig{Paths: []string{"~/.cache/**"}})
+
+			result := validate(hook.ToolTypeWrite, hook.ToolInput{FilePath: "../other/main.go"})
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(result.Message).To(ContainSubstring("outside the project root"))
+			Expect(result.FixHint).To(Equal(validator.GetSuggestion(validator.RefFileProtectedPath)))
+
+			Expect(validate(hook.ToolTypeWrite, hook.ToolInput{FilePath: "docs/../main.go"}).Passed).
+				To(BeTrue())
+			Expect(validate(hook.ToolTypeWrite, hook.ToolInput{FilePath: "~/.cache/app/x"}).Passed).
+				To(BeTrue())
+			Expect(validate(hook.ToolTypeRead, hook.ToolInput{FilePath: "../other/main.go"}).Passed).
+				To(BeTrue())
+		})
+
+		It("matches patterns with a slash relative to the project root", func() {
+			cfg.Deny = []config.PathRuleConfig{{Paths: []string{"deploy/secrets/**"}}}
+
+			Expect(validate(hook.ToolTypeRead, hook.ToolInput{FilePath: "deploy/secrets/prod.yaml"}).
+				ShouldBlock).To(BeTrue())
+			Expect(validate(hook.ToolTypeRead, hook.ToolInput{FilePath: "docs/deploy/secrets/x"}).
+				Passed).To(BeTrue())
+		})
+	})
+
+	It("reports all denied paths of a command", func() {
+		result := validate(hook.ToolTypeBash, hook.ToolInput{
+			Command: "cat < .env > /etc/app.env",
+		})
+
+		Expect(result.Message).To(ContainSubstring("2 path(s)"))
+		Expect(result.FixHint).To(ContainSubstring("; "))
+	})
+})
@@ -0,0 +1,46 @@
+package file
+
+import (
+	"os"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+// readWrittenFile reads the whole file written by the tool from disk.
+// In PostToolUse, the file already contains the result of the Write, Edit or
+// MultiEdit operation, so validating it catches problems that fragment
+// validation misses (e.g., a list broken by a sequence of edits).
+func readWrittenFile(ctx *hook.Context, log logger.Logger) (string, error) {
+	filePath := ctx.GetFilePath()
+	if filePath == "" {
+		return "", errNoContent
+	}
+
+	//nolint:gosec // filePath is from Claude Code tool context, not user input
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		log.Debug("failed to read file for post-tool-use validation", "file", filePath, "error", err)
+		return "", errors.Wrap(err, "reading file")
+	}
+
+	return string(content), nil
+}
+
+// asFeedback turns a failing result into non-blocking feedback in PostToolUse.
+// The tool has already run, so findings are reported back to the model instead
+// of blocking the operation.
+func asFeedback(hookCtx *hook.Context, result *validator.Result) *validator.Result {
+	if hookCtx.EventType != hook.EventTypePostToolUse || result.Passed {
+		return result
+	}
+
+	result.ShouldBlock = false
+	result.ShouldAsk = false
+	result.Feedback = true
+
+	return result
+}
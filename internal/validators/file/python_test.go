@@ -205,6 +205,84 @@ if __name__ == "__main__":
 		})
 	})
 
+	Describe("MultiEdit operations with fragments", func() {
+		var tempFile string
+
+		BeforeEach(func() {
+			tmpDir := GinkgoT().TempDir()
+			tempFile = filepath.Join(tmpDir, "test.py")
+
+			content := `def first():
+    return 1
+
+
+def second():
+    return 2
+
+
+def third():
+    return 3
+
+
+def fourth():
+    return 4
+`
+			err := os.WriteFile(tempFile, []byte(content), 0o600)
+			Expect(err).NotTo(HaveOccurred())
+
+			ctx.EventType = hook.EventTypePreToolUse
+			ctx.ToolName = hook.ToolTypeMultiEdit
+			ctx.ToolInput.FilePath = tempFile
+			ctx.ToolInput.Edits = []hook.Edit{
+				{OldString: "    return 1", NewString: "    return 10"},
+				{OldString: "    return 4", NewString: "    return 40"},
+			}
+		})
+
+		It("should validate each edit fragment", func() {
+			var linted []string
+
+			mockChecker.EXPECT().
+				CheckWithOptions(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(_ context.Context, content string, _ *linters.RuffCheckOptions) *linters.LintResult {
+					linted = append(linted, content)
+					return &linters.LintResult{Success: true}
+				}).
+				Times(2)
+
+			result := v.Validate(context.Background(), ctx)
+			Expect(result.Passed).To(BeTrue())
+			Expect(linted).To(HaveLen(2))
+			Expect(linted[0]).To(ContainSubstring("return 10"))
+			Expect(linted[0]).NotTo(ContainSubstring("return 40"))
+			Expect(linted[1]).To(ContainSubstring("return 40"))
+		})
+
+		It("should fail when any edit fragment fails", func() {
+			gomock.InOrder(
+				mockChecker.EXPECT().
+					CheckWithOptions(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&linters.LintResult{Success: true}),
+				mockChecker.EXPECT().
+					CheckWithOptions(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(&linters.LintResult{Success: false, RawOut: "E999 error"}),
+			)
+
+			result := v.Validate(context.Background(), ctx)
+			Expect(result.Passed).To(BeFalse())
+		})
+
+		It("should pass when an edit cannot be applied", func() {
+			ctx.ToolInput.Edits = append(ctx.ToolInput.Edits, hook.Edit{
+				OldString: "missing",
+				NewString: "value",
+			})
+
+			result := v.Validate(context.Background(), ctx)
+			Expect(result.Passed).To(BeTrue())
+		})
+	})
+
 	Describe("no file path", func() {
 		It("should pass when no file path is provided", func() {
 			ctx.ToolInput.FilePath = ""
@@ -81,7 +81,7 @@ func (v *RustValidator) Validate(
 	}
 
 	// Get content based on operation type
-	rustc, err := v.getContent(hookCtx, filePath)
+	contents, err := v.getContent(hookCtx, filePath)
 	if err != nil {
 		log.Debug("failed to get content", "error", err)
 		return validator.Pass()
@@ -93,16 +93,25 @@ func (v *RustValidator) Validate(
 
 	// Build options with edition detection
 	opts := v.buildRustfmtOptions(filePath)
-	result := v.checker.CheckWithOptions(lintCtx, rustc.content, opts)
 
-	if result.Success {
-		log.Debug("rustfmt passed")
-		return validator.Pass()
+	for _, rustc := range contents {
+		result := v.checker.CheckWithOptions(lintCtx, rustc.content, opts)
+
+		if result.Success {
+			continue
+		}
+
+		log.Debug("rustfmt failed", "output", result.RawOut)
+
+		return asFeedback(
+			hookCtx,
+			validator.FailWithRef(validator.RefRustfmtCheck, v.formatRustfmtOutput(result)),
+		)
 	}
 
-	log.Debug("rustfmt failed", "output", result.RawOut)
+	log.Debug("rustfmt passed")
 
-	return validator.FailWithRef(validator.RefRustfmtCheck, v.formatRustfmtOutput(result))
+	return validator.Pass()
 }
 
 // rustContent holds Rust code content and metadata for validation
@@ -117,23 +126,28 @@ type rustContent struct {
 func (v *RustValidator) getContent(
 	ctx *hook.Context,
 	filePath string,
-) (*rustContent, error) {
+) ([]*rustContent, error) {
 	log := v.Logger()
 
-	// For Edit operations, validate only the changed fragment with context
-	if ctx.EventType == hook.EventTypePreToolUse && ctx.ToolName == hook.ToolTypeEdit {
-		content, err := v.getEditContent(ctx, filePath)
+	// For Edit and MultiEdit operations, validate only the changed fragments with context
+	if ctx.EventType == hook.EventTypePreToolUse && ctx.IsEditTool() {
+		fragments, err := v.getEditContent(ctx, filePath)
 		if err != nil {
 			return nil, err
 		}
 
-		return &rustContent{content: content, isFragment: true}, nil
+		contents := make([]*rustContent, 0, len(fragments))
+		for _, fragment := range fragments {
+			contents = append(contents, &rustContent{content: fragment, isFragment: true})
+		}
+
+		return contents, nil
 	}
 
 	// Get content from context or read from file (Write operation)
 	content := ctx.ToolInput.Content
 	if content != "" {
-		return &rustContent{content: content, isFragment: false}, nil
+		return []*rustContent{{content: content, isFragment: false}}, nil
 	}
 
 	// Check if file exists
@@ -149,53 +163,62 @@ func (v *RustValidator) getContent(
 		return nil, err
 	}
 
-	return &rustContent{content: string(data), isFragment: false}, nil
+	return []*rustContent{{content: string(data), isFragment: false}}, nil
 }
 
-// getEditContent extracts content for Edit operations with context
+// getEditContent extracts fragments for Edit and MultiEdit operations with context,
+// one per changed region of the file
 func (v *RustValidator) getEditContent(
 	ctx *hook.Context,
 	filePath string,
-) (string, error) {
+) ([]string, error) {
 	log := v.Logger()
 
-	oldStr := ctx.ToolInput.OldString
-	newStr := ctx.ToolInput.NewString
-
-	if oldStr == "" || newStr == "" {
-		log.Debug("missing old_string or new_string in edit operation")
-		return "", os.ErrNotExist
-	}
-
-	// Read original file to extract context around the edit
-	//nolint:gosec // filePath is from Claude Code tool context, not user input
-	originalContent, err := os.ReadFile(filePath)
+	originalStr, replacements, err := readEditReplacements(
+		ctx,
+		filePath,
+		v.getContextLines(),
+		log,
+	)
 	if err != nil {
-		log.Debug("failed to read file for edit validation", "file", filePath, "error", err)
-		return "", err
+		return nil, err
 	}
 
-	originalStr := string(originalContent)
+	fragments := make([]string, 0, len(replacements))
 
-	// Extract fragment with context lines around the edit
-	fragment := ExtractEditFragment(
-		originalStr,
-		oldStr,
-		newStr,
-		v.getContextLines(),
-		log,
-	)
-	if fragment == "" {
-		log.Debug("could not extract edit fragment, skipping validation")
-		return "", os.ErrNotExist
+	for _, r := range replacements {
+		if r.OldString == "" || r.NewString == "" {
+			log.Debug("missing old_string or new_string in edit operation")
+			continue
+		}
+
+		// Extract fragment with context lines around the edit
+		fragment := ExtractEditFragment(
+			originalStr,
+			r.OldString,
+			r.NewString,
+			v.getContextLines(),
+			log,
+		)
+		if fragment == "" {
+			log.Debug("could not extract edit fragment, skipping it")
+			continue
+		}
+
+		fragmentLineCount := len(strings.Split(fragment, "\n"))
+		log.Debug("validating edit fragment with context",
+			"fragment_lines", fragmentLineCount,
+		)
+
+		fragments = append(fragments, fragment)
 	}
 
-	fragmentLineCount := len(strings.Split(fragment, "\n"))
-	log.Debug("validating edit fragment with context",
-		"fragment_lines", fragmentLineCount,
-	)
+	if len(fragments) == 0 {
+		log.Debug("no edit fragments to validate, skipping validation")
+		return nil, os.ErrNotExist
+	}
 
-	return fragment, nil
+	return fragments, nil
 }
 
 // buildRustfmtOptions creates RustfmtOptions with edition detection
@@ -79,34 +79,42 @@ func (v *ShellScriptValidator) Validate(
 	}
 
 	// Get content based on operation type
-	sc, err := v.getContent(hookCtx, filePath)
+	contents, err := v.getContent(hookCtx, filePath)
 	if err != nil {
 		log.Debug("failed to get content", "error", err)
 		return validator.Pass()
 	}
 
-	// Skip Fish scripts
-	if v.isFishScript(filePath, sc.content) {
-		log.Debug("skipping Fish script", "file", filePath)
-		return validator.Pass()
-	}
-
 	// Run shellcheck using the linter
 	lintCtx, cancel := context.WithTimeout(ctx, v.getTimeout())
 	defer cancel()
 
-	// Build exclude codes from config and fragment-specific excludes
-	opts := v.buildShellCheckOptions(sc.isFragment)
-	result := v.checker.CheckWithOptions(lintCtx, sc.content, opts)
+	for _, sc := range contents {
+		// Skip Fish scripts
+		if v.isFishScript(filePath, sc.content) {
+			log.Debug("skipping Fish script", "file", filePath)
+			return validator.Pass()
+		}
 
-	if result.Success {
-		log.Debug("shellcheck passed")
-		return validator.Pass()
+		// Build exclude codes from config and fragment-specific excludes
+		opts := v.buildShellCheckOptions(sc.isFragment)
+		result := v.checker.CheckWithOptions(lintCtx, sc.content, opts)
+
+		if result.Success {
+			continue
+		}
+
+		log.Debug("shellcheck failed", "output", result.RawOut)
+
+		return asFeedback(
+			hookCtx,
+			validator.FailWithRef(validator.RefShellcheck, v.formatShellCheckOutput(result.RawOut)),
+		)
 	}
 
-	log.Debug("shellcheck failed", "output", result.RawOut)
+	log.Debug("shellcheck passed")
 
-	return validator.FailWithRef(validator.RefShellcheck, v.formatShellCheckOutput(result.RawOut))
+	return validator.Pass()
 }
 
 // shellContent holds shell script content and metadata for validation
@@ -121,23 +129,28 @@ type shellContent struct {
 func (v *ShellScriptValidator) getContent(
 	ctx *hook.Context,
 	filePath string,
-) (*shellContent, error) {
+) ([]*shellContent, error) {
 	log := v.Logger()
 
-	// For Edit operations, validate only the changed fragment with context
-	if ctx.EventType == hook.EventTypePreToolUse && ctx.ToolName == hook.ToolTypeEdit {
-		content, err := v.getEditContent(ctx, filePath)
+	// For Edit and MultiEdit operations, validate only the changed fragments with context
+	if ctx.EventType == hook.EventTypePreToolUse && ctx.IsEditTool() {
+		fragments, err := v.getEditContent(ctx, filePath)
 		if err != nil {
 			return nil, err
 		}
 
-		return &shellContent{content: content, isFragment: true}, nil
+		contents := make([]*shellContent, 0, len(fragments))
+		for _, fragment := range fragments {
+			contents = append(contents, &shellContent{content: fragment, isFragment: true})
+		}
+
+		return contents, nil
 	}
 
 	// Get content from context or read from file (Write operation)
 	content := ctx.ToolInput.Content
 	if content != "" {
-		return &shellContent{content: content, isFragment: false}, nil
+		return []*shellContent{{content: content, isFragment: false}}, nil
 	}
 
 	// Check if file exists
@@ -153,63 +166,72 @@ func (v *ShellScriptValidator) getContent(
 		return nil, err
 	}
 
-	return &shellContent{content: string(data), isFragment: false}, nil
+	return []*shellContent{{content: string(data), isFragment: false}}, nil
 }
 
-// getEditContent extracts content for Edit operations with context
+// getEditContent extracts fragments for Edit and MultiEdit operations with context,
+// one per changed region of the file
 func (v *ShellScriptValidator) getEditContent(
 	ctx *hook.Context,
 	filePath string,
-) (string, error) {
+) ([]string, error) {
 	log := v.Logger()
 
-	oldStr := ctx.ToolInput.OldString
-	newStr := ctx.ToolInput.NewString
-
-	if oldStr == "" || newStr == "" {
-		log.Debug("missing old_string or new_string in edit operation")
-		return "", os.ErrNotExist
-	}
-
-	// Read original file to extract context around the edit
-	//nolint:gosec // filePath is from Claude Code tool context, not user input
-	originalContent, err := os.ReadFile(filePath)
-	if err != nil {
-		log.Debug("failed to read file for edit validation", "file", filePath, "error", err)
-		return "", err
-	}
-
-	originalStr := string(originalContent)
-
-	// Extract fragment with context lines around the edit
-	fragment := ExtractEditFragment(
-		originalStr,
-		oldStr,
-		newStr,
+	originalStr, replacements, err := readEditReplacements(
+		ctx,
+		filePath,
 		v.getContextLines(),
 		log,
 	)
-	if fragment == "" {
-		log.Debug("could not extract edit fragment, skipping validation")
-		return "", os.ErrNotExist
+	if err != nil {
+		return nil, err
 	}
 
-	// Calculate fragment start line to determine if shebang needs to be prepended
-	fragmentStartLine := getFragmentStartLine(originalStr, oldStr, v.getContextLines())
+	fragments := make([]string, 0, len(replacements))
+
+	for _, r := range replacements {
+		if r.OldString == "" || r.NewString == "" {
+			log.Debug("missing old_string or new_string in edit operation")
+			continue
+		}
+
+		// Extract fragment with context lines around the edit
+		fragment := ExtractEditFragment(
+			originalStr,
+			r.OldString,
+			r.NewString,
+			v.getContextLines(),
+			log,
+		)
+		if fragment == "" {
+			log.Debug("could not extract edit fragment, skipping it")
+			continue
+		}
+
+		// Calculate fragment start line to determine if shebang needs to be prepended
+		fragmentStartLine := getFragmentStartLine(originalStr, r.OldString, v.getContextLines())
+
+		// If fragment doesn't start at line 0, prepend shebang or shell directive
+		// to avoid SC2148 (unknown shell) errors
+		if fragmentStartLine > 0 {
+			fragment = v.prependShellDirective(originalStr, fragment, log)
+		}
+
+		fragmentLineCount := len(strings.Split(fragment, "\n"))
+		log.Debug("validating edit fragment with context",
+			"fragment_lines", fragmentLineCount,
+			"fragment_start_line", fragmentStartLine,
+		)
 
-	// If fragment doesn't start at line 0, prepend shebang or shell directive
-	// to avoid SC2148 (unknown shell) errors
-	if fragmentStartLine > 0 {
-		fragment = v.prependShellDirective(originalStr, fragment, log)
+		fragments = append(fragments, fragment)
 	}
 
-	fragmentLineCount := len(strings.Split(fragment, "\n"))
-	log.Debug("validating edit fragment with context",
-		"fragment_lines", fragmentLineCount,
-		"fragment_start_line", fragmentStartLine,
-	)
+	if len(fragments) == 0 {
+		log.Debug("no edit fragments to validate, skipping validation")
+		return nil, os.ErrNotExist
+	}
 
-	return fragment, nil
+	return fragments, nil
 }
 
 // prependShellDirective prepends a shellcheck shell directive to the fragment
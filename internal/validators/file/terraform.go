@@ -3,7 +3,6 @@ package file
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
 	"time"
 
@@ -66,25 +65,51 @@ func (v *TerraformValidator) Validate(
 		}
 	}
 
-	content, err := v.getContent(hookCtx)
+	contents, err := v.getContent(hookCtx)
 	if err != nil {
 		log.Debug("skipping terraform validation", "error", err)
 		return validator.Pass()
 	}
 
-	if content == "" {
-		return validator.Pass()
-	}
-
 	// Detect which tool to use
 	tool := v.formatter.DetectTool()
 	log.Debug("detected terraform tool", "tool", tool)
 
+	var warnings []string
+
+	for _, content := range contents {
+		warnings = append(warnings, v.validateContent(ctx, content, tool)...)
+	}
+
+	if len(warnings) > 0 {
+		message := "Terraform validation warnings"
+		details := map[string]string{
+			"warnings": strings.Join(warnings, "\n"),
+		}
+
+		return asFeedback(hookCtx, validator.WarnWithDetails(message, details))
+	}
+
+	return validator.Pass()
+}
+
+// validateContent runs the format check and tflint on content and returns warnings
+func (v *TerraformValidator) validateContent(
+	ctx context.Context,
+	content string,
+	tool string,
+) []string {
+	log := v.Logger()
+
+	if content == "" {
+		return nil
+	}
+
 	// Create temp file for tflint
 	tmpFile, cleanup, err := v.tempManager.Create("terraform-*.tf", content)
 	if err != nil {
 		log.Debug("failed to create temp file", "error", err)
-		return validator.Pass()
+		return nil
 	}
 	defer cleanup()
 
@@ -104,79 +129,82 @@ func (v *TerraformValidator) Validate(
 		}
 	}
 
-	if len(warnings) > 0 {
-		message := "Terraform validation warnings"
-		details := map[string]string{
-			"warnings": strings.Join(warnings, "\n"),
-		}
-
-		return validator.WarnWithDetails(message, details)
-	}
-
-	return validator.Pass()
+	return warnings
 }
 
 // getContent extracts terraform content from context
-func (v *TerraformValidator) getContent(ctx *hook.Context) (string, error) {
+func (v *TerraformValidator) getContent(ctx *hook.Context) ([]string, error) {
 	log := v.Logger()
 
+	// In PostToolUse, validate the whole file as written to disk
+	if ctx.EventType == hook.EventTypePostToolUse {
+		content, err := readWrittenFile(ctx, log)
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{content}, nil
+	}
+
 	// Try to get content from tool input (Write operation)
 	if ctx.ToolInput.Content != "" {
-		return ctx.ToolInput.Content, nil
+		return []string{ctx.ToolInput.Content}, nil
 	}
 
-	// For Edit operations in PreToolUse, validate only the changed fragment with context
-	// to avoid forcing users to fix all existing linting issues
-	if ctx.EventType == hook.EventTypePreToolUse && ctx.ToolName == hook.ToolTypeEdit {
+	// For Edit and MultiEdit operations in PreToolUse, validate only the changed
+	// fragments with context to avoid forcing users to fix all existing linting issues
+	if ctx.EventType == hook.EventTypePreToolUse && ctx.IsEditTool() {
 		filePath := ctx.GetFilePath()
 		if filePath == "" {
-			return "", errNoContent
+			return nil, errNoContent
 		}
 
-		oldStr := ctx.ToolInput.OldString
-		newStr := ctx.ToolInput.NewString
-
-		if oldStr == "" || newStr == "" {
-			log.Debug("missing old_string or new_string in edit operation")
-			return "", errNoContent
-		}
-
-		// Read original file to extract context around the edit
-		//nolint:gosec // filePath is from Claude Code tool context, not user input
-		originalContent, err := os.ReadFile(filePath)
-		if err != nil {
-			log.Debug("failed to read file for edit validation", "file", filePath, "error", err)
-			return "", err
-		}
-
-		// Extract fragment with context lines around the edit
-		fragment := ExtractEditFragment(
-			string(originalContent),
-			oldStr,
-			newStr,
+		// Read original file to extract context around the edits
+		originalContent, replacements, err := readEditReplacements(
+			ctx,
+			filePath,
 			v.getContextLines(),
 			log,
 		)
-		if fragment == "" {
-			log.Debug("could not extract edit fragment, skipping validation")
-			return "", errNoContent
+		if err != nil {
+			return nil, err
 		}
 
-		fragmentLineCount := len(strings.Split(fragment, "\n"))
-		log.Debug("validating edit fragment with context", "fragment_lines", fragmentLineCount)
+		fragments := make([]string, 0, len(replacements))
+
+		for _, r := range replacements {
+			if r.OldString == "" || r.NewString == "" {
+				log.Debug("missing old_string or new_string in edit operation")
+				continue
+			}
+
+			// Extract fragment with context lines around the edit
+			fragment := ExtractEditFragment(
+				originalContent,
+				r.OldString,
+				r.NewString,
+				v.getContextLines(),
+				log,
+			)
+			if fragment == "" {
+				log.Debug("could not extract edit fragment, skipping it")
+				continue
+			}
+
+			fragmentLineCount := len(strings.Split(fragment, "\n"))
+			log.Debug("validating edit fragment with context", "fragment_lines", fragmentLineCount)
+
+			fragments = append(fragments, fragment)
+		}
 
-		return fragment, nil
-	}
+		if len(fragments) == 0 {
+			return nil, errNoContent
+		}
 
-	// Try to get from file path (Edit or PostToolUse)
-	filePath := ctx.GetFilePath()
-	if filePath != "" {
-		// In PostToolUse, we could read the file, but for now skip
-		// as the Bash version doesn't handle this case well either
-		return "", errFileValidationNotImpl
+		return fragments, nil
 	}
 
-	return "", errNoContent
+	return nil, errNoContent
 }
 
 // checkFormat runs terraform/tofu fmt -check using TerraformFormatter
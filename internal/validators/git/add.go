@@ -77,7 +77,7 @@ func (v *AddValidator) Validate(ctx context.Context, hookCtx *hook.Context) *val
 	log.Debug("Git root found", "path", gitRoot)
 
 	// Parse the command
-	bashParser := parser.NewBashParser()
+	bashParser := v.NewBashParser()
 
 	result, err := bashParser.Parse(hookCtx.GetCommand())
 	if err != nil {
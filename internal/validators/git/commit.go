@@ -71,7 +71,7 @@ func (v *CommitValidator) Validate(ctx context.Context, hookCtx *hook.Context) *
 	}
 
 	// Parse the command
-	bashParser := parser.NewBashParser()
+	bashParser := v.NewBashParser()
 
 	result, err := bashParser.Parse(hookCtx.GetCommand())
 	if err != nil {
@@ -2,10 +2,13 @@ package git
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/cockroachdb/errors"
+
 	"github.com/smykla-labs/klaudiush/internal/exec"
 	gitpkg "github.com/smykla-labs/klaudiush/internal/git"
 )
@@ -179,6 +182,34 @@ func (r *CLIGitRunnerWithPath) GetRemotes() (map[string]string, error) {
 	return remotes, nil
 }
 
+// GetAheadBehind returns how many commits the branch is ahead of and behind
+// its upstream branch
+func (r *CLIGitRunnerWithPath) GetAheadBehind(branch string) (int, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	result := r.runner.Run(ctx, "git", "-C", r.path, "rev-list", "--left-right", "--count",
+		branch+"..."+branch+"@{upstream}")
+	if result.Err != nil {
+		return 0, 0, result.Err
+	}
+
+	return parseAheadBehind(result.Stdout)
+}
+
+// GetLastCommitAuthor returns the author of the HEAD commit as "Name <email>"
+func (r *CLIGitRunnerWithPath) GetLastCommitAuthor() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	result := r.runner.Run(ctx, "git", "-C", r.path, "log", "-1", "--format=%an <%ae>")
+	if result.Err != nil {
+		return "", result.Err
+	}
+
+	return strings.TrimSpace(result.Stdout), nil
+}
+
 // NewGitRunner creates a GitRunner instance based on environment configuration
 // By default, uses SDK-based implementation for better performance
 // Set KLAUDIUSH_USE_SDK_GIT to "false" or "0" to use CLI-based implementation
@@ -369,6 +400,46 @@ func (r *CLIGitRunner) GetRemotes() (map[string]string, error) {
 	return remotes, nil
 }
 
+// GetAheadBehind returns how many commits the branch is ahead of and behind
+// its upstream branch
+func (r *CLIGitRunner) GetAheadBehind(branch string) (int, int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	result := r.runner.Run(ctx, "git", "rev-list", "--left-right", "--count",
+		branch+"..."+branch+"@{upstream}")
+	if result.Err != nil {
+		return 0, 0, result.Err
+	}
+
+	return parseAheadBehind(result.Stdout)
+}
+
+// GetLastCommitAuthor returns the author of the HEAD commit as "Name <email>"
+func (r *CLIGitRunner) GetLastCommitAuthor() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.timeout)
+	defer cancel()
+
+	result := r.runner.Run(ctx, "git", "log", "-1", "--format=%an <%ae>")
+	if result.Err != nil {
+		return "", result.Err
+	}
+
+	return strings.TrimSpace(result.Stdout), nil
+}
+
+// parseAheadBehind parses the "<ahead>\t<behind>" output of
+// git rev-list --left-right --count
+func parseAheadBehind(output string) (int, int, error) {
+	var ahead, behind int
+
+	if _, err := fmt.Sscanf(strings.TrimSpace(output), "%d\t%d", &ahead, &behind); err != nil {
+		return 0, 0, errors.Wrapf(err, "unexpected rev-list output %q", output)
+	}
+
+	return ahead, behind, nil
+}
+
 // parseLines splits output by newlines and filters empty lines
 func parseLines(output string) []string {
 	output = strings.TrimSpace(output)
@@ -44,7 +44,7 @@ func (v *NoVerifyValidator) Validate(ctx context.Context, hookCtx *hook.Context)
 		}
 	}
 
-	bashParser := parser.NewBashParser()
+	bashParser := v.NewBashParser()
 
 	result, err := bashParser.Parse(hookCtx.GetCommand())
 	if err != nil {
@@ -57,6 +57,7 @@ func (v *PushValidator) Validate(ctx context.Context, hookCtx *hook.Context) *va
 		hookCtx,
 		v.ruleAdapter,
 		v.Logger(),
+		v.NewBashParser(),
 		"push",
 		v.validatePushCommand,
 	)
@@ -82,12 +82,14 @@ func FormatRemoteNotFoundError(remote string, remotes map[string]string) string
 }
 
 // ValidateGitSubcommand provides common validation loop for git subcommand validators.
-// It handles rule checking, command parsing, and subcommand filtering.
+// It handles rule checking, command parsing with the given parser, and
+// subcommand filtering.
 func ValidateGitSubcommand(
 	ctx context.Context,
 	hookCtx *hook.Context,
 	ruleAdapter *rules.RuleValidatorAdapter,
 	log logger.Logger,
+	bashParser *parser.BashParser,
 	subcommand string,
 	validateCmd GitCommandValidatorFunc,
 ) *validator.Result {
@@ -103,8 +105,6 @@ func ValidateGitSubcommand(
 		return validator.Pass()
 	}
 
-	bashParser := parser.NewBashParser()
-
 	parseResult, err := bashParser.Parse(command)
 	if err != nil {
 		log.Debug("failed to parse command", "error", err)
@@ -0,0 +1,79 @@
+package git
+
+import (
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+// NewRuleGitContextProvider returns a provider that builds the git context
+// used for rule matching from the runner and the hook command. The remote and
+// branch come from the first git command when it names them (e.g.
+// "git push upstream feat"), otherwise from the current branch and its
+// tracking remote. The runner also provides the working-tree state, which is
+// queried lazily by the rules that need it. The command is parsed with the
+// given parser options. Returns nil when not in a git repository.
+func NewRuleGitContextProvider(
+	runner GitRunner,
+	opts ...parser.BashParserOption,
+) func(hookCtx *hook.Context) *rules.GitContext {
+	return func(hookCtx *hook.Context) *rules.GitContext {
+		if runner == nil || !runner.IsInRepo() {
+			return nil
+		}
+
+		gitCtx := &rules.GitContext{IsInRepo: true, State: runner}
+
+		if root, err := runner.GetRepoRoot(); err == nil {
+			gitCtx.RepoRoot = root
+		}
+
+		if branch, err := runner.GetCurrentBranch(); err == nil {
+			gitCtx.Branch = branch
+		}
+
+		if gitCmd := firstGitCommand(hookCtx, opts); gitCmd != nil {
+			gitCtx.Remote = gitCmd.ExtractRemote()
+
+			if branch := gitCmd.ExtractBranchName(); branch != "" {
+				gitCtx.Branch = branch
+			}
+		}
+
+		if gitCtx.Remote == "" && gitCtx.Branch != "" {
+			if remote, err := runner.GetBranchRemote(gitCtx.Branch); err == nil {
+				gitCtx.Remote = remote
+			}
+		}
+
+		return gitCtx
+	}
+}
+
+// firstGitCommand returns the first git command in the hook's Bash command,
+// or nil if there is none.
+func firstGitCommand(hookCtx *hook.Context, opts []parser.BashParserOption) *parser.GitCommand {
+	if hookCtx == nil || hookCtx.GetCommand() == "" {
+		return nil
+	}
+
+	parseResult, err := parser.NewBashParser(opts...).Parse(hookCtx.GetCommand())
+	if err != nil {
+		return nil
+	}
+
+	for _, cmd := range parseResult.Commands {
+		if cmd.Name != gitCmdName || len(cmd.Args) == 0 {
+			continue
+		}
+
+		gitCmd, err := parser.ParseGitCommand(cmd)
+		if err != nil {
+			continue
+		}
+
+		return gitCmd
+	}
+
+	return nil
+}
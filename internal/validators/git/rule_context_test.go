@@ -0,0 +1,55 @@
+package git_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	gitpkg "github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/validators/git"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+)
+
+var _ = Describe("NewRuleGitContextProvider", func() {
+	var fakeGit *gitpkg.FakeRunner
+
+	BeforeEach(func() {
+		fakeGit = gitpkg.NewFakeRunner()
+		fakeGit.RepoRoot = "/src/myorg/app"
+		fakeGit.CurrentBranch = "feat"
+		fakeGit.BranchRemotes = map[string]string{"feat": "upstream"}
+	})
+
+	bash := func(command string) *hook.Context {
+		return &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			ToolInput: hook.ToolInput{Command: command},
+		}
+	}
+
+	It("should take remote and branch from the command", func() {
+		gitCtx := git.NewRuleGitContextProvider(fakeGit)(bash("git push origin main"))
+
+		Expect(gitCtx).To(Equal(&rules.GitContext{
+			RepoRoot: "/src/myorg/app",
+			Remote:   "origin",
+			Branch:   "main",
+			IsInRepo: true,
+			State:    fakeGit,
+		}))
+	})
+
+	It("should fall back to the current branch and its tracking remote", func() {
+		gitCtx := git.NewRuleGitContextProvider(fakeGit)(bash("git push"))
+
+		Expect(gitCtx.Branch).To(Equal("feat"))
+		Expect(gitCtx.Remote).To(Equal("upstream"))
+	})
+
+	It("should return nil outside of a repository", func() {
+		fakeGit.InRepo = false
+
+		Expect(git.NewRuleGitContextProvider(fakeGit)(bash("git push"))).To(BeNil())
+	})
+})
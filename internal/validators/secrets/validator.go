@@ -128,9 +128,9 @@ func (*SecretsValidator) getContent(hookCtx *hook.Context) string {
 		return hookCtx.GetContent()
 	}
 
-	// For Edit operations, validate the new content being written
-	if hookCtx.ToolName == hook.ToolTypeEdit {
-		return hookCtx.ToolInput.NewString
+	// For Edit and MultiEdit operations, validate the new content being written
+	if hookCtx.IsEditTool() {
+		return hookCtx.GetNewContent()
 	}
 
 	return ""
@@ -0,0 +1,367 @@
+package shell
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/projectpath"
+	"github.com/smykla-labs/klaudiush/internal/rules"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+// DestructiveFinding is a command matched by a destructive pattern.
+type DestructiveFinding struct {
+	// Pattern is the pattern that matched.
+	Pattern *DestructivePattern
+
+	// Severity is the severity of the pattern, after configuration overrides.
+	Severity config.Severity
+
+	// Command is the matched command.
+	Command string
+
+	// Reason explains what the command destroys.
+	Reason string
+}
+
+// DestructiveValidator validates Bash commands against a catalogue of
+// destructive commands, like rm -rf outside the project root, git reset
+// --hard on uncommitted changes or mkfs.
+type DestructiveValidator struct {
+	validator.BaseValidator
+	gitRunner    git.Runner
+	config       *config.DestructiveValidatorConfig
+	patterns     []DestructivePattern
+	severities   map[string]config.Severity
+	allowedPaths []string
+	ruleAdapter  *rules.RuleValidatorAdapter
+}
+
+// NewDestructiveValidator creates a new DestructiveValidator. The git runner
+// is used to find the project root and uncommitted changes; without one, the
+// working directory is the project root and changes are assumed to exist.
+func NewDestructiveValidator(
+	log logger.Logger,
+	gitRunner git.Runner,
+	cfg *config.DestructiveValidatorConfig,
+	ruleAdapter *rules.RuleValidatorAdapter,
+) *DestructiveValidator {
+	v := &DestructiveValidator{
+		BaseValidator: *validator.NewBaseValidator("validate-destructive", log),
+		gitRunner:     gitRunner,
+		config:        cfg,
+		severities:    make(map[string]config.Severity),
+		ruleAdapter:   ruleAdapter,
+	}
+
+	disabled := make(map[string]bool)
+
+	if cfg != nil {
+		for _, name := range cfg.DisabledPatterns {
+			disabled[name] = true
+		}
+
+		for name, severity := range cfg.Severities {
+			if severity != config.SeverityUnknown && severity.IsASeverity() {
+				v.severities[name] = severity
+			}
+		}
+
+		v.allowedPaths = cfg.AllowedPaths
+	}
+
+	for _, pattern := range DestructivePatterns() {
+		if !disabled[pattern.Name] {
+			v.patterns = append(v.patterns, pattern)
+		}
+	}
+
+	return v
+}
+
+// Validate checks a Bash command for destructive commands.
+func (v *DestructiveValidator) Validate(ctx context.Context, hookCtx *hook.Context) *validator.Result {
+	log := v.Logger()
+	log.Debug("Running destructive command validation")
+
+	// Check rules first if rule adapter is configured
+	if v.ruleAdapter != nil {
+		if result := v.ruleAdapter.CheckRules(ctx, hookCtx); result != nil {
+			return result
+		}
+	}
+
+	command := hookCtx.GetCommand()
+	if command == "" {
+		log.Debug("Empty command, skipping validation")
+		return validator.Pass()
+	}
+
+	c := v.newCheckContext(hookCtx)
+
+	result, err := v.NewBashParser(parser.WithEnvironment(c.environment())).Parse(command)
+	if err != nil {
+		log.Debug("Failed to parse command", "error", err)
+		return validator.Pass()
+	}
+
+	findings := v.findDestructive(result, c)
+	if len(findings) == 0 {
+		log.Debug("No destructive commands found")
+		return validator.Pass()
+	}
+
+	return v.createResult(findings)
+}
+
+// findDestructive matches the commands and file writes of a parse result
+// against the enabled patterns.
+func (v *DestructiveValidator) findDestructive(
+	result *parser.ParseResult,
+	c *checkContext,
+) []DestructiveFinding {
+	var findings []DestructiveFinding
+
+	commands := withFindPaths(result.Commands)
+
+	for i := range v.patterns {
+		pattern := &v.patterns[i]
+
+		for _, cmd := range commands {
+			cmd.Name = commandName(cmd.Name)
+
+			if pattern.Check == nil || !pattern.matchesCommand(cmd.Name) {
+				continue
+			}
+
+			if reason := pattern.Check(c, cmd); reason != "" {
+				findings = append(findings, v.newFinding(pattern, cmd.String(), reason))
+			}
+		}
+
+		if pattern.CheckWrite == nil {
+			continue
+		}
+
+		for _, fw := range result.FileWrites {
+			if reason := pattern.CheckWrite(c, fw); reason != "" {
+				findings = append(findings, v.newFinding(pattern, fw.String(), reason))
+			}
+		}
+	}
+
+	return findings
+}
+
+// newFinding creates a finding of a pattern, with its configured severity.
+func (v *DestructiveValidator) newFinding(
+	pattern *DestructivePattern,
+	command, reason string,
+) DestructiveFinding {
+	severity, ok := v.severities[pattern.Name]
+	if !ok {
+		severity = pattern.Severity
+	}
+
+	return DestructiveFinding{
+		Pattern:  pattern,
+		Severity: severity,
+		Command:  command,
+		Reason:   reason,
+	}
+}
+
+// createResult creates a validation result from findings. The result blocks
+// when any finding has error severity, and uses the reference of the first
+// such finding.
+func (*DestructiveValidator) createResult(findings []DestructiveFinding) *validator.Result {
+	messages := make([]string, 0, len(findings))
+
+	var blocking *DestructiveFinding
+
+	for i, finding := range findings {
+		messages = append(messages, fmt.Sprintf(
+			"%s: %s (%s)",
+			finding.Command,
+			finding.Reason,
+			finding.Pattern.Name,
+		))
+
+		if blocking == nil && finding.Severity.ShouldBlock() {
+			blocking = &findings[i]
+		}
+	}
+
+	message := fmt.Sprintf(
+		"Destructive command detected (%d finding(s)):\n%s",
+		len(findings),
+		strings.Join(messages, "\n"),
+	)
+
+	if blocking != nil {
+		return validator.FailWithRef(blocking.Pattern.Reference, message)
+	}
+
+	return validator.WarnWithRef(findings[0].Pattern.Reference, message)
+}
+
+// Category returns the validator category for parallel execution.
+// DestructiveValidator uses CategoryGit as it may query the repository state.
+func (*DestructiveValidator) Category() validator.ValidatorCategory {
+	return validator.CategoryGit
+}
+
+// commandName returns the name of a command without its directory.
+func commandName(name string) string {
+	return filepath.Base(name)
+}
+
+// checkContext resolves paths and repository state for the patterns
+// checking one command line. The project root and repository state are
+// looked up on first use, as most commands do not need them.
+type checkContext struct {
+	*projectpath.Resolver
+
+	gitRunner    git.Runner
+	allowedPaths []string
+}
+
+// newCheckContext creates the check context of a hook context.
+func (v *DestructiveValidator) newCheckContext(hookCtx *hook.Context) *checkContext {
+	return &checkContext{
+		Resolver:     projectpath.NewResolver(v.gitRunner, hookCtx),
+		gitRunner:    v.gitRunner,
+		allowedPaths: v.allowedPaths,
+	}
+}
+
+// environment returns the well-known variables the command runs with, HOME
+// and PWD, so that paths built from them are checked like literal ones.
+func (c *checkContext) environment() map[string]string {
+	env := map[string]string{}
+
+	if home := c.Home(); home != "" {
+		env["HOME"] = home
+	}
+
+	if cwd := c.Cwd(); cwd != "" {
+		env["PWD"] = cwd
+	}
+
+	return env
+}
+
+// resolve returns the absolute path of a path used by a command running in
+// dir. Glob patterns resolve to the directory containing the matched paths.
+func (c *checkContext) resolve(dir, path string) string {
+	return c.Absolute(dir, globBase(path))
+}
+
+// isAllowed reports whether an absolute path matches the allowed paths.
+func (c *checkContext) isAllowed(path string) bool {
+	for _, pattern := range c.allowedPaths {
+		if ok, err := doublestar.Match(c.ExpandHome(pattern), path); err == nil && ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// outsideProject describes a path used by a command running in dir when it
+// is outside the project root, or is the root itself unless includeRoot is
+// false. It returns an empty string for other paths and allowed paths.
+func (c *checkContext) outsideProject(dir, path string, includeRoot bool) string {
+	abs := c.resolve(dir, path)
+	if c.isAllowed(abs) {
+		return ""
+	}
+
+	switch {
+	case abs == "/":
+		return "the filesystem root"
+	case c.Home() != "" && abs == c.Home():
+		return "the home directory"
+	case abs == c.ProjectRoot():
+		if includeRoot {
+			return "the project root"
+		}
+
+		return ""
+	case !c.InProject(abs):
+		return abs + " outside the project root"
+	default:
+		return ""
+	}
+}
+
+// hasUncommittedChanges reports whether the repository of a command running
+// in dir (an absolute path) has staged or modified files.
+func (c *checkContext) hasUncommittedChanges(dir string) bool {
+	if c.gitRunner == nil {
+		return true
+	}
+
+	return c.hasFiles(dir, c.gitRunner.GetStagedFiles, c.gitRunner.GetModifiedFiles)
+}
+
+// hasUntrackedFiles reports whether the repository of a command running in
+// dir (an absolute path) has untracked files.
+func (c *checkContext) hasUntrackedFiles(dir string) bool {
+	if c.gitRunner == nil {
+		return true
+	}
+
+	return c.hasFiles(dir, c.gitRunner.GetUntrackedFiles)
+}
+
+// hasFiles reports whether any of the file lists of the repository is not
+// empty. Files are assumed to exist when the repository of dir is not the
+// one of the project, or when listing fails.
+func (c *checkContext) hasFiles(dir string, lists ...func() ([]string, error)) bool {
+	if !c.InProject(dir) {
+		return true
+	}
+
+	if !c.gitRunner.IsInRepo() {
+		return false
+	}
+
+	for _, list := range lists {
+		files, err := list()
+		if err != nil || len(files) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// globBase returns the part of a path before its first glob pattern, as the
+// directory containing the matched paths ("build/*.o" -> "build", "*" -> ".").
+func globBase(path string) string {
+	idx := strings.IndexAny(path, "*?[")
+	if idx == -1 {
+		return path
+	}
+
+	prefix := path[:idx]
+	if prefix == "" {
+		return "."
+	}
+
+	if strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+
+	return filepath.Dir(prefix)
+}
@@ -0,0 +1,722 @@
+package shell
+
+import (
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+// DestructivePattern is an entry of the destructive command catalogue.
+type DestructivePattern struct {
+	// Name is a unique identifier for the pattern, used in configuration.
+	Name string
+
+	// Description explains what type of command this pattern detects.
+	Description string
+
+	// Severity is the default severity of the pattern.
+	Severity config.Severity
+
+	// Reference is the URL that uniquely identifies this error type.
+	Reference validator.Reference
+
+	// Commands are the names of the commands checked by Check. A name ending
+	// with "*" matches names with its prefix ("mkfs*" matches "mkfs.ext4").
+	Commands []string
+
+	// Check returns what a command destroys, or an empty string when the
+	// command is not destructive.
+	Check func(c *checkContext, cmd parser.Command) string
+
+	// CheckWrite returns what a file write destroys, or an empty string when
+	// the write is not destructive.
+	CheckWrite func(c *checkContext, fw parser.FileWrite) string
+}
+
+// matchesCommand reports whether Check applies to commands named name.
+func (p *DestructivePattern) matchesCommand(name string) bool {
+	for _, command := range p.Commands {
+		if prefix, ok := strings.CutSuffix(command, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if command == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// databaseClients are the commands running SQL or database scripts given as
+// arguments.
+var databaseClients = []string{
+	"psql", "mysql", "mariadb", "sqlite3", "duckdb", "sqlcmd",
+	"clickhouse", "clickhouse-client", "cockroach", "cqlsh", "mongosh", "mongo",
+}
+
+var (
+	// diskDevicePattern matches the paths of disk and memory devices.
+	diskDevicePattern = regexp.MustCompile(
+		`^/dev/((s|h|v|xv)d[a-z]|nvme\d|mmcblk\d|r?disk\d|disk/|md\d|dm-\d|mapper/|loop\d|k?mem$|port$)`,
+	)
+
+	// sqlDropPattern matches statements dropping databases, schemas and tables.
+	sqlDropPattern = regexp.MustCompile(
+		`(?i)\bDROP\s+(DATABASE|SCHEMA|TABLE|KEYSPACE)\b|\.dropDatabase\(\s*\)`,
+	)
+
+	// sqlEmptyPattern matches statements deleting all rows of a table.
+	sqlEmptyPattern = regexp.MustCompile(
+		"(?i)\\bTRUNCATE\\b|\\bDELETE\\s+FROM\\s+[\\w.\"`\\[\\]]+\\s*(;|$)|\\.deleteMany\\(\\s*\\{\\s*\\}\\s*\\)",
+	)
+
+	// octalModePattern matches octal chmod modes.
+	octalModePattern = regexp.MustCompile(`^[0-7]{1,4}$`)
+)
+
+// DestructivePatterns returns the built-in destructive command catalogue.
+func DestructivePatterns() []DestructivePattern {
+	return []DestructivePattern{
+		{
+			Name:        "rm-recursive",
+			Description: "Recursive delete of the project root or a path outside it",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellRecursiveDelete,
+			Commands:    []string{"rm", "find"},
+			Check:       checkRecursiveDelete,
+		},
+		{
+			Name:        "rm-recursive-dynamic",
+			Description: "Recursive delete of paths only known at run time",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellRecursiveDelete,
+			Commands:    []string{"rm"},
+			Check:       checkDynamicRecursiveDelete,
+		},
+		{
+			Name:        "rm-git-dir",
+			Description: "Recursive delete of the .git directory",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellRecursiveDelete,
+			Commands:    []string{"rm"},
+			Check:       checkGitDirDelete,
+		},
+		{
+			Name:        "git-reset-hard",
+			Description: "git reset --hard with uncommitted changes",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellDiscardChanges,
+			Commands:    []string{"git"},
+			Check:       checkGitResetHard,
+		},
+		{
+			Name:        "git-discard-changes",
+			Description: "git checkout, switch or restore overwriting uncommitted changes",
+			Severity:    config.SeverityWarning,
+			Reference:   validator.RefShellDiscardChanges,
+			Commands:    []string{"git"},
+			Check:       checkGitDiscardChanges,
+		},
+		{
+			Name:        "git-clean",
+			Description: "git clean deleting untracked or ignored files",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellDiscardChanges,
+			Commands:    []string{"git"},
+			Check:       checkGitClean,
+		},
+		{
+			Name:        "chmod-world-writable",
+			Description: "World-writable permissions set recursively or outside the project",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellPermissions,
+			Commands:    []string{"chmod"},
+			Check:       checkWorldWritable,
+		},
+		{
+			Name:        "permissions-recursive",
+			Description: "Recursive permission or ownership change of the project root or a path outside it",
+			Severity:    config.SeverityWarning,
+			Reference:   validator.RefShellPermissions,
+			Commands:    []string{"chmod", "chown", "chgrp"},
+			Check:       checkRecursivePermissions,
+		},
+		{
+			Name:        "disk-write",
+			Description: "Write to a disk device",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellDiskWrite,
+			Commands:    []string{"shred"},
+			Check:       checkDiskShred,
+			CheckWrite:  checkDiskWrite,
+		},
+		{
+			Name:        "format-filesystem",
+			Description: "Filesystem creation or disk erasure",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellDiskWrite,
+			Commands:    []string{"mkfs*", "mke2fs", "mkswap", "mkntfs", "wipefs", "diskutil"},
+			Check:       checkFormatFilesystem,
+		},
+		{
+			Name:        "kill-all",
+			Description: "Signal sent to all processes",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellKillAll,
+			Commands:    []string{"kill", "killall5"},
+			Check:       checkKillAll,
+		},
+		{
+			Name:        "shutdown",
+			Description: "System shutdown or reboot",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellKillAll,
+			Commands:    []string{"shutdown", "reboot", "halt", "poweroff", "systemctl", "init", "telinit"},
+			Check:       checkShutdown,
+		},
+		{
+			Name:        "database-drop",
+			Description: "Database, schema or table dropped",
+			Severity:    config.SeverityError,
+			Reference:   validator.RefShellDatabaseDrop,
+			Commands:    append([]string{"dropdb", "redis-cli"}, databaseClients...),
+			Check:       checkDatabaseDrop,
+		},
+		{
+			Name:        "database-truncate",
+			Description: "All rows of a table deleted",
+			Severity:    config.SeverityWarning,
+			Reference:   validator.RefShellDatabaseDrop,
+			Commands:    databaseClients,
+			Check:       checkDatabaseTruncate,
+		},
+	}
+}
+
+// commandArgs are the arguments of a command split into options and operands.
+type commandArgs struct {
+	short    string   // letters of the short options ("-rf" -> "rf")
+	long     []string // long options, without values ("--force")
+	operands []string
+}
+
+// splitArgs splits arguments into options and operands. Arguments after
+// "--" are operands. Option values are not recognized, so only commands with
+// options without values should be split.
+func splitArgs(args []string) commandArgs {
+	var split commandArgs
+
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			split.operands = append(split.operands, args[i+1:]...)
+
+			return split
+		case strings.HasPrefix(arg, "--"):
+			name, _, _ := strings.Cut(arg, "=")
+			split.long = append(split.long, name)
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			split.short += arg[1:]
+		default:
+			split.operands = append(split.operands, arg)
+		}
+	}
+
+	return split
+}
+
+// has reports whether any of the short option letters or long options is set.
+func (a commandArgs) has(letters string, long ...string) bool {
+	if letters != "" && strings.ContainsAny(a.short, letters) {
+		return true
+	}
+
+	for _, name := range long {
+		if slices.Contains(a.long, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// describeOutside describes the paths used by a command that are outside the
+// project root, or the root itself when includeRoot is set. Paths of commands
+// with unresolved expansions are not known, so they are not described.
+func describeOutside(c *checkContext, cmd parser.Command, paths []string, includeRoot bool) string {
+	if cmd.Unresolved {
+		return ""
+	}
+
+	var outside []string
+
+	for _, path := range paths {
+		if desc := c.outsideProject(cmd.WorkingDirectory, path, includeRoot); desc != "" {
+			outside = append(outside, desc)
+		}
+	}
+
+	return strings.Join(outside, ", ")
+}
+
+// findStartPaths returns the starting points of a find command.
+func findStartPaths(args []string) []string {
+	var paths []string
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "-H" || arg == "-L" || arg == "-P" || strings.HasPrefix(arg, "-O"):
+		case arg == "-D":
+			i++
+		case strings.HasPrefix(arg, "-") || arg == "(" || arg == "!":
+			i = len(args)
+		default:
+			paths = append(paths, arg)
+		}
+	}
+
+	if len(paths) == 0 {
+		return []string{"."}
+	}
+
+	return paths
+}
+
+// isRecursiveDelete reports whether a command is rm with -r.
+func isRecursiveDelete(cmd parser.Command) bool {
+	return cmd.Name == "rm" && splitArgs(cmd.Args).has("rR", "--recursive")
+}
+
+// findExecPlaceholder is replaced by find with the paths it found in the
+// commands of its -exec actions.
+const findExecPlaceholder = "{}"
+
+// runByFind reports whether a command is run by an -exec action of find.
+func runByFind(cmd parser.Command) bool {
+	n := len(cmd.Wrappers)
+
+	return n > 0 && strings.HasPrefix(cmd.Wrappers[n-1], "find -")
+}
+
+// runByXargs reports whether a command is run by xargs, which appends the
+// arguments it reads from standard input.
+func runByXargs(cmd parser.Command) bool {
+	return slices.Contains(cmd.Wrappers, "xargs")
+}
+
+// withFindPaths returns the commands with the placeholders of the commands
+// run by find -exec replaced with the starting points of the find command,
+// which contain the paths it finds.
+func withFindPaths(commands []parser.Command) []parser.Command {
+	result := slices.Clone(commands)
+
+	for i, cmd := range commands {
+		if !runByFind(cmd) {
+			continue
+		}
+
+		outer := findOuterFind(commands[:i], cmd.Wrappers[:len(cmd.Wrappers)-1])
+		if outer == nil {
+			continue
+		}
+
+		starts := findStartPaths(outer.Args)
+		args := make([]string, 0, len(cmd.Args))
+
+		for _, arg := range cmd.Args {
+			if !strings.Contains(arg, findExecPlaceholder) {
+				args = append(args, arg)
+
+				continue
+			}
+
+			for _, start := range starts {
+				args = append(args, strings.ReplaceAll(arg, findExecPlaceholder, start))
+			}
+		}
+
+		result[i].Args = args
+	}
+
+	return result
+}
+
+// findOuterFind returns the find command running the command following the
+// preceding commands, which is recorded before it with the given wrappers.
+func findOuterFind(preceding []parser.Command, wrappers []string) *parser.Command {
+	for i := len(preceding) - 1; i >= 0; i-- {
+		cmd := &preceding[i]
+		if commandName(cmd.Name) == "find" && slices.Equal(cmd.Wrappers, wrappers) {
+			return cmd
+		}
+	}
+
+	return nil
+}
+
+func checkRecursiveDelete(c *checkContext, cmd parser.Command) string {
+	var outside string
+
+	switch {
+	case isRecursiveDelete(cmd):
+		// Like find -delete, find -exec rm filters the paths it deletes
+		outside = describeOutside(c, cmd, splitArgs(cmd.Args).operands, !runByFind(cmd))
+	case cmd.Name == "find" && slices.Contains(cmd.Args, "-delete"):
+		// find filters the files it deletes, so deleting in the root is fine
+		outside = describeOutside(c, cmd, findStartPaths(cmd.Args), false)
+	}
+
+	if outside == "" {
+		return ""
+	}
+
+	return "deletes " + outside
+}
+
+func checkDynamicRecursiveDelete(_ *checkContext, cmd parser.Command) string {
+	switch {
+	case !isRecursiveDelete(cmd):
+		return ""
+	case runByXargs(cmd):
+		return "deletes paths read from standard input by xargs, which are not shown"
+	case cmd.Unresolved:
+		return "deletes paths with expansions not known until the command runs, which are not shown"
+	default:
+		return ""
+	}
+}
+
+func checkGitDirDelete(c *checkContext, cmd parser.Command) string {
+	if !isRecursiveDelete(cmd) {
+		return ""
+	}
+
+	for _, operand := range splitArgs(cmd.Args).operands {
+		path := c.resolve(cmd.WorkingDirectory, operand)
+		if filepath.Base(path) == ".git" && c.InProject(path) {
+			return "deletes the repository history in " + path
+		}
+	}
+
+	return ""
+}
+
+// parseGit parses a git command and returns it with the absolute path of
+// the directory it runs in.
+func parseGit(c *checkContext, cmd parser.Command) (*parser.GitCommand, string) {
+	gitCmd, err := parser.ParseGitCommand(cmd)
+	if err != nil {
+		return nil, ""
+	}
+
+	return gitCmd, c.resolve(cmd.WorkingDirectory, gitCmd.GlobalOptions["-C"])
+}
+
+func checkGitResetHard(c *checkContext, cmd parser.Command) string {
+	gitCmd, dir := parseGit(c, cmd)
+	if gitCmd == nil || gitCmd.Subcommand != "reset" || !gitCmd.HasFlag("--hard") {
+		return ""
+	}
+
+	if !c.hasUncommittedChanges(dir) {
+		return ""
+	}
+
+	return "discards uncommitted changes"
+}
+
+func checkGitDiscardChanges(c *checkContext, cmd parser.Command) string {
+	gitCmd, dir := parseGit(c, cmd)
+	if gitCmd == nil {
+		return ""
+	}
+
+	var discards bool
+
+	switch gitCmd.Subcommand {
+	case "checkout":
+		discards = gitCmd.HasFlag("-f") || gitCmd.HasFlag("--force") ||
+			gitCmd.HasFlag("--") || slices.Contains(gitCmd.Args, ".")
+	case "switch":
+		discards = gitCmd.HasFlag("-f") || gitCmd.HasFlag("--force") ||
+			gitCmd.HasFlag("--discard-changes")
+	case "restore":
+		// restore --staged without --worktree only unstages changes
+		staged := gitCmd.HasFlag("-S") || gitCmd.HasFlag("--staged")
+		discards = !staged || gitCmd.HasFlag("-W") || gitCmd.HasFlag("--worktree")
+	}
+
+	if !discards || !c.hasUncommittedChanges(dir) {
+		return ""
+	}
+
+	return "overwrites uncommitted changes"
+}
+
+func checkGitClean(c *checkContext, cmd parser.Command) string {
+	gitCmd, dir := parseGit(c, cmd)
+	if gitCmd == nil || gitCmd.Subcommand != "clean" {
+		return ""
+	}
+
+	force := gitCmd.HasFlag("-f") || gitCmd.HasFlag("--force")
+	dryRun := gitCmd.HasFlag("-n") || gitCmd.HasFlag("--dry-run") ||
+		gitCmd.HasFlag("-i") || gitCmd.HasFlag("--interactive")
+
+	switch {
+	case !force || dryRun:
+		return ""
+	case gitCmd.HasFlag("-x") || gitCmd.HasFlag("-X"):
+		return "deletes ignored files, like local configuration and build caches"
+	case c.hasUntrackedFiles(dir):
+		return "deletes untracked files"
+	default:
+		return ""
+	}
+}
+
+// chmodOperands returns the mode and the paths of a chmod, chown or chgrp
+// command. The mode is the owner for chown and the group for chgrp, and is
+// empty with --reference.
+func chmodOperands(args commandArgs) (mode string, paths []string) {
+	if args.has("", "--reference") || len(args.operands) == 0 {
+		return "", args.operands
+	}
+
+	return args.operands[0], args.operands[1:]
+}
+
+// isWorldWritable reports whether a chmod mode gives others write permission.
+func isWorldWritable(mode string) bool {
+	if octalModePattern.MatchString(mode) {
+		return (mode[len(mode)-1]-'0')&2 != 0
+	}
+
+	for clause := range strings.SplitSeq(mode, ",") {
+		actions := strings.TrimLeft(clause, "ugoa")
+		if !strings.ContainsAny(clause[:len(clause)-len(actions)], "oa") {
+			continue
+		}
+
+		// actions are operators followed by permissions ("+rw-x")
+		for len(actions) > 0 {
+			end := strings.IndexAny(actions[1:], "+-=") + 1
+			if end == 0 {
+				end = len(actions)
+			}
+
+			if actions[0] != '-' && strings.Contains(actions[1:end], "w") {
+				return true
+			}
+
+			actions = actions[end:]
+		}
+	}
+
+	return false
+}
+
+func checkWorldWritable(c *checkContext, cmd parser.Command) string {
+	args := splitArgs(cmd.Args)
+
+	mode, paths := chmodOperands(args)
+	if !isWorldWritable(mode) {
+		return ""
+	}
+
+	if args.has("R", "--recursive") {
+		return "makes " + strings.Join(paths, ", ") + " and everything in them writable by everyone"
+	}
+
+	if outside := describeOutside(c, cmd, paths, false); outside != "" {
+		return "makes " + outside + " writable by everyone"
+	}
+
+	return ""
+}
+
+func checkRecursivePermissions(c *checkContext, cmd parser.Command) string {
+	args := splitArgs(cmd.Args)
+	if !args.has("R", "--recursive") {
+		return ""
+	}
+
+	mode, paths := chmodOperands(args)
+	if cmd.Name == "chmod" && isWorldWritable(mode) {
+		// reported by chmod-world-writable
+		return ""
+	}
+
+	if outside := describeOutside(c, cmd, paths, true); outside != "" {
+		return "changes permissions or ownership of everything in " + outside
+	}
+
+	return ""
+}
+
+func checkDiskShred(_ *checkContext, cmd parser.Command) string {
+	for _, operand := range splitArgs(cmd.Args).operands {
+		if diskDevicePattern.MatchString(operand) {
+			return "overwrites the disk device " + operand
+		}
+	}
+
+	return ""
+}
+
+func checkDiskWrite(_ *checkContext, fw parser.FileWrite) string {
+	if !diskDevicePattern.MatchString(fw.Path) {
+		return ""
+	}
+
+	return "overwrites the disk device " + fw.Path
+}
+
+// diskutilEraseVerbs are the diskutil verbs erasing disks and volumes.
+var diskutilEraseVerbs = []string{
+	"erasedisk", "erasevolume", "reformat", "partitiondisk",
+	"zerodisk", "randomdisk", "secureerase",
+}
+
+func checkFormatFilesystem(_ *checkContext, cmd parser.Command) string {
+	args := splitArgs(cmd.Args)
+
+	switch cmd.Name {
+	case "wipefs":
+		// without --all or --offset, wipefs only lists signatures
+		if !args.has("ao", "--all", "--offset") || args.has("n", "--no-act") {
+			return ""
+		}
+
+		return "erases filesystem signatures"
+	case "diskutil":
+		if len(args.operands) == 0 || !slices.Contains(diskutilEraseVerbs, strings.ToLower(args.operands[0])) {
+			return ""
+		}
+
+		return "erases a disk or volume"
+	default:
+		if args.has("", "--help", "--version") || len(args.operands) == 0 {
+			return ""
+		}
+
+		return "creates a filesystem, destroying the data on " + args.operands[len(args.operands)-1]
+	}
+}
+
+// killTargets returns the process IDs of a kill command, skipping the
+// signal option.
+func killTargets(args []string) []string {
+	if len(args) == 0 {
+		return nil
+	}
+
+	switch {
+	case args[0] == "-l" || args[0] == "-L":
+		return nil
+	case args[0] == "-s" || args[0] == "-n":
+		args = args[min(2, len(args)):]
+	case strings.HasPrefix(args[0], "-") && args[0] != "--":
+		// -9, -KILL, -SIGKILL
+		args = args[1:]
+	}
+
+	if len(args) > 0 && args[0] == "--" {
+		args = args[1:]
+	}
+
+	return args
+}
+
+func checkKillAll(_ *checkContext, cmd parser.Command) string {
+	if cmd.Name == "killall5" || slices.Contains(killTargets(cmd.Args), "-1") {
+		return "signals all processes"
+	}
+
+	return ""
+}
+
+// systemctlShutdownVerbs are the systemctl verbs shutting down or rebooting
+// the system.
+var systemctlShutdownVerbs = []string{"poweroff", "reboot", "halt", "kexec", "soft-reboot"}
+
+func checkShutdown(_ *checkContext, cmd parser.Command) string {
+	args := splitArgs(cmd.Args)
+
+	var shutsDown bool
+
+	switch cmd.Name {
+	case "systemctl":
+		shutsDown = len(args.operands) > 0 && slices.Contains(systemctlShutdownVerbs, args.operands[0])
+	case "init", "telinit":
+		shutsDown = len(args.operands) > 0 && (args.operands[0] == "0" || args.operands[0] == "6")
+	case "shutdown":
+		// -c cancels a pending shutdown and -k only warns users
+		shutsDown = !args.has("ck", "--help")
+	default:
+		shutsDown = !args.has("", "--help")
+	}
+
+	if !shutsDown {
+		return ""
+	}
+
+	return "shuts down or reboots the system"
+}
+
+// findStatement returns the first match of a statement pattern in the
+// arguments of a command, with whitespace collapsed.
+func findStatement(pattern *regexp.Regexp, args []string) string {
+	for _, arg := range args {
+		if match := pattern.FindString(arg); match != "" {
+			return strings.Join(strings.Fields(match), " ")
+		}
+	}
+
+	return ""
+}
+
+func checkDatabaseDrop(_ *checkContext, cmd parser.Command) string {
+	switch cmd.Name {
+	case "dropdb":
+		if splitArgs(cmd.Args).has("", "--help", "--version") {
+			return ""
+		}
+
+		return "drops a database"
+	case "redis-cli":
+		for _, arg := range cmd.Args {
+			if strings.EqualFold(arg, "FLUSHALL") || strings.EqualFold(arg, "FLUSHDB") {
+				return "deletes all keys"
+			}
+		}
+
+		return ""
+	}
+
+	if statement := findStatement(sqlDropPattern, cmd.Args); statement != "" {
+		return "runs " + statement
+	}
+
+	return ""
+}
+
+func checkDatabaseTruncate(_ *checkContext, cmd parser.Command) string {
+	if statement := findStatement(sqlEmptyPattern, cmd.Args); statement != "" {
+		return "runs " + statement
+	}
+
+	return ""
+}
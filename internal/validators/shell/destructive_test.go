@@ -0,0 +1,252 @@
+package shell_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/internal/git"
+	"github.com/smykla-labs/klaudiush/internal/validator"
+	"github.com/smykla-labs/klaudiush/internal/validators/shell"
+	"github.com/smykla-labs/klaudiush/pkg/config"
+	"github.com/smykla-labs/klaudiush/pkg/hook"
+	"github.com/smykla-labs/klaudiush/pkg/logger"
+)
+
+var _ = Describe("DestructiveValidator", func() {
+	var (
+		ctx    context.Context
+		runner *git.FakeRunner
+		cfg    *config.DestructiveValidatorConfig
+		cwd    string
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		runner = git.NewFakeRunner()
+		runner.RepoRoot = "/repo"
+		cfg = &config.DestructiveValidatorConfig{}
+		cwd = "/repo"
+
+		GinkgoT().Setenv("HOME", "/home/dev")
+	})
+
+	validate := func(command string) *validator.Result {
+		v := shell.NewDestructiveValidator(logger.NewNoOpLogger(), runner, cfg, nil)
+
+		return v.Validate(ctx, &hook.Context{
+			EventType: hook.EventTypePreToolUse,
+			ToolName:  hook.ToolTypeBash,
+			ToolInput: hook.ToolInput{Command: command},
+			Cwd:       cwd,
+		})
+	}
+
+	DescribeTable("blocks destructive commands",
+		func(command, pattern string, ref validator.Reference) {
+			runner.ModifiedFiles = []string{"main.go"}
+			runner.UntrackedFiles = []string{"notes.txt"}
+
+			result := validate(command)
+
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.ShouldBlock).To(BeTrue())
+			Expect(result.Message).To(ContainSubstring("(" + pattern + ")"))
+			Expect(result.Reference).To(Equal(ref))
+			Expect(result.FixHint).NotTo(BeEmpty())
+		},
+		Entry("rm -rf ~", "rm -rf ~", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm -rf /", "rm -rf /", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm -rf $HOME", `rm -rf "$HOME"`, "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm -rf $HOME/", "rm -rf $HOME/", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm -rf $PWD/..", `rm -rf "$PWD/.."`, "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm -rf of a variable", `rm -rf "$BUILD_DIR"/`, "rm-recursive-dynamic",
+			validator.RefShellRecursiveDelete),
+		Entry("find -exec rm -rf", "find / -exec rm -rf {} +", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("find -execdir rm -r outside", `find ~ -name x -execdir rm -r {} \;`, "rm-recursive",
+			validator.RefShellRecursiveDelete),
+		Entry("xargs rm -rf", "ls / | xargs rm -rf", "rm-recursive-dynamic", validator.RefShellRecursiveDelete),
+		Entry("rm -rf of $PWD after cd", `cd /srv && rm -rf "$PWD"/cache`, "rm-recursive-dynamic",
+			validator.RefShellRecursiveDelete),
+		Entry("rm outside the project", "rm -r -f /var/lib/data", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm of the project root", "rm -rf .", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm of everything in the root", "rm -rf *", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm after cd", "cd /etc && rm -R nginx", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm by path with sudo", "sudo /bin/rm -rf ~/projects", "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("rm in a nested shell", `bash -c "rm -rf ../other"`, "rm-recursive", validator.RefShellRecursiveDelete),
+		Entry("find -delete outside the project", "find / -name '*.log' -delete", "rm-recursive",
+			validator.RefShellRecursiveDelete),
+		Entry("rm of .git", "rm -rf .git", "rm-git-dir", validator.RefShellRecursiveDelete),
+		Entry("git reset --hard", "git reset --hard HEAD~1", "git-reset-hard", validator.RefShellDiscardChanges),
+		Entry("git clean -fdx", "git clean -fdx", "git-clean", validator.RefShellDiscardChanges),
+		Entry("git clean -fd", "git clean -f -d", "git-clean", validator.RefShellDiscardChanges),
+		Entry("chmod -R 777", "chmod -R 777 .", "chmod-world-writable", validator.RefShellPermissions),
+		Entry("chmod a+rwx outside", "chmod a+rwx /etc/hosts", "chmod-world-writable", validator.RefShellPermissions),
+		Entry("dd to a device", "dd if=/dev/zero of=/dev/sda bs=1M", "disk-write", validator.RefShellDiskWrite),
+		Entry("redirect to a device", "cat image.iso > /dev/disk2", "disk-write", validator.RefShellDiskWrite),
+		Entry("shred a device", "shred -n 1 /dev/nvme0n1", "disk-write", validator.RefShellDiskWrite),
+		Entry("mkfs", "sudo mkfs.ext4 /dev/sdb1", "format-filesystem", validator.RefShellDiskWrite),
+		Entry("wipefs -a", "wipefs -a /dev/sdb", "format-filesystem", validator.RefShellDiskWrite),
+		Entry("kill -9 -1", "kill -9 -1", "kill-all", validator.RefShellKillAll),
+		Entry("kill -- -1", "kill -s KILL -- -1", "kill-all", validator.RefShellKillAll),
+		Entry("shutdown", "shutdown -h now", "shutdown", validator.RefShellKillAll),
+		Entry("systemctl reboot", "systemctl reboot", "shutdown", validator.RefShellKillAll),
+		Entry("psql DROP DATABASE", `psql -c "DROP DATABASE prod"`, "database-drop", validator.RefShellDatabaseDrop),
+		Entry("mysql drop table", `mysql -e "drop  table users" app`, "database-drop", validator.RefShellDatabaseDrop),
+		Entry("dropdb", "dropdb prod", "database-drop", validator.RefShellDatabaseDrop),
+		Entry("redis FLUSHALL", "bash -c 'redis-cli FLUSHALL'", "database-drop", validator.RefShellDatabaseDrop),
+	)
+
+	DescribeTable("allows commands that are not destructive",
+		func(command string) {
+			runner.ModifiedFiles = []string{"main.go"}
+
+			Expect(validate(command).Passed).To(BeTrue())
+		},
+		Entry("rm -rf inside the project", "rm -rf build node_modules/.cache"),
+		Entry("rm -rf in a subdirectory", "cd web && rm -rf dist/*"),
+		Entry("rm -rf in the working directory", `rm -rf "$PWD/build"`),
+		Entry("find -exec rm -rf in the project", "find . -name node_modules -exec rm -rf {} +"),
+		Entry("find -exec rm -r of a subdirectory", "find build -type d -exec rm -r {} +"),
+		Entry("xargs rm without -r", "ls *.log | xargs rm -f"),
+		Entry("rm without -r", "rm -f ~/.cache/x /tmp/y"),
+		Entry("find -delete in the project", "find . -name '*.tmp' -delete"),
+		Entry("git reset without --hard", "git reset HEAD~1"),
+		Entry("git restore --staged", "git restore --staged main.go"),
+		Entry("git checkout of a branch", "git checkout -b feat/x"),
+		Entry("git clean dry run", "git clean -ndx"),
+		Entry("chmod +x", "chmod +x scripts/build.sh"),
+		Entry("chmod -R 755", "chmod -R 755 scripts"),
+		Entry("chmod 777 in the project", "chmod 777 tmp"),
+		Entry("dd between files", "dd if=in.img of=out.img"),
+		Entry("redirect to /dev/null", "make build > /dev/null 2>&1"),
+		Entry("wipefs listing", "wipefs /dev/sdb"),
+		Entry("kill -1 of a process", "kill -1 1234"),
+		Entry("kill -l", "kill -l"),
+		Entry("shutdown -c", "shutdown -c"),
+		Entry("systemctl status", "systemctl status nginx"),
+		Entry("psql query", `psql -c "SELECT * FROM users"`),
+		Entry("DELETE with WHERE", `mysql -e "DELETE FROM users WHERE id = 1"`),
+		Entry("echo of SQL", `echo "DROP TABLE users"`),
+	)
+
+	DescribeTable("warns about risky commands",
+		func(command, pattern string) {
+			runner.ModifiedFiles = []string{"main.go"}
+
+			result := validate(command)
+
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.ShouldBlock).To(BeFalse())
+			Expect(result.Message).To(ContainSubstring("(" + pattern + ")"))
+		},
+		Entry("git checkout -- .", "git checkout -- .", "git-discard-changes"),
+		Entry("git restore", "git restore main.go", "git-discard-changes"),
+		Entry("git switch --discard-changes", "git switch --discard-changes main", "git-discard-changes"),
+		Entry("chown -R outside", "chown -R dev:dev /opt/app", "permissions-recursive"),
+		Entry("TRUNCATE", `psql -c "TRUNCATE users"`, "database-truncate"),
+		Entry("DELETE without WHERE", `sqlite3 app.db "DELETE FROM sessions;"`, "database-truncate"),
+	)
+
+	Context("with a clean working tree", func() {
+		It("allows git reset --hard", func() {
+			Expect(validate("git reset --hard origin/main").Passed).To(BeTrue())
+		})
+
+		It("allows git checkout -- .", func() {
+			Expect(validate("git checkout -- .").Passed).To(BeTrue())
+		})
+
+		It("allows git clean without untracked files", func() {
+			Expect(validate("git clean -fd").Passed).To(BeTrue())
+		})
+
+		It("blocks git clean -x, as ignored files are not listed", func() {
+			Expect(validate("git clean -fdX").ShouldBlock).To(BeTrue())
+		})
+
+		It("blocks git reset --hard in another repository", func() {
+			Expect(validate("git -C /elsewhere reset --hard").ShouldBlock).To(BeTrue())
+		})
+	})
+
+	Context("without a git runner", func() {
+		It("assumes uncommitted changes", func() {
+			v := shell.NewDestructiveValidator(logger.NewNoOpLogger(), nil, cfg, nil)
+			result := v.Validate(ctx, &hook.Context{
+				EventType: hook.EventTypePreToolUse,
+				ToolName:  hook.ToolTypeBash,
+				ToolInput: hook.ToolInput{Command: "git reset --hard"},
+				Cwd:       cwd,
+			})
+
+			Expect(result.ShouldBlock).To(BeTrue())
+		})
+	})
+
+	Context("project root", func() {
+		It("uses the repository root from a subdirectory", func() {
+			cwd = "/repo/cmd/app"
+
+			Expect(validate("rm -rf ../../internal/old").Passed).To(BeTrue())
+			Expect(validate("rm -rf ../../../other").ShouldBlock).To(BeTrue())
+		})
+
+		It("uses the working directory outside repositories", func() {
+			runner.InRepo = false
+			cwd = "/work/project"
+
+			Expect(validate("rm -rf build").Passed).To(BeTrue())
+			Expect(validate("rm -rf /repo/build").ShouldBlock).To(BeTrue())
+		})
+	})
+
+	Context("configuration", func() {
+		It("skips disabled patterns", func() {
+			cfg.DisabledPatterns = []string{"kill-all"}
+
+			Expect(validate("kill -9 -1").Passed).To(BeTrue())
+		})
+
+		It("overrides pattern severities", func() {
+			cfg.Severities = map[string]config.Severity{
+				"rm-recursive":      config.SeverityWarning,
+				"database-truncate": config.SeverityError,
+			}
+
+			result := validate("rm -rf ~")
+			Expect(result.Passed).To(BeFalse())
+			Expect(result.ShouldBlock).To(BeFalse())
+
+			Expect(validate(`psql -c "TRUNCATE users"`).ShouldBlock).To(BeTrue())
+		})
+
+		It("allows recursive deletes of allowed paths", func() {
+			cfg.AllowedPaths = []string{"/tmp/**", "~/.cache/**"}
+
+			Expect(validate("rm -rf /tmp/build-123 ~/.cache/go-build").Passed).To(BeTrue())
+			Expect(validate("rm -rf ~/.config").ShouldBlock).To(BeTrue())
+		})
+	})
+
+	It("reports all findings with the reference of the first blocking one", func() {
+		result := validate(`psql -c "TRUNCATE logs" && rm -rf ~ && kill -9 -1`)
+
+		Expect(result.ShouldBlock).To(BeTrue())
+		Expect(result.Reference).To(Equal(validator.RefShellRecursiveDelete))
+		Expect(result.Message).To(ContainSubstring("3 finding(s)"))
+		Expect(result.Message).To(ContainSubstring("rm -rf ~: deletes the home directory (rm-recursive)"))
+		Expect(result.Message).To(ContainSubstring("(database-truncate)"))
+		Expect(result.Message).To(ContainSubstring("(kill-all)"))
+	})
+
+	It("has unique pattern names", func() {
+		names := make(map[string]bool)
+
+		for _, pattern := range shell.DestructivePatterns() {
+			Expect(names).NotTo(HaveKey(pattern.Name))
+			names[pattern.Name] = true
+		}
+	})
+})
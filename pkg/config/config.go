@@ -1,6 +1,22 @@
 // Package config provides configuration schema types for klaudiush validators.
 package config
 
+// Output formats for hook decisions.
+const (
+	// OutputFormatText writes decisions to stderr and signals a block with exit code 2.
+	OutputFormatText = "text"
+
+	// OutputFormatJSON writes Claude Code's structured JSON hook response to stdout.
+	OutputFormatJSON = "json"
+)
+
+// ValidOutputFormats are the valid values for the global output format.
+var ValidOutputFormats = []string{OutputFormatText, OutputFormatJSON}
+
+// DefaultMaxShellNestingDepth is the default depth up to which shell scripts
+// nested in Bash commands are analysed.
+const DefaultMaxShellNestingDepth = 3
+
 // Config represents the root configuration for klaudiush.
 type Config struct {
 	// Validators groups all validator configurations.
@@ -75,6 +91,21 @@ type GlobalConfig struct {
 	// MaxGitWorkers is the maximum number of concurrent git operations.
 	// Default: 1 (serialized to avoid index lock contention)
 	MaxGitWorkers *int `json:"max_git_workers,omitempty" koanf:"max_git_workers" toml:"max_git_workers"`
+
+	// OutputFormat controls how hook decisions are reported to Claude Code.
+	// "text" writes errors to stderr and exits with code 2 on block.
+	// "json" writes a structured JSON hook response (permission decision,
+	// reason, additional context) to stdout.
+	// Default: "text"
+	OutputFormat string `json:"output_format,omitempty" koanf:"output_format" toml:"output_format"`
+
+	// MaxShellNestingDepth is the depth up to which shell scripts nested in
+	// Bash commands (bash -c, sh -c, su -c, eval) are parsed, so validators
+	// and rules see the commands they run. Commands run by wrappers like sudo,
+	// xargs or find -exec are unwrapped regardless. Commands nesting scripts
+	// deeper are blocked, as their commands cannot be validated.
+	// Default: 3
+	MaxShellNestingDepth *int `json:"max_shell_nesting_depth,omitempty" koanf:"max_shell_nesting_depth" toml:"max_shell_nesting_depth"`
 }
 
 // IsParallelExecutionEnabled returns whether parallel execution is enabled.
@@ -86,6 +117,25 @@ func (g *GlobalConfig) IsParallelExecutionEnabled() bool {
 	return *g.ParallelExecution
 }
 
+// GetOutputFormat returns the output format, defaulting to "text".
+func (g *GlobalConfig) GetOutputFormat() string {
+	if g == nil || g.OutputFormat == "" {
+		return OutputFormatText
+	}
+
+	return g.OutputFormat
+}
+
+// GetMaxShellNestingDepth returns the maximum shell nesting depth,
+// defaulting to DefaultMaxShellNestingDepth.
+func (g *GlobalConfig) GetMaxShellNestingDepth() int {
+	if g == nil || g.MaxShellNestingDepth == nil {
+		return DefaultMaxShellNestingDepth
+	}
+
+	return *g.MaxShellNestingDepth
+}
+
 // GetValidators returns the validators config, creating it if it doesn't exist.
 func (c *Config) GetValidators() *ValidatorsConfig {
 	if c.Validators == nil {
@@ -1,6 +1,8 @@
 // Package config provides configuration schema types for klaudiush validators.
 package config
 
+import "slices"
+
 // FileConfig groups all file-related validator configurations.
 type FileConfig struct {
 	// Markdown validator configuration
@@ -26,11 +28,15 @@ type FileConfig struct {
 
 	// Rust validator configuration
 	Rust *RustValidatorConfig `json:"rust,omitempty" koanf:"rust" toml:"rust"`
+
+	// Paths validator configuration (protected paths)
+	Paths *PathsValidatorConfig `json:"paths,omitempty" koanf:"paths" toml:"paths"`
 }
 
 // MarkdownValidatorConfig configures the Markdown file validator.
 type MarkdownValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for markdown linting operations.
 	// Default: "10s"
@@ -90,7 +96,8 @@ type MarkdownValidatorConfig struct {
 
 // ShellScriptValidatorConfig configures the shell script validator.
 type ShellScriptValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for shellcheck operations.
 	// Default: "10s"
@@ -120,7 +127,8 @@ type ShellScriptValidatorConfig struct {
 
 // TerraformValidatorConfig configures the Terraform/OpenTofu validator.
 type TerraformValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for terraform/tofu operations.
 	// Default: "10s"
@@ -158,7 +166,8 @@ type TerraformValidatorConfig struct {
 
 // WorkflowValidatorConfig configures the GitHub Actions workflow validator.
 type WorkflowValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for actionlint operations.
 	// Default: "10s"
@@ -192,7 +201,8 @@ type WorkflowValidatorConfig struct {
 
 // GofumptValidatorConfig configures the Go code formatter validator.
 type GofumptValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for gofumpt operations.
 	// Default: "10s"
@@ -219,7 +229,8 @@ type GofumptValidatorConfig struct {
 
 // PythonValidatorConfig configures the Python file validator.
 type PythonValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for ruff operations.
 	// Default: "10s"
@@ -248,7 +259,8 @@ type PythonValidatorConfig struct {
 
 // JavaScriptValidatorConfig configures the JavaScript/TypeScript file validator.
 type JavaScriptValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for oxlint operations.
 	// Default: "10s"
@@ -277,7 +289,8 @@ type JavaScriptValidatorConfig struct {
 
 // RustValidatorConfig configures the Rust file validator.
 type RustValidatorConfig struct {
-	ValidatorConfig `koanf:",squash"`
+	ValidatorConfig   `koanf:",squash"`
+	PostToolUseConfig `koanf:",squash"`
 
 	// Timeout is the maximum time allowed for rustfmt operations.
 	// Default: "10s"
@@ -304,3 +317,83 @@ type RustValidatorConfig struct {
 	// Default: "" (use rustfmt defaults)
 	RustfmtConfig string `json:"rustfmt_config,omitempty" koanf:"rustfmt_config" toml:"rustfmt_config"`
 }
+
+// Path access types of path rules.
+const (
+	// PathAccessRead is reading a path: the Read, Grep and Glob tools and
+	// Bash input redirections.
+	PathAccessRead = "read"
+
+	// PathAccessWrite is writing a path: the Write, Edit and MultiEdit tools
+	// and Bash file writes.
+	PathAccessWrite = "write"
+)
+
+// PathsValidatorConfig configures the protected paths validator, which checks
+// the paths read and written by tools against deny and allow rules.
+type PathsValidatorConfig struct {
+	ValidatorConfig `koanf:",squash"`
+
+	// Deny is the list of rules for paths that must not be accessed.
+	// Setting it replaces the default rules.
+	// Default: reads and writes of ~/.ssh, ~/.aws and .env files, writes to /etc
+	Deny []PathRuleConfig `json:"deny,omitempty" koanf:"deny" toml:"deny"`
+
+	// Allow is the list of rules for paths that may be accessed even when a
+	// deny rule matches them.
+	// Default: reads and writes of .env.example, .env.sample and .env.template
+	Allow []PathRuleConfig `json:"allow,omitempty" koanf:"allow" toml:"allow"`
+}
+
+// PathRuleConfig matches the paths of a deny or allow rule.
+type PathRuleConfig struct {
+	// Paths are glob patterns (doublestar syntax, e.g. "~/.ssh/**") matched
+	// against absolute paths, with symlinks and ".." resolved.
+	// A leading "~" is the home directory, patterns without a "/" match
+	// file names in any directory (".env"), and other relative patterns are
+	// relative to the project root.
+	Paths []string `json:"paths,omitempty" koanf:"paths" toml:"paths"`
+
+	// OutsideProject matches all paths outside the project root (the git
+	// repository root, or the working directory outside repositories).
+	// Default: false
+	OutsideProject bool `json:"outside_project,omitempty" koanf:"outside_project" toml:"outside_project"`
+
+	// Access is the list of access types the rule applies to: "read", "write".
+	// Default: ["read", "write"]
+	Access []string `json:"access,omitempty" koanf:"access" toml:"access"`
+
+	// Suggestion is shown instead of the default fix hint when the rule
+	// denies access.
+	// Default: ""
+	Suggestion string `json:"suggestion,omitempty" koanf:"suggestion" toml:"suggestion"`
+}
+
+// AppliesTo returns true if the rule applies to the access type.
+// Rules without access types apply to all of them.
+func (r *PathRuleConfig) AppliesTo(access string) bool {
+	if len(r.Access) == 0 {
+		return true
+	}
+
+	return slices.Contains(r.Access, access)
+}
+
+// PostToolUseConfig configures whole-file validation after the tool has run.
+type PostToolUseConfig struct {
+	// PostToolUse validates the whole file read from disk after Write, Edit and
+	// MultiEdit operations, in addition to the PreToolUse validation.
+	// Findings are reported back to the model as feedback instead of blocking.
+	// Default: false
+	PostToolUse *bool `json:"post_tool_use,omitempty" koanf:"post_tool_use" toml:"post_tool_use"`
+}
+
+// IsPostToolUseEnabled returns true if PostToolUse validation is enabled.
+// Returns false if PostToolUse is nil (default behavior).
+func (c *PostToolUseConfig) IsPostToolUseEnabled() bool {
+	if c.PostToolUse == nil {
+		return false
+	}
+
+	return *c.PostToolUse
+}
@@ -5,10 +5,20 @@ package config
 // These are exported for use by validation and doctor packages.
 var (
 	// ValidActionTypes are the valid action types for rules.
-	ValidActionTypes = []string{"allow", "block", "warn"}
+	ValidActionTypes = []string{"allow", "ask", "block", "rewrite", "warn"}
 
 	// ValidEventTypes are the valid event types for rules (case-insensitive matching supported).
-	ValidEventTypes = []string{"PreToolUse", "PostToolUse", "Notification"}
+	ValidEventTypes = []string{
+		"PreToolUse",
+		"PostToolUse",
+		"Notification",
+		"UserPromptSubmit",
+		"Stop",
+		"SubagentStop",
+		"SessionStart",
+		"SessionEnd",
+		"PreCompact",
+	}
 
 	// ValidToolTypes are the valid tool types for rules (case-insensitive matching supported).
 	ValidToolTypes = []string{"Bash", "Write", "Edit", "MultiEdit", "Grep", "Read", "Glob"}
@@ -24,8 +34,29 @@ type RulesConfig struct {
 	// Default: true
 	StopOnFirstMatch *bool `json:"stop_on_first_match,omitempty" koanf:"stop_on_first_match" toml:"stop_on_first_match"`
 
+	// Include lists rule packs ("pack:<name>@<version>") and rule files
+	// (paths relative to the including config file) whose rules are loaded
+	// before the rules of the config file. Any include can be pinned with
+	// "#sha256:<checksum>".
+	Include []string `json:"include,omitempty" koanf:"include" toml:"include"`
+
 	// Rules is the list of validation rules.
 	Rules []RuleConfig `json:"rules,omitempty" koanf:"rules" toml:"rules"`
+
+	// Stats configures recording of rule hit statistics.
+	Stats *RuleStatsConfig `json:"stats,omitempty" koanf:"stats" toml:"stats"`
+}
+
+// RuleStatsConfig configures recording of rule hit statistics, reported by
+// "klaudiush rules stats".
+type RuleStatsConfig struct {
+	// Enabled controls whether rule matches are recorded.
+	// Default: true
+	Enabled *bool `json:"enabled,omitempty" koanf:"enabled" toml:"enabled"`
+
+	// StateFile is the path to the rule stats file.
+	// Default: "~/.klaudiush/rule_stats.json"
+	StateFile string `json:"state_file,omitempty" koanf:"state_file" toml:"state_file"`
 }
 
 // RuleConfig represents a single validation rule configuration.
@@ -49,10 +80,15 @@ type RuleConfig struct {
 
 	// Action specifies what happens when the rule matches.
 	Action *RuleActionConfig `json:"action,omitempty" koanf:"action" toml:"action"`
+
+	// Source is the include the rule was loaded from (e.g.
+	// "pack:kong-org@v2"), or empty for rules defined in a config file.
+	Source string `json:"source,omitempty" koanf:"-" toml:"-"`
 }
 
 // RuleMatchConfig contains all conditions for a rule to match.
-// All non-empty conditions must be satisfied (AND logic).
+// All non-empty conditions must be satisfied (AND logic). Nested Any, All and
+// Not blocks combine match blocks with OR, AND and NOT logic.
 type RuleMatchConfig struct {
 	// ValidatorType filters by validator type (supports wildcards).
 	// Examples: "git.push", "git.*", "*"
@@ -96,6 +132,82 @@ type RuleMatchConfig struct {
 	// CommandPatterns allows multiple command patterns (any/all based on PatternMode).
 	CommandPatterns []string `json:"command_patterns,omitempty" koanf:"command_patterns" toml:"command_patterns"`
 
+	// CommandName matches against the name of each command parsed from the
+	// bash command, including commands in chains, pipelines and substitutions.
+	// Supports glob patterns, regex, and negation (! prefix).
+	// Structured conditions (command_name, git_subcommand, has_flag(s),
+	// arg_pattern, in_pipeline, dynamic_args) must all hold for the same
+	// parsed command.
+	CommandName string `json:"command_name,omitempty" koanf:"command_name" toml:"command_name"`
+
+	// GitSubcommand matches against the subcommand of parsed git commands,
+	// after global options (e.g., "push" in "git -C repo push").
+	GitSubcommand string `json:"git_subcommand,omitempty" koanf:"git_subcommand" toml:"git_subcommand"`
+
+	// HasFlag requires the parsed command to have the flag.
+	// Matches combined short flags (-rf has -f) and --flag=value.
+	HasFlag string `json:"has_flag,omitempty" koanf:"has_flag" toml:"has_flag"`
+
+	// HasFlags allows multiple flags (any/all based on FlagMode).
+	HasFlags []string `json:"has_flags,omitempty" koanf:"has_flags" toml:"has_flags"`
+
+	// FlagMode specifies how HasFlags are combined, independently of PatternMode.
+	// Values: "any" (OR logic, default), "all" (AND logic)
+	FlagMode string `json:"flag_mode,omitempty" koanf:"flag_mode" toml:"flag_mode"`
+
+	// ArgPattern matches against each argument of the parsed command.
+	// Supports glob patterns, regex, and negation (! prefix).
+	ArgPattern string `json:"arg_pattern,omitempty" koanf:"arg_pattern" toml:"arg_pattern"`
+
+	// InPipeline requires the parsed command to be (true) or not be (false)
+	// part of a pipeline.
+	InPipeline *bool `json:"in_pipeline,omitempty" koanf:"in_pipeline" toml:"in_pipeline"`
+
+	// DynamicArgs requires the parsed command to have (true) or not have
+	// (false) expansions whose values are not known, like $(...) or
+	// variables not assigned constant values earlier in the command.
+	DynamicArgs *bool `json:"dynamic_args,omitempty" koanf:"dynamic_args" toml:"dynamic_args"`
+
+	// StagedFilePattern matches if any staged file matches the pattern.
+	// Paths are relative to the repository root.
+	// Working-tree conditions are only evaluated when all other conditions match.
+	StagedFilePattern string `json:"staged_file_pattern,omitempty" koanf:"staged_file_pattern" toml:"staged_file_pattern"`
+
+	// ModifiedFilePattern matches if any modified but unstaged file matches
+	// the pattern.
+	ModifiedFilePattern string `json:"modified_file_pattern,omitempty" koanf:"modified_file_pattern" toml:"modified_file_pattern"`
+
+	// UntrackedFilePattern matches if any untracked file matches the pattern.
+	UntrackedFilePattern string `json:"untracked_file_pattern,omitempty" koanf:"untracked_file_pattern" toml:"untracked_file_pattern"`
+
+	// DirtyTree requires tracked files to have (true) or not have (false)
+	// staged or unstaged changes. Untracked files are ignored.
+	DirtyTree *bool `json:"dirty_tree,omitempty" koanf:"dirty_tree" toml:"dirty_tree"`
+
+	// AheadOfUpstream requires the branch to be at least this many commits
+	// ahead of its upstream branch.
+	AheadOfUpstream int `json:"ahead_of_upstream,omitempty" koanf:"ahead_of_upstream" toml:"ahead_of_upstream"`
+
+	// BehindUpstream requires the branch to be at least this many commits
+	// behind its upstream branch.
+	BehindUpstream int `json:"behind_upstream,omitempty" koanf:"behind_upstream" toml:"behind_upstream"`
+
+	// LastCommitAuthor matches against the author of the HEAD commit,
+	// formatted as "Name <email>".
+	LastCommitAuthor string `json:"last_commit_author,omitempty" koanf:"last_commit_author" toml:"last_commit_author"`
+
+	// TimeWindow requires the current time to be within a weekly window.
+	TimeWindow *TimeWindowConfig `json:"time_window,omitempty" koanf:"time_window" toml:"time_window"`
+
+	// Env maps environment variable names to patterns their values must
+	// match. Unset variables have an empty value, so "!true" also matches
+	// when the variable is unset.
+	Env map[string]string `json:"env,omitempty" koanf:"env" toml:"env"`
+
+	// HostnamePattern matches against the host name of the machine.
+	// Supports glob patterns, regex, and negation (! prefix).
+	HostnamePattern string `json:"hostname_pattern,omitempty" koanf:"hostname_pattern" toml:"hostname_pattern"`
+
 	// ToolType matches against the hook tool type.
 	// Examples: "Bash", "Write", "Edit"
 	ToolType string `json:"tool_type,omitempty" koanf:"tool_type" toml:"tool_type"`
@@ -111,6 +223,31 @@ type RuleMatchConfig struct {
 	// PatternMode specifies how multiple patterns are combined when using pattern lists.
 	// Values: "any" (OR logic, default), "all" (AND logic)
 	PatternMode string `json:"pattern_mode,omitempty" koanf:"pattern_mode" toml:"pattern_mode"`
+
+	// Any matches if at least one of the nested match blocks matches (OR logic).
+	Any []*RuleMatchConfig `json:"any,omitempty" koanf:"any" toml:"any"`
+
+	// All matches if every nested match block matches (AND logic).
+	All []*RuleMatchConfig `json:"all,omitempty" koanf:"all" toml:"all"`
+
+	// Not matches if the nested match block does not match.
+	Not *RuleMatchConfig `json:"not,omitempty" koanf:"not" toml:"not"`
+}
+
+// TimeWindowConfig is a weekly recurring window of time.
+type TimeWindowConfig struct {
+	// Days lists day names ("mon", "friday") and ranges ("mon-fri").
+	// Default: every day
+	Days []string `json:"days,omitempty" koanf:"days" toml:"days"`
+
+	// Hours is the "HH:MM-HH:MM" time range, end exclusive. A range whose
+	// end is before its start ends on the next day ("22:00-06:00").
+	// Default: the whole day
+	Hours string `json:"hours,omitempty" koanf:"hours" toml:"hours"`
+
+	// Timezone is the IANA name of the time zone (e.g. "Europe/Warsaw").
+	// Default: local time
+	Timezone string `json:"timezone,omitempty" koanf:"timezone" toml:"timezone"`
 }
 
 // IsCaseInsensitive returns true if case-insensitive matching is enabled.
@@ -132,6 +269,15 @@ func (m *RuleMatchConfig) GetPatternMode() string {
 	return m.PatternMode
 }
 
+// GetFlagMode returns the flag mode, defaulting to "any".
+func (m *RuleMatchConfig) GetFlagMode() string {
+	if m == nil || m.FlagMode == "" {
+		return "any"
+	}
+
+	return m.FlagMode
+}
+
 // HasMatchConditions returns true if the match config has at least one condition defined.
 // This is used to validate that a rule will actually match something.
 func (m *RuleMatchConfig) HasMatchConditions() bool {
@@ -151,21 +297,78 @@ func (m *RuleMatchConfig) HasMatchConditions() bool {
 		len(m.ContentPatterns) > 0 ||
 		m.CommandPattern != "" ||
 		len(m.CommandPatterns) > 0 ||
+		m.CommandName != "" ||
+		m.GitSubcommand != "" ||
+		m.HasFlag != "" ||
+		len(m.HasFlags) > 0 ||
+		m.ArgPattern != "" ||
+		m.InPipeline != nil ||
+		m.DynamicArgs != nil ||
+		m.StagedFilePattern != "" ||
+		m.ModifiedFilePattern != "" ||
+		m.UntrackedFilePattern != "" ||
+		m.DirtyTree != nil ||
+		m.AheadOfUpstream > 0 ||
+		m.BehindUpstream > 0 ||
+		m.LastCommitAuthor != "" ||
+		m.TimeWindow != nil ||
+		len(m.Env) > 0 ||
+		m.HostnamePattern != "" ||
 		m.ToolType != "" ||
-		m.EventType != ""
+		m.EventType != "" ||
+		len(m.Any) > 0 ||
+		len(m.All) > 0 ||
+		m.Not != nil
 }
 
 // RuleActionConfig specifies what happens when a rule matches.
 type RuleActionConfig struct {
-	// Type is the action to take (block, warn, allow).
+	// Type is the action to take (block, warn, ask, allow, rewrite).
 	// Default: "block"
 	Type string `json:"type,omitempty" koanf:"type" toml:"type"`
 
 	// Message is the human-readable message to display.
+	// Rendered as a Go template with access to {{.Branch}}, {{.Remote}},
+	// {{.RepoRoot}}, {{.File}}, {{.Command}}, {{.Tool}}, {{.Event}} and the
+	// named capture groups of the matching regex patterns.
 	Message string `json:"message,omitempty" koanf:"message" toml:"message"`
 
 	// Reference is an optional error reference code (e.g., "GIT019").
 	Reference string `json:"reference,omitempty" koanf:"reference" toml:"reference"`
+
+	// FixHint is an optional short suggestion for fixing the issue, rendered
+	// as a template like Message. Replaces the suggestion for Reference.
+	FixHint string `json:"fix_hint,omitempty" koanf:"fix_hint" toml:"fix_hint"`
+
+	// Rewrite specifies how the tool input is rewritten. Required for the
+	// rewrite action.
+	Rewrite *RuleRewriteConfig `json:"rewrite,omitempty" koanf:"rewrite" toml:"rewrite"`
+}
+
+// RuleRewriteConfig specifies how a rule with the rewrite action transforms
+// the tool input of a PreToolUse call. The rewritten input is returned to
+// Claude Code instead of blocking the call.
+//
+// Bash commands are edited on their syntax tree: only the commands selected by
+// the rule's command_name and git_subcommand conditions are changed (all
+// commands if it has neither). Values are rendered as Go templates like
+// Message.
+type RuleRewriteConfig struct {
+	// AddFlags are flags added to the selected commands that do not have them,
+	// after the subcommand of git commands. Requires command_name or
+	// git_subcommand.
+	AddFlags []string `json:"add_flags,omitempty" koanf:"add_flags" toml:"add_flags"`
+
+	// ReplacePrefix is a path prefix replaced in the arguments of the selected
+	// commands, or in the file path of file tools.
+	ReplacePrefix string `json:"replace_prefix,omitempty" koanf:"replace_prefix" toml:"replace_prefix"`
+
+	// ReplacePrefixWith replaces ReplacePrefix.
+	ReplacePrefixWith string `json:"replace_prefix_with,omitempty" koanf:"replace_prefix_with" toml:"replace_prefix_with"`
+
+	// InsertRemote is the remote added to git push, fetch and pull commands
+	// without positional arguments.
+	InsertRemote string `json:"insert_remote,omitempty" koanf:"insert_remote" toml:"insert_remote"`
 }
 
 // IsEnabled returns true if the rules engine is enabled.
@@ -188,6 +391,35 @@ func (r *RulesConfig) ShouldStopOnFirstMatch() bool {
 	return *r.StopOnFirstMatch
 }
 
+// GetStats returns the rule stats configuration, or an empty one if unset.
+func (r *RulesConfig) GetStats() *RuleStatsConfig {
+	if r == nil || r.Stats == nil {
+		return &RuleStatsConfig{}
+	}
+
+	return r.Stats
+}
+
+// IsEnabled returns true if rule stats are recorded.
+// Returns true if Enabled is nil (default behavior).
+func (s *RuleStatsConfig) IsEnabled() bool {
+	if s == nil || s.Enabled == nil {
+		return true
+	}
+
+	return *s.Enabled
+}
+
+// GetStateFile returns the state file path.
+// Returns "~/.klaudiush/rule_stats.json" if StateFile is empty.
+func (s *RuleStatsConfig) GetStateFile() string {
+	if s == nil || s.StateFile == "" {
+		return "~/.klaudiush/rule_stats.json"
+	}
+
+	return s.StateFile
+}
+
 // IsRuleEnabled returns true if the rule is enabled.
 // Returns true if Enabled is nil (default behavior).
 func (r *RuleConfig) IsRuleEnabled() bool {
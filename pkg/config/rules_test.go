@@ -103,6 +103,23 @@ var _ = Describe("RuleMatchConfig", func() {
 		})
 	})
 
+	Describe("GetFlagMode", func() {
+		It("should return 'any' for nil config", func() {
+			var cfg *config.RuleMatchConfig
+			Expect(cfg.GetFlagMode()).To(Equal("any"))
+		})
+
+		It("should not follow PatternMode", func() {
+			cfg := &config.RuleMatchConfig{PatternMode: "all"}
+			Expect(cfg.GetFlagMode()).To(Equal("any"))
+		})
+
+		It("should return configured flag mode", func() {
+			cfg := &config.RuleMatchConfig{FlagMode: "all"}
+			Expect(cfg.GetFlagMode()).To(Equal("all"))
+		})
+	})
+
 	Describe("GetPatternMode", func() {
 		It("should return 'any' for nil config", func() {
 			var cfg *config.RuleMatchConfig
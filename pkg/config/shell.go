@@ -5,6 +5,9 @@ package config
 type ShellConfig struct {
 	// Backtick validator configuration
 	Backtick *BacktickValidatorConfig `json:"backtick,omitempty" koanf:"backtick" toml:"backtick"`
+
+	// Destructive validator configuration
+	Destructive *DestructiveValidatorConfig `json:"destructive,omitempty" koanf:"destructive" toml:"destructive"`
 }
 
 // BacktickValidatorConfig configures the backtick validator.
@@ -42,3 +45,20 @@ func (c *BacktickValidatorConfig) SuggestSingleQuotesOrDefault() bool {
 
 	return *c.SuggestSingleQuotes
 }
+
+// DestructiveValidatorConfig configures the destructive command validator.
+type DestructiveValidatorConfig struct {
+	ValidatorConfig `koanf:",squash"`
+
+	// DisabledPatterns is a list of built-in pattern names to disable
+	// (e.g., "git-clean", "sql-truncate").
+	DisabledPatterns []string `json:"disabled_patterns,omitempty" koanf:"disabled_patterns" toml:"disabled_patterns"`
+
+	// Severities overrides the severities of built-in patterns, by pattern name.
+	// A pattern with "warning" severity reports the command without blocking it.
+	Severities map[string]Severity `json:"severities,omitempty" koanf:"severities" toml:"severities"`
+
+	// AllowedPaths is a list of glob patterns of paths outside the project root
+	// that recursive rm, chmod and chown may target (e.g., "/tmp/**").
+	AllowedPaths []string `json:"allowed_paths,omitempty" koanf:"allowed_paths" toml:"allowed_paths"`
+}
@@ -1,7 +1,10 @@
 // Package hook provides core types for Claude Code hook context.
 package hook
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strings"
+)
 
 //go:generate enumer -type=EventType -trimprefix=EventType -json -text -yaml -sql
 //go:generate go run github.com/smykla-labs/klaudiush/tools/enumerfix eventtype_enumer.go
@@ -23,6 +26,24 @@ const (
 
 	// EventTypeNotification is triggered for user notifications.
 	EventTypeNotification
+
+	// EventTypeUserPromptSubmit is triggered when the user submits a prompt.
+	EventTypeUserPromptSubmit
+
+	// EventTypeStop is triggered when the main agent finishes responding.
+	EventTypeStop
+
+	// EventTypeSubagentStop is triggered when a subagent finishes responding.
+	EventTypeSubagentStop
+
+	// EventTypeSessionStart is triggered when a session starts or resumes.
+	EventTypeSessionStart
+
+	// EventTypeSessionEnd is triggered when a session ends.
+	EventTypeSessionEnd
+
+	// EventTypePreCompact is triggered before the conversation is compacted.
+	EventTypePreCompact
 )
 
 // ToolType represents the type of tool being used.
@@ -74,6 +95,12 @@ type ToolInput struct {
 	// NewString is the replacement string for Edit tool.
 	NewString string `json:"new_string,omitempty"`
 
+	// ReplaceAll replaces all occurrences of OldString for Edit tool.
+	ReplaceAll bool `json:"replace_all,omitempty"`
+
+	// Edits is the list of edits for MultiEdit tool, applied in order.
+	Edits []Edit `json:"edits,omitempty"`
+
 	// Pattern is the search pattern for Grep/Glob tools.
 	Pattern string `json:"pattern,omitempty"`
 
@@ -81,9 +108,21 @@ type ToolInput struct {
 	Additional map[string]json.RawMessage `json:"-"`
 }
 
+// Edit is a single string replacement within a file (MultiEdit tool).
+type Edit struct {
+	// OldString is the string to replace.
+	OldString string `json:"old_string"`
+
+	// NewString is the replacement string.
+	NewString string `json:"new_string"`
+
+	// ReplaceAll replaces all occurrences of OldString.
+	ReplaceAll bool `json:"replace_all,omitempty"`
+}
+
 // Context represents the complete hook invocation context.
 type Context struct {
-	// EventType is the type of hook event (PreToolUse, PostToolUse, Notification).
+	// EventType is the type of hook event (PreToolUse, PostToolUse, Stop, etc.).
 	EventType EventType
 
 	// ToolName is the name of the tool being invoked.
@@ -106,6 +145,43 @@ type Context struct {
 
 	// TranscriptPath is the path to the session transcript file.
 	TranscriptPath string
+
+	// HookEventName is the event name reported by Claude Code in the payload.
+	HookEventName string
+
+	// Cwd is the working directory of the Claude Code session.
+	Cwd string
+
+	// Prompt is the submitted prompt (for UserPromptSubmit events).
+	Prompt string
+
+	// Message is the notification message (for Notification events).
+	Message string
+
+	// StopHookActive is true when Claude Code is already continuing because of
+	// a stop hook (for Stop and SubagentStop events). Validators should not
+	// block again in that case to avoid infinite loops.
+	StopHookActive bool
+
+	// Source is how the session started (for SessionStart events),
+	// e.g., "startup", "resume", "clear" or "compact".
+	Source string
+
+	// Reason is why the session ended (for SessionEnd events),
+	// e.g., "clear", "logout" or "prompt_input_exit".
+	Reason string
+
+	// Trigger is what triggered compaction (for PreCompact events),
+	// either "manual" or "auto".
+	Trigger string
+
+	// CustomInstructions are the user-provided instructions for manual compaction.
+	CustomInstructions string
+
+	// Env holds the environment variables of the hook process when it is
+	// validated elsewhere (e.g., by the daemon). Nil means the environment of
+	// the current process.
+	Env map[string]string
 }
 
 // GetCommand returns the command from ToolInput.
@@ -127,6 +203,104 @@ func (c *Context) GetContent() string {
 	return c.ToolInput.Content
 }
 
+// GetEdits returns the edits for Edit and MultiEdit tools.
+// For Edit, the single old_string/new_string pair is returned as one edit.
+func (c *Context) GetEdits() []Edit {
+	if len(c.ToolInput.Edits) > 0 {
+		return c.ToolInput.Edits
+	}
+
+	if c.ToolInput.OldString == "" && c.ToolInput.NewString == "" {
+		return nil
+	}
+
+	return []Edit{{
+		OldString:  c.ToolInput.OldString,
+		NewString:  c.ToolInput.NewString,
+		ReplaceAll: c.ToolInput.ReplaceAll,
+	}}
+}
+
+// GetNewContent returns the content being written: the file content for Write,
+// or all new strings of Edit and MultiEdit joined by newlines.
+func (c *Context) GetNewContent() string {
+	if c.ToolInput.Content != "" {
+		return c.ToolInput.Content
+	}
+
+	edits := c.GetEdits()
+	newStrings := make([]string, 0, len(edits))
+
+	for _, edit := range edits {
+		if edit.NewString != "" {
+			newStrings = append(newStrings, edit.NewString)
+		}
+	}
+
+	return strings.Join(newStrings, "\n")
+}
+
+// GetRawToolInput returns the tool input as sent by Claude Code, including the
+// fields ToolInput does not parse. Falls back to ToolInput when RawJSON is not
+// set, e.g. for contexts built in code.
+func (c *Context) GetRawToolInput() map[string]any {
+	var payload struct {
+		ToolInput map[string]any `json:"tool_input"`
+	}
+
+	if c.RawJSON != "" {
+		if err := json.Unmarshal([]byte(c.RawJSON), &payload); err == nil &&
+			payload.ToolInput != nil {
+			return payload.ToolInput
+		}
+	}
+
+	input := make(map[string]any)
+
+	if data, err := json.Marshal(c.ToolInput); err == nil {
+		_ = json.Unmarshal(data, &input)
+	}
+
+	return input
+}
+
+// WithToolInput returns a copy of the context with the tool input replaced,
+// both parsed and in RawJSON.
+func (c *Context) WithToolInput(input map[string]any) (*Context, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	result := *c
+	result.ToolInput = ToolInput{}
+
+	if err := json.Unmarshal(data, &result.ToolInput); err != nil {
+		return nil, err
+	}
+
+	payload := make(map[string]any)
+	if c.RawJSON != "" {
+		_ = json.Unmarshal([]byte(c.RawJSON), &payload)
+	}
+
+	payload["tool_input"] = input
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	result.RawJSON = string(raw)
+
+	return &result, nil
+}
+
+// IsEditTool returns true if the tool edits an existing file (Edit, MultiEdit).
+func (c *Context) IsEditTool() bool {
+	return c.ToolName == ToolTypeEdit || c.ToolName == ToolTypeMultiEdit
+}
+
 // IsBashTool returns true if the tool is Bash.
 func (c *Context) IsBashTool() bool {
 	return c.ToolName == ToolTypeBash
@@ -139,6 +313,12 @@ func (c *Context) IsFileTool() bool {
 		c.ToolName == ToolTypeMultiEdit
 }
 
+// IsToolEvent returns true if the event is about a tool invocation
+// (PreToolUse or PostToolUse).
+func (c *Context) IsToolEvent() bool {
+	return c.EventType == EventTypePreToolUse || c.EventType == EventTypePostToolUse
+}
+
 // HasSessionID returns true if a session ID is present.
 func (c *Context) HasSessionID() bool {
 	return c.SessionID != ""
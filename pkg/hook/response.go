@@ -0,0 +1,68 @@
+package hook
+
+// PermissionDecision is the PreToolUse permission decision reported to Claude Code.
+type PermissionDecision string
+
+const (
+	// PermissionDecisionAllow bypasses the permission system and allows the tool call.
+	PermissionDecisionAllow PermissionDecision = "allow"
+
+	// PermissionDecisionDeny prevents the tool call and shows the reason to the model.
+	PermissionDecisionDeny PermissionDecision = "deny"
+
+	// PermissionDecisionAsk asks the user to confirm the tool call.
+	PermissionDecisionAsk PermissionDecision = "ask"
+)
+
+// Decision is the top-level decision used by events without a permission decision
+// (e.g., PostToolUse).
+type Decision string
+
+const (
+	// DecisionBlock reports the reason back to the model as feedback.
+	DecisionBlock Decision = "block"
+)
+
+// Response is the structured JSON output understood by Claude Code hooks.
+// It is written to stdout and must be paired with exit code 0.
+type Response struct {
+	// Decision is the top-level decision for events without a permission decision.
+	Decision Decision `json:"decision,omitempty"`
+
+	// Reason explains the top-level decision to the model.
+	Reason string `json:"reason,omitempty"`
+
+	// SystemMessage is an optional message shown to the user.
+	SystemMessage string `json:"systemMessage,omitempty"`
+
+	// HookSpecificOutput contains event-specific fields.
+	HookSpecificOutput *HookSpecificOutput `json:"hookSpecificOutput,omitempty"`
+}
+
+// HookSpecificOutput contains the event-specific part of a hook response.
+type HookSpecificOutput struct {
+	// HookEventName is the name of the event this output belongs to.
+	HookEventName string `json:"hookEventName"`
+
+	// PermissionDecision is the permission decision for PreToolUse events.
+	PermissionDecision PermissionDecision `json:"permissionDecision,omitempty"`
+
+	// PermissionDecisionReason explains the permission decision.
+	// For "deny" it is shown to the model, for "allow" and "ask" to the user.
+	PermissionDecisionReason string `json:"permissionDecisionReason,omitempty"`
+
+	// AdditionalContext is added to the model context (e.g., warnings).
+	AdditionalContext string `json:"additionalContext,omitempty"`
+
+	// UpdatedInput replaces the tool input for PreToolUse events.
+	UpdatedInput map[string]any `json:"updatedInput,omitempty"`
+}
+
+// IsEmpty returns true if the response carries no information.
+func (r *Response) IsEmpty() bool {
+	return r == nil ||
+		(r.Decision == "" &&
+			r.Reason == "" &&
+			r.SystemMessage == "" &&
+			r.HookSpecificOutput == nil)
+}
@@ -8,22 +8,80 @@ import (
 type astWalker struct {
 	commands   []Command
 	fileWrites []FileWrite
-	currentDir string // Tracks the effective working directory from cd commands
+	fileReads  []FileRead
+	currentDir string       // Tracks the effective working directory from cd commands
+	scopes     []blockScope // Pipelines, chains, subshells and substitutions seen so far
+
+	// outer is the command running the script walked, for nested scripts
+	outer *Command
+
+	// depth is the nesting depth of the script walked, maxDepth the maximum
+	depth, maxDepth int
+
+	// depthExceeded is set when a script nested too deep is skipped
+	depthExceeded bool
+
+	// vars holds the constant values of the shell variables assigned so far,
+	// exported the names of the exported ones
+	vars     map[string]string
+	exported map[string]bool
+
+	// dirStack holds the directories saved by pushd
+	dirStack []string
+
+	// frames holds the nodes being walked, innermost last
+	frames []walkFrame
+}
+
+// blockScope is the source range of a construct that contains commands.
+type blockScope struct {
+	start, end uint
+	cmdType    CmdType
+}
+
+// contains reports whether the offset lies within the scope.
+func (s blockScope) contains(offset uint) bool {
+	return offset >= s.start && offset < s.end
 }
 
-// visit is called for each node in the AST.
+// visit is called for each node in the AST, and with nil after the children
+// of each node.
 func (w *astWalker) visit(node syntax.Node) bool {
+	if node == nil {
+		w.leave()
+
+		return true
+	}
+
+	w.enter(node)
+
 	switch n := node.(type) {
 	case *syntax.CallExpr:
 		w.extractCommand(n)
 	case *syntax.Stmt:
 		w.extractRedirect(n)
+	case *syntax.BinaryCmd:
+		// Parents are visited before their children, so the scope is known
+		// by the time the commands inside it are extracted
+		cmdType := CmdTypeChain
+		if n.Op == syntax.Pipe || n.Op == syntax.PipeAll {
+			cmdType = CmdTypePipe
+		}
+
+		w.addScope(n, cmdType)
 	case *syntax.Subshell:
 		// Subshells are handled recursively by syntax.Walk
-		return true
+		w.addScope(n, CmdTypeSubshell)
 	case *syntax.CmdSubst:
 		// Command substitution is handled recursively
-		return true
+		w.addScope(n, CmdTypeCmdSubst)
+	case *syntax.DeclClause:
+		w.declare(n)
+	case *syntax.ForClause:
+		// Loop variables take values the walker does not track
+		if iter, ok := n.Loop.(*syntax.WordIter); ok {
+			w.unset(iter.Name.Value)
+		}
 	}
 
 	return true
@@ -31,48 +89,115 @@ func (w *astWalker) visit(node syntax.Node) bool {
 
 // extractCommand extracts a command from a CallExpr node.
 func (w *astWalker) extractCommand(call *syntax.CallExpr) {
+	// Assignments without a command set shell variables
 	if len(call.Args) == 0 {
+		w.assign(call.Assigns)
+
 		return
 	}
 
 	// First word is the command name
-	name := wordToString(call.Args[0])
+	name, nameResolved := w.wordValue(call.Args[0])
 	if name == "" {
 		return
 	}
 
 	// Remaining words are arguments
-	args := wordsToStrings(call.Args[1:])
+	args, argsResolved := w.wordValues(call.Args[1:])
 
-	// Determine location
-	loc := Location{
-		Line:   call.Pos().Line(),
-		Column: call.Pos().Col(),
-	}
+	// Assignments before the command name set its environment
+	env, envResolved := w.envValues(call.Assigns)
 
-	// Determine command type (simple for now, enhanced later)
-	cmdType := CmdTypeSimple
+	cmdType, inPipeline := w.scopeOf(call.Pos().Offset())
 
 	cmd := Command{
 		Name:             name,
 		Args:             args,
-		Location:         loc,
+		Location:         w.location(call.Pos()),
 		Type:             cmdType,
+		InPipeline:       inPipeline,
 		WorkingDirectory: w.currentDir,
+		Env:              env,
+		Unresolved:       !nameResolved || !argsResolved || !envResolved,
 	}
 
-	w.commands = append(w.commands, cmd)
+	if w.outer != nil {
+		// Commands of nested scripts are in the construct of the command
+		// running the script, unless nested in one of the script
+		if cmdType == CmdTypeSimple {
+			cmd.Type = w.outer.Type
+		}
 
-	// Check if this is a cd command and update current directory
-	if name == "cd" && len(args) > 0 {
-		w.currentDir = args[0]
+		cmd.InPipeline = inPipeline || w.outer.InPipeline
+		cmd.Wrappers = w.outer.Wrappers
 	}
 
+	w.addCommand(cmd)
+}
+
+// addCommand records a command with the files it writes to, and the
+// commands it runs if it is a wrapper.
+func (w *astWalker) addCommand(cmd Command) {
+	w.commands = append(w.commands, cmd)
+
+	// Track the working directory and shell variables changed by builtins
+	w.trackBuiltin(cmd)
+
 	// Check if this is a file write command
 	w.extractFileWriteCommand(cmd)
+
+	// Check if this command runs other commands
+	w.unwrapCommand(cmd)
 }
 
-// extractRedirect extracts file write operations from redirections.
+// location returns the location of a position. Positions in nested scripts
+// are reported at the command running the script.
+func (w *astWalker) location(pos syntax.Pos) Location {
+	if w.outer != nil {
+		return w.outer.Location
+	}
+
+	return Location{
+		Line:   pos.Line(),
+		Column: pos.Col(),
+	}
+}
+
+// addScope records the source range of a construct that contains commands.
+func (w *astWalker) addScope(node syntax.Node, cmdType CmdType) {
+	w.scopes = append(w.scopes, blockScope{
+		start:   node.Pos().Offset(),
+		end:     node.End().Offset(),
+		cmdType: cmdType,
+	})
+}
+
+// scopeOf returns the type of the innermost construct containing the offset,
+// and whether any pipeline contains it.
+func (w *astWalker) scopeOf(offset uint) (CmdType, bool) {
+	cmdType := CmdTypeSimple
+	inPipeline := false
+	innermost := ^uint(0)
+
+	for _, scope := range w.scopes {
+		if !scope.contains(offset) {
+			continue
+		}
+
+		if scope.cmdType == CmdTypePipe {
+			inPipeline = true
+		}
+
+		if size := scope.end - scope.start; size < innermost {
+			innermost = size
+			cmdType = scope.cmdType
+		}
+	}
+
+	return cmdType, inPipeline
+}
+
+// extractRedirect extracts file write and read operations from redirections.
 func (w *astWalker) extractRedirect(stmt *syntax.Stmt) {
 	if stmt.Redirs == nil {
 		return
@@ -94,7 +219,7 @@ func (w *astWalker) extractRedirect(stmt *syntax.Stmt) {
 
 	for _, redir := range stmt.Redirs {
 		if redir.Op == syntax.RdrOut || redir.Op == syntax.AppOut {
-			path := wordToString(redir.Word)
+			path, _ := w.wordValue(redir.Word)
 			if path == "" {
 				continue
 			}
@@ -106,24 +231,28 @@ func (w *astWalker) extractRedirect(stmt *syntax.Stmt) {
 				outputOp = WriteOpAppend
 			}
 
-			outputLoc = Location{
-				Line:   redir.Pos().Line(),
-				Column: redir.Pos().Col(),
-			}
+			outputLoc = w.location(redir.Pos())
 			hasOutput = true
 		}
 
+		if redir.Op == syntax.RdrIn || redir.Op == syntax.RdrInOut {
+			if path, _ := w.wordValue(redir.Word); path != "" {
+				w.fileReads = append(w.fileReads, FileRead{
+					Path:             path,
+					Location:         w.location(redir.Pos()),
+					WorkingDirectory: w.currentDir,
+				})
+			}
+		}
+
 		// Handle heredocs
 		if redir.Op == syntax.Hdoc || redir.Op == syntax.DashHdoc {
 			// Extract heredoc content from Hdoc field (may be empty)
 			if redir.Hdoc != nil {
-				heredocContent = wordToString(redir.Hdoc)
+				heredocContent, _ = w.wordValue(redir.Hdoc)
 			}
 			// Mark as heredoc even if content is empty
-			heredocLoc = Location{
-				Line:   redir.Pos().Line(),
-				Column: redir.Pos().Col(),
-			}
+			heredocLoc = w.location(redir.Pos())
 			hasHeredoc = true
 		}
 	}
@@ -132,18 +261,20 @@ func (w *astWalker) extractRedirect(stmt *syntax.Stmt) {
 	// If we have both output redirection and heredoc, combine them
 	if hasOutput && hasHeredoc {
 		fw := FileWrite{
-			Path:      outputPath,
-			Operation: WriteOpHeredoc,
-			Content:   heredocContent,
-			Location:  heredocLoc,
+			Path:             outputPath,
+			Operation:        WriteOpHeredoc,
+			Content:          heredocContent,
+			Location:         heredocLoc,
+			WorkingDirectory: w.currentDir,
 		}
 		w.fileWrites = append(w.fileWrites, fw)
 	} else if hasOutput {
 		// Just output redirection without heredoc
 		fw := FileWrite{
-			Path:      outputPath,
-			Operation: outputOp,
-			Location:  outputLoc,
+			Path:             outputPath,
+			Operation:        outputOp,
+			Location:         outputLoc,
+			WorkingDirectory: w.currentDir,
 		}
 		w.fileWrites = append(w.fileWrites, fw)
 	}
@@ -151,46 +282,10 @@ func (w *astWalker) extractRedirect(stmt *syntax.Stmt) {
 	// (it would just pipe to stdin of a command)
 }
 
-// extractFileWriteCommand detects file write commands (tee, cp, mv).
+// extractFileWriteCommand detects file writes of commands with a registered
+// write detector (see RegisterWriteDetector).
 func (w *astWalker) extractFileWriteCommand(cmd Command) {
-	op, targets := getFileWriteOperation(cmd)
-	if op == WriteOpNone {
-		return
-	}
-
-	for _, target := range targets {
-		fw := FileWrite{
-			Path:      target,
-			Operation: op,
-			Source:    cmd.Name,
-			Location:  cmd.Location,
-		}
-
-		w.fileWrites = append(w.fileWrites, fw)
-	}
-}
-
-// getFileWriteOperation determines if a command writes to files.
-func getFileWriteOperation(cmd Command) (WriteOp, []string) {
-	switch cmd.Name {
-	case "tee":
-		// tee writes to all file arguments
-		return WriteOpTee, extractTeeTargets(cmd.Args)
-
-	case "cp", "copy":
-		// cp writes to the last argument
-		if len(cmd.Args) >= 2 { //nolint:mnd // Trivial check for minimum args (source + dest)
-			return WriteOpCopy, []string{cmd.Args[len(cmd.Args)-1]}
-		}
-
-	case "mv", "move":
-		// mv writes to the last argument
-		if len(cmd.Args) >= 2 { //nolint:mnd // Trivial check for minimum args (source + dest)
-			return WriteOpMove, []string{cmd.Args[len(cmd.Args)-1]}
-		}
-	}
-
-	return WriteOpNone, nil
+	w.fileWrites = append(w.fileWrites, DetectFileWrites(cmd)...)
 }
 
 // extractTeeTargets extracts file targets from tee command arguments.
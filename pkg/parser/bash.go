@@ -14,23 +14,59 @@ var (
 	ErrParseFailed = errors.New("failed to parse command")
 )
 
+// DefaultMaxNestingDepth is the default depth up to which shell scripts
+// nested in commands (bash -c, eval) are parsed.
+const DefaultMaxNestingDepth = 3
+
 // ParseResult contains the results of parsing a Bash command.
 type ParseResult struct {
-	Commands      []Command   // All commands found
+	Commands      []Command   // All commands found, including commands run by wrappers
 	FileWrites    []FileWrite // All file write operations
+	FileReads     []FileRead  // All files read by input redirections
 	GitOperations []Command   // Git commands only
+	DepthExceeded bool        // Whether scripts nested deeper than the maximum depth were skipped
 }
 
 // BashParser parses Bash commands using mvdan.cc/sh.
 type BashParser struct {
-	parser *syntax.Parser
+	parser   *syntax.Parser
+	maxDepth int
+	env      map[string]string
+}
+
+// BashParserOption configures a BashParser.
+type BashParserOption func(*BashParser)
+
+// WithMaxNestingDepth sets the depth up to which shell scripts nested in
+// commands (bash -c, sh -c, su -c, eval) are parsed. Zero disables parsing
+// nested scripts; commands run by wrappers like sudo are still unwrapped.
+func WithMaxNestingDepth(depth int) BashParserOption {
+	return func(p *BashParser) {
+		p.maxDepth = depth
+	}
+}
+
+// WithEnvironment sets the environment variables the command runs with, e.g.
+// HOME. They are resolved in expansions like shell variables assigned by the
+// command, and passed to child shells.
+func WithEnvironment(env map[string]string) BashParserOption {
+	return func(p *BashParser) {
+		p.env = env
+	}
 }
 
 // NewBashParser creates a new BashParser instance.
-func NewBashParser() *BashParser {
-	return &BashParser{
-		parser: syntax.NewParser(),
+func NewBashParser(opts ...BashParserOption) *BashParser {
+	p := &BashParser{
+		parser:   syntax.NewParser(),
+		maxDepth: DefaultMaxNestingDepth,
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
+
+	return p
 }
 
 // Parse parses a Bash command string and extracts all commands and operations.
@@ -50,8 +86,12 @@ func (p *BashParser) Parse(command string) (*ParseResult, error) {
 	walker := &astWalker{
 		commands:   make([]Command, 0),
 		fileWrites: make([]FileWrite, 0),
+		fileReads:  make([]FileRead, 0),
+		maxDepth:   p.maxDepth,
 	}
 
+	walker.setEnvironment(p.env)
+
 	syntax.Walk(file, walker.visit)
 
 	// Extract git operations
@@ -66,7 +106,9 @@ func (p *BashParser) Parse(command string) (*ParseResult, error) {
 	return &ParseResult{
 		Commands:      walker.commands,
 		FileWrites:    walker.fileWrites,
+		FileReads:     walker.fileReads,
 		GitOperations: gitOps,
+		DepthExceeded: walker.depthExceeded,
 	}, nil
 }
 
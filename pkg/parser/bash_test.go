@@ -124,6 +124,19 @@ var _ = Describe("BashParser", func() {
 				Expect(result.Commands[1].Name).To(Equal("grep"))
 				Expect(result.Commands[2].Name).To(Equal("wc"))
 			})
+
+			It("marks commands in pipelines", func() {
+				result, err := p.Parse("git status && git log | head -5")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Commands).To(HaveLen(3))
+
+				Expect(result.Commands[0].Type).To(Equal(parser.CmdTypeChain))
+				Expect(result.Commands[0].InPipeline).To(BeFalse())
+				Expect(result.Commands[1].Type).To(Equal(parser.CmdTypePipe))
+				Expect(result.Commands[1].InPipeline).To(BeTrue())
+				Expect(result.Commands[2].Type).To(Equal(parser.CmdTypePipe))
+				Expect(result.Commands[2].InPipeline).To(BeTrue())
+			})
 		})
 
 		Context("with subshells", func() {
@@ -144,6 +157,20 @@ var _ = Describe("BashParser", func() {
 				Expect(result.Commands[0].Name).To(Equal("echo"))
 				Expect(result.Commands[1].Name).To(Equal("git"))
 			})
+
+			It("sets the innermost construct as command type", func() {
+				result, err := p.Parse("(git push) | tee $(date +%s).log")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Commands).To(HaveLen(3))
+
+				Expect(result.Commands[0].Name).To(Equal("git"))
+				Expect(result.Commands[0].Type).To(Equal(parser.CmdTypeSubshell))
+				Expect(result.Commands[0].InPipeline).To(BeTrue())
+				Expect(result.Commands[1].Name).To(Equal("tee"))
+				Expect(result.Commands[1].Type).To(Equal(parser.CmdTypePipe))
+				Expect(result.Commands[2].Name).To(Equal("date"))
+				Expect(result.Commands[2].Type).To(Equal(parser.CmdTypeCmdSubst))
+			})
 		})
 
 		Context("with quoted strings", func() {
@@ -195,6 +222,24 @@ var _ = Describe("BashParser", func() {
 				Expect(fw.Path).To(Equal("file.txt"))
 				Expect(fw.Operation).To(Equal(parser.WriteOpAppend))
 			})
+
+			It("detects input redirection", func() {
+				result, err := p.Parse("cd /etc && sort < hosts > sorted.txt")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.FileReads).To(HaveLen(1))
+				Expect(result.FileWrites).To(HaveLen(1))
+
+				fr := result.FileReads[0]
+				Expect(fr.Path).To(Equal("hosts"))
+				Expect(fr.WorkingDirectory).To(Equal("/etc"))
+			})
+
+			It("detects input redirection in nested scripts", func() {
+				result, err := p.Parse(`bash -c "wc -l < ~/.ssh/id_rsa"`)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.FileReads).To(HaveLen(1))
+				Expect(result.FileReads[0].Path).To(Equal("~/.ssh/id_rsa"))
+			})
 		})
 
 		Context("with heredoc", func() {
@@ -53,9 +53,27 @@ type Command struct {
 	Name             string   // Command name (e.g., "git")
 	Args             []string // Command arguments
 	Location         Location // Position in source
-	Type             CmdType  // Command type
+	Type             CmdType  // Innermost construct containing the command
+	InPipeline       bool     // Whether the command is part of a pipeline
 	Raw              string   // Raw command string
 	WorkingDirectory string   // Effective working directory from preceding cd commands
+	Wrappers         []string // Wrappers running the command, outermost first (e.g. "sudo", "bash -c")
+
+	// Env holds the environment assignments prefixing the command
+	// (FOO=1 git push) whose values are known.
+	Env map[string]string
+
+	// Unresolved is set when the name, arguments or environment assignments
+	// of the command have expansions whose values are not known, like
+	// $(date) or variables not assigned constant values earlier in the
+	// command. Unresolved expansions are left out of the values.
+	Unresolved bool
+}
+
+// IsWrapped reports whether the command is run by a wrapper command like
+// sudo or xargs, or in a script nested in a command like bash -c.
+func (c *Command) IsWrapped() bool {
+	return len(c.Wrappers) > 0
 }
 
 // String returns a string representation of the command.
@@ -77,39 +95,108 @@ func (c *Command) FullCommand() []string {
 
 // wordToString converts syntax.Word to string, handling quotes and expansions.
 func wordToString(word *syntax.Word) string {
+	value, _ := wordValue(word, nil)
+
+	return value
+}
+
+// variableLookup returns the value of a shell variable, if it is known.
+type variableLookup func(name string) (string, bool)
+
+// wordValue returns the value of a word, with the parameter expansions lookup
+// knows the value of resolved, and whether all its expansions were resolved.
+// Unresolved expansions are left out of the value.
+func wordValue(word *syntax.Word, lookup variableLookup) (string, bool) {
 	if word == nil {
-		return ""
+		return "", true
 	}
 
 	var result strings.Builder
 
-	for _, part := range word.Parts {
+	resolved := wordPartsValue(&result, word.Parts, lookup, false)
+
+	return result.String(), resolved
+}
+
+// wordPartsValue writes the value of word parts, in double quotes if quoted,
+// and reports whether all their expansions were resolved.
+func wordPartsValue(
+	result *strings.Builder,
+	parts []syntax.WordPart,
+	lookup variableLookup,
+	quoted bool,
+) bool {
+	resolved := true
+
+	for _, part := range parts {
 		switch p := part.(type) {
 		case *syntax.Lit:
-			result.WriteString(p.Value)
+			if quoted {
+				result.WriteString(unescapeDoubleQuoted(p.Value))
+			} else {
+				result.WriteString(p.Value)
+			}
 		case *syntax.SglQuoted:
 			result.WriteString(p.Value)
 		case *syntax.DblQuoted:
-			for _, dqPart := range p.Parts {
-				switch dqp := dqPart.(type) {
-				case *syntax.Lit:
-					result.WriteString(dqp.Value)
-				case *syntax.CmdSubst:
-					// Handle command substitution (e.g., "$(cat <<'EOF' ... EOF)")
-					if heredoc := extractHeredocFromCmdSubst(dqp); heredoc != "" {
-						result.WriteString(heredoc)
-					}
-				}
-			}
+			resolved = wordPartsValue(result, p.Parts, lookup, true) && resolved
+		case *syntax.ParamExp:
+			value, ok := paramValue(p, lookup)
+			result.WriteString(value)
+
+			resolved = resolved && ok
 		case *syntax.CmdSubst:
-			// Handle unquoted command substitution
-			if heredoc := extractHeredocFromCmdSubst(p); heredoc != "" {
-				result.WriteString(heredoc)
+			// Handle command substitution of heredocs (e.g., "$(cat <<'EOF' ... EOF)")
+			heredoc := extractHeredocFromCmdSubst(p)
+			result.WriteString(heredoc)
+
+			resolved = resolved && heredoc != ""
+		default:
+			// Arithmetic expansions, process substitutions and extended globs
+			resolved = false
+		}
+	}
+
+	return resolved
+}
+
+// paramValue returns the value of a plain parameter expansion ($VAR or
+// ${VAR}) of a variable lookup knows. Other forms are not resolved.
+func paramValue(param *syntax.ParamExp, lookup variableLookup) (string, bool) {
+	plain := param.Param != nil && !param.Excl && !param.Length && !param.Width &&
+		param.Index == nil && param.Slice == nil && param.Repl == nil &&
+		param.Names == 0 && param.Exp == nil
+	if !plain || lookup == nil {
+		return "", false
+	}
+
+	return lookup(param.Param.Value)
+}
+
+// unescapeDoubleQuoted removes the backslashes escaping characters in double
+// quotes. Only "\", "$", "`", '"' and newlines can be escaped there; other
+// backslashes are literal.
+func unescapeDoubleQuoted(value string) string {
+	if !strings.Contains(value, `\`) {
+		return value
+	}
+
+	var sb strings.Builder
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+1 < len(value) && strings.IndexByte("\\$`\"\n", value[i+1]) >= 0 {
+			i++
+
+			// A backslash-newline is a line continuation
+			if value[i] == '\n' {
+				continue
 			}
 		}
+
+		sb.WriteByte(value[i])
 	}
 
-	return result.String()
+	return sb.String()
 }
 
 // extractHeredocFromCmdSubst extracts heredoc content from command substitution.
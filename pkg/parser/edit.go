@@ -0,0 +1,229 @@
+package parser
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// CommandEdit is a simple command of a bash command being edited by
+// EditCommands. Arguments are indexed from 0, excluding the command name.
+// Redirections are indexed from 0 and only include file redirections
+// (> file, >> file, < file, &> file), not here-documents or descriptor
+// duplications.
+type CommandEdit struct {
+	call    *syntax.CallExpr
+	redirs  []*syntax.Redirect
+	changed bool
+}
+
+// Command returns the command with its current arguments.
+func (e *CommandEdit) Command() Command {
+	return Command{
+		Name: wordToString(e.call.Args[0]),
+		Args: wordsToStrings(e.call.Args[1:]),
+	}
+}
+
+// NumArgs returns the number of arguments.
+func (e *CommandEdit) NumArgs() int {
+	return len(e.call.Args) - 1
+}
+
+// Arg returns the value of the argument at index i. ok is false if the
+// argument contains expansions, so its value is not known before it runs.
+func (e *CommandEdit) Arg(i int) (value string, ok bool) {
+	return literalWord(e.call.Args[i+1])
+}
+
+// Args returns the values of the arguments, with an empty value for the
+// arguments that contain expansions.
+func (e *CommandEdit) Args() []string {
+	args := make([]string, 0, e.NumArgs())
+
+	for i := range e.NumArgs() {
+		value, _ := e.Arg(i)
+		args = append(args, value)
+	}
+
+	return args
+}
+
+// InsertArgs inserts arguments before the argument at index i, quoting them
+// as needed. An index of NumArgs appends them.
+func (e *CommandEdit) InsertArgs(i int, args ...string) error {
+	words := make([]*syntax.Word, 0, len(args))
+
+	for _, arg := range args {
+		word, err := quotedWord(arg)
+		if err != nil {
+			return err
+		}
+
+		words = append(words, word)
+	}
+
+	pos := i + 1
+	e.call.Args = append(e.call.Args[:pos], append(words, e.call.Args[pos:]...)...)
+	e.changed = true
+
+	return nil
+}
+
+// SetArg replaces the argument at index i, quoting it as needed.
+func (e *CommandEdit) SetArg(i int, value string) error {
+	word, err := quotedWord(value)
+	if err != nil {
+		return err
+	}
+
+	e.call.Args[i+1] = word
+	e.changed = true
+
+	return nil
+}
+
+// NumRedirects returns the number of file redirections.
+func (e *CommandEdit) NumRedirects() int {
+	return len(e.redirs)
+}
+
+// RedirectTarget returns the target of the file redirection at index i. ok
+// is false if the target contains expansions.
+func (e *CommandEdit) RedirectTarget(i int) (value string, ok bool) {
+	return literalWord(e.redirs[i].Word)
+}
+
+// SetRedirectTarget replaces the target of the file redirection at index i,
+// quoting it as needed.
+func (e *CommandEdit) SetRedirectTarget(i int, value string) error {
+	word, err := quotedWord(value)
+	if err != nil {
+		return err
+	}
+
+	e.redirs[i].Word = word
+	e.changed = true
+
+	return nil
+}
+
+// fileRedirectOps are the redirection operators whose target is a file.
+var fileRedirectOps = []syntax.RedirOperator{
+	syntax.RdrOut, syntax.AppOut, syntax.RdrIn, syntax.RdrInOut,
+	syntax.ClbOut, syntax.RdrAll, syntax.AppAll,
+}
+
+// fileRedirects returns the file redirections of a statement.
+func fileRedirects(stmt *syntax.Stmt) []*syntax.Redirect {
+	var redirs []*syntax.Redirect
+
+	for _, redir := range stmt.Redirs {
+		if redir.Word != nil && slices.Contains(fileRedirectOps, redir.Op) {
+			redirs = append(redirs, redir)
+		}
+	}
+
+	return redirs
+}
+
+// EditCommands parses a bash command and calls edit for each of its simple
+// commands, including commands in chains, pipelines, subshells and command
+// substitutions. If any command was changed, the command is printed from the
+// edited syntax tree, which normalizes its formatting; comments are kept.
+// Otherwise the command is returned unchanged.
+func EditCommands(command string, edit func(cmd *CommandEdit) error) (string, bool, error) {
+	if strings.TrimSpace(command) == "" {
+		return "", false, ErrEmptyCommand
+	}
+
+	file, err := syntax.NewParser(syntax.KeepComments(true)).
+		Parse(strings.NewReader(command), "")
+	if err != nil {
+		return "", false, errors.Wrap(ErrParseFailed, err.Error())
+	}
+
+	changed := false
+
+	var editErr error
+
+	syntax.Walk(file, func(node syntax.Node) bool {
+		stmt, ok := node.(*syntax.Stmt)
+		if !ok || editErr != nil {
+			return editErr == nil
+		}
+
+		call, ok := stmt.Cmd.(*syntax.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+
+		cmd := &CommandEdit{call: call, redirs: fileRedirects(stmt)}
+		editErr = edit(cmd)
+		changed = changed || cmd.changed
+
+		return editErr == nil
+	})
+
+	if editErr != nil {
+		return "", false, editErr
+	}
+
+	if !changed {
+		return command, false, nil
+	}
+
+	var sb strings.Builder
+	if err := syntax.NewPrinter().Print(&sb, file); err != nil {
+		return "", false, errors.Wrap(err, "failed to print edited command")
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n"), true, nil
+}
+
+// literalWord returns the value of a word without expansions.
+func literalWord(word *syntax.Word) (string, bool) {
+	var sb strings.Builder
+
+	for _, part := range word.Parts {
+		switch p := part.(type) {
+		case *syntax.Lit:
+			if strings.Contains(p.Value, `\`) {
+				return "", false
+			}
+
+			sb.WriteString(p.Value)
+		case *syntax.SglQuoted:
+			if p.Dollar {
+				return "", false
+			}
+
+			sb.WriteString(p.Value)
+		case *syntax.DblQuoted:
+			for _, dqPart := range p.Parts {
+				lit, ok := dqPart.(*syntax.Lit)
+				if !ok || strings.Contains(lit.Value, `\`) {
+					return "", false
+				}
+
+				sb.WriteString(lit.Value)
+			}
+		default:
+			return "", false
+		}
+	}
+
+	return sb.String(), true
+}
+
+// quotedWord returns a word with the value, quoted as needed.
+func quotedWord(value string) (*syntax.Word, error) {
+	quoted, err := syntax.Quote(value, syntax.LangBash)
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot quote %q", value)
+	}
+
+	return &syntax.Word{Parts: []syntax.WordPart{&syntax.Lit{Value: quoted}}}, nil
+}
@@ -0,0 +1,100 @@
+package parser_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+var _ = Describe("EditCommands", func() {
+	It("returns the command unchanged when nothing is edited", func() {
+		command := "git  push   origin main # keep formatting"
+
+		edited, changed, err := parser.EditCommands(command, func(*parser.CommandEdit) error {
+			return nil
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeFalse())
+		Expect(edited).To(Equal(command))
+	})
+
+	It("visits commands in chains, pipelines and substitutions", func() {
+		var names []string
+
+		_, _, err := parser.EditCommands(
+			"cd /src && echo $(git rev-parse HEAD) | tee out.txt",
+			func(cmd *parser.CommandEdit) error {
+				names = append(names, cmd.Command().Name)
+
+				return nil
+			},
+		)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(names).To(ConsistOf("cd", "echo", "git", "tee"))
+	})
+
+	It("inserts and quotes arguments", func() {
+		edited, changed, err := parser.EditCommands(
+			"git push origin && echo done",
+			func(cmd *parser.CommandEdit) error {
+				if cmd.Command().Name != "git" {
+					return nil
+				}
+
+				return cmd.InsertArgs(1, "--force-with-lease", "-o", "ci skip")
+			},
+		)
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(edited).To(Equal("git push --force-with-lease -o 'ci skip' origin && echo done"))
+	})
+
+	It("replaces arguments", func() {
+		edited, _, err := parser.EditCommands("cp a.txt /tmp/a.txt", func(cmd *parser.CommandEdit) error {
+			return cmd.SetArg(1, "/scratch/a.txt")
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(edited).To(Equal("cp a.txt /scratch/a.txt"))
+	})
+
+	It("replaces file redirection targets", func() {
+		edited, _, err := parser.EditCommands("echo hi > /tmp/a.txt 2>&1 <<< x", func(cmd *parser.CommandEdit) error {
+			Expect(cmd.NumRedirects()).To(Equal(1))
+
+			target, ok := cmd.RedirectTarget(0)
+			Expect(ok).To(BeTrue())
+			Expect(target).To(Equal("/tmp/a.txt"))
+
+			return cmd.SetRedirectTarget(0, "/scratch/a.txt")
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(edited).To(Equal("echo hi >/scratch/a.txt 2>&1 <<<x"))
+	})
+
+	It("reports arguments with expansions as not literal", func() {
+		_, _, err := parser.EditCommands(`cp "$SRC" 'a b' "c d"`, func(cmd *parser.CommandEdit) error {
+			_, ok := cmd.Arg(0)
+			Expect(ok).To(BeFalse())
+
+			Expect(cmd.Args()).To(Equal([]string{"", "a b", "c d"}))
+
+			return nil
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns parse errors", func() {
+		_, _, err := parser.EditCommands("echo 'unterminated", func(*parser.CommandEdit) error {
+			return nil
+		})
+
+		Expect(err).To(MatchError(parser.ErrParseFailed))
+	})
+})
@@ -20,6 +20,21 @@ const (
 	WriteOpMove
 	// WriteOpHeredoc indicates heredoc (<<).
 	WriteOpHeredoc
+	// WriteOpInPlace indicates an in-place edit (sed -i, perl -i, ruby -i).
+	WriteOpInPlace
+	// WriteOpDD indicates dd of=.
+	WriteOpDD
+	// WriteOpInstall indicates install command.
+	WriteOpInstall
+	// WriteOpSync indicates rsync command.
+	WriteOpSync
+	// WriteOpTruncate indicates truncate command.
+	WriteOpTruncate
+	// WriteOpLink indicates ln command.
+	WriteOpLink
+	// WriteOpScript indicates a write by interpreter one-liner code
+	// (python -c, node -e, perl -e, ruby -e).
+	WriteOpScript
 )
 
 // String returns string representation of WriteOp.
@@ -39,6 +54,20 @@ func (w WriteOp) String() string {
 		return "Move"
 	case WriteOpHeredoc:
 		return "Heredoc"
+	case WriteOpInPlace:
+		return "InPlace"
+	case WriteOpDD:
+		return "DD"
+	case WriteOpInstall:
+		return "Install"
+	case WriteOpSync:
+		return "Sync"
+	case WriteOpTruncate:
+		return "Truncate"
+	case WriteOpLink:
+		return "Link"
+	case WriteOpScript:
+		return "Script"
 	default:
 		return "Unknown"
 	}
@@ -46,11 +75,13 @@ func (w WriteOp) String() string {
 
 // FileWrite represents a file write operation detected in the command.
 type FileWrite struct {
-	Path      string   // Target file path
-	Operation WriteOp  // Type of write operation
-	Source    string   // Source command (for cp, mv, tee)
-	Content   string   // Content for heredoc operations
-	Location  Location // Position in source
+	Path             string       // Target file path
+	Operation        WriteOp      // Type of write operation
+	Source           string       // Source command (for cp, mv, tee, ...)
+	Content          string       // Content for heredoc operations
+	Edit             *InPlaceEdit // Edit applied to the file, if known (sed -i)
+	Location         Location     // Position in source
+	WorkingDirectory string       // Effective working directory from preceding cd commands
 }
 
 // String returns a string representation of the file write operation.
@@ -58,12 +89,30 @@ func (f *FileWrite) String() string {
 	return fmt.Sprintf("%s %s -> %s", f.Operation, f.Source, f.Path)
 }
 
+// FileRead represents a file read by an input redirection (<, <>).
+type FileRead struct {
+	Path             string   // Source file path
+	Location         Location // Position in source
+	WorkingDirectory string   // Effective working directory from preceding cd commands
+}
+
+// String returns a string representation of the file read operation.
+func (f *FileRead) String() string {
+	return "< " + f.Path
+}
+
 // IsProtectedPath checks if the path is a protected location.
+//
+// Deprecated: /tmp and /var/tmp are hardcoded. Use the configurable path
+// rules of validators.file.paths instead.
 func (f *FileWrite) IsProtectedPath() bool {
 	return IsProtectedPath(f.Path)
 }
 
 // IsProtectedPath checks if a path is protected (e.g., /tmp, /var/tmp).
+//
+// Deprecated: /tmp and /var/tmp are hardcoded. Use the configurable path
+// rules of validators.file.paths instead.
 func IsProtectedPath(path string) bool {
 	// Check for /tmp prefix
 	if len(path) >= 4 && path[:4] == "/tmp" {
@@ -8,6 +8,9 @@ import (
 )
 
 // PathViolation represents a protected path violation.
+//
+// Deprecated: Use the configurable path rules of validators.file.paths
+// instead.
 type PathViolation struct {
 	Path       string
 	Operation  WriteOp
@@ -26,12 +29,19 @@ func (v *PathViolation) String() string {
 	)
 }
 
-// PathValidator validates file paths for protected locations.
+// PathValidator validates file paths for protected locations, /tmp and
+// /var/tmp, suggesting a project-local tmp/ directory instead.
+//
+// Deprecated: The protected locations and the suggestion are hardcoded. Use
+// the configurable path rules of validators.file.paths instead.
 type PathValidator struct {
 	projectRoot string
 }
 
 // NewPathValidator creates a new PathValidator.
+//
+// Deprecated: Use the configurable path rules of validators.file.paths
+// instead.
 func NewPathValidator() *PathValidator {
 	// Try to detect project root
 	root, err := os.Getwd()
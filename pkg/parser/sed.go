@@ -0,0 +1,390 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// errUnsupportedSed is returned for sed scripts whose effect is not computed.
+var errUnsupportedSed = errors.New("unsupported sed script")
+
+// sedSpec are the sed options taking a value.
+var sedSpec = optionSpec{withValue: map[string]bool{
+	"-e": true, "--expression": true,
+	"-f": true, "--file": true,
+	"-l": true, "--line-length": true,
+}, attachedValue: map[string]bool{
+	// Backup suffix
+	"-i": true,
+}}
+
+// SedSubstitution is a sed s command.
+type SedSubstitution struct {
+	// Pattern matches the text to replace.
+	Pattern *regexp.Regexp
+
+	// Replacement is the replacement in regexp.Expand template syntax.
+	Replacement string
+
+	// Global replaces every match from the Occurrence-th on (the g flag).
+	Global bool
+
+	// Occurrence is the match replaced, starting at 1.
+	Occurrence int
+}
+
+// InPlaceEdit is the edit an in-place editor applies to each line of a file.
+type InPlaceEdit struct {
+	Substitutions []SedSubstitution
+}
+
+// Apply returns the content after the edit.
+func (e *InPlaceEdit) Apply(content string) string {
+	lines := strings.SplitAfter(content, "\n")
+
+	var sb strings.Builder
+
+	for _, line := range lines {
+		text, newline := strings.CutSuffix(line, "\n")
+
+		for _, sub := range e.Substitutions {
+			text = sub.apply(text)
+		}
+
+		sb.WriteString(text)
+
+		if newline {
+			sb.WriteByte('\n')
+		}
+	}
+
+	return sb.String()
+}
+
+// apply returns the line after the substitution.
+func (s *SedSubstitution) apply(line string) string {
+	matches := s.Pattern.FindAllStringSubmatchIndex(line, -1)
+
+	var result []byte
+
+	last := 0
+
+	for i, match := range matches {
+		n := i + 1
+		if n < s.Occurrence || (n > s.Occurrence && !s.Global) {
+			continue
+		}
+
+		result = append(result, line[last:match[0]]...)
+		result = s.Pattern.ExpandString(result, s.Replacement, line, match)
+		last = match[1]
+	}
+
+	return string(append(result, line[last:]...))
+}
+
+// detectSedWrites detects the files sed -i edits in place, with the edit
+// when the script only has substitutions.
+func detectSedWrites(cmd Command) []FileWrite {
+	var (
+		inPlace, extended, scriptFile, unsupported bool
+		scripts                                    []string
+	)
+
+	operands := sedSpec.scan(cmd.Args, func(name, value string) {
+		switch name {
+		case "-i", "--in-place":
+			inPlace = true
+		case "-E", "-r", "--regexp-extended":
+			extended = true
+		case "-e", "--expression":
+			scripts = append(scripts, value)
+		case "-f", "--file":
+			scriptFile = true
+		case "-n", "--quiet", "--silent", "-z", "--null-data":
+			unsupported = true
+		}
+	})
+
+	if !inPlace {
+		return nil
+	}
+
+	// GNU sed attaches the backup suffix (-i.bak), BSD sed takes it as the
+	// next argument, which is empty for no backup (-i ''). Without -e, an
+	// argument starting with "." cannot be the script, so it is a suffix
+	// (-i .bak)
+	if len(operands) > 0 &&
+		(operands[0] == "" || (len(scripts) == 0 && strings.HasPrefix(operands[0], "."))) {
+		operands = operands[1:]
+	}
+
+	if len(scripts) == 0 && !scriptFile && len(operands) > 0 {
+		scripts = []string{operands[0]}
+		operands = operands[1:]
+	}
+
+	var edit *InPlaceEdit
+
+	if !unsupported && !scriptFile {
+		if parsed, err := parseSedScript(strings.Join(scripts, "\n"), extended); err == nil {
+			edit = parsed
+		}
+	}
+
+	writes := writesTo(WriteOpInPlace, operands...)
+	for i := range writes {
+		writes[i].Edit = edit
+	}
+
+	return writes
+}
+
+// parseSedScript parses a sed script made of s commands only.
+func parseSedScript(script string, extended bool) (*InPlaceEdit, error) {
+	edit := &InPlaceEdit{}
+	rest := script
+
+	for {
+		rest = strings.TrimLeft(rest, " \t\n;")
+		if rest == "" {
+			break
+		}
+
+		if rest[0] != 's' || len(rest) < 2 {
+			return nil, errors.Wrapf(errUnsupportedSed, "command %q", rest[:1])
+		}
+
+		sub, remaining, err := parseSedSubstitution(rest[1:], extended)
+		if err != nil {
+			return nil, err
+		}
+
+		edit.Substitutions = append(edit.Substitutions, sub)
+		rest = remaining
+	}
+
+	if len(edit.Substitutions) == 0 {
+		return nil, errors.Wrap(errUnsupportedSed, "empty script")
+	}
+
+	return edit, nil
+}
+
+// parseSedSubstitution parses an s command after the "s", returning the
+// rest of the script.
+func parseSedSubstitution(script string, extended bool) (SedSubstitution, string, error) {
+	sub := SedSubstitution{Occurrence: 1}
+
+	delim := script[0]
+	if delim == '\\' || delim == '\n' {
+		return sub, "", errors.Wrap(errUnsupportedSed, "invalid delimiter")
+	}
+
+	pattern, rest, ok := cutSedPart(script[1:], delim)
+	if !ok {
+		return sub, "", errors.Wrap(errUnsupportedSed, "unterminated regex")
+	}
+
+	replacement, rest, ok := cutSedPart(rest, delim)
+	if !ok {
+		return sub, "", errors.Wrap(errUnsupportedSed, "unterminated replacement")
+	}
+
+	ignoreCase := false
+	occurrence := 0
+
+	for rest != "" && !strings.ContainsRune(";\n}", rune(rest[0])) {
+		switch c := rest[0]; {
+		case c == 'g':
+			sub.Global = true
+		case c == 'i' || c == 'I':
+			ignoreCase = true
+		case c == 'p' || c == ' ' || c == '\t':
+			// Printing only matters with -n, which is not supported
+		case c >= '0' && c <= '9':
+			occurrence = occurrence*10 + int(c-'0')
+		default:
+			return sub, "", errors.Wrapf(errUnsupportedSed, "flag %q", c)
+		}
+
+		rest = rest[1:]
+	}
+
+	if occurrence > 0 {
+		sub.Occurrence = occurrence
+	}
+
+	if pattern == "" {
+		return sub, "", errors.Wrap(errUnsupportedSed, "empty regex")
+	}
+
+	expr, err := sedRegexp(pattern, extended)
+	if err != nil {
+		return sub, "", err
+	}
+
+	if ignoreCase {
+		expr = "(?i)" + expr
+	}
+
+	if sub.Pattern, err = regexp.Compile(expr); err != nil {
+		return sub, "", errors.Wrap(errUnsupportedSed, err.Error())
+	}
+
+	if sub.Replacement, err = sedReplacement(replacement); err != nil {
+		return sub, "", err
+	}
+
+	return sub, rest, nil
+}
+
+// cutSedPart returns the part of an s command before the unescaped
+// delimiter, with escaped delimiters unescaped, and the rest after it.
+func cutSedPart(s string, delim byte) (part, rest string, found bool) {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == delim:
+			return sb.String(), s[i+1:], true
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == delim:
+			sb.WriteByte(delim)
+			i++
+		case s[i] == '\\' && i+1 < len(s):
+			sb.WriteString(s[i : i+2])
+			i++
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+
+	return "", "", false
+}
+
+// breSpecial are the characters that are special in basic regular
+// expressions only when escaped, and in extended ones only unescaped.
+const breSpecial = "(){}|+?"
+
+// sedRegexp translates a POSIX basic or extended regular expression to Go
+// syntax. Back-references and GNU word boundaries are not supported.
+func sedRegexp(pattern string, extended bool) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+
+		switch {
+		case c == '[':
+			end := bracketEnd(pattern, i)
+			if end < 0 {
+				return "", errors.Wrap(errUnsupportedSed, "unterminated bracket expression")
+			}
+
+			// Backslashes are literal in POSIX bracket expressions
+			sb.WriteString(strings.ReplaceAll(pattern[i:end+1], `\`, `\\`))
+			i = end
+
+		case c == '\\' && i+1 < len(pattern):
+			i++
+
+			next := pattern[i]
+
+			switch {
+			case next >= '1' && next <= '9', next == '<', next == '>', next == '`', next == '\'':
+				return "", errors.Wrapf(errUnsupportedSed, "escape \\%c", next)
+			case next == 'n':
+				sb.WriteString(`\n`)
+			case next == 't':
+				sb.WriteString(`\t`)
+			case strings.IndexByte("wWsSbB", next) >= 0:
+				sb.WriteByte('\\')
+				sb.WriteByte(next)
+			case !extended && strings.IndexByte(breSpecial, next) >= 0:
+				sb.WriteByte(next)
+			default:
+				sb.WriteString(regexp.QuoteMeta(string(next)))
+			}
+
+		case !extended && strings.IndexByte(breSpecial, c) >= 0:
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// bracketEnd returns the index of the "]" closing the bracket expression
+// starting at start, or -1.
+func bracketEnd(pattern string, start int) int {
+	i := start + 1
+	if i < len(pattern) && pattern[i] == '^' {
+		i++
+	}
+
+	// A leading "]" is literal
+	if i < len(pattern) && pattern[i] == ']' {
+		i++
+	}
+
+	for ; i < len(pattern); i++ {
+		switch {
+		case pattern[i] == '[' && i+1 < len(pattern) && strings.IndexByte(":.=", pattern[i+1]) >= 0:
+			// Character classes like [:alpha:]
+			end := strings.Index(pattern[i+2:], string(pattern[i+1])+"]")
+			if end < 0 {
+				return -1
+			}
+
+			i += end + 3
+		case pattern[i] == ']':
+			return i
+		}
+	}
+
+	return -1
+}
+
+// sedReplacement translates the replacement of an s command to
+// regexp.Expand template syntax. GNU case conversions are not supported.
+func sedReplacement(replacement string) (string, error) {
+	var sb strings.Builder
+
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+
+		switch {
+		case c == '&':
+			sb.WriteString("${0}")
+		case c == '$':
+			sb.WriteString("$$")
+		case c == '\\' && i+1 < len(replacement):
+			i++
+
+			switch next := replacement[i]; {
+			case next >= '0' && next <= '9':
+				sb.WriteString("${" + string(next) + "}")
+			case next == 'n':
+				sb.WriteByte('\n')
+			case next == 't':
+				sb.WriteByte('\t')
+			case strings.IndexByte("LUluE", next) >= 0:
+				return "", errors.Wrapf(errUnsupportedSed, "case conversion \\%c", next)
+			case next == '$':
+				sb.WriteString("$$")
+			default:
+				sb.WriteByte(next)
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+
+	return sb.String(), nil
+}
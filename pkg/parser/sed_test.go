@@ -0,0 +1,41 @@
+package parser_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+var _ = Describe("InPlaceEdit", func() {
+	// edit returns the edit of a sed -i command.
+	edit := func(command string) *parser.InPlaceEdit {
+		result, err := parser.NewBashParser().Parse(command)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.FileWrites).To(HaveLen(1))
+		Expect(result.FileWrites[0].Edit).NotTo(BeNil())
+
+		return result.FileWrites[0].Edit
+	}
+
+	DescribeTable("applies sed substitutions line by line",
+		func(command, content, expected string) {
+			Expect(edit(command).Apply(content)).To(Equal(expected))
+		},
+		Entry("first match per line", "sed -i 's/a/b/' f", "aa\naa\n", "ba\nba\n"),
+		Entry("global", "sed -i 's/a/b/g' f", "aa\naa", "bb\nbb"),
+		Entry("nth match", "sed -i 's/a/b/2' f", "aaa\n", "aba\n"),
+		Entry("nth match and later", "sed -i 's/a/b/2g' f", "aaa\n", "abb\n"),
+		Entry("ignore case", "sed -i 's/a/b/gI' f", "aA\n", "bb\n"),
+		Entry("whole match", "sed -i 's/[0-9][0-9]*/<&>/' f", "v12 x\n", "v<12> x\n"),
+		Entry("BRE groups", `sed -i 's/\(foo\)-\(bar\)/\2-\1/' f`, "foo-bar\n", "bar-foo\n"),
+		Entry("BRE literal parentheses", "sed -i 's/f(x)/g(x)/' f", "y = f(x)\n", "y = g(x)\n"),
+		Entry("BRE interval", `sed -i 's/a\{2\}/b/' f`, "aaa\n", "ba\n"),
+		Entry("ERE groups", "sed -E -i 's/(foo)+/bar/' f", "foofoo!\n", "bar!\n"),
+		Entry("escaped delimiter", `sed -i 's/\/usr/\/opt/' f`, "/usr/bin\n", "/opt/bin\n"),
+		Entry("character class", "sed -i 's/[[:space:]]*$//' f", "trailing  \n", "trailing\n"),
+		Entry("dollar in replacement", "sed -i 's/price/$5/' f", "price\n", "$5\n"),
+		Entry("anchors", "sed -i 's/^# //' f", "# Title\ntext # x\n", "Title\ntext # x\n"),
+		Entry("BSD backup suffix", "sed -i .bak 's/a/b/' f", "aa\n", "ba\n"),
+	)
+})
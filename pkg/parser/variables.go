@@ -0,0 +1,315 @@
+package parser
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// walkFrame is a node being walked, with the shell state to restore or
+// forget when leaving it.
+type walkFrame struct {
+	node syntax.Node
+
+	// saved is the state of the shell running a construct run by a child
+	// shell (subshells, substitutions and pipeline stages), restored on
+	// leaving it
+	saved *shellState
+
+	// conditional is set for constructs that may not run or run later
+	// (branches, loops and function bodies), whose assignments are forgotten
+	// on leaving them; before holds the variables on entering them
+	conditional bool
+	before      map[string]string
+}
+
+// shellState is the state of a shell tracked by the walker.
+type shellState struct {
+	vars       map[string]string
+	exported   map[string]bool
+	currentDir string
+	dirStack   []string
+}
+
+// enter starts walking a node, giving constructs run by a child shell their
+// own copy of the shell state.
+func (w *astWalker) enter(node syntax.Node) {
+	frame := walkFrame{node: node}
+
+	switch n := node.(type) {
+	case *syntax.Subshell, *syntax.CmdSubst, *syntax.ProcSubst:
+		frame.saved = w.fork()
+	case *syntax.IfClause, *syntax.WhileClause, *syntax.ForClause, *syntax.CaseClause, *syntax.FuncDecl:
+		frame.conditional = true
+		frame.before = maps.Clone(w.vars)
+	case *syntax.Stmt:
+		// Pipeline stages run in child shells, and the right-hand side of
+		// && and || only runs depending on the left-hand side
+		if parent, ok := w.parent().(*syntax.BinaryCmd); ok {
+			switch {
+			case parent.Op == syntax.Pipe || parent.Op == syntax.PipeAll:
+				frame.saved = w.fork()
+			case n == parent.Y:
+				frame.conditional = true
+				frame.before = maps.Clone(w.vars)
+			}
+		}
+	}
+
+	w.frames = append(w.frames, frame)
+}
+
+// leave finishes walking the innermost node, restoring the shell state saved
+// on entering it, or forgetting the variables assigned in it.
+func (w *astWalker) leave() {
+	n := len(w.frames)
+	if n == 0 {
+		return
+	}
+
+	frame := w.frames[n-1]
+	w.frames = w.frames[:n-1]
+
+	if frame.saved != nil {
+		w.vars = frame.saved.vars
+		w.exported = frame.saved.exported
+		w.currentDir = frame.saved.currentDir
+		w.dirStack = frame.saved.dirStack
+	}
+
+	if frame.conditional {
+		for name, value := range w.vars {
+			if prev, ok := frame.before[name]; !ok || prev != value {
+				w.unset(name)
+			}
+		}
+	}
+}
+
+// parent returns the node containing the node being entered, or nil.
+//
+//nolint:ireturn // syntax nodes are interfaces
+func (w *astWalker) parent() syntax.Node {
+	if len(w.frames) == 0 {
+		return nil
+	}
+
+	return w.frames[len(w.frames)-1].node
+}
+
+// fork gives the walker a copy of the shell state, as seen by a child shell,
+// and returns the state to restore when the child shell exits.
+func (w *astWalker) fork() *shellState {
+	saved := &shellState{
+		vars:       w.vars,
+		exported:   w.exported,
+		currentDir: w.currentDir,
+		dirStack:   w.dirStack,
+	}
+
+	w.vars = maps.Clone(w.vars)
+	w.exported = maps.Clone(w.exported)
+	w.dirStack = slices.Clone(w.dirStack)
+
+	return saved
+}
+
+// setEnvironment sets the exported variables the walked script starts with.
+func (w *astWalker) setEnvironment(env map[string]string) {
+	if len(env) == 0 {
+		return
+	}
+
+	w.vars = maps.Clone(env)
+	w.exported = make(map[string]bool, len(env))
+
+	for name := range env {
+		w.exported[name] = true
+	}
+}
+
+// lookup returns the constant value of a shell variable, if it is known.
+func (w *astWalker) lookup(name string) (string, bool) {
+	value, ok := w.vars[name]
+
+	return value, ok
+}
+
+// wordValue returns the value of a word with the variables known so far,
+// and whether all its expansions were resolved.
+func (w *astWalker) wordValue(word *syntax.Word) (string, bool) {
+	return wordValue(word, w.lookup)
+}
+
+// wordValues returns the non-empty values of words, and whether all their
+// expansions were resolved.
+func (w *astWalker) wordValues(words []*syntax.Word) ([]string, bool) {
+	values := make([]string, 0, len(words))
+	resolved := true
+
+	for _, word := range words {
+		value, ok := w.wordValue(word)
+		resolved = resolved && ok
+
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values, resolved
+}
+
+// assignValue returns the value an assignment gives its variable, and
+// whether it is known. Array and indexed assignments are not tracked.
+func (w *astWalker) assignValue(assign *syntax.Assign) (string, bool) {
+	if assign.Array != nil || assign.Index != nil {
+		return "", false
+	}
+
+	value, ok := w.wordValue(assign.Value)
+	if !ok || !assign.Append {
+		return value, ok
+	}
+
+	prev, known := w.lookup(assign.Name.Value)
+
+	return prev + value, known
+}
+
+// envValues returns the known values of the environment assignments of a
+// command, and whether all values are known.
+func (w *astWalker) envValues(assigns []*syntax.Assign) (map[string]string, bool) {
+	if len(assigns) == 0 {
+		return nil, true
+	}
+
+	env := make(map[string]string, len(assigns))
+	resolved := true
+
+	for _, assign := range assigns {
+		if assign.Name == nil {
+			continue
+		}
+
+		value, ok := w.assignValue(assign)
+		if !ok {
+			resolved = false
+
+			continue
+		}
+
+		env[assign.Name.Value] = value
+	}
+
+	return env, resolved
+}
+
+// assign sets the variables of assignments, forgetting the ones assigned
+// values that are not known.
+func (w *astWalker) assign(assigns []*syntax.Assign) {
+	for _, assign := range assigns {
+		if assign.Name == nil || assign.Naked {
+			continue
+		}
+
+		value, ok := w.assignValue(assign)
+		if !ok {
+			w.unset(assign.Name.Value)
+
+			continue
+		}
+
+		if w.vars == nil {
+			w.vars = make(map[string]string)
+		}
+
+		w.vars[assign.Name.Value] = value
+	}
+}
+
+// unset forgets the value of a variable.
+func (w *astWalker) unset(name string) {
+	delete(w.vars, name)
+}
+
+// declare handles the assignments of export, declare, local, readonly and
+// typeset, marking the variables of export as exported.
+func (w *astWalker) declare(decl *syntax.DeclClause) {
+	w.assign(decl.Args)
+
+	if decl.Variant == nil || decl.Variant.Value != "export" {
+		return
+	}
+
+	for _, assign := range decl.Args {
+		if assign.Name == nil {
+			continue
+		}
+
+		if w.exported == nil {
+			w.exported = make(map[string]bool)
+		}
+
+		w.exported[assign.Name.Value] = true
+	}
+}
+
+// trackBuiltin updates the working directory and variables changed by the
+// cd, pushd, popd, unset and read builtins. Changing the directory changes
+// PWD, whose value is then no longer known.
+func (w *astWalker) trackBuiltin(cmd Command) {
+	switch cmd.Name {
+	case "cd":
+		if len(cmd.Args) > 0 {
+			w.currentDir = cmd.Args[0]
+		}
+
+		w.unset("PWD")
+	case "pushd":
+		// pushd without a directory or with +N/-N rotates the stack
+		if len(cmd.Args) > 0 && !strings.HasPrefix(cmd.Args[0], "+") && !strings.HasPrefix(cmd.Args[0], "-") {
+			w.dirStack = append(w.dirStack, w.currentDir)
+			w.currentDir = cmd.Args[0]
+		}
+
+		w.unset("PWD")
+	case "popd":
+		if n := len(w.dirStack); n > 0 {
+			w.currentDir = w.dirStack[n-1]
+			w.dirStack = w.dirStack[:n-1]
+		}
+
+		w.unset("PWD")
+	case "unset", "read":
+		for _, arg := range cmd.Args {
+			if !strings.HasPrefix(arg, "-") {
+				w.unset(arg)
+			}
+		}
+	}
+}
+
+// childVariables returns the variables of a child shell run by a command:
+// the exported variables and the environment assignments of the command.
+func (w *astWalker) childVariables(cmd Command) (vars map[string]string, exported map[string]bool) {
+	vars = make(map[string]string, len(w.exported)+len(cmd.Env))
+	exported = make(map[string]bool, len(w.exported)+len(cmd.Env))
+
+	for name := range w.exported {
+		exported[name] = true
+
+		if value, ok := w.vars[name]; ok {
+			vars[name] = value
+		}
+	}
+
+	maps.Copy(vars, cmd.Env)
+
+	for name := range cmd.Env {
+		exported[name] = true
+	}
+
+	return vars, exported
+}
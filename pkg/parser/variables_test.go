@@ -0,0 +1,171 @@
+package parser_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+var _ = Describe("Shell variables", func() {
+	// last returns the last command named name of a command.
+	last := func(command, name string) parser.Command {
+		result, err := parser.NewBashParser().Parse(command)
+		Expect(err).NotTo(HaveOccurred())
+
+		commands := result.GetCommands(name)
+		Expect(commands).NotTo(BeEmpty())
+
+		return commands[len(commands)-1]
+	}
+
+	DescribeTable("resolves arguments from assignments",
+		func(command, expected string) {
+			cmd := last(command, "git")
+			Expect(cmd.String()).To(Equal(expected))
+			Expect(cmd.Unresolved).To(BeFalse())
+		},
+		Entry("assignment", "BRANCH=main; git push origin $BRANCH", "git push origin main"),
+		Entry("braces and quotes", `B=main && git push origin "${B}"`, "git push origin main"),
+		Entry("several assignments", "R=origin B=main; git push $R $B", "git push origin main"),
+		Entry("assignment from variables", "B=main; REF=refs/heads/$B; git push origin $REF:$REF",
+			"git push origin refs/heads/main:refs/heads/main"),
+		Entry("append", "F=--force; F+=-with-lease; git push $F", "git push --force-with-lease"),
+		Entry("export", "export BRANCH=main; git push origin $BRANCH", "git push origin main"),
+		Entry("declare", "declare -r B=main; git push origin $B", "git push origin main"),
+		Entry("reassignment", "B=dev; B=main; git push origin $B", "git push origin main"),
+		Entry("single quotes", `B=main; git commit -m '$B'`, "git commit -m $B"),
+		Entry("assignment in a subshell", "B=main; (B=feat); git push origin $B", "git push origin main"),
+		Entry("assignment in a command substitution", "B=main; echo $(B=feat); git push origin $B",
+			"git push origin main"),
+		Entry("assignment in a pipeline stage", "B=main; echo | B=feat; git push origin $B",
+			"git push origin main"),
+		Entry("unchanged in a branch", "B=main; [ -n x ] && B=main; git push origin $B", "git push origin main"),
+		Entry("assignment used in its branch", "if true; then B=main; git push origin $B; fi",
+			"git push origin main"),
+	)
+
+	DescribeTable("marks unresolved expansions",
+		func(command, expected string) {
+			cmd := last(command, "git")
+			Expect(cmd.String()).To(Equal(expected))
+			Expect(cmd.Unresolved).To(BeTrue())
+		},
+		Entry("unknown variable", "git push origin $BRANCH", "git push origin"),
+		Entry("command substitution", "git push origin $(cat branch.txt)", "git push origin"),
+		Entry("assigned command substitution", "B=$(date); git push origin $B", "git push origin"),
+		Entry("unset", "B=main; unset B; git push origin $B", "git push origin"),
+		Entry("read", "B=main; read -r B; git push origin $B", "git push origin"),
+		Entry("loop variable", "B=main; for B in a b; do git push origin $B; done", "git push origin"),
+		Entry("default value", "git push origin ${B:-main}", "git push origin"),
+		Entry("prefix assignment", "B=main git push origin $B", "git push origin"),
+		Entry("partly known", "D=x; git add $D/$F", "git add x/"),
+		Entry("assignment in a function body", "B=main; f(){ B=feat; }; f; git push origin $B",
+			"git push origin"),
+		Entry("assignment after &&", "B=main; [ -n x ] && B=feat; git push origin $B", "git push origin"),
+		Entry("assignment after ||", "B=main; false || B=feat; git push origin $B", "git push origin"),
+		Entry("assignment in an if branch", "B=main; if true; then B=feat; fi; git push origin $B",
+			"git push origin"),
+		Entry("assignment in an else branch", "B=main; if false; then :; else B=feat; fi; git push origin $B",
+			"git push origin"),
+		Entry("assignment in a loop", "B=main; while true; do B=feat; done; git push origin $B",
+			"git push origin"),
+		Entry("assignment in a case branch", "B=main; case x in x) B=feat;; esac; git push origin $B",
+			"git push origin"),
+		Entry("unset in a branch", "B=main; [ -n x ] && unset B; git push origin $B", "git push origin"),
+	)
+
+	It("records the environment assignments of commands", func() {
+		cmd := last("B=main; GIT_DIR=/repo/$B GIT_TRACE=1 git push", "git")
+
+		Expect(cmd.Env).To(Equal(map[string]string{"GIT_DIR": "/repo/main", "GIT_TRACE": "1"}))
+		Expect(cmd.Unresolved).To(BeFalse())
+	})
+
+	It("resolves file write paths and heredoc content", func() {
+		result, err := parser.NewBashParser().Parse(
+			"F=README.md; V=1.2; cat > \"$F\" <<EOF\nversion $V\nEOF\ncp a.txt $F.bak")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.FileWrites).To(HaveLen(2))
+		Expect(result.FileWrites[0].Path).To(Equal("README.md"))
+		Expect(result.FileWrites[0].Content).To(Equal("version 1.2\n"))
+		Expect(result.FileWrites[1].Path).To(Equal("README.md.bak"))
+	})
+
+	It("resolves working directories", func() {
+		cmd := last("DIR=/src; cd $DIR && git status", "git")
+
+		Expect(cmd.WorkingDirectory).To(Equal("/src"))
+	})
+
+	It("does not leak the working directory of subshells", func() {
+		result, err := parser.NewBashParser().Parse("cd /a && (cd /b && git status) && git push")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.GitOperations).To(HaveLen(2))
+		Expect(result.GitOperations[0].WorkingDirectory).To(Equal("/b"))
+		Expect(result.GitOperations[1].WorkingDirectory).To(Equal("/a"))
+	})
+
+	It("tracks pushd and popd", func() {
+		result, err := parser.NewBashParser().Parse("cd /a && pushd /b && git status && popd && git push")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.GitOperations).To(HaveLen(2))
+		Expect(result.GitOperations[0].WorkingDirectory).To(Equal("/b"))
+		Expect(result.GitOperations[1].WorkingDirectory).To(Equal("/a"))
+	})
+
+	Describe("environment", func() {
+		parse := func(command string) *parser.ParseResult {
+			result, err := parser.NewBashParser(
+				parser.WithEnvironment(map[string]string{"HOME": "/home/dev", "PWD": "/repo"}),
+			).Parse(command)
+			Expect(err).NotTo(HaveOccurred())
+
+			return result
+		}
+
+		It("resolves environment variables", func() {
+			cmd := parse(`rm -rf "$HOME"/.cache $PWD/build`).Commands[0]
+
+			Expect(cmd.String()).To(Equal("rm -rf /home/dev/.cache /repo/build"))
+			Expect(cmd.Unresolved).To(BeFalse())
+		})
+
+		It("passes environment variables to child shells", func() {
+			cmds := parse(`bash -c 'rm -rf $HOME'`).GetCommands("rm")
+
+			Expect(cmds).To(HaveLen(1))
+			Expect(cmds[0].String()).To(Equal("rm -rf /home/dev"))
+		})
+
+		It("forgets PWD after changing the directory", func() {
+			cmd := parse(`cd /srv && rm -rf "$PWD"/cache`).GetCommands("rm")[0]
+
+			Expect(cmd.Unresolved).To(BeTrue())
+		})
+	})
+
+	Describe("nested scripts", func() {
+		It("passes exported variables and the environment to child shells", func() {
+			cmd := last(`export R=origin; B=main; T=v1 bash -c 'git push $R $B $T'`, "git")
+
+			Expect(cmd.String()).To(Equal("git push origin v1"))
+			Expect(cmd.Unresolved).To(BeTrue())
+		})
+
+		It("passes env assignments to child shells", func() {
+			cmd := last(`env B=main sh -c 'git push origin "$B"'`, "git")
+
+			Expect(cmd.String()).To(Equal("git push origin main"))
+		})
+
+		It("shares variables with eval", func() {
+			cmd := last(`B=main; eval 'R=origin'; git push $R $B`, "git")
+
+			Expect(cmd.String()).To(Equal("git push origin main"))
+		})
+	})
+})
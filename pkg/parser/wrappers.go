@@ -0,0 +1,457 @@
+package parser
+
+import (
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"mvdan.cc/sh/v3/syntax"
+)
+
+// wrappedCommand is a command run by a wrapper command, given as arguments
+// (sudo git push) or as a shell script (bash -c 'git push').
+type wrappedCommand struct {
+	// wrapper names the wrapper in the wrapper chain, e.g. "sudo" or "bash -c"
+	wrapper string
+
+	// args are the command and its arguments, for commands given as arguments
+	args []string
+
+	// script is the shell script, for commands given as a script
+	script string
+
+	// dir is the working directory the wrapper runs the command in, if it
+	// changes it (env -C DIR)
+	dir string
+
+	// env holds the environment assignments of the wrapper (env FOO=1 cmd)
+	env map[string]string
+
+	// sameShell is set for scripts run by the current shell (eval), whose
+	// cd commands change the working directory of later commands
+	sameShell bool
+}
+
+// commandUnwrapper returns the commands a wrapper command runs.
+type commandUnwrapper func(cmd Command) []wrappedCommand
+
+// commandUnwrappers are the command unwrappers by command name.
+var commandUnwrappers = map[string]commandUnwrapper{
+	"sudo":    unwrapPrefix(sudoSpec, true),
+	"doas":    unwrapPrefix(doasSpec, false),
+	"env":     unwrapEnv,
+	"timeout": unwrapTimeout,
+	"nohup":   unwrapPrefix(optionSpec{}, false),
+	"nice":    unwrapPrefix(niceSpec, false),
+	"ionice":  unwrapPrefix(ioniceSpec, false),
+	"time":    unwrapPrefix(timeSpec, false),
+	"stdbuf":  unwrapPrefix(stdbufSpec, false),
+	"command": unwrapCommandBuiltin,
+	"exec":    unwrapPrefix(execSpec, false),
+	"xargs":   unwrapPrefix(xargsSpec, false),
+	"find":    unwrapFindExec,
+	"eval":    unwrapEval,
+	"su":      unwrapSu,
+	"sh":      unwrapShell,
+	"bash":    unwrapShell,
+	"dash":    unwrapShell,
+	"zsh":     unwrapShell,
+	"ksh":     unwrapShell,
+	"mksh":    unwrapShell,
+	"ash":     unwrapShell,
+}
+
+// unwrapCommand records the commands a wrapper command runs, with the
+// wrapper appended to their wrapper chain. Scripts are parsed up to the
+// maximum nesting depth.
+func (w *astWalker) unwrapCommand(cmd Command) {
+	unwrapper, ok := commandUnwrappers[path.Base(cmd.Name)]
+	if !ok {
+		return
+	}
+
+	for _, wrapped := range unwrapper(cmd) {
+		inner := cmd
+		inner.Wrappers = append(slices.Clone(cmd.Wrappers), wrapped.wrapper)
+
+		if wrapped.dir != "" {
+			inner.WorkingDirectory = wrapped.dir
+		}
+
+		if len(wrapped.env) > 0 {
+			inner.Env = maps.Clone(cmd.Env)
+			if inner.Env == nil {
+				inner.Env = make(map[string]string, len(wrapped.env))
+			}
+
+			maps.Copy(inner.Env, wrapped.env)
+		}
+
+		if wrapped.script != "" {
+			w.walkScript(inner, wrapped.script, wrapped.sameShell)
+
+			continue
+		}
+
+		if len(wrapped.args) == 0 || wrapped.args[0] == "" {
+			continue
+		}
+
+		inner.Name = wrapped.args[0]
+		inner.Args = wrapped.args[1:]
+
+		w.addCommand(inner)
+	}
+}
+
+// walkScript walks a shell script run by a command, recording its commands
+// and file operations as run by the command. Scripts run by a child shell see
+// the exported variables; scripts run by the current shell (eval) share its
+// variables and directory. Scripts that cannot be parsed are skipped.
+func (w *astWalker) walkScript(outer Command, script string, sameShell bool) {
+	if w.depth >= w.maxDepth {
+		w.depthExceeded = true
+
+		return
+	}
+
+	file, err := syntax.NewParser().Parse(strings.NewReader(script), "")
+	if err != nil {
+		return
+	}
+
+	nested := &astWalker{
+		commands:   make([]Command, 0),
+		fileWrites: make([]FileWrite, 0),
+		fileReads:  make([]FileRead, 0),
+		currentDir: outer.WorkingDirectory,
+		outer:      &outer,
+		depth:      w.depth + 1,
+		maxDepth:   w.maxDepth,
+		vars:       w.vars,
+		exported:   w.exported,
+		dirStack:   w.dirStack,
+	}
+
+	if !sameShell {
+		nested.vars, nested.exported = w.childVariables(outer)
+		nested.dirStack = nil
+	}
+
+	syntax.Walk(file, nested.visit)
+
+	w.commands = append(w.commands, nested.commands...)
+	w.fileWrites = append(w.fileWrites, nested.fileWrites...)
+	w.fileReads = append(w.fileReads, nested.fileReads...)
+	w.depthExceeded = w.depthExceeded || nested.depthExceeded
+
+	// Scripts run by the current shell change its state
+	if sameShell {
+		w.currentDir = nested.currentDir
+		w.vars = nested.vars
+		w.exported = nested.exported
+		w.dirStack = nested.dirStack
+	}
+}
+
+// scanUntilOperand calls option for each option of the arguments before the
+// first operand, like optionSpec.scan, and returns the index of the first
+// operand. Options starting with "+" (bash +o) are scanned like options
+// starting with "-".
+func (s optionSpec) scanUntilOperand(args []string, option func(name, value string)) int {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			return i + 1
+
+		case strings.HasPrefix(arg, "--"):
+			name, value, attached := strings.Cut(arg, "=")
+			if !attached && s.withValue[name] && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+
+			option(name, value)
+
+		case len(arg) > 1 && (arg[0] == '-' || arg[0] == '+'):
+			for j := 1; j < len(arg); j++ {
+				name := arg[:1] + arg[j:j+1]
+				if s.attachedValue[name] {
+					option(name, arg[j+1:])
+
+					break
+				}
+
+				if !s.withValue[name] {
+					option(name, "")
+
+					continue
+				}
+
+				value := arg[j+1:]
+				if value == "" && i+1 < len(args) {
+					i++
+					value = args[i]
+				}
+
+				option(name, value)
+
+				break
+			}
+
+		default:
+			return i
+		}
+	}
+
+	return len(args)
+}
+
+// firstOperand returns the index of the first operand of the arguments.
+func (s optionSpec) firstOperand(args []string) int {
+	return s.scanUntilOperand(args, func(string, string) {})
+}
+
+// skipAssignments returns the environment variable assignments (NAME=VALUE)
+// of the arguments from start, and the index of the first argument after
+// them.
+func skipAssignments(args []string, start int) (map[string]string, int) {
+	var env map[string]string
+
+	for ; start < len(args) && isAssignment(args[start]); start++ {
+		if env == nil {
+			env = make(map[string]string)
+		}
+
+		name, value, _ := strings.Cut(args[start], "=")
+		env[name] = value
+	}
+
+	return env, start
+}
+
+// isAssignment reports whether an argument is an environment variable
+// assignment (NAME=VALUE).
+func isAssignment(arg string) bool {
+	name, _, found := strings.Cut(arg, "=")
+
+	return found && syntax.ValidName(name)
+}
+
+// unwrapPrefix returns an unwrapper of wrappers running the command given by
+// their first operand, optionally preceded by environment assignments.
+func unwrapPrefix(spec optionSpec, assignments bool) commandUnwrapper {
+	return func(cmd Command) []wrappedCommand {
+		var env map[string]string
+
+		start := spec.firstOperand(cmd.Args)
+		if assignments {
+			env, start = skipAssignments(cmd.Args, start)
+		}
+
+		return []wrappedCommand{{wrapper: path.Base(cmd.Name), args: cmd.Args[start:], env: env}}
+	}
+}
+
+// sudoSpec are the sudo options taking a value.
+var sudoSpec = optionSpec{withValue: map[string]bool{
+	"-u": true, "--user": true,
+	"-g": true, "--group": true,
+	"-h": true, "--host": true,
+	"-p": true, "--prompt": true,
+	"-C": true, "--close-from": true,
+	"-D": true, "--chdir": true,
+	"-r": true, "--role": true,
+	"-t": true, "--type": true,
+	"-T": true, "--command-timeout": true,
+	"-U": true, "--other-user": true,
+}}
+
+// doasSpec are the doas options taking a value.
+var doasSpec = optionSpec{withValue: map[string]bool{"-u": true, "-C": true}}
+
+// niceSpec are the nice options taking a value.
+var niceSpec = optionSpec{withValue: map[string]bool{"-n": true, "--adjustment": true}}
+
+// ioniceSpec are the ionice options taking a value.
+var ioniceSpec = optionSpec{withValue: map[string]bool{
+	"-c": true, "--class": true,
+	"-n": true, "--classdata": true,
+}}
+
+// timeSpec are the time options taking a value.
+var timeSpec = optionSpec{withValue: map[string]bool{
+	"-f": true, "--format": true,
+	"-o": true, "--output": true,
+}}
+
+// stdbufSpec are the stdbuf options taking a value.
+var stdbufSpec = optionSpec{withValue: map[string]bool{
+	"-i": true, "--input": true,
+	"-o": true, "--output": true,
+	"-e": true, "--error": true,
+}}
+
+// execSpec are the exec options taking a value.
+var execSpec = optionSpec{withValue: map[string]bool{"-a": true}}
+
+// xargsSpec are the xargs options taking a value.
+var xargsSpec = optionSpec{withValue: map[string]bool{
+	"-a": true, "--arg-file": true,
+	"-d": true, "--delimiter": true,
+	"-E": true,
+	"-I": true,
+	"-L": true, "--max-lines": true,
+	"-n": true, "--max-args": true,
+	"-P": true, "--max-procs": true,
+	"-s": true, "--max-chars": true,
+	"--process-slot-var": true,
+}, attachedValue: map[string]bool{
+	"-e": true, "-i": true, "-l": true,
+}}
+
+// envSpec are the env options taking a value.
+var envSpec = optionSpec{withValue: map[string]bool{
+	"-u": true, "--unset": true,
+	"-C": true, "--chdir": true,
+	"-S": true, "--split-string": true,
+}}
+
+// unwrapEnv returns the command env runs after its options and assignments,
+// in the directory of env -C.
+func unwrapEnv(cmd Command) []wrappedCommand {
+	var dir string
+
+	start := envSpec.scanUntilOperand(cmd.Args, func(name, value string) {
+		if name == "-C" || name == "--chdir" {
+			dir = value
+		}
+	})
+
+	env, start := skipAssignments(cmd.Args, start)
+
+	return []wrappedCommand{{wrapper: "env", args: cmd.Args[start:], dir: dir, env: env}}
+}
+
+// timeoutSpec are the timeout options taking a value.
+var timeoutSpec = optionSpec{withValue: map[string]bool{
+	"-s": true, "--signal": true,
+	"-k": true, "--kill-after": true,
+}}
+
+// unwrapTimeout returns the command timeout runs after its duration.
+func unwrapTimeout(cmd Command) []wrappedCommand {
+	start := timeoutSpec.firstOperand(cmd.Args) + 1
+	if start > len(cmd.Args) {
+		return nil
+	}
+
+	return []wrappedCommand{{wrapper: "timeout", args: cmd.Args[start:]}}
+}
+
+// unwrapCommandBuiltin returns the command the command builtin runs, unless
+// it only describes it (command -v, command -V).
+func unwrapCommandBuiltin(cmd Command) []wrappedCommand {
+	describes := false
+
+	start := optionSpec{}.scanUntilOperand(cmd.Args, func(name, _ string) {
+		describes = describes || name == "-v" || name == "-V"
+	})
+
+	if describes {
+		return nil
+	}
+
+	return []wrappedCommand{{wrapper: "command", args: cmd.Args[start:]}}
+}
+
+// findExecActions are the find actions running a command.
+var findExecActions = []string{"-exec", "-execdir", "-ok", "-okdir"}
+
+// findExecTerminators end the commands of find actions. Escaped semicolons
+// keep their backslash in unquoted words.
+var findExecTerminators = []string{";", `\;`, "+"}
+
+// unwrapFindExec returns the commands of the -exec actions of find, each
+// ending with ";" or "+".
+func unwrapFindExec(cmd Command) []wrappedCommand {
+	var wrapped []wrappedCommand
+
+	for i := 0; i < len(cmd.Args); i++ {
+		action := cmd.Args[i]
+		if !slices.Contains(findExecActions, action) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(cmd.Args) && !slices.Contains(findExecTerminators, cmd.Args[end]) {
+			end++
+		}
+
+		wrapped = append(wrapped, wrappedCommand{
+			wrapper: "find " + action,
+			args:    cmd.Args[i+1 : end],
+		})
+
+		i = end
+	}
+
+	return wrapped
+}
+
+// unwrapEval returns the script eval runs: its arguments joined by spaces.
+func unwrapEval(cmd Command) []wrappedCommand {
+	return []wrappedCommand{{
+		wrapper:   "eval",
+		script:    strings.Join(cmd.Args, " "),
+		sameShell: true,
+	}}
+}
+
+// suSpec are the su options taking a value.
+var suSpec = optionSpec{withValue: map[string]bool{
+	"-c": true, "--command": true,
+	"-s": true, "--shell": true,
+	"-g": true, "--group": true,
+	"-G": true, "--supp-group": true,
+	"-w": true, "--whitelist-environment": true,
+}}
+
+// unwrapSu returns the script of su -c.
+func unwrapSu(cmd Command) []wrappedCommand {
+	var wrapped []wrappedCommand
+
+	suSpec.scan(cmd.Args, func(name, value string) {
+		if name == "-c" || name == "--command" {
+			wrapped = append(wrapped, wrappedCommand{wrapper: "su -c", script: value})
+		}
+	})
+
+	return wrapped
+}
+
+// shellSpec are the shell options taking a value.
+var shellSpec = optionSpec{withValue: map[string]bool{
+	"-o": true, "+o": true,
+	"-O": true, "+O": true,
+	"--rcfile": true, "--init-file": true,
+}}
+
+// unwrapShell returns the script of sh -c and other shells: the first
+// operand after the options, which include -c.
+func unwrapShell(cmd Command) []wrappedCommand {
+	script := false
+
+	start := shellSpec.scanUntilOperand(cmd.Args, func(name, _ string) {
+		script = script || name == "-c"
+	})
+
+	if !script || start >= len(cmd.Args) {
+		return nil
+	}
+
+	return []wrappedCommand{{wrapper: path.Base(cmd.Name) + " -c", script: cmd.Args[start]}}
+}
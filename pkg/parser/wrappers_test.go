@@ -0,0 +1,152 @@
+package parser_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+var _ = Describe("Command wrappers", func() {
+	// wrapped returns the commands of a command that are run by wrappers.
+	wrapped := func(command string, opts ...parser.BashParserOption) []parser.Command {
+		result, err := parser.NewBashParser(opts...).Parse(command)
+		Expect(err).NotTo(HaveOccurred())
+
+		var commands []parser.Command
+
+		for _, cmd := range result.Commands {
+			if cmd.IsWrapped() {
+				commands = append(commands, cmd)
+			}
+		}
+
+		return commands
+	}
+
+	DescribeTable("surfaces the commands wrappers run",
+		func(command, expected string, wrappers []string) {
+			commands := wrapped(command)
+			Expect(commands).To(HaveLen(1))
+			Expect(commands[0].String()).To(Equal(expected))
+			Expect(commands[0].Wrappers).To(Equal(wrappers))
+		},
+		Entry("bash -c", "bash -c 'git push --force'", "git push --force", []string{"bash -c"}),
+		Entry("sh -ec", `sh -ec "git push"`, "git push", []string{"sh -c"}),
+		Entry("bash -c with escaped quotes", `bash -c "git commit -m \"feat: \\\$x\""`,
+			`git commit -m feat: $x`, []string{"bash -c"}),
+		Entry("bash with options", "bash --norc -o pipefail -c 'git push'", "git push", []string{"bash -c"}),
+		Entry("eval", "eval git push origin", "git push origin", []string{"eval"}),
+		Entry("su -c", "su - deploy -c 'git pull'", "git pull", []string{"su -c"}),
+		Entry("sudo", "sudo -u deploy -E GIT_DIR=x git commit -m msg", "git commit -m msg", []string{"sudo"}),
+		Entry("doas", "doas -u root rm -rf /var/cache", "rm -rf /var/cache", []string{"doas"}),
+		Entry("env", "env -i PATH=/bin GIT_TRACE=1 git push", "git push", []string{"env"}),
+		Entry("timeout", "timeout -k 5 60 git push", "git push", []string{"timeout"}),
+		Entry("nohup", "nohup git fetch --all", "git fetch --all", []string{"nohup"}),
+		Entry("nice", "nice -n 10 make build", "make build", []string{"nice"}),
+		Entry("ionice", "ionice -c 3 rsync -a a b", "rsync -a a b", []string{"ionice"}),
+		Entry("stdbuf", "stdbuf -oL git log", "git log", []string{"stdbuf"}),
+		Entry("command", "command git push", "git push", []string{"command"}),
+		Entry("exec", "exec -a name git push", "git push", []string{"exec"}),
+		Entry("xargs", "git ls-files -m | xargs -n 1 -I{} git add {}", "git add {}", []string{"xargs"}),
+		Entry("find -exec", "find . -name '*.tmp' -exec rm -f {} +", "rm -f {}", []string{"find -exec"}),
+		Entry("find -execdir", `find . -type d -execdir git pull \;`, "git pull", []string{"find -execdir"}),
+		Entry("wrapper by path", "/usr/bin/sudo git push", "git push", []string{"sudo"}),
+	)
+
+	DescribeTable("does not unwrap commands that do not run commands",
+		func(command string) {
+			Expect(wrapped(command)).To(BeEmpty())
+		},
+		Entry("command -v", "command -v git"),
+		Entry("bash running a script", "bash deploy.sh -c"),
+		Entry("sudo -l", "sudo -l"),
+		Entry("find without -exec", "find . -name '*.go' -delete"),
+		Entry("bash -c with an expansion", `bash -c "$CMD"`),
+	)
+
+	It("records the wrapper chain of nested wrappers", func() {
+		commands := wrapped(`sudo bash -c "timeout 60 sh -c 'git push --force'"`)
+
+		Expect(commands).To(HaveLen(4))
+		Expect(commands[3].Name).To(Equal("git"))
+		Expect(commands[3].Wrappers).To(Equal([]string{"sudo", "bash -c", "timeout", "sh -c"}))
+	})
+
+	It("surfaces git operations and file writes of nested scripts", func() {
+		result, err := parser.NewBashParser().Parse(`sudo sh -c 'echo 127.0.0.1 x >> /etc/hosts' && bash -c "git push"`)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.GitOperations).To(HaveLen(1))
+		Expect(result.GitOperations[0].Wrappers).To(Equal([]string{"bash -c"}))
+
+		Expect(result.FileWrites).To(HaveLen(1))
+		Expect(result.FileWrites[0].Path).To(Equal("/etc/hosts"))
+		Expect(result.FileWrites[0].Operation).To(Equal(parser.WriteOpAppend))
+	})
+
+	It("detects file writes of wrapped commands", func() {
+		result, err := parser.NewBashParser().Parse("echo x | sudo tee -a /etc/hosts")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.FileWrites).To(HaveLen(1))
+		Expect(result.FileWrites[0].Path).To(Equal("/etc/hosts"))
+	})
+
+	It("reports nested commands at the location of the command running them", func() {
+		commands := wrapped("cd /src && bash -c 'git add . && git commit'")
+
+		Expect(commands).To(HaveLen(2))
+
+		for _, cmd := range commands {
+			Expect(cmd.Location).To(Equal(parser.Location{Line: 1, Column: 12}))
+			Expect(cmd.Type).To(Equal(parser.CmdTypeChain))
+			Expect(cmd.WorkingDirectory).To(Equal("/src"))
+		}
+	})
+
+	It("tracks directory changes of eval but not of nested shells", func() {
+		result, err := parser.NewBashParser().Parse("bash -c 'cd /a' && eval cd /b && git push")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(result.GitOperations).To(HaveLen(1))
+		Expect(result.GitOperations[0].WorkingDirectory).To(Equal("/b"))
+	})
+
+	It("runs commands of env -C in its directory", func() {
+		commands := wrapped("env -C /repo git status")
+
+		Expect(commands).To(HaveLen(1))
+		Expect(commands[0].WorkingDirectory).To(Equal("/repo"))
+	})
+
+	Describe("nesting depth", func() {
+		const command = `bash -c "sh -c 'eval git push'"`
+
+		It("parses nested scripts up to the default depth", func() {
+			result, err := parser.NewBashParser().Parse(command)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.GitOperations).To(HaveLen(1))
+			Expect(result.DepthExceeded).To(BeFalse())
+		})
+
+		It("skips scripts nested deeper than the maximum depth", func() {
+			result, err := parser.NewBashParser(parser.WithMaxNestingDepth(2)).Parse(command)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.GitOperations).To(BeEmpty())
+			Expect(result.DepthExceeded).To(BeTrue())
+		})
+
+		It("unwraps prefix wrappers without parsing scripts", func() {
+			result, err := parser.NewBashParser(parser.WithMaxNestingDepth(0)).
+				Parse("sudo timeout 5 git push && bash -c 'git push'")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(result.GitOperations).To(HaveLen(1))
+			Expect(result.GitOperations[0].Wrappers).To(Equal([]string{"sudo", "timeout"}))
+			Expect(result.DepthExceeded).To(BeTrue())
+		})
+	})
+})
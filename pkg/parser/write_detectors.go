@@ -0,0 +1,421 @@
+package parser
+
+import (
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// WriteDetector returns the files a command writes to. Detectors set the
+// Path, Operation and, where known, the Content or Edit of each write; the
+// parser fills in the source command, location and working directory.
+type WriteDetector func(cmd Command) []FileWrite
+
+var (
+	writeDetectorsMu sync.RWMutex
+
+	// writeDetectors are the write detectors by command name.
+	writeDetectors = map[string]WriteDetector{
+		"tee":      detectTeeWrites,
+		"cp":       detectLastOperandWrite(WriteOpCopy),
+		"copy":     detectLastOperandWrite(WriteOpCopy),
+		"mv":       detectLastOperandWrite(WriteOpMove),
+		"move":     detectLastOperandWrite(WriteOpMove),
+		"sed":      detectSedWrites,
+		"gsed":     detectSedWrites,
+		"dd":       detectDDWrites,
+		"install":  detectInstallWrites,
+		"rsync":    detectRsyncWrites,
+		"truncate": detectTruncateWrites,
+		"ln":       detectLinkWrites,
+		"perl":     detectScriptWrites(perlWritePatterns, "-e", "-E"),
+		"ruby":     detectScriptWrites(rubyWritePatterns, "-e"),
+		"python":   detectScriptWrites(pythonWritePatterns, "-c"),
+		"python2":  detectScriptWrites(pythonWritePatterns, "-c"),
+		"python3":  detectScriptWrites(pythonWritePatterns, "-c"),
+		"node":     detectScriptWrites(nodeWritePatterns, "-e", "--eval", "-p", "--print"),
+	}
+)
+
+// RegisterWriteDetector registers the write detector of a command, replacing
+// any detector registered for it. Commands are looked up by the base name of
+// the command, so "/usr/bin/sed" uses the detector of "sed".
+func RegisterWriteDetector(name string, detector WriteDetector) {
+	writeDetectorsMu.Lock()
+	defer writeDetectorsMu.Unlock()
+
+	writeDetectors[name] = detector
+}
+
+// DetectFileWrites returns the files the command writes to, using the write
+// detector registered for its name.
+func DetectFileWrites(cmd Command) []FileWrite {
+	writeDetectorsMu.RLock()
+	detector, ok := writeDetectors[path.Base(cmd.Name)]
+	writeDetectorsMu.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	writes := detector(cmd)
+
+	for i := range writes {
+		writes[i].Source = cmd.Name
+		writes[i].Location = cmd.Location
+		writes[i].WorkingDirectory = cmd.WorkingDirectory
+	}
+
+	return writes
+}
+
+// optionSpec describes the options of a command for splitting its arguments
+// into options and operands.
+type optionSpec struct {
+	// withValue are the short (single letter) and long options that take a
+	// value, attached ("-m755", "--mode=755") or in the next argument.
+	withValue map[string]bool
+
+	// attachedValue are the short options that take an optional value, only
+	// attached ("-i.bak").
+	attachedValue map[string]bool
+}
+
+// scan calls option for each option of the arguments, with its value if it
+// takes one, and returns the operands. A value-taking short option ends a
+// cluster of short options ("-pe CODE", "-m755"). Arguments after "--" are
+// operands.
+func (s optionSpec) scan(args []string, option func(name, value string)) []string {
+	operands := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		switch {
+		case arg == "--":
+			return append(operands, args[i+1:]...)
+
+		case strings.HasPrefix(arg, "--"):
+			name, value, attached := strings.Cut(arg, "=")
+			if !attached && s.withValue[name] && i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+
+			option(name, value)
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			for j := 1; j < len(arg); j++ {
+				name := "-" + arg[j:j+1]
+				if s.attachedValue[name] {
+					option(name, arg[j+1:])
+
+					break
+				}
+
+				if !s.withValue[name] {
+					option(name, "")
+
+					continue
+				}
+
+				value := arg[j+1:]
+				if value == "" && i+1 < len(args) {
+					i++
+					value = args[i]
+				}
+
+				option(name, value)
+
+				break
+			}
+
+		default:
+			operands = append(operands, arg)
+		}
+	}
+
+	return operands
+}
+
+// operands returns the arguments that are not options or option values.
+func (s optionSpec) operands(args []string) []string {
+	return s.scan(args, func(string, string) {})
+}
+
+// optionValues returns the values of an option, in any of its spellings.
+func (s optionSpec) optionValues(args []string, names ...string) []string {
+	var values []string
+
+	s.scan(args, func(name, value string) {
+		if slices.Contains(names, name) {
+			values = append(values, value)
+		}
+	})
+
+	return values
+}
+
+// hasOption reports whether the arguments contain an option, in any of its
+// spellings.
+func (s optionSpec) hasOption(args []string, names ...string) bool {
+	found := false
+
+	s.scan(args, func(name, _ string) {
+		found = found || slices.Contains(names, name)
+	})
+
+	return found
+}
+
+// writesTo returns writes of the paths.
+func writesTo(op WriteOp, paths ...string) []FileWrite {
+	writes := make([]FileWrite, 0, len(paths))
+
+	for _, p := range paths {
+		if p != "" {
+			writes = append(writes, FileWrite{Path: p, Operation: op})
+		}
+	}
+
+	return writes
+}
+
+// detectTeeWrites detects the files tee writes to: all its arguments that
+// are not flags.
+func detectTeeWrites(cmd Command) []FileWrite {
+	return writesTo(WriteOpTee, extractTeeTargets(cmd.Args)...)
+}
+
+// detectLastOperandWrite returns a detector of commands writing to their last
+// argument, given a source and a destination.
+func detectLastOperandWrite(op WriteOp) WriteDetector {
+	return func(cmd Command) []FileWrite {
+		if len(cmd.Args) < 2 { //nolint:mnd // Trivial check for minimum args (source + dest)
+			return nil
+		}
+
+		return writesTo(op, cmd.Args[len(cmd.Args)-1])
+	}
+}
+
+// ddOutputPrefix prefixes the output file operand of dd.
+const ddOutputPrefix = "of="
+
+// detectDDWrites detects the output file of dd (of=PATH).
+func detectDDWrites(cmd Command) []FileWrite {
+	var writes []FileWrite
+
+	for _, arg := range cmd.Args {
+		if target, ok := strings.CutPrefix(arg, ddOutputPrefix); ok {
+			writes = append(writes, writesTo(WriteOpDD, target)...)
+		}
+	}
+
+	return writes
+}
+
+// installSpec are the options of install.
+var installSpec = optionSpec{withValue: map[string]bool{
+	"-m": true, "--mode": true,
+	"-o": true, "--owner": true,
+	"-g": true, "--group": true,
+	"-S": true, "--suffix": true,
+	"-t": true, "--target-directory": true,
+	"--strip-program": true,
+}}
+
+// detectInstallWrites detects the files install creates: the directories of
+// install -d, the sources copied into install -t DIR, or the last operand.
+func detectInstallWrites(cmd Command) []FileWrite {
+	operands := installSpec.operands(cmd.Args)
+
+	if installSpec.hasOption(cmd.Args, "-d", "--directory") {
+		return writesTo(WriteOpInstall, operands...)
+	}
+
+	if dirs := installSpec.optionValues(cmd.Args, "-t", "--target-directory"); len(dirs) > 0 {
+		return writesTo(WriteOpInstall, intoDirectory(dirs[len(dirs)-1], operands)...)
+	}
+
+	if len(operands) < 2 { //nolint:mnd // Trivial check for minimum args (source + dest)
+		return nil
+	}
+
+	return writesTo(WriteOpInstall, operands[len(operands)-1])
+}
+
+// rsyncSpec are the rsync options taking a value in the next argument.
+var rsyncSpec = optionSpec{withValue: map[string]bool{
+	"-e": true, "--rsh": true,
+	"-f": true, "--filter": true,
+	"-T": true, "--temp-dir": true,
+	"-B": true, "--block-size": true,
+	"--exclude": true, "--include": true,
+	"--exclude-from": true, "--include-from": true, "--files-from": true,
+	"--backup-dir": true, "--suffix": true, "--partial-dir": true,
+	"--compare-dest": true, "--copy-dest": true, "--link-dest": true,
+	"--chmod": true, "--chown": true, "--usermap": true, "--groupmap": true,
+	"--rsync-path": true, "--password-file": true,
+	"--log-file": true, "--log-file-format": true, "--out-format": true,
+	"--max-size": true, "--min-size": true, "--bwlimit": true,
+	"--timeout": true, "--contimeout": true, "--port": true,
+}}
+
+// detectRsyncWrites detects the local destination of rsync, its last
+// operand. Remote destinations (host:path, rsync://) are not files of this
+// machine.
+func detectRsyncWrites(cmd Command) []FileWrite {
+	operands := rsyncSpec.operands(cmd.Args)
+	if len(operands) < 2 { //nolint:mnd // Trivial check for minimum args (source + dest)
+		return nil
+	}
+
+	dest := operands[len(operands)-1]
+	if isRemoteRsyncPath(dest) {
+		return nil
+	}
+
+	return writesTo(WriteOpSync, dest)
+}
+
+// isRemoteRsyncPath reports whether an rsync path is on a remote host. A
+// colon before the first slash separates the host, as in "host:path".
+func isRemoteRsyncPath(p string) bool {
+	if strings.HasPrefix(p, "rsync://") {
+		return true
+	}
+
+	colon := strings.IndexByte(p, ':')
+	slash := strings.IndexByte(p, '/')
+
+	return colon > 0 && (slash < 0 || colon < slash)
+}
+
+// truncateSpec are the options of truncate.
+var truncateSpec = optionSpec{withValue: map[string]bool{
+	"-s": true, "--size": true,
+	"-r": true, "--reference": true,
+}}
+
+// detectTruncateWrites detects the files truncate resizes: its operands.
+func detectTruncateWrites(cmd Command) []FileWrite {
+	return writesTo(WriteOpTruncate, truncateSpec.operands(cmd.Args)...)
+}
+
+// lnSpec are the options of ln.
+var lnSpec = optionSpec{withValue: map[string]bool{
+	"-S": true, "--suffix": true,
+	"-t": true, "--target-directory": true,
+}}
+
+// detectLinkWrites detects the links ln creates: the last operand, the base
+// names of the targets in ln -t DIR, or the base name of a single target in
+// the working directory.
+func detectLinkWrites(cmd Command) []FileWrite {
+	operands := lnSpec.operands(cmd.Args)
+
+	if dirs := lnSpec.optionValues(cmd.Args, "-t", "--target-directory"); len(dirs) > 0 {
+		return writesTo(WriteOpLink, intoDirectory(dirs[len(dirs)-1], operands)...)
+	}
+
+	switch len(operands) {
+	case 0:
+		return nil
+	case 1:
+		return writesTo(WriteOpLink, path.Base(operands[0]))
+	default:
+		return writesTo(WriteOpLink, operands[len(operands)-1])
+	}
+}
+
+// intoDirectory returns the paths of the sources copied into a directory.
+func intoDirectory(dir string, sources []string) []string {
+	paths := make([]string, 0, len(sources))
+
+	for _, source := range sources {
+		paths = append(paths, path.Join(dir, path.Base(source)))
+	}
+
+	return paths
+}
+
+// Write patterns of interpreter one-liners. The last capture group of each
+// is the path written to.
+var (
+	pythonWritePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`open\(\s*(['"])([^'"]+)['"]\s*,\s*(?:mode\s*=\s*)?['"][^'"]*[wax+][^'"]*['"]`),
+		regexp.MustCompile(`Path\(\s*['"]([^'"]+)['"]\s*\)\s*\.\s*(?:write_text|write_bytes|touch)\(`),
+	}
+
+	nodeWritePatterns = []*regexp.Regexp{
+		regexp.MustCompile(
+			"(?:writeFileSync|appendFileSync|writeFile|appendFile|createWriteStream|truncateSync|truncate)" +
+				"\\(\\s*['\"`]([^'\"`]+)['\"`]",
+		),
+	}
+
+	perlWritePatterns = []*regexp.Regexp{
+		// open(my $fh, '>', 'file') and open(FH, ">>", "file")
+		regexp.MustCompile(`open\s*\(?\s*[^,]+,\s*['"](?:>>?|\+<|\+>)['"]\s*,\s*['"]([^'"]+)['"]`),
+		// open(FH, ">file")
+		regexp.MustCompile(`open\s*\(?\s*[^,]+,\s*['"](?:>>?|\+<|\+>)\s*([^'"]+)['"]`),
+	}
+
+	rubyWritePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`File\.(?:write|binwrite)\(\s*['"]([^'"]+)['"]`),
+		regexp.MustCompile(`File\.open\(\s*['"]([^'"]+)['"]\s*,\s*['"][^'"]*[wa+][^'"]*['"]`),
+	}
+)
+
+// interpreterSpec are the interpreter options taking a value.
+var interpreterSpec = optionSpec{withValue: map[string]bool{
+	// Code of perl, ruby, python and node
+	"-e": true, "-E": true, "-c": true, "--eval": true, "--print": true,
+	// Modules, include paths and warnings
+	"-I": true, "-M": true, "-m": true, "-r": true, "--require": true,
+	"-W": true, "-X": true,
+}, attachedValue: map[string]bool{
+	// In-place backup extension, record separator and line ending of perl
+	"-i": true, "-0": true, "-l": true, "-x": true,
+}}
+
+// detectScriptWrites returns a detector of the files an interpreter writes
+// to: the paths its code opens for writing, and for perl -i and ruby -i the
+// files edited in place. Paths are only found in literal strings.
+func detectScriptWrites(patterns []*regexp.Regexp, codeOptions ...string) WriteDetector {
+	return func(cmd Command) []FileWrite {
+		var writes []FileWrite
+
+		code := interpreterSpec.optionValues(cmd.Args, codeOptions...)
+
+		for _, snippet := range code {
+			for _, pattern := range patterns {
+				for _, match := range pattern.FindAllStringSubmatch(snippet, -1) {
+					writes = append(writes, writesTo(WriteOpScript, match[len(match)-1])...)
+				}
+			}
+		}
+
+		name := path.Base(cmd.Name)
+		if (name == "perl" || name == "ruby") && hasInPlaceFlag(cmd.Args) {
+			// Without code options, the first operand is the script file
+			operands := interpreterSpec.operands(cmd.Args)
+			if len(code) == 0 && len(operands) > 0 {
+				operands = operands[1:]
+			}
+
+			writes = append(writes, writesTo(WriteOpInPlace, operands...)...)
+		}
+
+		return writes
+	}
+}
+
+// hasInPlaceFlag reports whether perl or ruby arguments contain -i, alone
+// ("-i", "-i.bak") or in a cluster ("-pi", "-pi.bak").
+func hasInPlaceFlag(args []string) bool {
+	return interpreterSpec.hasOption(args, "-i")
+}
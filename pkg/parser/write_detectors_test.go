@@ -0,0 +1,135 @@
+package parser_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/smykla-labs/klaudiush/pkg/parser"
+)
+
+var _ = Describe("Write detectors", func() {
+	var p *parser.BashParser
+
+	BeforeEach(func() {
+		p = parser.NewBashParser()
+	})
+
+	// writes returns the paths and operations of the file writes of a command.
+	writes := func(command string) map[string]parser.WriteOp {
+		result, err := p.Parse(command)
+		Expect(err).NotTo(HaveOccurred())
+
+		paths := make(map[string]parser.WriteOp, len(result.FileWrites))
+		for _, fw := range result.FileWrites {
+			paths[fw.Path] = fw.Operation
+		}
+
+		return paths
+	}
+
+	DescribeTable("detects the files commands write to",
+		func(command string, expected map[string]parser.WriteOp) {
+			Expect(writes(command)).To(Equal(expected))
+		},
+		Entry("sed -i", "sed -i 's/x/y/' README.md",
+			map[string]parser.WriteOp{"README.md": parser.WriteOpInPlace}),
+		Entry("sed -i with suffix and expressions", "sed -i.bak -e 's/a/b/' -e 's/c/d/' a.md b.md",
+			map[string]parser.WriteOp{"a.md": parser.WriteOpInPlace, "b.md": parser.WriteOpInPlace}),
+		Entry("BSD sed -i with empty suffix", "sed -i '' 's/x/y/' README.md",
+			map[string]parser.WriteOp{"README.md": parser.WriteOpInPlace}),
+		Entry("BSD sed -i with a separate suffix", "sed -i .bak 's/x/y/' README.md",
+			map[string]parser.WriteOp{"README.md": parser.WriteOpInPlace}),
+		Entry("sed -i with a dotfile after an expression", "sed -i -e 's/x/y/' .bashrc",
+			map[string]parser.WriteOp{".bashrc": parser.WriteOpInPlace}),
+		Entry("sed --in-place in a cluster", "sed -Ei 's/x+/y/' notes.md",
+			map[string]parser.WriteOp{"notes.md": parser.WriteOpInPlace}),
+		Entry("sed without -i", "sed 's/x/y/' README.md", map[string]parser.WriteOp{}),
+		Entry("dd", "dd if=image.iso of=/dev/sda bs=4M",
+			map[string]parser.WriteOp{"/dev/sda": parser.WriteOpDD}),
+		Entry("install", "install -m755 -o root x /usr/local/bin/x",
+			map[string]parser.WriteOp{"/usr/local/bin/x": parser.WriteOpInstall}),
+		Entry("install -t", "install -t /usr/local/bin build/a build/b",
+			map[string]parser.WriteOp{
+				"/usr/local/bin/a": parser.WriteOpInstall,
+				"/usr/local/bin/b": parser.WriteOpInstall,
+			}),
+		Entry("install -d", "install -d -m 0700 /etc/app /var/lib/app",
+			map[string]parser.WriteOp{
+				"/etc/app":     parser.WriteOpInstall,
+				"/var/lib/app": parser.WriteOpInstall,
+			}),
+		Entry("rsync", "rsync -av --exclude .git src/ /backup/src",
+			map[string]parser.WriteOp{"/backup/src": parser.WriteOpSync}),
+		Entry("rsync to a remote host", "rsync -av src/ host:/backup/src", map[string]parser.WriteOp{}),
+		Entry("truncate", "truncate -s0 app.log other.log",
+			map[string]parser.WriteOp{"app.log": parser.WriteOpTruncate, "other.log": parser.WriteOpTruncate}),
+		Entry("ln -sf", "ln -sf /etc/app.conf config/app.conf",
+			map[string]parser.WriteOp{"config/app.conf": parser.WriteOpLink}),
+		Entry("ln with a single target", "ln -s /etc/app.conf",
+			map[string]parser.WriteOp{"app.conf": parser.WriteOpLink}),
+		Entry("perl -pi -e", "perl -pi -e 's/foo/bar/g' config.yaml",
+			map[string]parser.WriteOp{"config.yaml": parser.WriteOpInPlace}),
+		Entry("perl -i.bak without -e", "perl -i.bak fix.pl data.txt",
+			map[string]parser.WriteOp{"data.txt": parser.WriteOpInPlace}),
+		Entry("perl -e opening a file for writing",
+			`perl -e 'open(my $fh, ">", "out.txt"); print $fh "x"'`,
+			map[string]parser.WriteOp{"out.txt": parser.WriteOpScript}),
+		Entry("perl -ne reading files", "perl -ne 'print if /x/' in.txt", map[string]parser.WriteOp{}),
+		Entry("python -c", `python -c "open('f.txt', 'w').write('x')"`,
+			map[string]parser.WriteOp{"f.txt": parser.WriteOpScript}),
+		Entry("python3 -c with pathlib", `python3 -c "from pathlib import Path; Path('a.md').write_text('x')"`,
+			map[string]parser.WriteOp{"a.md": parser.WriteOpScript}),
+		Entry("python -c reading a file", `python -c "print(open('f.txt').read())"`,
+			map[string]parser.WriteOp{}),
+		Entry("node -e", `node -e "require('fs').writeFileSync('out.json', '{}')"`,
+			map[string]parser.WriteOp{"out.json": parser.WriteOpScript}),
+		Entry("ruby -e", `ruby -e 'File.write("out.txt", "x")'`,
+			map[string]parser.WriteOp{"out.txt": parser.WriteOpScript}),
+		Entry("command by path", "/usr/bin/sed -i 's/x/y/' README.md",
+			map[string]parser.WriteOp{"README.md": parser.WriteOpInPlace}),
+	)
+
+	It("records the source command and working directory", func() {
+		result, err := p.Parse("cd docs && sed -i 's/x/y/' README.md")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.FileWrites).To(HaveLen(1))
+
+		fw := result.FileWrites[0]
+		Expect(fw.Source).To(Equal("sed"))
+		Expect(fw.WorkingDirectory).To(Equal("docs"))
+	})
+
+	It("computes the edit of sed substitution scripts", func() {
+		result, err := p.Parse("sed -i 's/foo/bar/g; s|/usr|/opt|' README.md")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.FileWrites).To(HaveLen(1))
+
+		edit := result.FileWrites[0].Edit
+		Expect(edit).NotTo(BeNil())
+		Expect(edit.Apply("foo foo /usr/bin\n")).To(Equal("bar bar /opt/bin\n"))
+	})
+
+	DescribeTable("does not compute the edit of other sed scripts",
+		func(command string) {
+			result, err := p.Parse(command)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.FileWrites).To(HaveLen(1))
+			Expect(result.FileWrites[0].Edit).To(BeNil())
+		},
+		Entry("delete command", "sed -i '/foo/d' README.md"),
+		Entry("addresses", "sed -i '1s/a/b/' README.md"),
+		Entry("script file", "sed -i -f fix.sed README.md"),
+		Entry("quiet mode", "sed -n -i 's/a/b/p' README.md"),
+		Entry("back-references", `sed -i 's/\(a\)\1/b/' README.md`),
+		Entry("case conversion", `sed -i 's/a/\U&/' README.md`),
+	)
+
+	It("uses registered write detectors", func() {
+		parser.RegisterWriteDetector("klaudiush-test-writer", func(cmd parser.Command) []parser.FileWrite {
+			return []parser.FileWrite{{Path: cmd.Args[0], Operation: parser.WriteOpRedirect}}
+		})
+
+		Expect(writes("klaudiush-test-writer out.txt")).
+			To(Equal(map[string]parser.WriteOp{"out.txt": parser.WriteOpRedirect}))
+	})
+})
@@ -59,7 +59,8 @@ type Info struct {
 
 // ValidateRequest contains the context passed to plugin validators.
 type ValidateRequest struct {
-	// EventType is the hook event type ("PreToolUse", "PostToolUse", "Notification").
+	// EventType is the hook event type ("PreToolUse", "PostToolUse", "Notification",
+	// "UserPromptSubmit", "Stop", "SubagentStop", "SessionStart", "SessionEnd", "PreCompact").
 	EventType string `json:"event_type"`
 
 	// ToolName is the tool being invoked ("Bash", "Write", "Edit", etc.).
@@ -86,6 +87,25 @@ type ValidateRequest struct {
 	// Config contains plugin-specific configuration from the config file.
 	// The structure depends on how the plugin is configured in config.toml.
 	Config map[string]any `json:"config,omitempty"`
+
+	// Prompt is the submitted prompt (for UserPromptSubmit events).
+	Prompt string `json:"prompt,omitempty"`
+
+	// Cwd is the working directory of the Claude Code session.
+	Cwd string `json:"cwd,omitempty"`
+
+	// StopHookActive is true when Claude Code is already continuing because of
+	// a stop hook (for Stop and SubagentStop events).
+	StopHookActive bool `json:"stop_hook_active,omitempty"`
+
+	// Source is how the session started (for SessionStart events).
+	Source string `json:"source,omitempty"`
+
+	// Reason is why the session ended (for SessionEnd events).
+	Reason string `json:"reason,omitempty"`
+
+	// Trigger is what triggered compaction (for PreCompact events).
+	Trigger string `json:"trigger,omitempty"`
 }
 
 // ValidateResponse contains the validation result returned by a plugin.
@@ -98,6 +118,10 @@ type ValidateResponse struct {
 	// Set to false to allow the operation with a warning.
 	ShouldBlock bool `json:"should_block"`
 
+	// ShouldAsk indicates whether the user should be asked to confirm the operation.
+	// Use for risky but legitimate operations. Ignored when ShouldBlock is true.
+	ShouldAsk bool `json:"should_ask,omitempty"`
+
 	// Message is a human-readable message describing the result.
 	Message string `json:"message,omitempty"`
 
@@ -150,6 +174,16 @@ func WarnResponse(message string) *ValidateResponse {
 	}
 }
 
+// AskResponse returns a response asking the user to confirm the operation.
+func AskResponse(message string) *ValidateResponse {
+	return &ValidateResponse{
+		Passed:      false,
+		ShouldBlock: false,
+		ShouldAsk:   true,
+		Message:     message,
+	}
+}
+
 // FailWithCode returns a response with an error code and optional hint/link.
 func FailWithCode(code, message, fixHint, docLink string) *ValidateResponse {
 	return &ValidateResponse{
@@ -193,6 +193,17 @@ var _ = Describe("API", func() {
 			})
 		})
 
+		Describe("AskResponse", func() {
+			It("should create a failing response that asks for confirmation", func() {
+				resp := plugin.AskResponse("Confirm message")
+
+				Expect(resp.Passed).To(BeFalse())
+				Expect(resp.ShouldBlock).To(BeFalse())
+				Expect(resp.ShouldAsk).To(BeTrue())
+				Expect(resp.Message).To(Equal("Confirm message"))
+			})
+		})
+
 		Describe("FailWithCode", func() {
 			It("should create a failing response with code and hints", func() {
 				resp := plugin.FailWithCode(